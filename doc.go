@@ -6,7 +6,8 @@
 //
 // # Features
 //
-//   - Dual transport support: gRPC and HTTP REST API
+//   - Transports: gRPC, HTTP REST API, and the eth_* JSON-RPC endpoint for
+//     block and contract reads
 //   - Round-robin load balancing across multiple nodes
 //   - Tier-based fallback: primary/fallback node groups via NodeConfig.Tier
 //   - Background health checking with automatic node recovery
@@ -14,9 +15,12 @@
 //   - BIP39/BIP44 mnemonic and address generation
 //   - Transaction creation, signing, and broadcasting
 //   - TRC20 token support (transfer, balance, metadata)
+//   - TRC10 token support (issue, sale, transfer, balances with metadata, estimates)
 //   - Resource delegation (bandwidth and energy)
 //   - Staking 2.0: stake, unstake, withdraw, aggregated stake overview
-//   - Super representative voting and reward claiming
+//   - Super representative voting, reward claiming and witness applications
+//   - Governance proposals with a typed parameter registry
+//   - Bancor exchanges: create, fund, trade, and quote a trade offline
 //   - Account operations and activation
 //   - Block and transaction queries
 //   - Multi-network support (Mainnet, Shasta, Nile)
@@ -118,9 +122,42 @@
 // Each request uses the next node in round-robin fashion. By default a
 // background health-checker probes every node and excludes unhealthy ones
 // from selection; failed live requests count toward the per-node failure
-// threshold. The error of the failed call is still returned to the caller —
-// there is no automatic retry on the same request, the next request just
-// goes to the next healthy node.
+// threshold. By default the error of the failed call is returned to the
+// caller and the next request goes to the next healthy node. Health.Retry
+// turns on automatic retries: reads and BroadcastTransaction that failed at
+// the network level are repeated on another healthy node, with jittered
+// backoff and a per-method retry budget:
+//
+//	Health: client.HealthConfig{
+//	    Retry: client.RetryPolicy{
+//	        MaxAttempts:    3,
+//	        InitialBackoff: 50 * time.Millisecond,
+//	        MaxBackoff:     time.Second,
+//	    },
+//	}
+//
+// Transaction builders (CreateTransaction, TriggerContract, ...) and request
+// errors such as ContractValidateError are never retried. Every retry is
+// reported through MetricsCollector.RecordRetry.
+//
+// Health.Hedge cuts the tail latency a single slow node causes: when a
+// read-only call has not been answered within the method's recent Percentile
+// latency, the same request goes to a second healthy node of the same tier,
+// and the first good answer wins:
+//
+//	Health: client.HealthConfig{
+//	    Hedge: client.HedgePolicy{Enabled: true, Percentile: 0.95},
+//	}
+//
+// Health.Selection replaces round-robin within a tier by a load-aware
+// SelectionStrategy. NewLeastOutstandingStrategy sends each call to the node
+// with the fewest calls in flight; NewEWMAStrategy compares two random nodes
+// by their moving-average latency and in-flight count, so a slow node gets
+// little traffic without being marked unhealthy:
+//
+//	Health: client.HealthConfig{
+//	    Selection: client.NewEWMAStrategy(),
+//	}
 //
 // # Tier-based Fallback with Health Checking
 //
@@ -148,13 +185,106 @@
 //	    },
 //	}
 //
+// Health.MaxLagBlocks and Health.MaxLagTime catch nodes that answer but have
+// stopped syncing: a node whose head block trails the best head seen across
+// the pool by more than either limit is marked unhealthy at once, and returns
+// when SuccessThreshold probes find it caught up.
+//
 // When every node of every tier is unhealthy, calls return ErrNoHealthyNodes —
 // detect with errors.Is(err, client.ErrNoHealthyNodes). The health-checker
 // keeps probing all nodes (at UnhealthyInterval) and they re-enter the pool
 // the moment they recover.
 //
+// Nodes change at runtime without recreating the client: AddNode adds one,
+// RemoveNode stops sending it calls, waits for the running ones and closes it,
+// and SetNodeTier moves one to another tier:
+//
+//	err := tron.AddNode(client.NodeConfig{Address: "new-node:50051", UseTLS: true})
+//	err = tron.RemoveNode(ctx, "old-node:50051")
+//
+// PoolStatus returns a snapshot of every node - tier, health, failure counts,
+// last error, last probe, latency - and SubscribeHealth calls a function on
+// every change: a node turning unhealthy or healthy, a tier shift, a node
+// added, removed or moved:
+//
+//	unsubscribe, err := tron.SubscribeHealth(func(e client.HealthEvent) {
+//		if e.Kind == client.EventNodeUnhealthy {
+//			log.Printf("%s is down: %v", e.Address, e.Cause)
+//		}
+//	})
+//
+// HealthHandler serves the same status as JSON - healthy nodes per tier, the
+// active tier, each node's head and lag - and answers 503 once no node is
+// healthy, which makes it a readiness check. Nodes without an alias are named
+// by their host, and errors by their class, unless HealthReportAddresses and
+// HealthReportErrors ask for more:
+//
+//	ready, err := tron.HealthHandler()
+//	mux.Handle("/readyz", ready)
+//
 // To disable the health-checker entirely (legacy round-robin without health),
-// set Health.Disabled = true. NodeConfig.Tier is then ignored.
+// set Health.Disabled = true. NodeConfig.Tier is then ignored, and nodes can
+// no longer be changed at runtime.
+//
+// # Request Coalescing
+//
+// With Config.Coalesce set, identical reads that are in flight at the same
+// time - same method, arguments and call options - are merged into a single
+// upstream call through CoalescingTransport, and every caller receives its own
+// copy of the answer. Transaction-building and broadcast calls are never
+// merged.
+//
+// # Read Cache
+//
+// With Config.Cache enabled, CachingTransport keeps the answers of reads that
+// rarely or never change: TRC10 assets, blocks by id and the TRC20 name,
+// symbol and decimals forever, contracts and the chain parameters for 10
+// minutes, and in the solidity pool also blocks by number and transactions.
+// CacheConfig.TTL sets the policy per method and CacheConfig.MaxEntries bounds
+// the cache, the least recently used answers going first. Client.InvalidateCache
+// drops cached answers on demand.
+//
+// # Solidity Nodes
+//
+// Nodes with NodeConfig.Solidity set serve only solidified - irreversible -
+// state, through the WalletSolidity gRPC service or the /walletsolidity HTTP
+// API. They form a separate pool, reached through Client.Confirmed, so that
+// code which must never act on a reversible state, such as deposit crediting,
+// cannot reach a full node by accident:
+//
+//	confirmed, err := tron.Confirmed()
+//	if err != nil {
+//	    return err // ErrNoSolidityNodes
+//	}
+//	info, err := confirmed.GetTransactionInfoByHash(ctx, txID)
+//
+// Solidity nodes answer reads only; other calls fail with
+// ErrUnsupportedBySolidity.
+//
+// # JSON-RPC Nodes
+//
+// ProtocolJSONRPC talks to the Ethereum-compatible /jsonrpc endpoint some
+// providers offer instead of the Tron APIs. It serves blocks, transaction
+// info by id, constant contract calls and energy estimates through eth_*
+// methods, converting addresses between base58 and 20-byte hex; every other
+// call fails with ErrUnsupportedByJSONRPC before anything is sent:
+//
+//	{Protocol: client.ProtocolJSONRPC, Address: "https://api.trongrid.io/jsonrpc"}
+//
+// See JSONRPCTransport for what the answers carry. Give such nodes a tier
+// of their own when mixing them with gRPC or HTTP nodes.
+//
+// # Per-call Options
+//
+// WithCallOptions attaches options to a context that change how the calls
+// made with it are routed: CallSolidified reads through the solidity pool for
+// that call alone, CallNode pins it to one node, CallTier restricts it to one
+// tier and CallTimeout bounds it, retries included:
+//
+//	ctx = client.WithCallOptions(ctx, client.CallNode(broadcastNode), client.CallTimeout(2*time.Second))
+//	tx, err := tron.GetTransactionByHash(ctx, txID)
+//
+// A pinned node or tier that is not configured fails with ErrNodeNotFound.
 //
 // # Using TronGrid with API Key
 //
@@ -174,6 +304,12 @@
 //	    },
 //	}
 //
+// To stay within TronGrid's per-key limits, set NodeConfig.RateLimit to pace
+// the node and list several keys in NodeConfig.APIKeys. The key in use rests
+// for the node's Retry-After when it is rate-limited, and the next one takes
+// over and resends the request. A call rate-limited on every key leaves node
+// health untouched and is retried on another node when RetryPolicy allows.
+//
 // # Address Generation
 //
 // Generate addresses from mnemonic phrases using BIP39/BIP44:
@@ -264,6 +400,28 @@
 //	    100_000_000,                  // Fee limit in SUN
 //	)
 //
+// # TRC10 Tokens
+//
+// TRC10 tokens go by a numeric id, and their amounts are TokenAmounts scaled
+// by the token's precision:
+//
+//	// Every TRC10 token an account holds, with its metadata
+//	balances, err := tron.GetTRC10Balances(ctx, "TAddress")
+//
+//	// Send 1.5 BTT (id 1002000, precision 6)
+//	amount, err := gotron.FromTokenDecimal(decimal.RequireFromString("1.5"), 6)
+//	estimate, err := tron.EstimateTRC10Transfer(ctx, "TFromAddress", "TToAddress", "1002000", amount)
+//	tx, err := tron.CreateTransferAssetTransaction(ctx, "TFromAddress", "TToAddress", "1002000", amount)
+//
+// Issuing a token takes a client.AssetIssueRequest, which is validated before
+// anything is sent; CreateAssetIssue also checks the owner can pay the issue
+// fee. ParticipateAssetIssue, UpdateAsset and UnfreezeAsset cover the rest of
+// the token's life, and AssetIssues walks every token page by page:
+//
+//	for asset, err := range tron.AssetIssues(ctx, 100) {
+//	    // ...
+//	}
+//
 // # Resource Management
 //
 // Delegate and reclaim resources:
@@ -317,19 +475,45 @@
 //	})
 //	tx, err = tron.ClaimRewards(ctx, "TOwnerAddress")
 //
+// Apply as a witness, then set its URL and the brokerage it keeps:
+//
+//	tx, err := tron.CreateWitness(ctx, "TOwnerAddress", "https://sr.example.com")
+//	tx, err = tron.UpdateBrokerage(ctx, "TOwnerAddress", 20)
+//
+// Witnesses change network parameters through proposals. CreateProposal
+// checks every key and value against the registry client.ProposalParams
+// returns; the reads decode each proposal's state, approvals and named
+// parameters into a client.Proposal:
+//
+//	tx, err := tron.CreateProposal(ctx, "TWitnessAddress", map[int64]int64{11: 210})
+//	tx, err = tron.ApproveProposal(ctx, "TWitnessAddress", 92, true)
+//	p, err := tron.GetProposalById(ctx, 92)
+//
+// Exchanges trade TRX, written client.ExchangeTRX, against TRC10 tokens.
+// QuoteExchange prices a sale with the chain's Bancor formula before the
+// trade is built, and MinExpected turns the quote into the trade's floor:
+//
+//	quote, err := tron.QuoteExchange(ctx, 5, client.ExchangeTRX, 10_000_000)
+//	tx, err := tron.TradeExchange(ctx, client.ExchangeTradeRequest{
+//		Owner: "TOwnerAddress", ExchangeID: 5, SellTokenID: client.ExchangeTRX,
+//		Quant: 10_000_000, Expected: quote.MinExpected(0.01),
+//	})
+//
 // # Package Organization
 //
 // The SDK is organized into several packages:
 //
 //   - gotron: High-level wrapper and convenience functions
 //   - pkg/client: Core client with gRPC and HTTP transport implementations
+//   - pkg/client/clienttest: Simulated in-memory chain and mock node server for testing
 //   - pkg/address: Address generation, validation, and key management
 //   - pkg/tronutils: Utility functions for encoding, formatting, etc.
 //   - schema/pb: Protocol buffer definitions from Tron protocol
 //
 // The client package uses a Transport interface pattern. The default
 // transport stack is HealthAwareTransport (tier-based fallback + per-node
-// health checking) wrapped by an optional MetricsTransport. Setting
+// health checking) wrapped by the optional metrics, coalescing, caching,
+// middleware and tracing layers. Setting
 // Config.Health.Disabled = true falls back to a plain RoundRobinTransport.
 //
 // # Error Handling
@@ -349,7 +533,12 @@
 //   - client.ErrInvalidAmount
 //   - client.ErrTransactionNotFound
 //   - client.ErrInvalidConfig
+//   - client.ErrInsufficientBalance
+//   - client.ErrProposalNotFound
 //   - client.ErrNoHealthyNodes
+//   - client.ErrNoSolidityNodes
+//   - client.ErrUnsupportedBySolidity
+//   - client.ErrUnsupportedByJSONRPC
 //
 // # Prometheus Metrics
 //
@@ -375,12 +564,22 @@
 //   - gotron_rpc_duration_seconds: Histogram with labels blockchain, method
 //   - gotron_rpc_in_flight: Gauge for current active requests
 //   - gotron_rpc_retries_total: Counter with labels blockchain, method
+//   - gotron_rpc_hedges_total: Counter with labels blockchain, method, result
+//   - gotron_rpc_node_lag_blocks: Gauge with labels blockchain, node
+//   - gotron_rpc_node_requests_total: Counter with labels blockchain, node, method, status, error_class
+//   - gotron_rpc_node_duration_seconds: Histogram with labels blockchain, node
+//   - gotron_rpc_probe_duration_seconds: Histogram with labels blockchain, node, status
+//   - gotron_rpc_node_transitions_total: Counter with labels blockchain, node, to
+//   - gotron_rpc_tier_total, gotron_rpc_tier_healthy, gotron_rpc_tier_active: Gauges with labels blockchain, tier
 //   - gotron_rpc_pool_total: Gauge with label blockchain
 //   - gotron_rpc_pool_healthy: Gauge with label blockchain
 //   - gotron_rpc_pool_disabled: Gauge with label blockchain
 //
-// The pool_* gauges are kept up to date by HealthAwareTransport on every
-// node state transition (and once at construction).
+// The pool_* and tier_* gauges are kept up to date by HealthAwareTransport on
+// every node state transition (and once at construction). The node label is
+// NodeConfig.Alias, or the address when the node has no alias; error_class
+// says whether a failed attempt was a network failure, a rate limit, a
+// validation error or a rejected broadcast.
 //
 // Custom MetricsCollector:
 //
@@ -389,12 +588,86 @@
 //	func (m *myMetrics) RecordRetry(blockchain, method string) { ... }
 //	func (m *myMetrics) SetPoolHealth(blockchain string, total, healthy, disabled int) { ... }
 //
+// A collector that also implements NodeMetricsRecorder, TierHealthRecorder or
+// the other *Recorder interfaces receives those events too; one that does not
+// keeps working unchanged.
+//
 //	cfg := client.Config{
 //	    Nodes:      nodes,
 //	    Blockchain: "tron",
 //	    Metrics:    &myMetrics{},
 //	}
 //
+// # Tracing
+//
+// With Config.Tracing.Provider set, every call opens an OpenTelemetry span,
+// "gotron.<Method>", and every request sent to a node a client span under it
+// that names the node, its protocol and tier, and the status it answered
+// with. Errors, rejected broadcasts and refused transactions mark the spans
+// as failed. The trace context goes to the node in the gRPC metadata or HTTP
+// headers of the request.
+//
+// # Custom Transports
+//
+// NewWithTransport runs a Client over a Transport of the caller's own - a
+// proxy, a fixture, a fake - wrapped in the same health checking, metrics,
+// cache, middleware and tracing layers as a node. RegisterProtocol adds a
+// NodeConfig.Protocol, so that custom transports can be configured as nodes,
+// in tiers:
+//
+//	client.RegisterProtocol("proxy", func(node client.NodeConfig) (client.Transport, error) {
+//	    return newProxyTransport(node.Address)
+//	})
+//
+// # Middleware
+//
+// Config.Middlewares runs functions around every Transport call, the first
+// one outermost. A client.Middleware gets the method name, the request as a
+// single proto message - *api.EmptyMessage, *api.NumberMessage,
+// *api.BytesMessage or *api.BlockLimit for methods without a proto argument -
+// and the next step of the call, which it may skip:
+//
+//	audit := func(ctx context.Context, method string, request proto.Message, next client.Invoker) (proto.Message, error) {
+//	    res, err := next(ctx, request)
+//	    log.Printf("%s: err=%v", method, err)
+//	    return res, err
+//	}
+//	cfg := client.Config{Nodes: nodes, Middlewares: []client.Middleware{audit}}
+//
+// # Testing
+//
+// clienttest.Chain is an in-memory chain that implements Transport. It
+// verifies signatures against account permissions and applies transfers,
+// staking, delegations and scripted TRC20 calls, so wallet flows can be
+// tested offline:
+//
+//	chain := clienttest.NewChain()
+//	alice := chain.NewAccount(100_000_000)
+//	tron, err := client.NewWithTransport(chain, client.Config{})
+//	tx, err := tron.CreateTransferTransaction(ctx, alice.Address, to, 1_000_000)
+//	err = tron.SignTransaction(tx.GetTransaction(), alice.PrivateKey)
+//	_, err = tron.BroadcastTransaction(ctx, tx.GetTransaction())
+//	chain.NextBlock()
+//
+// clienttest.Server serves a chain over real gRPC and HTTP sockets, so the
+// transports themselves are under test, and injects faults - latency, error
+// statuses, dropped connections, a lagging head - to exercise failover:
+//
+//	srv, err := clienttest.NewServer(chain, clienttest.ServerConfig{})
+//	defer srv.Close()
+//	tron, err := client.New(client.Config{Nodes: []client.NodeConfig{srv.NodeConfig(client.ProtocolGRPC)}})
+//	srv.Inject(clienttest.Fault{Status: http.StatusServiceUnavailable, Times: 1})
+//
+// To replay real node answers instead, record them once with
+// RecordingTransport into a Cassette, save it, and serve it back with
+// ReplayTransport:
+//
+//	cassette, err := client.LoadCassette("testdata/account.json")
+//	tron, err := client.NewWithTransport(client.NewReplayTransport(cassette), cfg)
+//
+// A call the cassette has no recording of fails with ErrNotRecorded; a
+// recorded failure replays with its gRPC status code or *HTTPStatusError.
+//
 // # Advanced Usage
 //
 // Generate multiple addresses from a single mnemonic:
@@ -1,8 +1,14 @@
 package client
 
 import (
+	"cmp"
 	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
 
+	"github.com/sxwebdev/gotron/pkg/tronutils"
 	"github.com/sxwebdev/gotron/schema/pb/api"
 	"github.com/sxwebdev/gotron/schema/pb/core"
 )
@@ -16,3 +22,143 @@ func (c *Client) GetAssetIssueById(ctx context.Context, id string) (*core.AssetI
 func (c *Client) GetAssetIssueListByName(ctx context.Context, name string) (*api.AssetIssueList, error) {
 	return c.transport.GetAssetIssueListByName(ctx, []byte(name))
 }
+
+// CreateTransferAssetTransaction creates a TRC10 transfer transaction.
+//
+// assetID is the token's numeric id, e.g. "1002000": since AllowSameTokenName
+// two tokens may share a name, so the chain no longer takes one. The amount is
+// in the token's minimal units; build it with units.FromTokenDecimal and the
+// precision GetAssetIssueById reports. The chain keeps TRC10 balances in an
+// int64, so a larger amount is rejected. A transfer the node refuses to build
+// - an unknown token, a short balance - fails with a *ContractValidateError.
+func (c *Client) CreateTransferAssetTransaction(ctx context.Context, from, to, assetID string, amount TokenAmount) (*api.TransactionExtention, error) {
+	if from == "" {
+		return nil, fmt.Errorf("%w: from address is required", ErrInvalidAddress)
+	}
+
+	if to == "" {
+		return nil, fmt.Errorf("%w: to address is required", ErrInvalidAddress)
+	}
+
+	if err := validateAssetID(assetID); err != nil {
+		return nil, err
+	}
+
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("%w: amount must be greater than zero", ErrInvalidAmount)
+	}
+
+	units := amount.TokenUnits()
+	if !units.IsInt64() {
+		return nil, fmt.Errorf("%w: a TRC10 amount must fit in an int64", ErrInvalidAmount)
+	}
+
+	var err error
+	contract := &core.TransferAssetContract{
+		AssetName: []byte(assetID),
+		Amount:    units.Int64(),
+	}
+	if contract.OwnerAddress, err = tronutils.DecodeCheck(from); err != nil {
+		return nil, err
+	}
+
+	if contract.ToAddress, err = tronutils.DecodeCheck(to); err != nil {
+		return nil, err
+	}
+
+	tx, err := c.transport.TransferAsset(ctx, contract)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// GetTRC10Balances returns every TRC10 token addr holds, ordered by id, with
+// each token's metadata and its precision applied. Tokens whose balance has
+// gone to zero are left out, although the account keeps them on record.
+//
+// It costs one GetAssetIssueById per token on top of the account lookup.
+func (c *Client) GetTRC10Balances(ctx context.Context, addr string) ([]TRC10Balance, error) {
+	account, err := c.GetAccount(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(account.GetAssetV2()))
+	for id, units := range account.GetAssetV2() {
+		if units > 0 {
+			ids = append(ids, id)
+		}
+	}
+	// Ids are decimal numbers, so the shorter one is the smaller.
+	slices.SortFunc(ids, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(a), len(b)), cmp.Compare(a, b))
+	})
+
+	balances := make([]TRC10Balance, 0, len(ids))
+	for _, id := range ids {
+		balance, err := c.trc10Balance(ctx, id, account.GetAssetV2()[id])
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, balance)
+	}
+
+	return balances, nil
+}
+
+// GetTRC10Balance returns how much of the TRC10 token assetID addr holds, with
+// the token's metadata and its precision applied. An account that does not
+// hold the token has a zero balance.
+func (c *Client) GetTRC10Balance(ctx context.Context, addr, assetID string) (TRC10Balance, error) {
+	if err := validateAssetID(assetID); err != nil {
+		return TRC10Balance{}, err
+	}
+
+	account, err := c.GetAccount(ctx, addr)
+	if err != nil {
+		return TRC10Balance{}, err
+	}
+
+	return c.trc10Balance(ctx, assetID, account.GetAssetV2()[assetID])
+}
+
+// trc10Balance reads the metadata of the token id and applies it to units.
+func (c *Client) trc10Balance(ctx context.Context, id string, units int64) (TRC10Balance, error) {
+	asset, err := c.transport.GetAssetIssueById(ctx, []byte(id))
+	if err != nil {
+		return TRC10Balance{}, fmt.Errorf("get asset %s: %w", id, err)
+	}
+
+	amount, err := FromTokenUnits(big.NewInt(units))
+	if err != nil {
+		return TRC10Balance{}, fmt.Errorf("asset %s: %w", id, err)
+	}
+
+	return TRC10Balance{
+		ID:        id,
+		Name:      string(asset.GetName()),
+		Abbr:      string(asset.GetAbbr()),
+		Precision: asset.GetPrecision(),
+		Amount:    amount,
+		Balance:   amount.Decimal(asset.GetPrecision()),
+	}, nil
+}
+
+// validateAssetID checks that id is a TRC10 token id rather than a name.
+func validateAssetID(id string) error {
+	if id == "" {
+		return fmt.Errorf("%w: asset id is required", ErrInvalidParams)
+	}
+
+	if n, err := strconv.ParseInt(id, 10, 64); err != nil || n <= 0 {
+		return fmt.Errorf("%w: asset id %q is not a token id", ErrInvalidParams, id)
+	}
+
+	return nil
+}
@@ -0,0 +1,496 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/sxwebdev/gotron/pkg/tronutils"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+)
+
+// The bounds java-tron's asset actuators check. They are fixed in the node's
+// code rather than chain parameters, so nothing is fetched to apply them.
+const (
+	// MaxAssetNameLength is the longest a TRC10 name or abbreviation may be.
+	MaxAssetNameLength = 32
+	// MaxAssetDescriptionLength is the longest a TRC10 description may be.
+	MaxAssetDescriptionLength = 200
+	// MaxAssetURLLength is the longest a TRC10 url may be.
+	MaxAssetURLLength = 256
+	// MaxAssetPrecision is the most decimals a TRC10 token may have.
+	MaxAssetPrecision = 6
+	// MaxFrozenSupplyEntries is how many frozen supply tranches an issue may
+	// have.
+	MaxFrozenSupplyEntries = 10
+	// MaxFrozenSupplyDays is the longest a tranche may stay frozen; the
+	// shortest is a day.
+	MaxFrozenSupplyDays = 3652
+	// MaxAssetNetLimit bounds the free bandwidth an asset grants: both limits
+	// must stay below it.
+	MaxAssetNetLimit = 57_600_000_000
+	// MaxAssetIssuePage is the most assets GetPaginatedAssetIssueList returns
+	// in one page.
+	MaxAssetIssuePage = 1000
+)
+
+// FrozenSupply is a tranche of a new token's supply that stays with the issuer
+// but frozen until Days after the sale starts; UnfreezeAsset releases it then.
+type FrozenSupply struct {
+	// Amount is in the token's minimal units.
+	Amount TokenAmount
+	// Days is how long the tranche stays frozen, from 1 to MaxFrozenSupplyDays.
+	Days int64
+}
+
+// AssetIssueRequest describes a TRC10 token to issue: its name and metadata,
+// its supply and the tranches of it kept frozen, the window and price of its
+// sale, and the bandwidth it lets holders spend on transfers of it.
+//
+// The supply that is not frozen goes to the issuer, who sells it between
+// StartTime and EndTime at Num tokens for every TRXNum SUN through
+// ParticipateAssetIssue - or simply transfers it.
+type AssetIssueRequest struct {
+	// Owner is the issuing account in base58check form. An account issues at
+	// most one token, and pays the getAssetIssueFee chain parameter to do so.
+	Owner string
+	// Name is 1 to 32 visible ASCII characters and not "trx". Since
+	// AllowSameTokenName it need not be unique: the chain assigns the token
+	// a numeric id.
+	Name string
+	// Abbr is the token's symbol, optional, with the same rules as Name.
+	Abbr string
+	// Description is up to 200 bytes.
+	Description string
+	// URL is required, up to 256 bytes.
+	URL string
+	// Precision is the number of decimals, 0 to 6.
+	Precision int32
+	// TotalSupply is in the token's minimal units and must fit in an int64.
+	TotalSupply TokenAmount
+	// TRXNum and Num set the sale price: Num tokens, in minimal units, for
+	// every TRXNum SUN. Both must be positive and fit in an int32.
+	TRXNum SUN
+	Num    TokenAmount
+	// StartTime and EndTime bound the sale. The node also requires StartTime
+	// to be after its head block, which Validate cannot check.
+	StartTime time.Time
+	EndTime   time.Time
+	// FrozenSupply is up to 10 tranches, which together may not exceed
+	// TotalSupply.
+	FrozenSupply []FrozenSupply
+	// FreeAssetNetLimit is the bandwidth each holder may spend on transfers of
+	// the token at the issuer's expense, and PublicFreeAssetNetLimit the total
+	// over all holders. Both are per day and below MaxAssetNetLimit.
+	FreeAssetNetLimit       int64
+	PublicFreeAssetNetLimit int64
+}
+
+// Validate reports whether the request is one the chain would accept, without
+// spending a round-trip to find out. It cannot check the fee or whether the
+// owner already issued a token; CreateAssetIssue does.
+func (r AssetIssueRequest) Validate() error {
+	_, err := r.build()
+	return err
+}
+
+// build validates the request and turns it into the contract the transport
+// takes, in one pass as DeployContractRequest.build does.
+func (r AssetIssueRequest) build() (*core.AssetIssueContract, error) {
+	owner, err := decodeOwner(r.Owner)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateAssetName("name", r.Name); err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(r.Name, "trx") {
+		return nil, fmt.Errorf("%w: an asset cannot be named trx", ErrInvalidParams)
+	}
+	if r.Abbr != "" {
+		if err := validateAssetName("abbr", r.Abbr); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateAssetInfo(r.Description, r.URL, r.FreeAssetNetLimit, r.PublicFreeAssetNetLimit); err != nil {
+		return nil, err
+	}
+	if r.Precision < 0 || r.Precision > MaxAssetPrecision {
+		return nil, fmt.Errorf("%w: precision must be between 0 and %d", ErrInvalidParams, MaxAssetPrecision)
+	}
+
+	if !r.TotalSupply.IsPositive() || !r.TotalSupply.TokenUnits().IsInt64() {
+		return nil, fmt.Errorf("%w: total supply must be greater than zero and fit in an int64", ErrInvalidAmount)
+	}
+	total := r.TotalSupply.TokenUnits().Int64()
+
+	num := r.Num.TokenUnits()
+	if r.TRXNum <= 0 || r.TRXNum.Int64() > math.MaxInt32 {
+		return nil, fmt.Errorf("%w: trx num must be greater than zero and fit in an int32", ErrInvalidAmount)
+	}
+	if !r.Num.IsPositive() || !num.IsInt64() || num.Int64() > math.MaxInt32 {
+		return nil, fmt.Errorf("%w: num must be greater than zero and fit in an int32", ErrInvalidAmount)
+	}
+
+	if r.StartTime.IsZero() || r.EndTime.IsZero() {
+		return nil, fmt.Errorf("%w: start and end time are required", ErrInvalidParams)
+	}
+	if !r.EndTime.After(r.StartTime) {
+		return nil, fmt.Errorf("%w: end time must be after start time", ErrInvalidParams)
+	}
+
+	if len(r.FrozenSupply) > MaxFrozenSupplyEntries {
+		return nil, fmt.Errorf("%w: at most %d frozen supply entries", ErrInvalidParams, MaxFrozenSupplyEntries)
+	}
+	frozen := make([]*core.AssetIssueContract_FrozenSupply, 0, len(r.FrozenSupply))
+	remain := total
+	for i, f := range r.FrozenSupply {
+		if !f.Amount.IsPositive() || !f.Amount.TokenUnits().IsInt64() {
+			return nil, fmt.Errorf("%w: frozen supply %d must be greater than zero and fit in an int64", ErrInvalidAmount, i)
+		}
+		if f.Days < 1 || f.Days > MaxFrozenSupplyDays {
+			return nil, fmt.Errorf("%w: frozen supply %d must stay frozen between 1 and %d days", ErrInvalidParams, i, MaxFrozenSupplyDays)
+		}
+		amount := f.Amount.TokenUnits().Int64()
+		if amount > remain {
+			return nil, fmt.Errorf("%w: frozen supply exceeds the total supply", ErrInvalidAmount)
+		}
+		remain -= amount
+		frozen = append(frozen, &core.AssetIssueContract_FrozenSupply{FrozenAmount: amount, FrozenDays: f.Days})
+	}
+
+	return &core.AssetIssueContract{
+		OwnerAddress:            owner,
+		Name:                    []byte(r.Name),
+		Abbr:                    []byte(r.Abbr),
+		TotalSupply:             total,
+		FrozenSupply:            frozen,
+		TrxNum:                  int32(r.TRXNum),
+		Num:                     int32(num.Int64()),
+		Precision:               r.Precision,
+		StartTime:               r.StartTime.UnixMilli(),
+		EndTime:                 r.EndTime.UnixMilli(),
+		Description:             []byte(r.Description),
+		Url:                     []byte(r.URL),
+		FreeAssetNetLimit:       r.FreeAssetNetLimit,
+		PublicFreeAssetNetLimit: r.PublicFreeAssetNetLimit,
+	}, nil
+}
+
+// AssetIssueFee returns what issuing a TRC10 token costs, the getAssetIssueFee
+// chain parameter.
+func (c *Client) AssetIssueFee(ctx context.Context) (SUN, error) {
+	param, err := c.ChainParam(ctx, "getAssetIssueFee")
+	if err != nil {
+		return 0, err
+	}
+
+	return SUN(param.GetValue()), nil
+}
+
+// CreateAssetIssue builds a transaction issuing a TRC10 token. The transaction
+// is unsigned and is not broadcast.
+//
+// Before asking the node it checks what a request cannot: that the owner
+// exists, has not issued a token yet and holds the issue fee, which is
+// returned as ErrInsufficientBalance when it does not. The token's id is only
+// known once the transaction is confirmed; read it from GetAssetIssueByAccount.
+func (c *Client) CreateAssetIssue(ctx context.Context, req AssetIssueRequest) (*api.TransactionExtention, error) {
+	ct, err := req.build()
+	if err != nil {
+		return nil, err
+	}
+
+	fee, err := c.AssetIssueFee(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get asset issue fee: %w", err)
+	}
+
+	owner, err := c.GetAccount(ctx, req.Owner)
+	if err != nil {
+		return nil, err
+	}
+	if len(owner.GetAssetIssued_ID()) > 0 {
+		return nil, fmt.Errorf("%w: %s already issued asset %s", ErrInvalidParams, req.Owner, owner.GetAssetIssued_ID())
+	}
+	if owner.GetBalance() < fee.Int64() {
+		return nil, fmt.Errorf("%w: issuing costs %s, %s holds %s", ErrInsufficientBalance, fee, req.Owner, SUN(owner.GetBalance()))
+	}
+
+	tx, err := c.transport.CreateAssetIssue(ctx, ct)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// ParticipateAssetIssueRequest buys a TRC10 token from its issuer during the
+// sale, at the price the issue set.
+type ParticipateAssetIssueRequest struct {
+	// Owner is the buying account in base58check form.
+	Owner string
+	// Issuer is the account that issued the token.
+	Issuer string
+	// AssetID is the token's numeric id.
+	AssetID string
+	// Amount is the TRX paid; the tokens received are Amount * Num / TRXNum,
+	// rounded down.
+	Amount SUN
+}
+
+// Validate reports whether the request is one the chain would accept, short
+// of the sale window and balances.
+func (r ParticipateAssetIssueRequest) Validate() error {
+	_, err := r.build()
+	return err
+}
+
+func (r ParticipateAssetIssueRequest) build() (*core.ParticipateAssetIssueContract, error) {
+	owner, err := decodeOwner(r.Owner)
+	if err != nil {
+		return nil, err
+	}
+	if r.Issuer == "" {
+		return nil, fmt.Errorf("%w: issuer address is required", ErrEmptyAddress)
+	}
+	issuer, err := tronutils.DecodeCheck(r.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("%w: issuer address: %s", ErrInvalidAddress, err)
+	}
+	if string(owner) == string(issuer) {
+		return nil, fmt.Errorf("%w: cannot participate in your own asset issue", ErrInvalidParams)
+	}
+	if err := validateAssetID(r.AssetID); err != nil {
+		return nil, err
+	}
+	if r.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be greater than zero", ErrInvalidAmount)
+	}
+
+	return &core.ParticipateAssetIssueContract{
+		OwnerAddress: owner,
+		ToAddress:    issuer,
+		AssetName:    []byte(r.AssetID),
+		Amount:       r.Amount.Int64(),
+	}, nil
+}
+
+// ParticipateAssetIssue builds a transaction buying a TRC10 token from its
+// issuer. The transaction is unsigned and is not broadcast.
+func (c *Client) ParticipateAssetIssue(ctx context.Context, req ParticipateAssetIssueRequest) (*api.TransactionExtention, error) {
+	ct, err := req.build()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := c.transport.ParticipateAssetIssue(ctx, ct)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// UpdateAssetRequest changes the mutable fields of the token Owner issued.
+// Every field is replaced: an empty Description clears it and a zero limit
+// turns free bandwidth off, so carry over what should stay from
+// GetAssetIssueByAccount.
+type UpdateAssetRequest struct {
+	// Owner is the issuing account in base58check form.
+	Owner string
+	// Description is up to 200 bytes.
+	Description string
+	// URL is required, up to 256 bytes.
+	URL string
+	// FreeAssetNetLimit and PublicFreeAssetNetLimit are as in
+	// AssetIssueRequest.
+	FreeAssetNetLimit       int64
+	PublicFreeAssetNetLimit int64
+}
+
+// Validate reports whether the request is one the chain would accept, short
+// of whether Owner issued a token.
+func (r UpdateAssetRequest) Validate() error {
+	_, err := r.build()
+	return err
+}
+
+func (r UpdateAssetRequest) build() (*core.UpdateAssetContract, error) {
+	owner, err := decodeOwner(r.Owner)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateAssetInfo(r.Description, r.URL, r.FreeAssetNetLimit, r.PublicFreeAssetNetLimit); err != nil {
+		return nil, err
+	}
+
+	return &core.UpdateAssetContract{
+		OwnerAddress:   owner,
+		Description:    []byte(r.Description),
+		Url:            []byte(r.URL),
+		NewLimit:       r.FreeAssetNetLimit,
+		NewPublicLimit: r.PublicFreeAssetNetLimit,
+	}, nil
+}
+
+// UpdateAsset builds a transaction updating the token Owner issued. The
+// transaction is unsigned and is not broadcast.
+func (c *Client) UpdateAsset(ctx context.Context, req UpdateAssetRequest) (*api.TransactionExtention, error) {
+	ct, err := req.build()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := c.transport.UpdateAsset(ctx, ct)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// UnfreezeAsset builds a transaction releasing the frozen supply of owner's
+// token whose time has come. The node refuses it while every tranche is still
+// frozen. The transaction is unsigned and is not broadcast.
+func (c *Client) UnfreezeAsset(ctx context.Context, owner string) (*api.TransactionExtention, error) {
+	addr, err := decodeOwner(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := c.transport.UnfreezeAsset(ctx, &core.UnfreezeAssetContract{OwnerAddress: addr})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// GetAssetIssueByAccount returns the TRC10 tokens addr issued - at most one
+// since AllowSameTokenName.
+func (c *Client) GetAssetIssueByAccount(ctx context.Context, addr string) (*api.AssetIssueList, error) {
+	if addr == "" {
+		return nil, ErrEmptyAddress
+	}
+
+	address, err := tronutils.DecodeCheck(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.transport.GetAssetIssueByAccount(ctx, address)
+}
+
+// GetAssetIssueList returns every TRC10 token on the chain. There are tens of
+// thousands on mainnet; prefer GetPaginatedAssetIssueList or AssetIssues.
+func (c *Client) GetAssetIssueList(ctx context.Context) (*api.AssetIssueList, error) {
+	return c.transport.GetAssetIssueList(ctx)
+}
+
+// GetPaginatedAssetIssueList returns up to limit TRC10 tokens starting at
+// offset, ordered by name. limit is 1 to MaxAssetIssuePage.
+func (c *Client) GetPaginatedAssetIssueList(ctx context.Context, offset, limit int64) (*api.AssetIssueList, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: offset cannot be negative", ErrInvalidParams)
+	}
+	if limit < 1 || limit > MaxAssetIssuePage {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidParams, MaxAssetIssuePage)
+	}
+
+	return c.transport.GetPaginatedAssetIssueList(ctx, GetPaginatedMessage(offset, limit))
+}
+
+// AssetIssues walks every TRC10 token, pageSize at a time, and stops after the
+// first error it yields. Tokens issued while it runs can shift the pages, so a
+// token may be seen twice or missed.
+func (c *Client) AssetIssues(ctx context.Context, pageSize int64) iter.Seq2[*core.AssetIssueContract, error] {
+	return func(yield func(*core.AssetIssueContract, error) bool) {
+		for offset := int64(0); ; offset += pageSize {
+			page, err := c.GetPaginatedAssetIssueList(ctx, offset, pageSize)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, asset := range page.GetAssetIssue() {
+				if !yield(asset, nil) {
+					return
+				}
+			}
+
+			if int64(len(page.GetAssetIssue())) < pageSize {
+				return
+			}
+		}
+	}
+}
+
+// decodeOwner decodes the required owner address of an asset request.
+func decodeOwner(addr string) ([]byte, error) {
+	if addr == "" {
+		return nil, fmt.Errorf("%w: owner address is required", ErrEmptyAddress)
+	}
+
+	owner, err := tronutils.DecodeCheck(addr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: owner address: %s", ErrInvalidAddress, err)
+	}
+
+	return owner, nil
+}
+
+// validateAssetName checks a name or abbreviation: 1 to 32 visible ASCII
+// characters, as java-tron's TransactionUtil.validAssetName.
+func validateAssetName(field, name string) error {
+	if name == "" || len(name) > MaxAssetNameLength {
+		return fmt.Errorf("%w: %s must be 1 to %d characters", ErrInvalidParams, field, MaxAssetNameLength)
+	}
+
+	for i := range len(name) {
+		if name[i] < 0x21 || name[i] > 0x7e {
+			return fmt.Errorf("%w: %s may only hold visible ASCII characters", ErrInvalidParams, field)
+		}
+	}
+
+	return nil
+}
+
+// validateAssetInfo checks the fields an issue and an update share.
+func validateAssetInfo(description, url string, limit, publicLimit int64) error {
+	if len(description) > MaxAssetDescriptionLength {
+		return fmt.Errorf("%w: description is longer than %d bytes", ErrInvalidParams, MaxAssetDescriptionLength)
+	}
+	if url == "" || len(url) > MaxAssetURLLength {
+		return fmt.Errorf("%w: url must be 1 to %d bytes", ErrInvalidParams, MaxAssetURLLength)
+	}
+	if limit < 0 || limit >= MaxAssetNetLimit {
+		return fmt.Errorf("%w: free asset net limit must be at least 0 and below %d", ErrInvalidParams, int64(MaxAssetNetLimit))
+	}
+	if publicLimit < 0 || publicLimit >= MaxAssetNetLimit {
+		return fmt.Errorf("%w: public free asset net limit must be at least 0 and below %d", ErrInvalidParams, int64(MaxAssetNetLimit))
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+)
+
+func validAssetIssue(t *testing.T) AssetIssueRequest {
+	start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	return AssetIssueRequest{
+		Owner:        testAddr,
+		Name:         "Loyalty",
+		Abbr:         "LOY",
+		Description:  "Partner points",
+		URL:          "https://example.com",
+		Precision:    2,
+		TotalSupply:  tokenUnits(t, big.NewInt(1_000_000)),
+		TRXNum:       1,
+		Num:          tokenUnits(t, big.NewInt(10)),
+		StartTime:    start,
+		EndTime:      start.Add(24 * time.Hour),
+		FrozenSupply: []FrozenSupply{{Amount: tokenUnits(t, big.NewInt(100_000)), Days: 30}},
+	}
+}
+
+func TestAssetIssueRequestValidate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validAssetIssue(t).Validate())
+
+	cases := []struct {
+		name    string
+		mutate  func(r *AssetIssueRequest)
+		wantErr error
+	}{
+		{"no owner", func(r *AssetIssueRequest) { r.Owner = "" }, ErrEmptyAddress},
+		{"bad owner", func(r *AssetIssueRequest) { r.Owner = "T123" }, ErrInvalidAddress},
+		{"empty name", func(r *AssetIssueRequest) { r.Name = "" }, ErrInvalidParams},
+		{"long name", func(r *AssetIssueRequest) { r.Name = strings.Repeat("a", 33) }, ErrInvalidParams},
+		{"name with a space", func(r *AssetIssueRequest) { r.Name = "Loyalty Points" }, ErrInvalidParams},
+		{"named trx", func(r *AssetIssueRequest) { r.Name = "TRX" }, ErrInvalidParams},
+		{"bad abbr", func(r *AssetIssueRequest) { r.Abbr = "LÖY" }, ErrInvalidParams},
+		{"no url", func(r *AssetIssueRequest) { r.URL = "" }, ErrInvalidParams},
+		{"long description", func(r *AssetIssueRequest) { r.Description = strings.Repeat("a", 201) }, ErrInvalidParams},
+		{"precision", func(r *AssetIssueRequest) { r.Precision = 7 }, ErrInvalidParams},
+		{"zero supply", func(r *AssetIssueRequest) { r.TotalSupply = TokenAmount{} }, ErrInvalidAmount},
+		{"zero trx num", func(r *AssetIssueRequest) { r.TRXNum = 0 }, ErrInvalidAmount},
+		{"num beyond int32", func(r *AssetIssueRequest) { r.Num = tokenUnits(t, big.NewInt(1<<31)) }, ErrInvalidAmount},
+		{"no start", func(r *AssetIssueRequest) { r.StartTime = time.Time{} }, ErrInvalidParams},
+		{"end before start", func(r *AssetIssueRequest) { r.EndTime = r.StartTime }, ErrInvalidParams},
+		{"negative net limit", func(r *AssetIssueRequest) { r.FreeAssetNetLimit = -1 }, ErrInvalidParams},
+		{"public net limit", func(r *AssetIssueRequest) { r.PublicFreeAssetNetLimit = MaxAssetNetLimit }, ErrInvalidParams},
+		{"frozen for no days", func(r *AssetIssueRequest) { r.FrozenSupply[0].Days = 0 }, ErrInvalidParams},
+		{"frozen for too long", func(r *AssetIssueRequest) { r.FrozenSupply[0].Days = MaxFrozenSupplyDays + 1 }, ErrInvalidParams},
+		{"frozen beyond supply", func(r *AssetIssueRequest) {
+			r.FrozenSupply = append(r.FrozenSupply, FrozenSupply{Amount: tokenUnits(t, big.NewInt(900_001)), Days: 1})
+		}, ErrInvalidAmount},
+		{"too many tranches", func(r *AssetIssueRequest) {
+			for range MaxFrozenSupplyEntries {
+				r.FrozenSupply = append(r.FrozenSupply, FrozenSupply{Amount: tokenUnits(t, big.NewInt(1)), Days: 1})
+			}
+		}, ErrInvalidParams},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := validAssetIssue(t)
+			tc.mutate(&req)
+			require.ErrorIs(t, req.Validate(), tc.wantErr)
+		})
+	}
+}
+
+func TestAssetIssueRequestBuildsContract(t *testing.T) {
+	ct, err := validAssetIssue(t).build()
+	require.NoError(t, err)
+
+	require.Equal(t, mustDecode(t, testAddr), ct.GetOwnerAddress())
+	require.Equal(t, "Loyalty", string(ct.GetName()))
+	require.Equal(t, int64(1_000_000), ct.GetTotalSupply())
+	require.Equal(t, int32(1), ct.GetTrxNum())
+	require.Equal(t, int32(10), ct.GetNum())
+	require.Equal(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli(), ct.GetStartTime())
+	require.Len(t, ct.GetFrozenSupply(), 1)
+	require.Equal(t, int64(100_000), ct.GetFrozenSupply()[0].GetFrozenAmount())
+	require.Equal(t, int64(30), ct.GetFrozenSupply()[0].GetFrozenDays())
+}
+
+func TestCreateAssetIssueChecksOwner(t *testing.T) {
+	params := &core.ChainParameters{ChainParameter: []*core.ChainParameters_ChainParameter{
+		{Key: "getAssetIssueFee", Value: 1_024_000_000},
+	}}
+
+	cases := []struct {
+		name    string
+		account *core.Account
+		wantErr error
+	}{
+		{"balance below the fee", &core.Account{Balance: 1_023_999_999}, ErrInsufficientBalance},
+		{"already issued", &core.Account{Balance: 2_000_000_000, AssetIssued_ID: []byte("1000001")}, ErrInvalidParams},
+		{"enough", &core.Account{Balance: 1_024_000_000}, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			issued := false
+			c := newTestClient(&fakeTransport{
+				getChainParameters: func(context.Context) (*core.ChainParameters, error) { return params, nil },
+				getAccount: func(_ context.Context, a *core.Account) (*core.Account, error) {
+					tc.account.Address = a.GetAddress()
+					return tc.account, nil
+				},
+				createAssetIssue: func(context.Context, *core.AssetIssueContract) (*api.TransactionExtention, error) {
+					issued = true
+					return okTx(), nil
+				},
+			})
+
+			_, err := c.CreateAssetIssue(t.Context(), validAssetIssue(t))
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				require.False(t, issued, "a request the owner cannot pay for is not sent")
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, issued)
+		})
+	}
+}
+
+func TestAssetRequestsValidate(t *testing.T) {
+	t.Parallel()
+
+	participate := ParticipateAssetIssueRequest{Owner: testAddr, Issuer: testAddr2, AssetID: "1000001", Amount: 1_000_000}
+	require.NoError(t, participate.Validate())
+
+	self := participate
+	self.Issuer = testAddr
+	require.ErrorIs(t, self.Validate(), ErrInvalidParams)
+
+	byName := participate
+	byName.AssetID = "Loyalty"
+	require.ErrorIs(t, byName.Validate(), ErrInvalidParams)
+
+	free := participate
+	free.Amount = 0
+	require.ErrorIs(t, free.Validate(), ErrInvalidAmount)
+
+	update := UpdateAssetRequest{Owner: testAddr, URL: "https://example.com", FreeAssetNetLimit: 5000}
+	require.NoError(t, update.Validate())
+
+	update.URL = ""
+	require.ErrorIs(t, update.Validate(), ErrInvalidParams)
+}
+
+func TestAssetIssuesPages(t *testing.T) {
+	var pages []*api.PaginatedMessage
+	c := newTestClient(&fakeTransport{
+		getPaginatedAssetIssueList: func(_ context.Context, m *api.PaginatedMessage) (*api.AssetIssueList, error) {
+			pages = append(pages, m)
+			list := &api.AssetIssueList{}
+			for i := m.GetOffset(); i < min(m.GetOffset()+m.GetLimit(), 5); i++ {
+				list.AssetIssue = append(list.AssetIssue, &core.AssetIssueContract{Id: strconv.FormatInt(1_000_001+i, 10)})
+			}
+			return list, nil
+		},
+	})
+
+	var ids []string
+	for asset, err := range c.AssetIssues(t.Context(), 2) {
+		require.NoError(t, err)
+		ids = append(ids, asset.GetId())
+	}
+	require.Equal(t, []string{"1000001", "1000002", "1000003", "1000004", "1000005"}, ids)
+	require.Len(t, pages, 3, "a short page is the last")
+
+	for _, err := range c.AssetIssues(t.Context(), MaxAssetIssuePage+1) {
+		require.ErrorIs(t, err, ErrInvalidParams)
+	}
+
+	boom := errors.New("boom")
+	failing := newTestClient(&fakeTransport{
+		getPaginatedAssetIssueList: func(context.Context, *api.PaginatedMessage) (*api.AssetIssueList, error) {
+			return nil, boom
+		},
+	})
+	var seen int
+	for _, err := range failing.AssetIssues(t.Context(), 10) {
+		require.ErrorIs(t, err, boom)
+		seen++
+	}
+	require.Equal(t, 1, seen)
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+)
+
+func tokenUnits(t *testing.T, units *big.Int) TokenAmount {
+	t.Helper()
+	amount, err := FromTokenUnits(units)
+	require.NoError(t, err)
+	return amount
+}
+
+func TestCreateTransferAssetTransactionValidation(t *testing.T) {
+	t.Parallel()
+
+	one := tokenUnits(t, big.NewInt(1))
+	overflow := tokenUnits(t, new(big.Int).Lsh(big.NewInt(1), 63))
+
+	cases := []struct {
+		name     string
+		from, to string
+		id       string
+		amount   TokenAmount
+		wantErr  error
+	}{
+		{"empty from", "", testAddr2, "1002000", one, ErrInvalidAddress},
+		{"empty to", testAddr, "", "1002000", one, ErrInvalidAddress},
+		{"empty id", testAddr, testAddr2, "", one, ErrInvalidParams},
+		{"name for an id", testAddr, testAddr2, "BitTorrent", one, ErrInvalidParams},
+		{"zero amount", testAddr, testAddr2, "1002000", TokenAmount{}, ErrInvalidAmount},
+		{"beyond int64", testAddr, testAddr2, "1002000", overflow, ErrInvalidAmount},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := newTestClient(&fakeTransport{})
+			_, err := c.CreateTransferAssetTransaction(t.Context(), tc.from, tc.to, tc.id, tc.amount)
+			require.ErrorIs(t, err, tc.wantErr)
+
+			_, err = c.EstimateTRC10Transfer(t.Context(), tc.from, tc.to, tc.id, tc.amount)
+			require.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestCreateTransferAssetTransactionBuildsContract(t *testing.T) {
+	var got *core.TransferAssetContract
+	c := newTestClient(&fakeTransport{
+		transferAsset: func(_ context.Context, ct *core.TransferAssetContract) (*api.TransactionExtention, error) {
+			got = ct
+			return okTx(), nil
+		},
+	})
+
+	_, err := c.CreateTransferAssetTransaction(t.Context(), testAddr, testAddr2, "1002000", tokenUnits(t, big.NewInt(1_500_000)))
+	require.NoError(t, err)
+	require.Equal(t, mustDecode(t, testAddr), got.GetOwnerAddress())
+	require.Equal(t, mustDecode(t, testAddr2), got.GetToAddress())
+	require.Equal(t, []byte("1002000"), got.GetAssetName())
+	require.Equal(t, int64(1_500_000), got.GetAmount())
+}
+
+func TestCreateTransferAssetTransactionRefused(t *testing.T) {
+	c := newTestClient(&fakeTransport{
+		transferAsset: func(context.Context, *core.TransferAssetContract) (*api.TransactionExtention, error) {
+			return &api.TransactionExtention{Result: &api.Return{
+				Code:    api.Return_CONTRACT_VALIDATE_ERROR,
+				Message: []byte("assetBalance is not sufficient."),
+			}}, nil
+		},
+	})
+
+	_, err := c.CreateTransferAssetTransaction(t.Context(), testAddr, testAddr2, "1002000", tokenUnits(t, big.NewInt(1)))
+	cve, ok := errors.AsType[*ContractValidateError](err)
+	require.True(t, ok, "got %v", err)
+	require.Equal(t, api.Return_CONTRACT_VALIDATE_ERROR, cve.Code)
+	require.Equal(t, "assetBalance is not sufficient.", cve.Message)
+}
+
+func TestGetTRC10Balances(t *testing.T) {
+	owner := mustDecode(t, testAddr)
+	issues := map[string]*core.AssetIssueContract{
+		"1002000": {Id: "1002000", Name: []byte("BitTorrent"), Abbr: []byte("BTT"), Precision: 6},
+		"1000001": {Id: "1000001", Name: []byte("SEED"), Abbr: []byte("SEED")},
+	}
+	var looked []string
+	c := newTestClient(&fakeTransport{
+		getAccount: func(context.Context, *core.Account) (*core.Account, error) {
+			return &core.Account{Address: owner, AssetV2: map[string]int64{
+				"1002000": 1_500_000,
+				"1000001": 42,
+				"1000010": 0,
+			}}, nil
+		},
+		getAssetIssueById: func(_ context.Context, id []byte) (*core.AssetIssueContract, error) {
+			looked = append(looked, string(id))
+			return issues[string(id)], nil
+		},
+	})
+
+	balances, err := c.GetTRC10Balances(t.Context(), testAddr)
+	require.NoError(t, err)
+	require.Len(t, balances, 2, "a zero balance is left out")
+	require.Equal(t, []string{"1000001", "1002000"}, looked, "a token left out is not looked up")
+
+	require.Equal(t, "1000001", balances[0].ID)
+	require.Equal(t, "42", balances[0].Amount.String())
+	require.True(t, balances[0].Balance.Equal(decimal.NewFromInt(42)))
+
+	require.Equal(t, "BitTorrent", balances[1].Name)
+	require.Equal(t, "BTT", balances[1].Abbr)
+	require.Equal(t, int32(6), balances[1].Precision)
+	require.Equal(t, "1500000", balances[1].Amount.String())
+	require.True(t, balances[1].Balance.Equal(decimal.RequireFromString("1.5")))
+
+	single, err := c.GetTRC10Balance(t.Context(), testAddr, "1000010")
+	require.NoError(t, err)
+	require.True(t, single.Amount.IsZero())
+	require.True(t, single.Balance.IsZero())
+}
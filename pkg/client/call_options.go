@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// CallOption adjusts how a single call is routed. Attach options to the
+// context of the call with WithCallOptions; they work the same over gRPC and
+// HTTP nodes.
+type CallOption func(*callOptions)
+
+// callOptions is the resolved set of CallOptions carried by a context.
+type callOptions struct {
+	solidified bool
+	node       string
+	tier       int
+	hasTier    bool
+	timeout    time.Duration
+	energyUsed bool
+}
+
+type callOptionsKey struct{}
+
+// WithCallOptions returns a copy of ctx that carries opts to every Client call
+// made with it. Options add to those already in ctx; a later option of the
+// same kind replaces an earlier one.
+//
+//	ctx := client.WithCallOptions(ctx, client.CallSolidified(), client.CallTimeout(2*time.Second))
+//	balance, err := tron.GetAccountBalance(ctx, addr)
+func WithCallOptions(ctx context.Context, opts ...CallOption) context.Context {
+	o := callOptionsFrom(ctx)
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return context.WithValue(ctx, callOptionsKey{}, o)
+}
+
+// CallSolidified makes the call read solidified - irreversible - state from
+// the solidity nodes, as Client.Confirmed does. The call fails with
+// ErrNoSolidityNodes when none are configured, and with
+// ErrUnsupportedBySolidity when solidity nodes do not serve it.
+func CallSolidified() CallOption {
+	return func(o *callOptions) { o.solidified = true }
+}
+
+// CallNode pins the call to the node whose NodeConfig.Address is address,
+// for example to read a transaction back from the node it was broadcast to.
+// The node is used even when the health-checker marks it unhealthy, the call
+// is neither retried nor hedged elsewhere, and it fails with ErrNodeNotFound
+// when no such node is configured.
+func CallNode(address string) CallOption {
+	return func(o *callOptions) { o.node = address }
+}
+
+// CallTier restricts the call to the nodes of the given NodeConfig.Tier,
+// instead of the lowest tier that has a healthy node. The call fails with
+// ErrNodeNotFound when no node has that tier, and with ErrNoHealthyNodes when
+// none of them is healthy.
+func CallTier(tier int) CallOption {
+	return func(o *callOptions) {
+		o.tier = tier
+		o.hasTier = true
+	}
+}
+
+// CallTimeout bounds the call, retries and backoff included, to d. A deadline
+// already on the context still applies when it is earlier.
+func CallTimeout(d time.Duration) CallOption {
+	return func(o *callOptions) { o.timeout = d }
+}
+
+// CallEnergyUsed asks for the EnergyUsed of a TriggerConstantContract from a
+// JSONRPCTransport node, which has to send eth_estimateGas after eth_call to
+// learn it. gRPC and HTTP nodes report it with every call; there the option
+// changes nothing.
+func CallEnergyUsed() CallOption {
+	return func(o *callOptions) { o.energyUsed = true }
+}
+
+// callOptionsFrom returns the options carried by ctx; the zero value when
+// there are none.
+func callOptionsFrom(ctx context.Context) callOptions {
+	o, _ := ctx.Value(callOptionsKey{}).(callOptions)
+	return o
+}
+
+// withTimeout applies CallTimeout to ctx. The returned cancel func must be
+// called once the call is done.
+func (o callOptions) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, o.timeout)
+}
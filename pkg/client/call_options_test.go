@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/grpc/codes"
+)
+
+func TestWithCallOptionsAccumulates(t *testing.T) {
+	ctx := WithCallOptions(t.Context(), CallSolidified(), CallNode("a"))
+	ctx = WithCallOptions(ctx, CallNode("b"), CallTier(2), CallTimeout(time.Second))
+
+	assert.Equal(t, callOptions{
+		solidified: true,
+		node:       "b",
+		tier:       2,
+		hasTier:    true,
+		timeout:    time.Second,
+	}, callOptionsFrom(ctx))
+	assert.Equal(t, callOptions{}, callOptionsFrom(t.Context()))
+}
+
+func TestHealthAware_CallNodePinsEvenUnhealthyNode(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0, 1}, quietProbes(HealthConfig{FailureThreshold: 1}))
+		h.nodes[0].setNextErr(grpcErr(codes.Unavailable))
+		_, _ = h.transport.GetAccount(bgctx(), &core.Account{})
+		require.False(t, h.nodeHealthy(0))
+		h.nodes[0].setNextErr(nil)
+
+		ctx := WithCallOptions(bgctx(), CallNode(h.nodes[0].name))
+		for range 3 {
+			_, err := h.transport.GetAccount(ctx, &core.Account{})
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int64(4), h.nodes[0].liveCallCount.Load())
+
+		ctx = WithCallOptions(bgctx(), CallNode(h.nodes[2].name))
+		_, err := h.transport.GetAccount(ctx, &core.Account{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), h.nodes[2].liveCallCount.Load(), "a fallback node can be pinned")
+	})
+}
+
+func TestHealthAware_CallNodeIsNotRetriedOrHedged(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		cfg := hedgeConfig()
+		cfg.Retry = RetryPolicy{MaxAttempts: 3}
+		h := newHarness(t, []int{0, 0}, cfg)
+		h.nodes[0].setDelay(time.Second)
+		h.nodes[0].setNextErr(grpcErr(codes.Unavailable))
+
+		ctx := WithCallOptions(bgctx(), CallNode(h.nodes[0].name))
+		_, err := h.transport.GetAccount(ctx, &core.Account{})
+		require.Error(t, err)
+
+		assert.Zero(t, h.nodes[1].liveCallCount.Load())
+		assert.Empty(t, h.metrics.hedges)
+		assert.Empty(t, h.metrics.retries)
+	})
+}
+
+func TestHealthAware_CallNodeUnknown(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0}, quietProbes(HealthConfig{}))
+
+		ctx := WithCallOptions(bgctx(), CallNode("nowhere"))
+		_, err := h.transport.GetAccount(ctx, &core.Account{})
+		require.ErrorIs(t, err, ErrNodeNotFound)
+	})
+}
+
+func TestHealthAware_CallTierOverridesPriority(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 1, 1}, quietProbes(HealthConfig{FailureThreshold: 1}))
+
+		ctx := WithCallOptions(bgctx(), CallTier(1))
+		for range 4 {
+			_, err := h.transport.GetAccount(ctx, &core.Account{})
+			require.NoError(t, err)
+		}
+		assert.Zero(t, h.nodes[0].liveCallCount.Load())
+		assert.Equal(t, int64(2), h.nodes[1].liveCallCount.Load())
+		assert.Equal(t, int64(2), h.nodes[2].liveCallCount.Load())
+
+		_, err := h.transport.GetAccount(WithCallOptions(bgctx(), CallTier(7)), &core.Account{})
+		require.ErrorIs(t, err, ErrNodeNotFound)
+
+		// A tier whose nodes are all unhealthy does not fall back elsewhere.
+		h.nodes[1].setNextErr(grpcErr(codes.Unavailable))
+		h.nodes[2].setNextErr(grpcErr(codes.Unavailable))
+		_, _ = h.transport.GetAccount(ctx, &core.Account{})
+		_, _ = h.transport.GetAccount(ctx, &core.Account{})
+		_, err = h.transport.GetAccount(ctx, &core.Account{})
+		require.ErrorIs(t, err, ErrNoHealthyNodes)
+		assert.Zero(t, h.nodes[0].liveCallCount.Load())
+	})
+}
+
+func TestHealthAware_CallTimeout(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0}, quietProbes(HealthConfig{}))
+		h.nodes[0].setDelay(time.Hour)
+
+		start := time.Now()
+		ctx := WithCallOptions(bgctx(), CallTimeout(2*time.Second))
+		_, err := h.transport.GetAccount(ctx, &core.Account{})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 2*time.Second, time.Since(start))
+	})
+}
+
+func newOptionsRoundRobin(t *testing.T) (*RoundRobinTransport, []*controllableTransport) {
+	t.Helper()
+	cts := []*controllableTransport{newCT("a"), newCT("b"), newCT("c")}
+	rr := NewRoundRobinTransport([]Transport{cts[0], cts[1], cts[2]})
+	rr.nodes = []NodeConfig{{Address: "a"}, {Address: "b", Tier: 1}, {Address: "c", Tier: 1}}
+	return rr, cts
+}
+
+func TestRoundRobin_CallNodeAndTier(t *testing.T) {
+	rr, cts := newOptionsRoundRobin(t)
+
+	ctx := WithCallOptions(t.Context(), CallNode("c"))
+	for range 3 {
+		_, err := rr.GetAccount(ctx, &core.Account{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int64(3), cts[2].liveCallCount.Load())
+
+	ctx = WithCallOptions(t.Context(), CallTier(1))
+	for range 4 {
+		_, err := rr.GetAccount(ctx, &core.Account{})
+		require.NoError(t, err)
+	}
+	assert.Zero(t, cts[0].liveCallCount.Load())
+	assert.Equal(t, int64(7), cts[1].liveCallCount.Load()+cts[2].liveCallCount.Load())
+
+	_, err := rr.GetAccount(WithCallOptions(t.Context(), CallNode("x")), &core.Account{})
+	require.ErrorIs(t, err, ErrNodeNotFound)
+	_, err = rr.GetAccount(WithCallOptions(t.Context(), CallTier(5)), &core.Account{})
+	require.ErrorIs(t, err, ErrNodeNotFound)
+}
+
+func TestRoundRobin_CallTimeout(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		rr, cts := newOptionsRoundRobin(t)
+		for _, ct := range cts {
+			ct.setDelay(time.Hour)
+		}
+
+		ctx := WithCallOptions(bgctx(), CallTimeout(time.Second))
+		_, err := rr.GetAccount(ctx, &core.Account{})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestRoundRobin_CallNodeWithoutNodeConfigs(t *testing.T) {
+	rr := NewRoundRobinTransport([]Transport{newCT("a")})
+	_, err := rr.GetAccount(WithCallOptions(t.Context(), CallNode("a")), &core.Account{})
+	require.ErrorIs(t, err, ErrNodeNotFound)
+}
+
+func TestClient_CallSolidifiedRoutesToSolidityPool(t *testing.T) {
+	full, solidity := newCT("full"), newCT("solidity")
+	c := &Client{transport: newSolidifiedRouter(full, solidity)}
+
+	_, err := c.transport.GetAccount(t.Context(), &core.Account{})
+	require.NoError(t, err)
+	_, err = c.transport.GetAccount(WithCallOptions(t.Context(), CallSolidified()), &core.Account{})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1), full.liveCallCount.Load())
+	assert.Equal(t, int64(1), solidity.liveCallCount.Load())
+}
+
+func TestClient_CallSolidifiedWithoutSolidityNodes(t *testing.T) {
+	full := newCT("full")
+	c := &Client{transport: newSolidifiedRouter(full, nil)}
+
+	_, err := c.transport.GetNowBlock(WithCallOptions(t.Context(), CallSolidified()))
+	require.ErrorIs(t, err, ErrNoSolidityNodes)
+	assert.Zero(t, full.liveCallCount.Load(), "never fall back to reversible state")
+}
@@ -14,6 +14,9 @@ type ChainParams struct {
 	FreeNetLimit                        int64
 	CreateNewAccountFeeInSystemContract int64
 	CreateAccountFee                    int64
+	AssetIssueFee                       int64
+	AccountUpgradeCost                  int64
+	ExchangeCreateFee                   int64
 }
 
 // ChainParam get chain parameters
@@ -53,6 +56,12 @@ func (c *Client) ChainParams(ctx context.Context) (*ChainParams, error) {
 			res.CreateAccountFee = item.Value
 		case "getCreateNewAccountFeeInSystemContract":
 			res.CreateNewAccountFeeInSystemContract = item.Value
+		case "getAssetIssueFee":
+			res.AssetIssueFee = item.Value
+		case "getAccountUpgradeCost":
+			res.AccountUpgradeCost = item.Value
+		case "getExchangeCreateFee":
+			res.ExchangeCreateFee = item.Value
 		}
 	}
 
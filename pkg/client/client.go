@@ -9,6 +9,10 @@ import (
 type Client struct {
 	transport Transport
 	config    Config
+
+	// confirmed is the view over the solidity nodes, nil when none are
+	// configured.
+	confirmed *Client
 }
 
 // New creates a new Tron client with the given configuration.
@@ -18,6 +22,9 @@ type Client struct {
 // is used, and a background health-checker tracks every node's status. To
 // restore the legacy plain round-robin (no health-checking, NodeConfig.Tier
 // ignored), set cfg.Health.Disabled = true.
+//
+// Nodes with NodeConfig.Solidity set are kept out of that pool and get one of
+// their own, reached through Confirmed or per call with CallSolidified.
 func New(cfg Config) (*Client, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, err
@@ -28,11 +35,117 @@ func New(cfg Config) (*Client, error) {
 		blockchain = "tron"
 	}
 
+	var full, solidity []NodeConfig
+	for _, nodeCfg := range cfg.Nodes {
+		if nodeCfg.Solidity {
+			solidity = append(solidity, nodeCfg)
+		} else {
+			full = append(full, nodeCfg)
+		}
+	}
+
+	transport, err := newPoolTransport(full, cfg, blockchain, false, createTransportFromNode)
+	if err != nil {
+		return nil, err
+	}
+	router := newSolidifiedRouter(transport, nil)
+	c := &Client{
+		config:    cfg,
+		transport: router,
+	}
+
+	if len(solidity) > 0 {
+		// The solidity pool reports metrics under its own blockchain label,
+		// so that its pool gauges do not overwrite the full nodes' ones.
+		st, err := newPoolTransport(solidity, cfg, blockchain+"-solidity", true, createTransportFromNode)
+		if err != nil {
+			_ = transport.Close()
+			return nil, fmt.Errorf("solidity nodes: %w", err)
+		}
+		router.solidity = st
+		c.confirmed = &Client{config: cfg, transport: st}
+	}
+
+	return c, nil
+}
+
+// customNode describes the Transport given to NewWithTransport in metrics,
+// logs and spans.
+var customNode = NodeConfig{Protocol: "custom", Address: "custom"}
+
+// NewWithTransport creates a Client whose calls all go to transport, a
+// Transport of the caller's own - a proxy, a recorded fixture, a fake. The
+// Client takes the place of its nodes: cfg.Nodes must be empty, and the rest
+// of cfg applies as in New, so health checking, metrics, coalescing, the
+// cache, middlewares and tracing wrap transport as they would a node. The
+// node is labelled "custom" in metrics and spans.
+//
+// The Client owns transport and closes it on Close. It has no solidity pool:
+// Confirmed returns ErrNoSolidityNodes. To run several custom transports in
+// tiers, register a Protocol with RegisterProtocol and use New. Nodes added
+// later with AddNode are created from their Protocol, as New creates them.
+func NewWithTransport(transport Transport, cfg Config) (*Client, error) {
+	if transport == nil {
+		return nil, fmt.Errorf("%w: transport is required", ErrInvalidConfig)
+	}
+	if len(cfg.Nodes) > 0 {
+		return nil, fmt.Errorf("%w: nodes must be empty with a custom transport", ErrInvalidConfig)
+	}
+	if err := cfg.validateLayers(); err != nil {
+		return nil, err
+	}
+
+	blockchain := cfg.Blockchain
+	if blockchain == "" {
+		blockchain = "tron"
+	}
+	// The pool is built with transport as its only node; any node added to
+	// it later is created as New would.
+	built := false
+	pool, err := newPoolTransport([]NodeConfig{customNode}, cfg, blockchain, false, func(nc NodeConfig) (Transport, error) {
+		if !built {
+			built = true
+			return transport, nil
+		}
+		return createTransportFromNode(nc)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Client{config: cfg, transport: newSolidifiedRouter(pool, nil)}, nil
+}
+
+// newPoolTransport builds the transport stack over one pool of nodes: the
+// health-aware (or plain round-robin) transport, wrapped in MetricsTransport
+// when metrics are configured, in CoalescingTransport when cfg.Coalesce is
+// set, in CachingTransport when cfg.Cache is enabled, in the user's
+// cfg.Middlewares and in OTelTransport when cfg.Tracing has a provider, which
+// also traces each node. solidified tells the cache that the pool serves
+// irreversible state; factory creates the transport of each node.
+func newPoolTransport(nodes []NodeConfig, cfg Config, blockchain string, solidified bool, factory TransportFactory) (Transport, error) {
+	rec, observe := cfg.Metrics.(RateLimitRecorder)
+	create := factory
+	if observe || cfg.Tracing.Provider != nil {
+		create = func(nodeCfg NodeConfig) (Transport, error) {
+			t, err := factory(nodeCfg)
+			if err != nil {
+				return nil, err
+			}
+			if observe {
+				observeRateLimitWaits(t, rec, blockchain, nodeCfg.metricsLabel())
+			}
+			if cfg.Tracing.Provider != nil {
+				t = newNodeOTelTransport(t, cfg.Tracing, nodeCfg)
+			}
+			return t, nil
+		}
+	}
+
 	var transport Transport
 	if cfg.Health.Disabled {
-		transports := make([]Transport, 0, len(cfg.Nodes))
-		for i, nodeCfg := range cfg.Nodes {
-			t, err := createTransportFromNode(nodeCfg)
+		transports := make([]Transport, 0, len(nodes))
+		for i, nodeCfg := range nodes {
+			t, err := create(nodeCfg)
 			if err != nil {
 				for _, x := range transports {
 					_ = x.Close()
@@ -41,9 +154,11 @@ func New(cfg Config) (*Client, error) {
 			}
 			transports = append(transports, t)
 		}
-		transport = NewRoundRobinTransport(transports)
+		rr := NewRoundRobinTransport(transports)
+		rr.nodes = nodes
+		transport = rr
 	} else {
-		ht, err := NewHealthAwareTransport(cfg.Nodes, createTransportFromNode, cfg.Health, cfg.Metrics, blockchain)
+		ht, err := NewHealthAwareTransport(nodes, create, cfg.Health, cfg.Metrics, blockchain)
 		if err != nil {
 			return nil, err
 		}
@@ -55,27 +170,35 @@ func New(cfg Config) (*Client, error) {
 		transport = NewMetricsTransport(transport, cfg.Metrics, blockchain)
 	}
 
-	return &Client{
-		config:    cfg,
-		transport: transport,
-	}, nil
-}
+	// Merge identical reads before they reach the metrics, so that only the
+	// calls actually sent are recorded as requests
+	if cfg.Coalesce {
+		transport = NewCoalescingTransport(transport, cfg.Metrics, blockchain)
+	}
 
-// createTransportFromNode creates a Transport from NodeConfig
-func createTransportFromNode(nodeCfg NodeConfig) (Transport, error) {
-	switch nodeCfg.GetProtocol() {
-	case ProtocolGRPC:
-		return NewGRPCTransport(nodeCfg)
+	// Answer from the cache before anything else, so that a cached read is
+	// neither merged nor counted as a request
+	if cfg.Cache.Enabled {
+		transport = newCachingTransport(transport, cfg.Cache, cfg.Metrics, blockchain, solidified)
+	}
 
-	case ProtocolHTTP:
-		return NewHTTPTransport(nodeCfg)
+	// User middlewares see every call as the Client made it, cached ones
+	// included, inside the span of the call
+	if len(cfg.Middlewares) > 0 {
+		transport = NewMiddlewareTransport(transport, cfg.Middlewares...)
+	}
 
-	default:
-		return nil, fmt.Errorf("unsupported protocol: %s", nodeCfg.Protocol)
+	// Trace every call made, cached ones included; the per-node spans show
+	// which of them reached a node
+	if cfg.Tracing.Provider != nil {
+		transport = NewOTelTransport(transport, cfg.Tracing, blockchain)
 	}
+
+	return transport, nil
 }
 
-// Close closes the client connection
+// Close closes the client connection, and the solidity nodes' connections
+// when there are any.
 func (c *Client) Close() error {
 	if c.transport != nil {
 		return c.transport.Close()
@@ -83,6 +206,31 @@ func (c *Client) Close() error {
 	return nil
 }
 
+// Confirmed returns a Client that reads solidified - irreversible - state
+// from the nodes configured with NodeConfig.Solidity. Use it wherever a value
+// must not be reverted later, such as crediting a deposit: a balance or a
+// transaction it reports is final.
+//
+// The returned Client only serves the reads solidity nodes answer; anything
+// else fails with ErrUnsupportedBySolidity. It lives as long as c and is
+// closed by c.Close, so it must not be closed on its own. Confirmed returns
+// ErrNoSolidityNodes when no solidity node is configured, rather than falling
+// back to full nodes whose state may still be reverted.
+func (c *Client) Confirmed() (*Client, error) {
+	if c.confirmed == nil {
+		return nil, ErrNoSolidityNodes
+	}
+	return c.confirmed, nil
+}
+
+// InvalidateCache drops the answers Config.Cache keeps for the given
+// Transport methods, such as "GetChainParameters", or every cached answer
+// when no method is given. It covers the solidity pool as well and does
+// nothing when the cache is disabled.
+func (c *Client) InvalidateCache(methods ...string) {
+	invalidateCaches(c.transport, methods)
+}
+
 // GetNetwork returns the current network based on configuration
 func (c *Client) GetNetwork() Network {
 	return c.config.Network
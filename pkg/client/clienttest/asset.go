@@ -0,0 +1,240 @@
+package clienttest
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// firstAssetID is the id of the first TRC10 token issued; java-tron counts
+	// on from 1000000.
+	firstAssetID = 1_000_001
+	// maxFrozenSupply is getMaxFrozenSupplyNumber.
+	maxFrozenSupply = 10
+	// maxFrozenSupplyDays is getMaxFrozenSupplyTime; the least is a day.
+	maxFrozenSupplyDays = 3652
+	// oneDayNetLimit is getOneDayNetLimit, the cap on an asset's free bandwidth.
+	oneDayNetLimit = 57_600_000_000
+	// maxAssetPage is the most assets a page of GetPaginatedAssetIssueList
+	// holds.
+	maxAssetPage = 1000
+)
+
+// validAssetName reports whether b is a valid asset name or abbreviation: 1 to
+// 32 visible ASCII characters.
+func validAssetName(b []byte) bool {
+	if len(b) == 0 || len(b) > 32 {
+		return false
+	}
+	for _, c := range b {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// checkAssetInfo validates the fields an UpdateAssetContract can change.
+func checkAssetInfo(description, url []byte, limit, publicLimit int64) error {
+	switch {
+	case len(url) == 0 || len(url) > 256:
+		return invalid("Invalid url")
+	case len(description) > 200:
+		return invalid("Invalid description")
+	case limit < 0 || limit >= oneDayNetLimit:
+		return invalid("Invalid FreeAssetNetLimit")
+	case publicLimit < 0 || publicLimit >= oneDayNetLimit:
+		return invalid("Invalid PublicFreeAssetNetLimit")
+	}
+	return nil
+}
+
+// issueAsset validates ct as AssetIssueActuator does and issues the token.
+// The supply not frozen goes to the owner at once.
+func (s *state) issueAsset(ct *core.AssetIssueContract, now int64) (*core.TransactionInfo, error) {
+	owner, err := s.owner(ct.GetOwnerAddress())
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case !validAssetName(ct.GetName()):
+		return nil, invalid("Invalid assetName")
+	case strings.EqualFold(string(ct.GetName()), "trx"):
+		return nil, invalid("assetName can't be trx")
+	case ct.GetPrecision() < 0 || ct.GetPrecision() > 6:
+		return nil, invalid("precision cannot exceed 6")
+	case len(ct.GetAbbr()) > 0 && !validAssetName(ct.GetAbbr()):
+		return nil, invalid("Invalid abbreviation for token")
+	case ct.GetStartTime() == 0:
+		return nil, invalid("Start time should be not empty")
+	case ct.GetEndTime() == 0:
+		return nil, invalid("End time should be not empty")
+	case ct.GetEndTime() <= ct.GetStartTime():
+		return nil, invalid("End time should be greater than start time")
+	case ct.GetStartTime() <= now:
+		return nil, invalid("Start time should be greater than HeadBlockTime")
+	case ct.GetTotalSupply() <= 0:
+		return nil, invalid("TotalSupply must greater than 0!")
+	case ct.GetTrxNum() <= 0:
+		return nil, invalid("TrxNum must greater than 0!")
+	case ct.GetNum() <= 0:
+		return nil, invalid("Num must greater than 0!")
+	case len(ct.GetFrozenSupply()) > maxFrozenSupply:
+		return nil, invalid("Frozen supply list length is too long")
+	case len(owner.GetAssetIssued_ID()) > 0:
+		return nil, invalid("An account can only issue one asset")
+	}
+	if err := checkAssetInfo(ct.GetDescription(), ct.GetUrl(), ct.GetFreeAssetNetLimit(), ct.GetPublicFreeAssetNetLimit()); err != nil {
+		return nil, err
+	}
+	remain := ct.GetTotalSupply()
+	for _, f := range ct.GetFrozenSupply() {
+		switch {
+		case f.GetFrozenAmount() <= 0:
+			return nil, invalid("Frozen supply must be greater than 0!")
+		case f.GetFrozenAmount() > remain:
+			return nil, invalid("Frozen supply cannot exceed total supply")
+		case f.GetFrozenDays() < 1 || f.GetFrozenDays() > maxFrozenSupplyDays:
+			return nil, invalid("frozenDuration must be less than %d days and more than 1 days", maxFrozenSupplyDays)
+		}
+		remain -= f.GetFrozenAmount()
+	}
+
+	id := strconv.Itoa(firstAssetID + len(s.assets))
+	asset := proto.Clone(ct).(*core.AssetIssueContract)
+	asset.Id = id
+	s.assets[id] = asset
+
+	owner.AssetIssuedName = bytes.Clone(ct.GetName())
+	owner.AssetIssued_ID = []byte(id)
+	for _, f := range ct.GetFrozenSupply() {
+		owner.FrozenSupply = append(owner.FrozenSupply, &core.Account_Frozen{
+			FrozenBalance: f.GetFrozenAmount(),
+			ExpireTime:    ct.GetStartTime() + f.GetFrozenDays()*(24*time.Hour).Milliseconds(),
+		})
+	}
+	if owner.AssetV2 == nil {
+		owner.AssetV2 = make(map[string]int64)
+	}
+	owner.AssetV2[id] += remain
+	return &core.TransactionInfo{}, nil
+}
+
+// participateAsset buys a token from its issuer during the sale, at Num
+// tokens for every TrxNum SUN.
+func (s *state) participateAsset(ct *core.ParticipateAssetIssueContract, now int64) (*core.TransactionInfo, error) {
+	owner, err := s.owner(ct.GetOwnerAddress())
+	if err != nil {
+		return nil, err
+	}
+	asset, ok := s.assets[string(ct.GetAssetName())]
+	switch {
+	case ct.GetAmount() <= 0:
+		return nil, invalid("Amount must greater than 0!")
+	case bytes.Equal(ct.GetOwnerAddress(), ct.GetToAddress()):
+		return nil, invalid("Cannot participate asset Issue yourself !")
+	case !ok:
+		return nil, invalid("No asset named %s", ct.GetAssetName())
+	case !bytes.Equal(asset.GetOwnerAddress(), ct.GetToAddress()):
+		return nil, invalid("The asset is not issued by %x", ct.GetToAddress())
+	case now < asset.GetStartTime() || now >= asset.GetEndTime():
+		return nil, invalid("No longer valid period!")
+	case owner.GetBalance() < ct.GetAmount():
+		return nil, invalid("No enough balance !")
+	}
+	exchanged := ct.GetAmount() * int64(asset.GetNum()) / int64(asset.GetTrxNum())
+	issuer, err := s.owner(ct.GetToAddress())
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case exchanged <= 0:
+		return nil, invalid("Can not process the exchange!")
+	case issuer.GetAssetV2()[asset.GetId()] < exchanged:
+		return nil, invalid("Asset balance is not enough !")
+	}
+
+	owner.Balance -= ct.GetAmount()
+	issuer.Balance += ct.GetAmount()
+	issuer.AssetV2[asset.GetId()] -= exchanged
+	if owner.AssetV2 == nil {
+		owner.AssetV2 = make(map[string]int64)
+	}
+	owner.AssetV2[asset.GetId()] += exchanged
+	return &core.TransactionInfo{}, nil
+}
+
+// updateAsset replaces the description, url and free bandwidth limits of the
+// owner's token.
+func (s *state) updateAsset(ct *core.UpdateAssetContract) (*core.TransactionInfo, error) {
+	owner, err := s.owner(ct.GetOwnerAddress())
+	if err != nil {
+		return nil, err
+	}
+	asset, ok := s.assets[string(owner.GetAssetIssued_ID())]
+	if !ok {
+		return nil, invalid("Account has not issued any asset")
+	}
+	if err := checkAssetInfo(ct.GetDescription(), ct.GetUrl(), ct.GetNewLimit(), ct.GetNewPublicLimit()); err != nil {
+		return nil, err
+	}
+	asset.Description = bytes.Clone(ct.GetDescription())
+	asset.Url = bytes.Clone(ct.GetUrl())
+	asset.FreeAssetNetLimit = ct.GetNewLimit()
+	asset.PublicFreeAssetNetLimit = ct.GetNewPublicLimit()
+	return &core.TransactionInfo{}, nil
+}
+
+// unfreezeAsset returns the issuer's frozen supply that has expired.
+func (s *state) unfreezeAsset(ct *core.UnfreezeAssetContract, now int64) (*core.TransactionInfo, error) {
+	owner, err := s.owner(ct.GetOwnerAddress())
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(owner.GetFrozenSupply()) == 0:
+		return nil, invalid("no frozen supply balance")
+	case len(owner.GetAssetIssued_ID()) == 0:
+		return nil, invalid("this account has not issued any asset")
+	}
+	var released int64
+	kept := owner.FrozenSupply[:0]
+	for _, f := range owner.GetFrozenSupply() {
+		if f.GetExpireTime() <= now {
+			released += f.GetFrozenBalance()
+			continue
+		}
+		kept = append(kept, f)
+	}
+	if released == 0 {
+		return nil, invalid("It's not time to unfreeze asset supply")
+	}
+	owner.FrozenSupply = kept
+	if owner.AssetV2 == nil {
+		owner.AssetV2 = make(map[string]int64)
+	}
+	owner.AssetV2[string(owner.GetAssetIssued_ID())] += released
+	return &core.TransactionInfo{}, nil
+}
+
+// assetIssues returns copies of the issued tokens, by name and then by id as
+// java-tron lists them.
+func (s *state) assetIssues(keep func(*core.AssetIssueContract) bool) []*core.AssetIssueContract {
+	var list []*core.AssetIssueContract
+	for _, asset := range s.assets {
+		if keep == nil || keep(asset) {
+			list = append(list, proto.Clone(asset).(*core.AssetIssueContract))
+		}
+	}
+	slices.SortFunc(list, func(a, b *core.AssetIssueContract) int {
+		return cmp.Or(bytes.Compare(a.GetName(), b.GetName()), cmp.Compare(len(a.GetId()), len(b.GetId())), cmp.Compare(a.GetId(), b.GetId()))
+	})
+	return list
+}
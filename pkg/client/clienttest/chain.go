@@ -0,0 +1,370 @@
+// Package clienttest provides a simulated Tron chain for testing code built on
+// package client without a network.
+//
+// A Chain is an in-memory client.Transport. Hand it to client.NewWithTransport
+// and the Client builds, signs and broadcasts transactions against it the way
+// it would against a node:
+//
+//	chain := clienttest.NewChain()
+//	alice := chain.NewAccount(100_000_000)
+//	c, err := client.NewWithTransport(chain, client.Config{})
+//	...
+//	tx, err := c.CreateTransferTransaction(ctx, alice.Address, bob, 1_000_000)
+//	err = c.SignTransaction(tx.GetTransaction(), alice.PrivateKey)
+//	_, err = c.BroadcastTransaction(ctx, tx.GetTransaction())
+//	chain.NextBlock()
+//
+// Before it accepts a transaction the chain checks what a node checks: the
+// reference block, the expiry, duplicates, and the signatures against the
+// owner's permissions. It then applies the contract - TRX and TRC10
+// transfers, account creation and permission updates, Stake 2.0 freezes,
+// unfreezes and delegations, and calls to the TRC20 tokens registered with
+// DeployTRC20. A broadcast transaction changes balances at once and is found
+// by id, with its info, once NextBlock has sealed it into a block.
+//
+// The chain charges no fees and meters no bandwidth or energy: a transaction
+// moves exactly the amounts in its contract. Transport methods it does not
+// simulate return ErrUnsupported.
+package clienttest
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/sxwebdev/gotron/pkg/client"
+	"github.com/sxwebdev/gotron/pkg/tronutils"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const (
+	// BlockInterval is the time between two blocks, as on mainnet.
+	BlockInterval = 3 * time.Second
+	// expirationWindow is how long after the head block a new transaction
+	// expires; java-tron uses the same default.
+	expirationWindow = 60 * time.Second
+	// maxExpiration is the furthest past the head block a node accepts an
+	// expiry.
+	maxExpiration = 24 * time.Hour
+	// maintenanceInterval is getMaintenanceTimeInterval.
+	maintenanceInterval = 6 * time.Hour
+)
+
+// ErrUnsupported is returned by the Transport methods the chain does not
+// simulate.
+var ErrUnsupported = errors.New("not supported by the simulated chain")
+
+// witnessAddress produces every block.
+var witnessAddress = append([]byte{tronutils.TronBytePrefix}, tronutils.Keccak256([]byte("clienttest witness"))[12:]...)
+
+// Account is a key pair created by NewAccount.
+type Account struct {
+	// Address is the base58check address of the key.
+	Address string
+	// PrivateKey signs for the address, with Client.SignTransaction.
+	PrivateKey *ecdsa.PrivateKey
+}
+
+// Chain is a simulated Tron chain. It implements client.Transport and is safe
+// for concurrent use.
+type Chain struct {
+	mu     sync.Mutex
+	state  *state
+	blocks []*api.BlockExtention
+	// pending holds the accepted transactions the next block seals.
+	pending []*entry
+	txs     map[string]*entry
+	// next is the timestamp of the next block.
+	next time.Time
+	// stamp is the timestamp of the last transaction built, kept increasing
+	// so that two identical requests never build the same transaction.
+	stamp   int64
+	genesis time.Time
+	// deployed counts the tokens deployed, to give each its own address.
+	deployed uint64
+}
+
+// entry is an accepted transaction and its outcome.
+type entry struct {
+	tx     *core.Transaction
+	info   *core.TransactionInfo
+	sealed bool
+}
+
+var _ client.Transport = (*Chain)(nil)
+
+// NewChain creates a chain holding only a genesis block, stamped with the
+// current time.
+func NewChain() *Chain {
+	now := time.Now().Truncate(time.Millisecond)
+	c := &Chain{
+		state:   newState(),
+		txs:     make(map[string]*entry),
+		next:    now,
+		genesis: now,
+	}
+	c.seal()
+	return c
+}
+
+// NewAccount creates a key pair and funds its address with balance SUN.
+func (c *Chain) NewAccount(balance client.SUN) Account {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		panic(fmt.Sprintf("clienttest: generate key: %v", err))
+	}
+	account := Account{
+		Address:    tronutils.PubkeyToAddress(key.PublicKey).String(),
+		PrivateKey: key,
+	}
+	c.Fund(account.Address, balance)
+	return account
+}
+
+// Fund credits amount SUN to address out of thin air, creating its account if
+// it has none. It panics on an invalid address, as the other helpers that take
+// one do.
+func (c *Chain) Fund(address string, amount client.SUN) {
+	addr := mustDecode(address)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state.account(addr, c.headTime()).Balance += amount.Int64()
+}
+
+// FundAsset credits amount of the TRC10 token assetID to address, creating its
+// account if it has none.
+func (c *Chain) FundAsset(address, assetID string, amount int64) {
+	addr := mustDecode(address)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	account := c.state.account(addr, c.headTime())
+	if account.AssetV2 == nil {
+		account.AssetV2 = make(map[string]int64)
+	}
+	account.AssetV2[assetID] += amount
+}
+
+// Balance returns the TRX balance of address, zero when it has no account.
+func (c *Chain) Balance(address string) client.SUN {
+	addr := mustDecode(address)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return client.SUN(c.state.accounts[string(addr)].GetBalance())
+}
+
+// AssetBalance returns the balance of the TRC10 token assetID held by
+// address.
+func (c *Chain) AssetBalance(address, assetID string) int64 {
+	addr := mustDecode(address)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state.accounts[string(addr)].GetAssetV2()[assetID]
+}
+
+// NextBlock seals the pending transactions into a new block and returns its
+// number. The block is stamped BlockInterval after the previous one, plus any
+// time added by AdvanceTime since.
+func (c *Chain) NextBlock() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.seal().GetBlockHeader().GetRawData().GetNumber()
+}
+
+// AdvanceTime moves the chain's clock forward by d: the next block is stamped
+// d later than it would have been. Unfreeze and delegation locks expire
+// against block time, so this is how a test gets past them.
+func (c *Chain) AdvanceTime(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.next = c.next.Add(d)
+}
+
+// head returns the latest block.
+func (c *Chain) head() *api.BlockExtention {
+	return c.blocks[len(c.blocks)-1]
+}
+
+// headTime returns the timestamp of the latest block in milliseconds.
+func (c *Chain) headTime() int64 {
+	return c.head().GetBlockHeader().GetRawData().GetTimestamp()
+}
+
+// seal produces the next block from the pending transactions.
+func (c *Chain) seal() *api.BlockExtention {
+	num := int64(len(c.blocks))
+	header := &core.BlockHeaderRaw{
+		Number:         num,
+		Timestamp:      c.next.UnixMilli(),
+		WitnessAddress: witnessAddress,
+		Version:        32,
+	}
+	if num > 0 {
+		header.ParentHash = c.head().GetBlockid()
+	}
+
+	block := &api.BlockExtention{BlockHeader: &core.BlockHeader{RawData: header}}
+	root := sha256.New()
+	for _, e := range c.pending {
+		e.sealed = true
+		e.info.BlockNumber = num
+		e.info.BlockTimeStamp = header.Timestamp
+		block.Transactions = append(block.Transactions, &api.TransactionExtention{
+			Transaction: e.tx,
+			Txid:        e.info.GetId(),
+		})
+		root.Write(e.info.GetId())
+	}
+	c.pending = nil
+	// Not a Merkle root, but it ties the block id to the transactions.
+	header.TxTrieRoot = root.Sum(nil)
+
+	block.Blockid = blockID(header)
+
+	c.blocks = append(c.blocks, block)
+	c.next = c.next.Add(BlockInterval)
+	return block
+}
+
+// build wraps contract in an unsigned transaction that references the head
+// block. A contract the current state would refuse is answered the way a node
+// answers it: a TransactionExtention carrying the reason and no transaction.
+func (c *Chain) build(typ core.Transaction_Contract_ContractType, contract proto.Message) (*api.TransactionExtention, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.state.clone().apply(contract, c.headTime()); err != nil {
+		return refused(err), nil
+	}
+	tx, err := c.transaction(typ, contract)
+	if err != nil {
+		return nil, err
+	}
+	return &api.TransactionExtention{
+		Transaction: tx,
+		Txid:        txID(tx.GetRawData()),
+		Result:      &api.Return{Result: true},
+	}, nil
+}
+
+// transaction wraps contract in an unsigned transaction that references the
+// head block.
+func (c *Chain) transaction(typ core.Transaction_Contract_ContractType, contract proto.Message) (*core.Transaction, error) {
+	parameter, err := anypb.New(contract)
+	if err != nil {
+		return nil, err
+	}
+	head := c.head()
+	c.stamp = max(c.headTime(), c.stamp+1)
+	return &core.Transaction{
+		RawData: &core.TransactionRaw{
+			RefBlockBytes: slices.Clone(head.GetBlockid()[6:8]),
+			RefBlockHash:  slices.Clone(head.GetBlockid()[8:16]),
+			Expiration:    c.headTime() + expirationWindow.Milliseconds(),
+			Timestamp:     c.stamp,
+			Contract: []*core.Transaction_Contract{{
+				Type:      typ,
+				Parameter: parameter,
+			}},
+		},
+	}, nil
+}
+
+// broadcast accepts tx into the pending block, or answers why not.
+func (c *Chain) broadcast(tx *core.Transaction) *api.Return {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	raw := tx.GetRawData()
+	if len(raw.GetContract()) != 1 {
+		return rejected(invalid("a transaction must carry exactly one contract"))
+	}
+	id := txID(raw)
+	if _, ok := c.txs[string(id)]; ok {
+		return rejected(&rejection{code: api.Return_DUP_TRANSACTION_ERROR, message: "dup transaction"})
+	}
+	if !c.references(raw) {
+		return rejected(&rejection{code: api.Return_TAPOS_ERROR, message: "reference block not found"})
+	}
+	if now := c.headTime(); raw.GetExpiration() <= now || raw.GetExpiration() > now+maxExpiration.Milliseconds() {
+		return rejected(&rejection{code: api.Return_TRANSACTION_EXPIRATION_ERROR, message: "transaction expired"})
+	}
+
+	contract := raw.GetContract()[0]
+	message, err := contract.GetParameter().UnmarshalNew()
+	if err != nil {
+		return rejected(invalid("decode contract: %v", err))
+	}
+	s := c.state.clone()
+	if err := s.authorize(contract, message, id, tx.GetSignature()); err != nil {
+		return rejected(err)
+	}
+	info, err := s.apply(message, c.headTime())
+	if err != nil {
+		return rejected(err)
+	}
+	c.state = s
+
+	info.Id = id
+	accepted := proto.Clone(tx).(*core.Transaction)
+	result := core.Transaction_Result_SUCCESS
+	if info.GetReceipt() != nil {
+		result = info.GetReceipt().GetResult()
+	}
+	accepted.Ret = []*core.Transaction_Result{{ContractRet: result}}
+	e := &entry{tx: accepted, info: info}
+	c.pending = append(c.pending, e)
+	c.txs[string(id)] = e
+	return &api.Return{Result: true, Code: api.Return_SUCCESS}
+}
+
+// references reports whether raw names a block of this chain as its
+// reference block.
+func (c *Chain) references(raw *core.TransactionRaw) bool {
+	for _, block := range c.blocks {
+		id := block.GetBlockid()
+		if string(raw.GetRefBlockBytes()) == string(id[6:8]) && string(raw.GetRefBlockHash()) == string(id[8:16]) {
+			return true
+		}
+	}
+	return false
+}
+
+// blockID is the id java-tron gives a block: the hash of its header, with
+// the block number in the first eight bytes.
+func blockID(header *core.BlockHeaderRaw) []byte {
+	raw, err := proto.Marshal(header)
+	if err != nil {
+		panic(fmt.Sprintf("clienttest: marshal block header: %v", err))
+	}
+	id := sha256.Sum256(raw)
+	binary.BigEndian.PutUint64(id[:8], uint64(header.GetNumber()))
+	return id[:]
+}
+
+// txID is the transaction id a node computes: the SHA-256 of the raw data.
+func txID(raw *core.TransactionRaw) []byte {
+	data, err := proto.Marshal(raw)
+	if err != nil {
+		panic(fmt.Sprintf("clienttest: marshal transaction: %v", err))
+	}
+	id := sha256.Sum256(data)
+	return id[:]
+}
+
+// mustDecode decodes a base58check address or panics.
+func mustDecode(address string) []byte {
+	addr, err := tronutils.DecodeCheck(address)
+	if err != nil || len(addr) != tronutils.AddressLength {
+		panic(fmt.Sprintf("clienttest: invalid address %q", address))
+	}
+	return addr
+}
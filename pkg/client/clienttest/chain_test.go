@@ -0,0 +1,290 @@
+package clienttest
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/pkg/client"
+	"github.com/sxwebdev/gotron/pkg/tronutils"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+)
+
+// newClient returns a Client that talks to chain.
+func newClient(t *testing.T, chain *Chain) *client.Client {
+	t.Helper()
+	c, err := client.NewWithTransport(chain, client.Config{
+		Health: client.HealthConfig{Disabled: true},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+// send signs tx with keys and broadcasts it.
+func send(t *testing.T, c *client.Client, tx *api.TransactionExtention, keys ...*ecdsa.PrivateKey) error {
+	t.Helper()
+	for _, key := range keys {
+		require.NoError(t, c.SignTransaction(tx.GetTransaction(), key))
+	}
+	_, err := c.BroadcastTransaction(t.Context(), tx.GetTransaction())
+	return err
+}
+
+// broadcastCode returns the code of a BroadcastError.
+func broadcastCode(t *testing.T, err error) api.ReturnResponseCode {
+	t.Helper()
+	be, ok := errors.AsType[*client.BroadcastError](err)
+	require.True(t, ok, "want a BroadcastError, got %v", err)
+	return be.Code
+}
+
+func TestTransfer(t *testing.T) {
+	chain := NewChain()
+	c := newClient(t, chain)
+	alice := chain.NewAccount(100_000_000)
+	bob := chain.NewAccount(0)
+
+	activated, err := c.IsAccountActivated(t.Context(), bob.Address)
+	require.NoError(t, err)
+	assert.True(t, activated, "NewAccount creates the account")
+
+	tx, err := c.CreateTransferTransaction(t.Context(), alice.Address, bob.Address, 30_000_000)
+	require.NoError(t, err)
+	require.NoError(t, send(t, c, tx, alice.PrivateKey))
+	assert.Equal(t, client.SUN(70_000_000), chain.Balance(alice.Address), "a broadcast applies at once")
+	assert.Equal(t, client.SUN(30_000_000), chain.Balance(bob.Address))
+
+	hash := tronutils.BytesToHexString(tx.GetTxid())
+	_, err = c.GetTransactionInfoByHash(t.Context(), hash)
+	require.ErrorIs(t, err, client.ErrTransactionInfoNotFound, "the transaction is pending")
+
+	num := chain.NextBlock()
+	info, err := c.GetTransactionInfoByHash(t.Context(), hash)
+	require.NoError(t, err)
+	assert.Equal(t, num, info.GetBlockNumber())
+	found, err := c.GetTransactionByHash(t.Context(), hash)
+	require.NoError(t, err)
+	assert.Equal(t, core.Transaction_Result_SUCCESS, found.GetRet()[0].GetContractRet())
+
+	block, err := c.GetBlockByHeight(t.Context(), uint64(num))
+	require.NoError(t, err)
+	require.Len(t, block.GetTransactions(), 1)
+	assert.Equal(t, tx.GetTxid(), block.GetTransactions()[0].GetTxid())
+
+	err = send(t, c, tx)
+	assert.Equal(t, api.Return_DUP_TRANSACTION_ERROR, broadcastCode(t, err))
+
+	carol := chain.NewAccount(0)
+	_, err = c.CreateTransferTransaction(t.Context(), carol.Address, bob.Address, 1)
+	require.Error(t, err, "the node refuses to build a transfer the balance does not cover")
+}
+
+func TestTransferActivatesTheRecipient(t *testing.T) {
+	chain := NewChain()
+	c := newClient(t, chain)
+	alice := chain.NewAccount(10_000_000)
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	fresh := tronutils.PubkeyToAddress(key.PublicKey).String()
+
+	activated, err := c.IsAccountActivated(t.Context(), fresh)
+	require.NoError(t, err)
+	require.False(t, activated)
+
+	tx, err := c.CreateTransferTransaction(t.Context(), alice.Address, fresh, 1_000_000)
+	require.NoError(t, err)
+	require.NoError(t, send(t, c, tx, alice.PrivateKey))
+
+	balance, err := c.GetAccountBalance(t.Context(), fresh)
+	require.NoError(t, err)
+	assert.Equal(t, client.SUN(1_000_000), balance)
+}
+
+func TestBroadcastChecks(t *testing.T) {
+	chain := NewChain()
+	c := newClient(t, chain)
+	alice := chain.NewAccount(100_000_000)
+	mallory := chain.NewAccount(100_000_000)
+
+	build := func() *api.TransactionExtention {
+		tx, err := c.CreateTransferTransaction(t.Context(), alice.Address, mallory.Address, 1_000_000)
+		require.NoError(t, err)
+		return tx
+	}
+
+	assert.Equal(t, api.Return_SIGERROR, broadcastCode(t, send(t, c, build())), "unsigned")
+	assert.Equal(t, api.Return_SIGERROR, broadcastCode(t, send(t, c, build(), mallory.PrivateKey)), "signed by another key")
+
+	tx := build()
+	tx.Transaction.RawData.RefBlockHash = []byte("elsewhere")
+	assert.Equal(t, api.Return_TAPOS_ERROR, broadcastCode(t, send(t, c, tx, alice.PrivateKey)))
+
+	tx = build()
+	chain.AdvanceTime(time.Minute)
+	chain.NextBlock()
+	assert.Equal(t, api.Return_TRANSACTION_EXPIRATION_ERROR, broadcastCode(t, send(t, c, tx, alice.PrivateKey)))
+
+	assert.Equal(t, client.SUN(100_000_000), chain.Balance(alice.Address), "nothing refused was applied")
+}
+
+func TestMultiSignature(t *testing.T) {
+	chain := NewChain()
+	c := newClient(t, chain)
+	wallet := chain.NewAccount(100_000_000)
+	signer1 := chain.NewAccount(0)
+	signer2 := chain.NewAccount(0)
+	operator := chain.NewAccount(0)
+	to := chain.NewAccount(0)
+
+	owner, err := client.NewOwnerPermission("owner", 2,
+		client.PermissionKey{Address: signer1.Address, Weight: 1},
+		client.PermissionKey{Address: signer2.Address, Weight: 1},
+	)
+	require.NoError(t, err)
+	operations, err := client.ContractOperations(core.Transaction_Contract_TransferContract)
+	require.NoError(t, err)
+	active, err := client.NewActivePermission("transfers", 1, operations,
+		client.PermissionKey{Address: operator.Address, Weight: 1},
+	)
+	require.NoError(t, err)
+
+	update, err := c.UpdateAccountPermissions(t.Context(), client.AccountPermissionUpdateRequest{
+		Account: wallet.Address,
+		Owner:   owner,
+		Actives: []*core.Permission{active},
+	})
+	require.NoError(t, err)
+	require.NoError(t, send(t, c, update, wallet.PrivateKey))
+	chain.NextBlock()
+
+	require.NoError(t, c.ValidatePermissionSigner(t.Context(), wallet.Address, operator.Address,
+		client.FirstActivePermissionID, core.Transaction_Contract_TransferContract))
+
+	transfer := func(permissionID int32) *api.TransactionExtention {
+		tx, err := c.CreateTransferTransaction(t.Context(), wallet.Address, to.Address, 1_000_000)
+		require.NoError(t, err)
+		require.NoError(t, client.SetPermissionID(tx, permissionID))
+		return tx
+	}
+
+	err = send(t, c, transfer(client.OwnerPermissionID), wallet.PrivateKey)
+	assert.Equal(t, api.Return_SIGERROR, broadcastCode(t, err), "the address no longer owns itself")
+	err = send(t, c, transfer(client.OwnerPermissionID), signer1.PrivateKey)
+	assert.Equal(t, api.Return_SIGERROR, broadcastCode(t, err), "one signature is below the threshold")
+	require.NoError(t, send(t, c, transfer(client.OwnerPermissionID), signer1.PrivateKey, signer2.PrivateKey))
+	require.NoError(t, send(t, c, transfer(client.FirstActivePermissionID), operator.PrivateKey))
+
+	stake, err := c.Stake(t.Context(), wallet.Address, client.ResourceTypeEnergy, 1_000_000)
+	require.NoError(t, err)
+	require.NoError(t, client.SetPermissionID(stake, client.FirstActivePermissionID))
+	err = send(t, c, stake, operator.PrivateKey)
+	assert.Equal(t, api.Return_SIGERROR, broadcastCode(t, err), "the active permission only allows transfers")
+
+	assert.Equal(t, client.SUN(2_000_000), chain.Balance(to.Address))
+}
+
+func TestStaking(t *testing.T) {
+	chain := NewChain()
+	c := newClient(t, chain)
+	alice := chain.NewAccount(100_000_000)
+	bob := chain.NewAccount(0)
+
+	_, err := c.Stake(t.Context(), alice.Address, client.ResourceTypeEnergy, 200_000_000)
+	var validateErr *client.ContractValidateError
+	require.ErrorAs(t, err, &validateErr, "a stake above the balance is refused when built")
+
+	stake, err := c.Stake(t.Context(), alice.Address, client.ResourceTypeEnergy, 50_000_000)
+	require.NoError(t, err)
+	require.NoError(t, send(t, c, stake, alice.PrivateKey))
+	res, err := c.GetAccountResource(t.Context(), alice.Address)
+	require.NoError(t, err)
+	assert.Equal(t, int64(500), res.GetEnergyLimit(), "10 energy per staked TRX")
+
+	delegate, err := c.DelegateResource(t.Context(), alice.Address, bob.Address, client.ResourceTypeEnergy, 20_000_000, false, 0)
+	require.NoError(t, err)
+	require.NoError(t, send(t, c, delegate, alice.PrivateKey))
+	delegations, err := c.GetDelegatedResourcesV2(t.Context(), alice.Address)
+	require.NoError(t, err)
+	require.Len(t, delegations, 1)
+	assert.Equal(t, bob.Address, delegations[0].To)
+	assert.Equal(t, client.SUN(20_000_000), delegations[0].Energy)
+	res, err = c.GetAccountResource(t.Context(), bob.Address)
+	require.NoError(t, err)
+	assert.Equal(t, int64(200), res.GetEnergyLimit())
+
+	reclaim, err := c.ReclaimResource(t.Context(), alice.Address, bob.Address, client.ResourceTypeEnergy, 20_000_000)
+	require.NoError(t, err)
+	require.NoError(t, send(t, c, reclaim, alice.PrivateKey))
+
+	unstake, err := c.Unstake(t.Context(), alice.Address, client.ResourceTypeEnergy, 10_000_000)
+	require.NoError(t, err)
+	require.NoError(t, send(t, c, unstake, alice.PrivateKey))
+	chain.NextBlock()
+
+	_, err = c.WithdrawUnstaked(t.Context(), alice.Address)
+	require.ErrorAs(t, err, &validateErr, "the unstake has not expired")
+
+	chain.AdvanceTime(unfreezeDelay)
+	chain.NextBlock()
+	withdrawable, err := c.GetWithdrawableUnstaked(t.Context(), alice.Address)
+	require.NoError(t, err)
+	assert.Equal(t, client.SUN(10_000_000), withdrawable)
+
+	withdraw, err := c.WithdrawUnstaked(t.Context(), alice.Address)
+	require.NoError(t, err)
+	require.NoError(t, send(t, c, withdraw, alice.PrivateKey))
+	assert.Equal(t, client.SUN(60_000_000), chain.Balance(alice.Address))
+
+	info, err := c.GetStakeInfo(t.Context(), alice.Address)
+	require.NoError(t, err)
+	assert.Equal(t, client.SUN(40_000_000), info.StakedEnergy)
+	assert.Zero(t, info.UnstakingTotal)
+}
+
+func TestLockedDelegation(t *testing.T) {
+	chain := NewChain()
+	c := newClient(t, chain)
+	alice := chain.NewAccount(10_000_000)
+	bob := chain.NewAccount(0)
+
+	stake, err := c.Stake(t.Context(), alice.Address, client.ResourceTypeBandwidth, 5_000_000)
+	require.NoError(t, err)
+	require.NoError(t, send(t, c, stake, alice.PrivateKey))
+	delegate, err := c.DelegateResource(t.Context(), alice.Address, bob.Address, client.ResourceTypeBandwidth, 5_000_000, true, 100)
+	require.NoError(t, err)
+	require.NoError(t, send(t, c, delegate, alice.PrivateKey))
+	chain.NextBlock()
+
+	_, err = c.ReclaimResource(t.Context(), alice.Address, bob.Address, client.ResourceTypeBandwidth, 5_000_000)
+	var validateErr *client.ContractValidateError
+	require.ErrorAs(t, err, &validateErr, "the delegation is locked for 100 blocks")
+
+	chain.AdvanceTime(100 * BlockInterval)
+	chain.NextBlock()
+	_, err = c.ReclaimResource(t.Context(), alice.Address, bob.Address, client.ResourceTypeBandwidth, 5_000_000)
+	require.NoError(t, err)
+}
+
+func TestFundAsset(t *testing.T) {
+	chain := NewChain()
+	c := newClient(t, chain)
+	alice := chain.NewAccount(0)
+	chain.FundAsset(alice.Address, "1002000", 500)
+
+	account, err := c.GetAccount(t.Context(), alice.Address)
+	require.NoError(t, err)
+	assert.Equal(t, int64(500), account.GetAssetV2()["1002000"])
+	assert.Equal(t, int64(500), chain.AssetBalance(alice.Address, "1002000"))
+}
+
+func TestUnsupported(t *testing.T) {
+	chain := NewChain()
+	_, err := chain.DeployContract(t.Context(), &core.CreateSmartContract{})
+	require.ErrorIs(t, err, ErrUnsupported)
+}
@@ -0,0 +1,246 @@
+package clienttest
+
+import (
+	"bytes"
+	"cmp"
+	"math/big"
+	"slices"
+	"strconv"
+
+	"github.com/sxwebdev/gotron/pkg/client"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxExchangePage is the most exchanges a page of GetPaginatedExchangeList
+// holds.
+const maxExchangePage = 1000
+
+// validExchangeToken reports whether id is TRX or a numeric token id.
+func validExchangeToken(id []byte) bool {
+	if string(id) == client.ExchangeTRX {
+		return true
+	}
+	n, err := strconv.ParseInt(string(id), 10, 64)
+	return err == nil && n > 0
+}
+
+// holding returns how much of token, TRX as "_", account holds.
+func holding(account *core.Account, token []byte) int64 {
+	if string(token) == client.ExchangeTRX {
+		return account.GetBalance()
+	}
+	return account.GetAssetV2()[string(token)]
+}
+
+// credit adds amount of token, which may be negative, to account.
+func credit(account *core.Account, token []byte, amount int64) {
+	if string(token) == client.ExchangeTRX {
+		account.Balance += amount
+		return
+	}
+	if account.AssetV2 == nil {
+		account.AssetV2 = make(map[string]int64)
+	}
+	account.AssetV2[string(token)] += amount
+}
+
+// side returns the balance of token in exchange and the other token with its
+// balance, and false when token is not one of the pair.
+func side(exchange *core.Exchange, token []byte) (balance int64, other []byte, otherBalance int64, ok bool) {
+	switch {
+	case bytes.Equal(token, exchange.GetFirstTokenId()):
+		return exchange.GetFirstTokenBalance(), exchange.GetSecondTokenId(), exchange.GetSecondTokenBalance(), true
+	case bytes.Equal(token, exchange.GetSecondTokenId()):
+		return exchange.GetSecondTokenBalance(), exchange.GetFirstTokenId(), exchange.GetFirstTokenBalance(), true
+	}
+	return 0, nil, 0, false
+}
+
+// setSide sets the balances of token and the other token of exchange.
+func setSide(exchange *core.Exchange, token []byte, balance, otherBalance int64) {
+	if bytes.Equal(token, exchange.GetFirstTokenId()) {
+		exchange.FirstTokenBalance, exchange.SecondTokenBalance = balance, otherBalance
+		return
+	}
+	exchange.SecondTokenBalance, exchange.FirstTokenBalance = balance, otherBalance
+}
+
+// proportion returns balance * quant / of, rounded down, and the fraction of a
+// unit it drops.
+func proportion(balance, quant, of int64) (int64, float64) {
+	q, r := new(big.Int).QuoRem(new(big.Int).Mul(big.NewInt(balance), big.NewInt(quant)), big.NewInt(of), new(big.Int))
+	return q.Int64(), float64(r.Int64()) / float64(of)
+}
+
+// createExchange opens an exchange as ExchangeCreateActuator does, funded from
+// the owner's balances. No create fee is charged.
+func (s *state) createExchange(ct *core.ExchangeCreateContract, now int64) (*core.TransactionInfo, error) {
+	owner, err := s.owner(ct.GetOwnerAddress())
+	if err != nil {
+		return nil, err
+	}
+	first, second := ct.GetFirstTokenId(), ct.GetSecondTokenId()
+	switch {
+	case !validExchangeToken(first):
+		return nil, invalid("first token id is not a valid number")
+	case !validExchangeToken(second):
+		return nil, invalid("second token id is not a valid number")
+	case bytes.Equal(first, second):
+		return nil, invalid("cannot exchange same tokens")
+	case ct.GetFirstTokenBalance() <= 0 || ct.GetSecondTokenBalance() <= 0:
+		return nil, invalid("token balance must greater than zero")
+	case ct.GetFirstTokenBalance() > client.MaxExchangeBalance || ct.GetSecondTokenBalance() > client.MaxExchangeBalance:
+		return nil, invalid("token balance must less than %d", int64(client.MaxExchangeBalance))
+	case holding(owner, first) < ct.GetFirstTokenBalance():
+		return nil, invalid("first token balance is not enough")
+	}
+	credit(owner, first, -ct.GetFirstTokenBalance())
+	if holding(owner, second) < ct.GetSecondTokenBalance() {
+		return nil, invalid("second token balance is not enough")
+	}
+	credit(owner, second, -ct.GetSecondTokenBalance())
+
+	id := int64(len(s.exchanges)) + 1
+	s.exchanges[id] = &core.Exchange{
+		ExchangeId:         id,
+		CreatorAddress:     bytes.Clone(ct.GetOwnerAddress()),
+		CreateTime:         now,
+		FirstTokenId:       bytes.Clone(first),
+		FirstTokenBalance:  ct.GetFirstTokenBalance(),
+		SecondTokenId:      bytes.Clone(second),
+		SecondTokenBalance: ct.GetSecondTokenBalance(),
+	}
+	return &core.TransactionInfo{ExchangeId: id}, nil
+}
+
+// creatorExchange returns the exchange id its creator owner funds or drains.
+func (s *state) creatorExchange(ownerAddress []byte, id int64) (*core.Account, *core.Exchange, error) {
+	owner, err := s.owner(ownerAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	exchange, ok := s.exchanges[id]
+	switch {
+	case !ok:
+		return nil, nil, invalid("Exchange[%d] not exists", id)
+	case !bytes.Equal(exchange.GetCreatorAddress(), ownerAddress):
+		return nil, nil, invalid("account[%x] is not creator", ownerAddress)
+	}
+	return owner, exchange, nil
+}
+
+// injectExchange adds the creator's tokens to an exchange, the other token in
+// proportion so that the price holds.
+func (s *state) injectExchange(ct *core.ExchangeInjectContract) (*core.TransactionInfo, error) {
+	owner, exchange, err := s.creatorExchange(ct.GetOwnerAddress(), ct.GetExchangeId())
+	if err != nil {
+		return nil, err
+	}
+	balance, other, otherBalance, ok := side(exchange, ct.GetTokenId())
+	switch {
+	case !ok:
+		return nil, invalid("token id is not in exchange")
+	case balance == 0 || otherBalance == 0:
+		return nil, invalid("Token balance in exchange is equal with 0,the exchange has been closed")
+	case ct.GetQuant() <= 0:
+		return nil, invalid("injected token quant must greater than zero")
+	}
+	otherQuant, _ := proportion(otherBalance, ct.GetQuant(), balance)
+	switch {
+	case otherQuant <= 0:
+		return nil, invalid("the calculated token quant  must be greater than 0")
+	case balance+ct.GetQuant() > client.MaxExchangeBalance || otherBalance+otherQuant > client.MaxExchangeBalance:
+		return nil, invalid("token balance must less than %d", int64(client.MaxExchangeBalance))
+	case holding(owner, ct.GetTokenId()) < ct.GetQuant():
+		return nil, invalid("token balance is not enough")
+	case holding(owner, other) < otherQuant:
+		return nil, invalid("another token balance is not enough")
+	}
+	credit(owner, ct.GetTokenId(), -ct.GetQuant())
+	credit(owner, other, -otherQuant)
+	setSide(exchange, ct.GetTokenId(), balance+ct.GetQuant(), otherBalance+otherQuant)
+	return &core.TransactionInfo{ExchangeInjectAnotherAmount: otherQuant}, nil
+}
+
+// withdrawExchange returns tokens of an exchange to its creator, the other
+// token in proportion.
+func (s *state) withdrawExchange(ct *core.ExchangeWithdrawContract) (*core.TransactionInfo, error) {
+	owner, exchange, err := s.creatorExchange(ct.GetOwnerAddress(), ct.GetExchangeId())
+	if err != nil {
+		return nil, err
+	}
+	balance, other, otherBalance, ok := side(exchange, ct.GetTokenId())
+	switch {
+	case !ok:
+		return nil, invalid("token is not in exchange")
+	case ct.GetQuant() <= 0:
+		return nil, invalid("withdraw token quant must greater than zero")
+	case balance == 0 || otherBalance == 0:
+		return nil, invalid("Token balance in exchange is equal with 0,the exchange has been closed")
+	case balance < ct.GetQuant():
+		return nil, invalid("exchange balance is not enough")
+	}
+	otherQuant, dropped := proportion(otherBalance, ct.GetQuant(), balance)
+	switch {
+	case otherQuant <= 0:
+		return nil, invalid("withdraw another token quant must greater than zero")
+	case dropped/float64(otherQuant) > 0.0001:
+		// The withdrawal would round away more than a ten-thousandth of what
+		// it returns of the other token.
+		return nil, invalid("Not precise enough")
+	}
+	credit(owner, ct.GetTokenId(), ct.GetQuant())
+	credit(owner, other, otherQuant)
+	setSide(exchange, ct.GetTokenId(), balance-ct.GetQuant(), otherBalance-otherQuant)
+	return &core.TransactionInfo{ExchangeWithdrawAnotherAmount: otherQuant}, nil
+}
+
+// tradeExchange sells the owner's tokens into an exchange at the price
+// client.BancorExchange gives, as ExchangeTransactionActuator does.
+func (s *state) tradeExchange(ct *core.ExchangeTransactionContract) (*core.TransactionInfo, error) {
+	owner, err := s.owner(ct.GetOwnerAddress())
+	if err != nil {
+		return nil, err
+	}
+	exchange, ok := s.exchanges[ct.GetExchangeId()]
+	if !ok {
+		return nil, invalid("Exchange[%d] not exists", ct.GetExchangeId())
+	}
+	balance, other, otherBalance, ok := side(exchange, ct.GetTokenId())
+	switch {
+	case !ok:
+		return nil, invalid("token is not in exchange")
+	case ct.GetQuant() <= 0:
+		return nil, invalid("token quant must greater than zero")
+	case ct.GetExpected() <= 0:
+		return nil, invalid("token expected must greater than zero")
+	case balance == 0 || otherBalance == 0:
+		return nil, invalid("Token balance in exchange is equal with 0,the exchange has been closed")
+	case balance+ct.GetQuant() > client.MaxExchangeBalance:
+		return nil, invalid("token balance must less than %d", int64(client.MaxExchangeBalance))
+	case holding(owner, ct.GetTokenId()) < ct.GetQuant():
+		return nil, invalid("token balance is not enough")
+	}
+	bought := client.BancorExchange(balance, otherBalance, ct.GetQuant())
+	if bought < ct.GetExpected() {
+		return nil, invalid("token required must greater than expected")
+	}
+	credit(owner, ct.GetTokenId(), -ct.GetQuant())
+	credit(owner, other, bought)
+	setSide(exchange, ct.GetTokenId(), balance+ct.GetQuant(), otherBalance-bought)
+	return &core.TransactionInfo{ExchangeReceivedAmount: bought}, nil
+}
+
+// exchangeList returns copies of the exchanges, newest first as java-tron
+// lists them.
+func (s *state) exchangeList() []*core.Exchange {
+	list := make([]*core.Exchange, 0, len(s.exchanges))
+	for _, exchange := range s.exchanges {
+		list = append(list, proto.Clone(exchange).(*core.Exchange))
+	}
+	slices.SortFunc(list, func(a, b *core.Exchange) int {
+		return cmp.Or(cmp.Compare(b.GetCreateTime(), a.GetCreateTime()), cmp.Compare(b.GetExchangeId(), a.GetExchangeId()))
+	})
+	return list
+}
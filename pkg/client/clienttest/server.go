@@ -0,0 +1,339 @@
+package clienttest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sxwebdev/gotron/pkg/client"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Server serves a client.Transport - typically a Chain - as a Tron node
+// listening on real sockets: the Wallet gRPC service and java-tron's /wallet
+// HTTP API, each on a port of 127.0.0.1. Pointing a GRPCTransport or an
+// HTTPTransport at it exercises the whole transport - encoding, headers, TLS,
+// status codes - which a Transport handed straight to NewWithTransport skips.
+//
+// Faults make the node misbehave on purpose: answer late, fail with a status,
+// send a body that is not JSON, drop the connection, or report a head block
+// that lags the chain. With several servers behind one Client, that is enough
+// to watch the health checker evict a node and fail over to the next.
+//
+// The solidity services are not served.
+type Server struct {
+	backend client.Transport
+
+	grpcServer *grpc.Server
+	grpcConns  *connSet
+	grpcAddr   string
+	httpServer *http.Server
+	httpURL    string
+	// clientTLS trusts the server's certificate; nil without TLS.
+	clientTLS *tls.Config
+
+	mu      sync.Mutex
+	faults  []*Fault
+	headLag int64
+	calls   []Call
+}
+
+// ServerConfig configures NewServer.
+type ServerConfig struct {
+	// TLS serves both APIs over TLS, with a self-signed certificate for
+	// 127.0.0.1 and localhost. NodeConfig trusts it.
+	TLS bool
+}
+
+// Fault is a misbehaviour of a Server. Of Disconnect, Status and Malformed
+// the first set applies, after Latency; a Fault with Latency alone delays the
+// call and then answers it.
+type Fault struct {
+	// Methods limits the fault to calls of these Transport methods, e.g.
+	// "GetNowBlock". Empty matches every call.
+	Methods []string
+	// Times is how many calls the fault applies to before it is spent. 0
+	// means until ClearFaults.
+	Times int
+	// Latency delays the answer.
+	Latency time.Duration
+	// Disconnect closes the connection without answering.
+	Disconnect bool
+	// Status fails the call with this HTTP status code. gRPC gets the
+	// matching code: ResourceExhausted for 429, Unavailable for 502 and 503,
+	// DeadlineExceeded for 408 and 504, Internal for 500, and so on.
+	Status int
+	// RetryAfter is sent in a Retry-After header with the Status, over HTTP.
+	RetryAfter time.Duration
+	// Malformed answers with a body that is not JSON. gRPC frames its
+	// messages, so there the call fails with Internal, as a client that
+	// cannot decode an answer reports it.
+	Malformed bool
+}
+
+// Call is a request a Server received.
+type Call struct {
+	// Protocol is client.ProtocolGRPC or client.ProtocolHTTP.
+	Protocol client.Protocol
+	// Method is the Transport method the request serves, e.g. "GetAccount".
+	Method string
+	// Path is the gRPC method or the HTTP path, e.g.
+	// "/protocol.Wallet/GetAccount" or "/wallet/getaccount".
+	Path string
+	// Header holds the HTTP headers or the gRPC metadata, with canonical
+	// keys.
+	Header http.Header
+}
+
+// NewServer starts a Server answering from backend. Close stops it.
+func NewServer(backend client.Transport, cfg ServerConfig) (*Server, error) {
+	s := &Server{backend: backend}
+
+	var serverTLS *tls.Config
+	if cfg.TLS {
+		var err error
+		if serverTLS, s.clientTLS, err = selfSignedTLS(); err != nil {
+			return nil, fmt.Errorf("clienttest: certificate: %w", err)
+		}
+	}
+
+	grpcListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	httpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		_ = grpcListener.Close()
+		return nil, err
+	}
+
+	opts := []grpc.ServerOption{grpc.UnaryInterceptor(s.intercept)}
+	if serverTLS != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(serverTLS)))
+	}
+	s.grpcServer = grpc.NewServer(opts...)
+	api.RegisterWalletServer(s.grpcServer, &walletServer{s: s})
+	s.grpcConns = &connSet{Listener: grpcListener, conns: make(map[string]net.Conn)}
+	s.grpcAddr = grpcListener.Addr().String()
+
+	s.httpServer = &http.Server{
+		Handler:           http.HandlerFunc(s.serveHTTP),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	s.httpURL = "http://" + httpListener.Addr().String()
+	if serverTLS != nil {
+		// Without "h2" in NextProtos the server speaks HTTP/1.1, which a
+		// Disconnect needs to take the connection over.
+		httpListener = tls.NewListener(httpListener, serverTLS)
+		s.httpURL = "https://" + httpListener.Addr().String()
+	}
+
+	go func() { _ = s.grpcServer.Serve(s.grpcConns) }()
+	go func() { _ = s.httpServer.Serve(httpListener) }()
+	return s, nil
+}
+
+// GRPCAddress returns the host:port of the gRPC service.
+func (s *Server) GRPCAddress() string {
+	return s.grpcAddr
+}
+
+// HTTPURL returns the base URL of the HTTP API, e.g. "http://127.0.0.1:41234".
+func (s *Server) HTTPURL() string {
+	return s.httpURL
+}
+
+// NodeConfig returns the configuration of a node reaching the server over
+// protocol, trusting its certificate when it serves TLS.
+func (s *Server) NodeConfig(protocol client.Protocol) client.NodeConfig {
+	cfg := client.NodeConfig{Protocol: protocol, Address: s.grpcAddr}
+	if protocol == client.ProtocolHTTP {
+		cfg.Address = s.httpURL
+	}
+	if s.clientTLS != nil {
+		cfg.UseTLS = protocol != client.ProtocolHTTP
+		cfg.TLSConfig = s.clientTLS.Clone()
+	}
+	return cfg
+}
+
+// Inject adds a fault. Faults apply in the order they were added: a call
+// gets the first one that matches it and is not spent.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f.Methods = slices.Clone(f.Methods)
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes every fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// SetHeadLag makes GetNowBlock answer with the block that many blocks behind
+// the backend's head - a node that has stopped syncing - until it is set back
+// to 0.
+func (s *Server) SetHeadLag(blocks int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.headLag = blocks
+}
+
+// Calls returns the requests the server received, in the order it received
+// them.
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.calls)
+}
+
+// Close stops both services, dropping their connections.
+func (s *Server) Close() error {
+	s.grpcServer.Stop()
+	return s.httpServer.Close()
+}
+
+// begin records a call and returns the fault it gets, nil for none.
+func (s *Server) begin(call Call) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, call)
+	for i, f := range s.faults {
+		if len(f.Methods) > 0 && !slices.Contains(f.Methods, call.Method) {
+			continue
+		}
+		applied := *f
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = slices.Delete(s.faults, i, i+1)
+			}
+		}
+		return &applied
+	}
+	return nil
+}
+
+// nowBlock is GetNowBlock with the head lag applied.
+func (s *Server) nowBlock(ctx context.Context) (*api.BlockExtention, error) {
+	head, err := s.backend.GetNowBlock(ctx)
+	s.mu.Lock()
+	lag := s.headLag
+	s.mu.Unlock()
+	if err != nil || lag <= 0 {
+		return head, err
+	}
+	return s.backend.GetBlockByNum(ctx, max(head.GetBlockHeader().GetRawData().GetNumber()-lag, 0))
+}
+
+// sleep waits d, and reports false if ctx ended first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// connSet is a listener that keeps the connections it accepted, so that a
+// Disconnect can drop the one a gRPC call came in on.
+type connSet struct {
+	net.Listener
+
+	mu    sync.Mutex
+	conns map[string]net.Conn
+}
+
+func (l *connSet) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.conns[conn.RemoteAddr().String()] = conn
+	return &trackedConn{Conn: conn, set: l}, nil
+}
+
+// drop closes the connection from remote.
+func (l *connSet) drop(remote string) {
+	l.mu.Lock()
+	conn := l.conns[remote]
+	delete(l.conns, remote)
+	l.mu.Unlock()
+	if conn != nil {
+		_ = conn.Close()
+	}
+}
+
+type trackedConn struct {
+	net.Conn
+	set *connSet
+}
+
+func (c *trackedConn) Close() error {
+	c.set.mu.Lock()
+	delete(c.set.conns, c.RemoteAddr().String())
+	c.set.mu.Unlock()
+	return c.Conn.Close()
+}
+
+// selfSignedTLS creates a certificate for 127.0.0.1 and localhost and returns
+// the server's TLS configuration and a client's that trusts it.
+func selfSignedTLS() (serverConfig, clientConfig *tls.Config, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "clienttest"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		DNSNames:              []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	serverConfig = &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}},
+		MinVersion:   tls.VersionTLS12,
+	}
+	clientConfig = &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+	return serverConfig, clientConfig, nil
+}
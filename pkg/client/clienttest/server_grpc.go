@@ -0,0 +1,425 @@
+package clienttest
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/sxwebdev/gotron/pkg/client"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// grpcRenamed maps the Wallet RPCs GRPCTransport calls under another name to
+// the Transport method they serve.
+var grpcRenamed = map[string]string{
+	"GetNowBlock2":           "GetNowBlock",
+	"GetBlockByNum2":         "GetBlockByNum",
+	"GetBlockByLimitNext2":   "GetBlockByLimitNext",
+	"GetBlockByLatestNum2":   "GetBlockByLatestNum",
+	"CreateTransaction2":     "CreateTransaction",
+	"CreateAccount2":         "CreateAccount",
+	"VoteWitnessAccount2":    "VoteWitnessAccount",
+	"CreateWitness2":         "CreateWitness",
+	"UpdateWitness2":         "UpdateWitness",
+	"WithdrawBalance2":       "WithdrawBalance",
+	"TransferAsset2":         "TransferAsset",
+	"CreateAssetIssue2":      "CreateAssetIssue",
+	"ParticipateAssetIssue2": "ParticipateAssetIssue",
+	"UpdateAsset2":           "UpdateAsset",
+	"UnfreezeAsset2":         "UnfreezeAsset",
+}
+
+// intercept records a gRPC call, applies its fault and reports the calls the
+// backend does not serve as Unimplemented, as a node does.
+func (s *Server) intercept(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	method := info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]
+	if renamed, ok := grpcRenamed[method]; ok {
+		method = renamed
+	}
+	header := http.Header{}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for key, values := range md {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+
+	if f := s.begin(Call{Protocol: client.ProtocolGRPC, Method: method, Path: info.FullMethod, Header: header}); f != nil {
+		if !sleep(ctx, f.Latency) {
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		switch {
+		case f.Disconnect:
+			if p, ok := peer.FromContext(ctx); ok {
+				s.grpcConns.drop(p.Addr.String())
+			}
+			return nil, status.Error(codes.Unavailable, "connection dropped")
+		case f.Status != 0:
+			return nil, status.Error(grpcCode(f.Status), http.StatusText(f.Status))
+		case f.Malformed:
+			return nil, status.Error(codes.Internal, "grpc: failed to unmarshal the received message")
+		}
+	}
+
+	res, err := handler(ctx, req)
+	if errors.Is(err, ErrUnsupported) {
+		return nil, status.Error(codes.Unimplemented, err.Error())
+	}
+	return res, err
+}
+
+// grpcCode is the gRPC code of an HTTP status, as gRPC gateways map them.
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return codes.Unavailable
+	}
+	if httpStatus >= 500 {
+		return codes.Internal
+	}
+	return codes.Unknown
+}
+
+// walletServer is the Wallet gRPC service of a Server: each RPC GRPCTransport
+// calls is passed on to the backend. The others answer Unimplemented.
+type walletServer struct {
+	api.UnimplementedWalletServer
+	s *Server
+}
+
+// Account operations
+
+func (w *walletServer) GetAccount(ctx context.Context, in *core.Account) (*core.Account, error) {
+	return w.s.backend.GetAccount(ctx, in)
+}
+
+func (w *walletServer) GetAccountResource(ctx context.Context, in *core.Account) (*api.AccountResourceMessage, error) {
+	return w.s.backend.GetAccountResource(ctx, in)
+}
+
+func (w *walletServer) CreateAccount2(ctx context.Context, in *core.AccountCreateContract) (*api.TransactionExtention, error) {
+	return w.s.backend.CreateAccount(ctx, in)
+}
+
+func (w *walletServer) AccountPermissionUpdate(ctx context.Context, in *core.AccountPermissionUpdateContract) (*api.TransactionExtention, error) {
+	return w.s.backend.AccountPermissionUpdate(ctx, in)
+}
+
+// Block operations
+
+func (w *walletServer) GetNowBlock2(ctx context.Context, _ *api.EmptyMessage) (*api.BlockExtention, error) {
+	return w.s.nowBlock(ctx)
+}
+
+func (w *walletServer) GetBlockByNum2(ctx context.Context, in *api.NumberMessage) (*api.BlockExtention, error) {
+	return w.s.backend.GetBlockByNum(ctx, in.GetNum())
+}
+
+func (w *walletServer) GetBlockById(ctx context.Context, in *api.BytesMessage) (*core.Block, error) {
+	return w.s.backend.GetBlockById(ctx, in.GetValue())
+}
+
+func (w *walletServer) GetBlockByLimitNext2(ctx context.Context, in *api.BlockLimit) (*api.BlockListExtention, error) {
+	return w.s.backend.GetBlockByLimitNext(ctx, in.GetStartNum(), in.GetEndNum())
+}
+
+func (w *walletServer) GetBlockByLatestNum2(ctx context.Context, in *api.NumberMessage) (*api.BlockListExtention, error) {
+	return w.s.backend.GetBlockByLatestNum(ctx, in.GetNum())
+}
+
+func (w *walletServer) GetTransactionInfoByBlockNum(ctx context.Context, in *api.NumberMessage) (*api.TransactionInfoList, error) {
+	return w.s.backend.GetTransactionInfoByBlockNum(ctx, in.GetNum())
+}
+
+// Transaction operations
+
+func (w *walletServer) GetTransactionById(ctx context.Context, in *api.BytesMessage) (*core.Transaction, error) {
+	return w.s.backend.GetTransactionById(ctx, in.GetValue())
+}
+
+func (w *walletServer) GetTransactionInfoById(ctx context.Context, in *api.BytesMessage) (*core.TransactionInfo, error) {
+	return w.s.backend.GetTransactionInfoById(ctx, in.GetValue())
+}
+
+func (w *walletServer) BroadcastTransaction(ctx context.Context, in *core.Transaction) (*api.Return, error) {
+	return w.s.backend.BroadcastTransaction(ctx, in)
+}
+
+func (w *walletServer) CreateTransaction2(ctx context.Context, in *core.TransferContract) (*api.TransactionExtention, error) {
+	return w.s.backend.CreateTransaction(ctx, in)
+}
+
+// Contract operations
+
+func (w *walletServer) TriggerContract(ctx context.Context, in *core.TriggerSmartContract) (*api.TransactionExtention, error) {
+	return w.s.backend.TriggerContract(ctx, in)
+}
+
+func (w *walletServer) TriggerConstantContract(ctx context.Context, in *core.TriggerSmartContract) (*api.TransactionExtention, error) {
+	return w.s.backend.TriggerConstantContract(ctx, in)
+}
+
+func (w *walletServer) EstimateEnergy(ctx context.Context, in *core.TriggerSmartContract) (*api.EstimateEnergyMessage, error) {
+	return w.s.backend.EstimateEnergy(ctx, in)
+}
+
+func (w *walletServer) DeployContract(ctx context.Context, in *core.CreateSmartContract) (*api.TransactionExtention, error) {
+	return w.s.backend.DeployContract(ctx, in)
+}
+
+func (w *walletServer) GetContract(ctx context.Context, in *api.BytesMessage) (*core.SmartContract, error) {
+	return w.s.backend.GetContract(ctx, in.GetValue())
+}
+
+func (w *walletServer) UpdateSetting(ctx context.Context, in *core.UpdateSettingContract) (*api.TransactionExtention, error) {
+	return w.s.backend.UpdateSetting(ctx, in)
+}
+
+func (w *walletServer) UpdateEnergyLimit(ctx context.Context, in *core.UpdateEnergyLimitContract) (*api.TransactionExtention, error) {
+	return w.s.backend.UpdateEnergyLimit(ctx, in)
+}
+
+// Resource operations
+
+func (w *walletServer) GetDelegatedResource(ctx context.Context, in *api.DelegatedResourceMessage) (*api.DelegatedResourceList, error) {
+	return w.s.backend.GetDelegatedResource(ctx, in)
+}
+
+func (w *walletServer) GetDelegatedResourceV2(ctx context.Context, in *api.DelegatedResourceMessage) (*api.DelegatedResourceList, error) {
+	return w.s.backend.GetDelegatedResourceV2(ctx, in)
+}
+
+func (w *walletServer) GetDelegatedResourceAccountIndex(ctx context.Context, in *api.BytesMessage) (*core.DelegatedResourceAccountIndex, error) {
+	return w.s.backend.GetDelegatedResourceAccountIndex(ctx, in.GetValue())
+}
+
+func (w *walletServer) GetDelegatedResourceAccountIndexV2(ctx context.Context, in *api.BytesMessage) (*core.DelegatedResourceAccountIndex, error) {
+	return w.s.backend.GetDelegatedResourceAccountIndexV2(ctx, in.GetValue())
+}
+
+func (w *walletServer) GetCanDelegatedMaxSize(ctx context.Context, in *api.CanDelegatedMaxSizeRequestMessage) (*api.CanDelegatedMaxSizeResponseMessage, error) {
+	return w.s.backend.GetCanDelegatedMaxSize(ctx, in)
+}
+
+func (w *walletServer) DelegateResource(ctx context.Context, in *core.DelegateResourceContract) (*api.TransactionExtention, error) {
+	return w.s.backend.DelegateResource(ctx, in)
+}
+
+func (w *walletServer) UnDelegateResource(ctx context.Context, in *core.UnDelegateResourceContract) (*api.TransactionExtention, error) {
+	return w.s.backend.UnDelegateResource(ctx, in)
+}
+
+// Staking operations (Stake 2.0)
+
+func (w *walletServer) FreezeBalanceV2(ctx context.Context, in *core.FreezeBalanceV2Contract) (*api.TransactionExtention, error) {
+	return w.s.backend.FreezeBalanceV2(ctx, in)
+}
+
+func (w *walletServer) UnfreezeBalanceV2(ctx context.Context, in *core.UnfreezeBalanceV2Contract) (*api.TransactionExtention, error) {
+	return w.s.backend.UnfreezeBalanceV2(ctx, in)
+}
+
+func (w *walletServer) WithdrawExpireUnfreeze(ctx context.Context, in *core.WithdrawExpireUnfreezeContract) (*api.TransactionExtention, error) {
+	return w.s.backend.WithdrawExpireUnfreeze(ctx, in)
+}
+
+func (w *walletServer) CancelAllUnfreezeV2(ctx context.Context, in *core.CancelAllUnfreezeV2Contract) (*api.TransactionExtention, error) {
+	return w.s.backend.CancelAllUnfreezeV2(ctx, in)
+}
+
+func (w *walletServer) GetAvailableUnfreezeCount(ctx context.Context, in *api.GetAvailableUnfreezeCountRequestMessage) (*api.GetAvailableUnfreezeCountResponseMessage, error) {
+	return w.s.backend.GetAvailableUnfreezeCount(ctx, in)
+}
+
+func (w *walletServer) GetCanWithdrawUnfreezeAmount(ctx context.Context, in *api.CanWithdrawUnfreezeAmountRequestMessage) (*api.CanWithdrawUnfreezeAmountResponseMessage, error) {
+	return w.s.backend.GetCanWithdrawUnfreezeAmount(ctx, in)
+}
+
+// Witness operations
+
+func (w *walletServer) VoteWitnessAccount2(ctx context.Context, in *core.VoteWitnessContract) (*api.TransactionExtention, error) {
+	return w.s.backend.VoteWitnessAccount(ctx, in)
+}
+
+func (w *walletServer) WithdrawBalance2(ctx context.Context, in *core.WithdrawBalanceContract) (*api.TransactionExtention, error) {
+	return w.s.backend.WithdrawBalance(ctx, in)
+}
+
+func (w *walletServer) ListWitnesses(ctx context.Context, _ *api.EmptyMessage) (*api.WitnessList, error) {
+	return w.s.backend.ListWitnesses(ctx)
+}
+
+func (w *walletServer) GetRewardInfo(ctx context.Context, in *api.BytesMessage) (*api.NumberMessage, error) {
+	return w.s.backend.GetRewardInfo(ctx, in.GetValue())
+}
+
+func (w *walletServer) GetBrokerageInfo(ctx context.Context, in *api.BytesMessage) (*api.NumberMessage, error) {
+	return w.s.backend.GetBrokerageInfo(ctx, in.GetValue())
+}
+
+func (w *walletServer) CreateWitness2(ctx context.Context, in *core.WitnessCreateContract) (*api.TransactionExtention, error) {
+	return w.s.backend.CreateWitness(ctx, in)
+}
+
+func (w *walletServer) UpdateWitness2(ctx context.Context, in *core.WitnessUpdateContract) (*api.TransactionExtention, error) {
+	return w.s.backend.UpdateWitness(ctx, in)
+}
+
+func (w *walletServer) UpdateBrokerage(ctx context.Context, in *core.UpdateBrokerageContract) (*api.TransactionExtention, error) {
+	return w.s.backend.UpdateBrokerage(ctx, in)
+}
+
+func (w *walletServer) GetPaginatedNowWitnessList(ctx context.Context, in *api.PaginatedMessage) (*api.WitnessList, error) {
+	return w.s.backend.GetPaginatedNowWitnessList(ctx, in)
+}
+
+// Asset operations
+
+func (w *walletServer) GetAssetIssueById(ctx context.Context, in *api.BytesMessage) (*core.AssetIssueContract, error) {
+	return w.s.backend.GetAssetIssueById(ctx, in.GetValue())
+}
+
+func (w *walletServer) GetAssetIssueListByName(ctx context.Context, in *api.BytesMessage) (*api.AssetIssueList, error) {
+	return w.s.backend.GetAssetIssueListByName(ctx, in.GetValue())
+}
+
+func (w *walletServer) GetAssetIssueByAccount(ctx context.Context, in *core.Account) (*api.AssetIssueList, error) {
+	return w.s.backend.GetAssetIssueByAccount(ctx, in.GetAddress())
+}
+
+func (w *walletServer) GetAssetIssueList(ctx context.Context, _ *api.EmptyMessage) (*api.AssetIssueList, error) {
+	return w.s.backend.GetAssetIssueList(ctx)
+}
+
+func (w *walletServer) GetPaginatedAssetIssueList(ctx context.Context, in *api.PaginatedMessage) (*api.AssetIssueList, error) {
+	return w.s.backend.GetPaginatedAssetIssueList(ctx, in)
+}
+
+func (w *walletServer) TransferAsset2(ctx context.Context, in *core.TransferAssetContract) (*api.TransactionExtention, error) {
+	return w.s.backend.TransferAsset(ctx, in)
+}
+
+func (w *walletServer) CreateAssetIssue2(ctx context.Context, in *core.AssetIssueContract) (*api.TransactionExtention, error) {
+	return w.s.backend.CreateAssetIssue(ctx, in)
+}
+
+func (w *walletServer) ParticipateAssetIssue2(ctx context.Context, in *core.ParticipateAssetIssueContract) (*api.TransactionExtention, error) {
+	return w.s.backend.ParticipateAssetIssue(ctx, in)
+}
+
+func (w *walletServer) UpdateAsset2(ctx context.Context, in *core.UpdateAssetContract) (*api.TransactionExtention, error) {
+	return w.s.backend.UpdateAsset(ctx, in)
+}
+
+func (w *walletServer) UnfreezeAsset2(ctx context.Context, in *core.UnfreezeAssetContract) (*api.TransactionExtention, error) {
+	return w.s.backend.UnfreezeAsset(ctx, in)
+}
+
+// Proposal operations
+
+func (w *walletServer) ProposalCreate(ctx context.Context, in *core.ProposalCreateContract) (*api.TransactionExtention, error) {
+	return w.s.backend.ProposalCreate(ctx, in)
+}
+
+func (w *walletServer) ProposalApprove(ctx context.Context, in *core.ProposalApproveContract) (*api.TransactionExtention, error) {
+	return w.s.backend.ProposalApprove(ctx, in)
+}
+
+func (w *walletServer) ProposalDelete(ctx context.Context, in *core.ProposalDeleteContract) (*api.TransactionExtention, error) {
+	return w.s.backend.ProposalDelete(ctx, in)
+}
+
+func (w *walletServer) ListProposals(ctx context.Context, _ *api.EmptyMessage) (*api.ProposalList, error) {
+	return w.s.backend.ListProposals(ctx)
+}
+
+func (w *walletServer) GetPaginatedProposalList(ctx context.Context, in *api.PaginatedMessage) (*api.ProposalList, error) {
+	return w.s.backend.GetPaginatedProposalList(ctx, in)
+}
+
+// GetProposalById reads the id as the eight big-endian bytes java-tron's
+// ByteArray.toLong expects; any other length is no id at all.
+func (w *walletServer) GetProposalById(ctx context.Context, in *api.BytesMessage) (*core.Proposal, error) {
+	if len(in.GetValue()) != 8 {
+		return nil, status.Errorf(codes.InvalidArgument, "proposal id is %d bytes, want 8", len(in.GetValue()))
+	}
+	return w.s.backend.GetProposalById(ctx, int64(binary.BigEndian.Uint64(in.GetValue())))
+}
+
+// Exchange operations
+
+func (w *walletServer) ExchangeCreate(ctx context.Context, in *core.ExchangeCreateContract) (*api.TransactionExtention, error) {
+	return w.s.backend.ExchangeCreate(ctx, in)
+}
+
+func (w *walletServer) ExchangeInject(ctx context.Context, in *core.ExchangeInjectContract) (*api.TransactionExtention, error) {
+	return w.s.backend.ExchangeInject(ctx, in)
+}
+
+func (w *walletServer) ExchangeWithdraw(ctx context.Context, in *core.ExchangeWithdrawContract) (*api.TransactionExtention, error) {
+	return w.s.backend.ExchangeWithdraw(ctx, in)
+}
+
+func (w *walletServer) ExchangeTransaction(ctx context.Context, in *core.ExchangeTransactionContract) (*api.TransactionExtention, error) {
+	return w.s.backend.ExchangeTransaction(ctx, in)
+}
+
+func (w *walletServer) ListExchanges(ctx context.Context, _ *api.EmptyMessage) (*api.ExchangeList, error) {
+	return w.s.backend.ListExchanges(ctx)
+}
+
+func (w *walletServer) GetPaginatedExchangeList(ctx context.Context, in *api.PaginatedMessage) (*api.ExchangeList, error) {
+	return w.s.backend.GetPaginatedExchangeList(ctx, in)
+}
+
+// GetExchangeById reads the id as GetProposalById does.
+func (w *walletServer) GetExchangeById(ctx context.Context, in *api.BytesMessage) (*core.Exchange, error) {
+	if len(in.GetValue()) != 8 {
+		return nil, status.Errorf(codes.InvalidArgument, "exchange id is %d bytes, want 8", len(in.GetValue()))
+	}
+	return w.s.backend.GetExchangeById(ctx, int64(binary.BigEndian.Uint64(in.GetValue())))
+}
+
+// Network operations
+
+func (w *walletServer) ListNodes(ctx context.Context, _ *api.EmptyMessage) (*api.NodeList, error) {
+	return w.s.backend.ListNodes(ctx)
+}
+
+func (w *walletServer) GetNodeInfo(ctx context.Context, _ *api.EmptyMessage) (*core.NodeInfo, error) {
+	return w.s.backend.GetNodeInfo(ctx)
+}
+
+func (w *walletServer) GetChainParameters(ctx context.Context, _ *api.EmptyMessage) (*core.ChainParameters, error) {
+	return w.s.backend.GetChainParameters(ctx)
+}
+
+func (w *walletServer) GetNextMaintenanceTime(ctx context.Context, _ *api.EmptyMessage) (*api.NumberMessage, error) {
+	return w.s.backend.GetNextMaintenanceTime(ctx)
+}
+
+func (w *walletServer) TotalTransaction(ctx context.Context, _ *api.EmptyMessage) (*api.NumberMessage, error) {
+	return w.s.backend.TotalTransaction(ctx)
+}
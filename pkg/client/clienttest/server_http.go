@@ -0,0 +1,493 @@
+package clienttest
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/sxwebdev/gotron/pkg/client"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// tronRequest is the JSON body of an HTTP request.
+type tronRequest struct {
+	body    map[string]any
+	visible bool
+}
+
+func (r *tronRequest) decode(m proto.Message) error {
+	return fromTronJSON(r.body, m, r.visible)
+}
+
+// serveFunc answers an HTTP request with the value to send as JSON.
+type serveFunc func(ctx context.Context, s *Server, r *tronRequest) (any, error)
+
+// httpEndpoint is a /wallet endpoint and the Transport method it serves.
+type httpEndpoint struct {
+	method string
+	serve  serveFunc
+}
+
+// httpEndpoints are the endpoints HTTPTransport calls, each answered in the
+// shape java-tron gives it.
+var httpEndpoints = map[string]httpEndpoint{
+	// Account operations
+	"/wallet/getaccount":              {"GetAccount", query(client.Transport.GetAccount, asJSON)},
+	"/wallet/getaccountresource":      {"GetAccountResource", query(client.Transport.GetAccountResource, asJSON)},
+	"/wallet/createaccount":           {"CreateAccount", query(client.Transport.CreateAccount, builtJSON)},
+	"/wallet/accountpermissionupdate": {"AccountPermissionUpdate", query(client.Transport.AccountPermissionUpdate, builtJSON)},
+
+	// Block operations
+	"/wallet/getnowblock":                  {"GetNowBlock", serveNowBlock},
+	"/wallet/getblockbynum":                {"GetBlockByNum", byNum(client.Transport.GetBlockByNum, blockJSON)},
+	"/wallet/getblockbyid":                 {"GetBlockById", byBytes(client.Transport.GetBlockById, coreBlockJSON)},
+	"/wallet/getblockbylimitnext":          {"GetBlockByLimitNext", query(blockLimit, blockListJSON)},
+	"/wallet/getblockbylatestnum":          {"GetBlockByLatestNum", byNum(client.Transport.GetBlockByLatestNum, blockListJSON)},
+	"/wallet/gettransactioninfobyblocknum": {"GetTransactionInfoByBlockNum", byNum(client.Transport.GetTransactionInfoByBlockNum, infoListJSON)},
+
+	// Transaction operations
+	"/wallet/gettransactionbyid":     {"GetTransactionById", byBytes(client.Transport.GetTransactionById, transactionJSON)},
+	"/wallet/gettransactioninfobyid": {"GetTransactionInfoById", byBytes(client.Transport.GetTransactionInfoById, asJSON)},
+	"/wallet/broadcasthex":           {"BroadcastTransaction", serveBroadcastHex},
+	"/wallet/createtransaction":      {"CreateTransaction", query(client.Transport.CreateTransaction, builtJSON)},
+
+	// Contract operations
+	"/wallet/triggersmartcontract":    {"TriggerContract", query(client.Transport.TriggerContract, triggerJSON)},
+	"/wallet/triggerconstantcontract": {"TriggerConstantContract", query(client.Transport.TriggerConstantContract, constantJSON)},
+	"/wallet/estimateenergy":          {"EstimateEnergy", query(client.Transport.EstimateEnergy, asJSON)},
+	"/wallet/deploycontract":          {"DeployContract", serveDeployContract},
+	"/wallet/getcontract":             {"GetContract", byBytes(client.Transport.GetContract, asJSON)},
+	"/wallet/updatesetting":           {"UpdateSetting", query(client.Transport.UpdateSetting, builtJSON)},
+	"/wallet/updateenergylimit":       {"UpdateEnergyLimit", query(client.Transport.UpdateEnergyLimit, builtJSON)},
+
+	// Resource operations
+	"/wallet/getdelegatedresource":               {"GetDelegatedResource", query(client.Transport.GetDelegatedResource, asJSON)},
+	"/wallet/getdelegatedresourcev2":             {"GetDelegatedResourceV2", query(client.Transport.GetDelegatedResourceV2, asJSON)},
+	"/wallet/getdelegatedresourceaccountindex":   {"GetDelegatedResourceAccountIndex", byBytes(client.Transport.GetDelegatedResourceAccountIndex, asJSON)},
+	"/wallet/getdelegatedresourceaccountindexv2": {"GetDelegatedResourceAccountIndexV2", byBytes(client.Transport.GetDelegatedResourceAccountIndexV2, asJSON)},
+	"/wallet/getcandelegatedmaxsize":             {"GetCanDelegatedMaxSize", query(client.Transport.GetCanDelegatedMaxSize, asJSON)},
+	"/wallet/delegateresource":                   {"DelegateResource", query(client.Transport.DelegateResource, builtJSON)},
+	"/wallet/undelegateresource":                 {"UnDelegateResource", query(client.Transport.UnDelegateResource, builtJSON)},
+	"/wallet/freezebalancev2":                    {"FreezeBalanceV2", query(client.Transport.FreezeBalanceV2, builtJSON)},
+	"/wallet/unfreezebalancev2":                  {"UnfreezeBalanceV2", query(client.Transport.UnfreezeBalanceV2, builtJSON)},
+	"/wallet/withdrawexpireunfreeze":             {"WithdrawExpireUnfreeze", query(client.Transport.WithdrawExpireUnfreeze, builtJSON)},
+	"/wallet/cancelallunfreezev2":                {"CancelAllUnfreezeV2", query(client.Transport.CancelAllUnfreezeV2, builtJSON)},
+	"/wallet/getavailableunfreezecount":          {"GetAvailableUnfreezeCount", query(client.Transport.GetAvailableUnfreezeCount, asJSON)},
+	"/wallet/getcanwithdrawunfreezeamount":       {"GetCanWithdrawUnfreezeAmount", query(client.Transport.GetCanWithdrawUnfreezeAmount, asJSON)},
+
+	// Witness operations
+	"/wallet/votewitnessaccount":         {"VoteWitnessAccount", query(client.Transport.VoteWitnessAccount, builtJSON)},
+	"/wallet/withdrawbalance":            {"WithdrawBalance", query(client.Transport.WithdrawBalance, builtJSON)},
+	"/wallet/listwitnesses":              {"ListWitnesses", noArgs(client.Transport.ListWitnesses)},
+	"/wallet/getpaginatednowwitnesslist": {"GetPaginatedNowWitnessList", query(client.Transport.GetPaginatedNowWitnessList, asJSON)},
+	"/wallet/getReward":                  {"GetRewardInfo", serveNumberByAddress(client.Transport.GetRewardInfo, "reward")},
+	"/wallet/getBrokerage":               {"GetBrokerageInfo", serveNumberByAddress(client.Transport.GetBrokerageInfo, "brokerage")},
+	"/wallet/createwitness":              {"CreateWitness", query(client.Transport.CreateWitness, builtJSON)},
+	"/wallet/updatewitness":              {"UpdateWitness", query(client.Transport.UpdateWitness, builtJSON)},
+	"/wallet/updateBrokerage":            {"UpdateBrokerage", query(client.Transport.UpdateBrokerage, builtJSON)},
+
+	// Asset operations
+	"/wallet/getassetissuebyid":          {"GetAssetIssueById", serveAssetIssueByID},
+	"/wallet/getassetissuelistbyname":    {"GetAssetIssueListByName", byBytes(client.Transport.GetAssetIssueListByName, asJSON)},
+	"/wallet/getassetissuebyaccount":     {"GetAssetIssueByAccount", serveAssetIssueByAccount},
+	"/wallet/getassetissuelist":          {"GetAssetIssueList", noArgs(client.Transport.GetAssetIssueList)},
+	"/wallet/getpaginatedassetissuelist": {"GetPaginatedAssetIssueList", query(client.Transport.GetPaginatedAssetIssueList, asJSON)},
+	"/wallet/transferasset":              {"TransferAsset", query(client.Transport.TransferAsset, builtJSON)},
+	"/wallet/createassetissue":           {"CreateAssetIssue", query(client.Transport.CreateAssetIssue, builtJSON)},
+	"/wallet/participateassetissue":      {"ParticipateAssetIssue", query(client.Transport.ParticipateAssetIssue, builtJSON)},
+	"/wallet/updateasset":                {"UpdateAsset", query(client.Transport.UpdateAsset, builtJSON)},
+	"/wallet/unfreezeasset":              {"UnfreezeAsset", query(client.Transport.UnfreezeAsset, builtJSON)},
+
+	// Proposal operations
+	"/wallet/proposalcreate":           {"ProposalCreate", query(client.Transport.ProposalCreate, builtJSON)},
+	"/wallet/proposalapprove":          {"ProposalApprove", query(client.Transport.ProposalApprove, builtJSON)},
+	"/wallet/proposaldelete":           {"ProposalDelete", query(client.Transport.ProposalDelete, builtJSON)},
+	"/wallet/listproposals":            {"ListProposals", noArgs(client.Transport.ListProposals)},
+	"/wallet/getpaginatedproposallist": {"GetPaginatedProposalList", query(client.Transport.GetPaginatedProposalList, asJSON)},
+	"/wallet/getproposalbyid":          {"GetProposalById", serveProposalByID},
+
+	// Exchange operations
+	"/wallet/exchangecreate":           {"ExchangeCreate", query(client.Transport.ExchangeCreate, builtJSON)},
+	"/wallet/exchangeinject":           {"ExchangeInject", query(client.Transport.ExchangeInject, builtJSON)},
+	"/wallet/exchangewithdraw":         {"ExchangeWithdraw", query(client.Transport.ExchangeWithdraw, builtJSON)},
+	"/wallet/exchangetransaction":      {"ExchangeTransaction", query(client.Transport.ExchangeTransaction, builtJSON)},
+	"/wallet/listexchanges":            {"ListExchanges", noArgs(client.Transport.ListExchanges)},
+	"/wallet/getpaginatedexchangelist": {"GetPaginatedExchangeList", query(client.Transport.GetPaginatedExchangeList, asJSON)},
+	"/wallet/getexchangebyid":          {"GetExchangeById", serveExchangeByID},
+
+	// Network operations
+	"/wallet/listnodes":              {"ListNodes", noArgs(client.Transport.ListNodes)},
+	"/wallet/getnodeinfo":            {"GetNodeInfo", noArgs(client.Transport.GetNodeInfo)},
+	"/wallet/getchainparameters":     {"GetChainParameters", noArgs(client.Transport.GetChainParameters)},
+	"/wallet/getnextmaintenancetime": {"GetNextMaintenanceTime", noArgs(client.Transport.GetNextMaintenanceTime)},
+	"/wallet/totaltransaction":       {"TotalTransaction", noArgs(client.Transport.TotalTransaction)},
+}
+
+// serveHTTP answers a request to the HTTP API.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	endpoint, ok := httpEndpoints[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	call := Call{Protocol: client.ProtocolHTTP, Method: endpoint.method, Path: r.URL.Path, Header: r.Header.Clone()}
+	if f := s.begin(call); f != nil {
+		if !sleep(r.Context(), f.Latency) {
+			return
+		}
+		switch {
+		case f.Disconnect:
+			if conn, _, err := http.NewResponseController(w).Hijack(); err == nil {
+				_ = conn.Close()
+			}
+			return
+		case f.Status != 0:
+			if f.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(f.RetryAfter.Seconds()))))
+			}
+			http.Error(w, http.StatusText(f.Status), f.Status)
+			return
+		case f.Malformed:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"blockID":"00`))
+			return
+		}
+	}
+
+	var out any
+	req, err := readTronRequest(r.Body)
+	if err == nil {
+		out, err = endpoint.serve(r.Context(), s, req)
+	}
+	// java-tron reports a request it cannot serve with HTTP 200 and an
+	// "Error" field.
+	if err != nil {
+		out = map[string]any{"Error": err.Error()}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func readTronRequest(body io.Reader) (*tronRequest, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	r := &tronRequest{body: map[string]any{}}
+	if len(bytes.TrimSpace(data)) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&r.body); err != nil {
+			return nil, err
+		}
+	}
+	r.visible, _ = r.body["visible"].(bool)
+	return r, nil
+}
+
+// query serves a Transport method taking a request message, decoded from the
+// body, and renders its answer with render.
+func query[Req, Res proto.Message](call func(client.Transport, context.Context, Req) (Res, error), render func(Res, bool) any) serveFunc {
+	return func(ctx context.Context, s *Server, r *tronRequest) (any, error) {
+		var req Req
+		req = req.ProtoReflect().New().Interface().(Req)
+		if err := r.decode(req); err != nil {
+			return nil, err
+		}
+		res, err := call(s.backend, ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return render(res, r.visible), nil
+	}
+}
+
+// byBytes serves a Transport method taking bytes, sent as "value".
+func byBytes[Res proto.Message](call func(client.Transport, context.Context, []byte) (Res, error), render func(Res, bool) any) serveFunc {
+	return query(func(t client.Transport, ctx context.Context, req *api.BytesMessage) (Res, error) {
+		return call(t, ctx, req.GetValue())
+	}, render)
+}
+
+// byNum serves a Transport method taking a number, sent as "num".
+func byNum[Res proto.Message](call func(client.Transport, context.Context, int64) (Res, error), render func(Res, bool) any) serveFunc {
+	return query(func(t client.Transport, ctx context.Context, req *api.NumberMessage) (Res, error) {
+		return call(t, ctx, req.GetNum())
+	}, render)
+}
+
+// noArgs serves a Transport method taking nothing.
+func noArgs[Res proto.Message](call func(client.Transport, context.Context) (Res, error)) serveFunc {
+	return query(func(t client.Transport, ctx context.Context, _ *api.EmptyMessage) (Res, error) {
+		return call(t, ctx)
+	}, asJSON)
+}
+
+func blockLimit(t client.Transport, ctx context.Context, req *api.BlockLimit) (*api.BlockListExtention, error) {
+	return t.GetBlockByLimitNext(ctx, req.GetStartNum(), req.GetEndNum())
+}
+
+func serveNowBlock(ctx context.Context, s *Server, r *tronRequest) (any, error) {
+	head, err := s.nowBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return blockJSON(head, r.visible), nil
+}
+
+// serveBroadcastHex takes the transaction as its marshalled protobuf, in hex,
+// and answers with the outcome and the plain-text message.
+func serveBroadcastHex(ctx context.Context, s *Server, r *tronRequest) (any, error) {
+	text, _ := r.body["transaction"].(string)
+	data, err := hex.DecodeString(text)
+	if err != nil {
+		return nil, fmt.Errorf("transaction: %w", err)
+	}
+	tx := &core.Transaction{}
+	if err := proto.Unmarshal(data, tx); err != nil {
+		return nil, fmt.Errorf("transaction: %w", err)
+	}
+	res, err := s.backend.BroadcastTransaction(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"result":  res.GetResult(),
+		"code":    res.GetCode().String(),
+		"txid":    hex.EncodeToString(txID(tx.GetRawData())),
+		"message": string(res.GetMessage()),
+	}, nil
+}
+
+// serveDeployContract reads the contract from the flat body java-tron takes,
+// with the ABI in protojson form.
+func serveDeployContract(ctx context.Context, s *Server, r *tronRequest) (any, error) {
+	contract := &core.CreateSmartContract{NewContract: &core.SmartContract{}}
+	if err := r.decode(contract); err != nil {
+		return nil, err
+	}
+	if err := r.decode(contract.NewContract); err != nil {
+		return nil, err
+	}
+	if abi, ok := r.body["abi"]; ok {
+		data, err := json.Marshal(abi)
+		if err != nil {
+			return nil, err
+		}
+		contract.NewContract.Abi = &core.SmartContract_ABI{}
+		if err := protojson.Unmarshal(data, contract.NewContract.Abi); err != nil {
+			return nil, fmt.Errorf("abi: %w", err)
+		}
+	}
+	res, err := s.backend.DeployContract(ctx, contract)
+	if err != nil {
+		return nil, err
+	}
+	return builtJSON(res, r.visible), nil
+}
+
+// serveNumberByAddress serves getReward and getBrokerage, which take
+// "address" and answer the number under key.
+func serveNumberByAddress(call func(client.Transport, context.Context, []byte) (*api.NumberMessage, error), key string) serveFunc {
+	return func(ctx context.Context, s *Server, r *tronRequest) (any, error) {
+		text, _ := r.body["address"].(string)
+		address, err := parseTronBytes(text, r.visible)
+		if err != nil {
+			return nil, fmt.Errorf("address: %w", err)
+		}
+		res, err := call(s.backend, ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{key: res.GetNum()}, nil
+	}
+}
+
+// serveAssetIssueByID takes the decimal id as it stands rather than in hex.
+func serveAssetIssueByID(ctx context.Context, s *Server, r *tronRequest) (any, error) {
+	id, _ := r.body["value"].(string)
+	res, err := s.backend.GetAssetIssueById(ctx, []byte(id))
+	if err != nil {
+		return nil, err
+	}
+	return tronJSON(res, r.visible), nil
+}
+
+// serveAssetIssueByAccount takes the issuer as "address", which names no
+// field of the BytesMessage the other lookups decode into.
+func serveAssetIssueByAccount(ctx context.Context, s *Server, r *tronRequest) (any, error) {
+	text, _ := r.body["address"].(string)
+	address, err := parseTronBytes(text, r.visible)
+	if err != nil {
+		return nil, fmt.Errorf("address: %w", err)
+	}
+	res, err := s.backend.GetAssetIssueByAccount(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	return tronJSON(res, r.visible), nil
+}
+
+// serveProposalByID takes the id as "id", which names no field of the
+// NumberMessage byNum decodes into.
+func serveProposalByID(ctx context.Context, s *Server, r *tronRequest) (any, error) {
+	id, err := tronInt(r.body["id"])
+	if err != nil {
+		return nil, fmt.Errorf("id: %w", err)
+	}
+	res, err := s.backend.GetProposalById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return tronJSON(res, r.visible), nil
+}
+
+func serveExchangeByID(ctx context.Context, s *Server, r *tronRequest) (any, error) {
+	id, err := tronInt(r.body["id"])
+	if err != nil {
+		return nil, fmt.Errorf("id: %w", err)
+	}
+	res, err := s.backend.GetExchangeById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return tronJSON(res, r.visible), nil
+}
+
+func asJSON[Res proto.Message](res Res, visible bool) any {
+	return tronJSON(res, visible)
+}
+
+// transactionJSON renders a transaction with the txID and raw_data_hex
+// java-tron adds to it; an empty one renders as {}.
+func transactionJSON(tx *core.Transaction, visible bool) any {
+	if tx.GetRawData() == nil {
+		return map[string]any{}
+	}
+	raw, err := proto.Marshal(tx.GetRawData())
+	if err != nil {
+		return map[string]any{"Error": err.Error()}
+	}
+	out := tronJSON(tx, visible)
+	out["txID"] = hex.EncodeToString(txID(tx.GetRawData()))
+	out["raw_data_hex"] = hex.EncodeToString(raw)
+	out["visible"] = visible
+	return out
+}
+
+// builtJSON renders a transaction a node built at the top level, or the
+// refusal in "Error" with the exception class java-tron names.
+func builtJSON(tx *api.TransactionExtention, visible bool) any {
+	if r := tx.GetResult(); r.GetCode() != api.Return_SUCCESS || tx.GetTransaction() == nil {
+		return map[string]any{"Error": "class org.tron.core.exception.ContractValidateException : " + string(r.GetMessage())}
+	}
+	return transactionJSON(tx.GetTransaction(), visible)
+}
+
+// triggerJSON renders /wallet/triggersmartcontract, which nests the
+// transaction and reports a refusal in "result", with the message in hex.
+func triggerJSON(tx *api.TransactionExtention, visible bool) any {
+	if r := tx.GetResult(); r.GetCode() != api.Return_SUCCESS || tx.GetTransaction() == nil {
+		return map[string]any{"result": map[string]any{
+			"code":    r.GetCode().String(),
+			"message": hex.EncodeToString(r.GetMessage()),
+		}}
+	}
+	return map[string]any{
+		"result":      map[string]any{"result": true},
+		"transaction": transactionJSON(tx.GetTransaction(), visible),
+	}
+}
+
+// constantJSON renders /wallet/triggerconstantcontract, whose result message
+// is plain text - "REVERT opcode executed" - and results are hex.
+func constantJSON(tx *api.TransactionExtention, visible bool) any {
+	r := tx.GetResult()
+	result := map[string]any{"result": r.GetResult()}
+	if r.GetCode() != api.Return_SUCCESS {
+		result["code"] = r.GetCode().String()
+	}
+	if len(r.GetMessage()) > 0 {
+		result["message"] = string(r.GetMessage())
+	}
+	constant := make([]string, 0, len(tx.GetConstantResult()))
+	for _, value := range tx.GetConstantResult() {
+		constant = append(constant, hex.EncodeToString(value))
+	}
+	out := map[string]any{"result": result, "constant_result": constant}
+	if tx.GetEnergyUsed() > 0 {
+		out["energy_used"] = tx.GetEnergyUsed()
+	}
+	if tx.GetEnergyPenalty() > 0 {
+		out["energy_penalty"] = tx.GetEnergyPenalty()
+	}
+	if tx.GetTransaction() != nil {
+		out["transaction"] = transactionJSON(tx.GetTransaction(), visible)
+	}
+	return out
+}
+
+// blockJSON renders a block with its id and its transactions in the form
+// transactionJSON gives them; a block not found renders as {}.
+func blockJSON(block *api.BlockExtention, visible bool) any {
+	if block.GetBlockHeader() == nil {
+		return map[string]any{}
+	}
+	txs := make([]*core.Transaction, 0, len(block.GetTransactions()))
+	for _, tx := range block.GetTransactions() {
+		txs = append(txs, tx.GetTransaction())
+	}
+	return renderBlock(block.GetBlockid(), block.GetBlockHeader(), txs, visible)
+}
+
+func coreBlockJSON(block *core.Block, visible bool) any {
+	if block.GetBlockHeader() == nil {
+		return map[string]any{}
+	}
+	return renderBlock(blockID(block.GetBlockHeader().GetRawData()), block.GetBlockHeader(), block.GetTransactions(), visible)
+}
+
+func renderBlock(id []byte, header *core.BlockHeader, txs []*core.Transaction, visible bool) map[string]any {
+	out := map[string]any{
+		"blockID":      hex.EncodeToString(id),
+		"block_header": tronJSON(header, visible),
+	}
+	if len(txs) > 0 {
+		list := make([]any, 0, len(txs))
+		for _, tx := range txs {
+			list = append(list, transactionJSON(tx, visible))
+		}
+		out["transactions"] = list
+	}
+	return out
+}
+
+func blockListJSON(list *api.BlockListExtention, visible bool) any {
+	if len(list.GetBlock()) == 0 {
+		return map[string]any{}
+	}
+	blocks := make([]any, 0, len(list.GetBlock()))
+	for _, block := range list.GetBlock() {
+		blocks = append(blocks, blockJSON(block, visible))
+	}
+	return map[string]any{"block": blocks}
+}
+
+// infoListJSON renders the infos of a block as a bare array.
+func infoListJSON(list *api.TransactionInfoList, visible bool) any {
+	infos := make([]any, 0, len(list.GetTransactionInfo()))
+	for _, info := range list.GetTransactionInfo() {
+		infos = append(infos, tronJSON(info, visible))
+	}
+	return infos
+}
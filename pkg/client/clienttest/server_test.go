@@ -0,0 +1,542 @@
+package clienttest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/pkg/client"
+	"github.com/sxwebdev/gotron/pkg/tronutils"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newServer starts a Server for chain.
+func newServer(t *testing.T, chain *Chain, cfg ServerConfig) *Server {
+	t.Helper()
+	srv, err := NewServer(chain, cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = srv.Close() })
+	return srv
+}
+
+// serverClient returns a Client with health checking disabled that reaches
+// the nodes over the network.
+func serverClient(t *testing.T, nodes ...client.NodeConfig) *client.Client {
+	t.Helper()
+	c, err := client.New(client.Config{
+		Nodes:  nodes,
+		Health: client.HealthConfig{Disabled: true},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+// forEachProtocol runs test against a server over each protocol, in plain
+// text and over TLS.
+func forEachProtocol(t *testing.T, test func(t *testing.T, chain *Chain, srv *Server, node client.NodeConfig)) {
+	for _, protocol := range []client.Protocol{client.ProtocolGRPC, client.ProtocolHTTP} {
+		for _, useTLS := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/tls=%t", protocol, useTLS), func(t *testing.T) {
+				chain := NewChain()
+				srv := newServer(t, chain, ServerConfig{TLS: useTLS})
+				test(t, chain, srv, srv.NodeConfig(protocol))
+			})
+		}
+	}
+}
+
+func TestServerTransfer(t *testing.T) {
+	forEachProtocol(t, func(t *testing.T, chain *Chain, _ *Server, node client.NodeConfig) {
+		c := serverClient(t, node)
+		alice := chain.NewAccount(100_000_000)
+		bob := chain.NewAccount(0)
+
+		tx, err := c.CreateTransferTransaction(t.Context(), alice.Address, bob.Address, 30_000_000)
+		require.NoError(t, err)
+		require.NoError(t, send(t, c, tx, alice.PrivateKey))
+		balance, err := c.GetAccountBalance(t.Context(), bob.Address)
+		require.NoError(t, err)
+		assert.Equal(t, client.SUN(30_000_000), balance)
+
+		num := chain.NextBlock()
+		hash := tronutils.BytesToHexString(tx.GetTxid())
+		info, err := c.GetTransactionInfoByHash(t.Context(), hash)
+		require.NoError(t, err)
+		assert.Equal(t, num, info.GetBlockNumber())
+		found, err := c.GetTransactionByHash(t.Context(), hash)
+		require.NoError(t, err)
+		assert.Equal(t, tx.GetTransaction().GetRawData().GetTimestamp(), found.GetRawData().GetTimestamp())
+		require.Len(t, found.GetRet(), 1)
+		assert.Equal(t, core.Transaction_Result_SUCCESS, found.GetRet()[0].GetContractRet())
+
+		block, err := c.GetBlockByHeight(t.Context(), uint64(num))
+		require.NoError(t, err)
+		require.Len(t, block.GetTransactions(), 1)
+		assert.Equal(t, tx.GetTxid(), block.GetTransactions()[0].GetTxid())
+
+		err = send(t, c, tx)
+		assert.Equal(t, "DUP_TRANSACTION_ERROR", broadcastCode(t, err).String(), "the rejection code crosses the wire")
+	})
+}
+
+func TestServerRefusal(t *testing.T) {
+	forEachProtocol(t, func(t *testing.T, chain *Chain, _ *Server, node client.NodeConfig) {
+		c := serverClient(t, node)
+		alice := chain.NewAccount(1_000_000)
+		bob := chain.NewAccount(0)
+
+		_, err := c.CreateTransferTransaction(t.Context(), alice.Address, bob.Address, 2_000_000)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "balance is not sufficient")
+	})
+}
+
+func TestServerTRC20(t *testing.T) {
+	forEachProtocol(t, func(t *testing.T, chain *Chain, _ *Server, node client.NodeConfig) {
+		c := serverClient(t, node)
+		alice := chain.NewAccount(100_000_000)
+		bob := chain.NewAccount(0)
+		usdt := chain.DeployTRC20(TRC20{Name: "Tether USD", Symbol: "USDT", Decimals: 6})
+		chain.MintTRC20(usdt, alice.Address, tokens(t, 1000))
+
+		symbol, err := c.TRC20GetSymbol(t.Context(), usdt)
+		require.NoError(t, err)
+		assert.Equal(t, "USDT", symbol)
+		tx, err := c.TRC20Send(t.Context(), alice.Address, bob.Address, usdt, tokens(t, 250), 10_000_000)
+		require.NoError(t, err)
+		require.NoError(t, send(t, c, tx, alice.PrivateKey))
+
+		balance, err := c.TRC20ContractBalance(t.Context(), bob.Address, usdt)
+		require.NoError(t, err)
+		assert.Equal(t, "250", balance.String())
+		_, err = c.EstimateTRC20Transfer(t.Context(), bob.Address, alice.Address, usdt, tokens(t, 251))
+		require.ErrorIs(t, err, client.ErrContractCallFailed, "a revert crosses the wire")
+	})
+}
+
+func TestServerTRC10(t *testing.T) {
+	forEachProtocol(t, func(t *testing.T, chain *Chain, _ *Server, node client.NodeConfig) {
+		c := serverClient(t, node)
+		alice := chain.NewAccount(100_000_000)
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		fresh := tronutils.PubkeyToAddress(key.PublicKey).String()
+		chain.FundAsset(alice.Address, "1002000", 1000)
+
+		estimate, err := c.EstimateTRC10Transfer(t.Context(), alice.Address, fresh, "1002000", tokens(t, 250))
+		require.NoError(t, err)
+		assert.Positive(t, estimate.Charges.AccountCreation, "the transfer creates the recipient")
+
+		tx, err := c.CreateTransferAssetTransaction(t.Context(), alice.Address, fresh, "1002000", tokens(t, 250))
+		require.NoError(t, err)
+		require.NoError(t, send(t, c, tx, alice.PrivateKey))
+		assert.Equal(t, int64(750), chain.AssetBalance(alice.Address, "1002000"))
+
+		balances, err := c.GetTRC10Balances(t.Context(), fresh)
+		require.NoError(t, err)
+		require.Len(t, balances, 1)
+		assert.Equal(t, "1002000", balances[0].ID)
+		assert.Equal(t, "250", balances[0].Amount.String())
+
+		_, err = c.CreateTransferAssetTransaction(t.Context(), fresh, alice.Address, "1002000", tokens(t, 251))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "assetBalance is not sufficient")
+	})
+}
+
+func TestServerAssetIssue(t *testing.T) {
+	forEachProtocol(t, func(t *testing.T, chain *Chain, _ *Server, node client.NodeConfig) {
+		c := serverClient(t, node)
+		issuer := chain.NewAccount(2_000_000_000)
+		buyer := chain.NewAccount(100_000_000)
+		start := time.Now().Add(time.Hour)
+
+		issue, err := c.CreateAssetIssue(t.Context(), client.AssetIssueRequest{
+			Owner:        issuer.Address,
+			Name:         "Loyalty",
+			Abbr:         "LOY",
+			Description:  "Partner points",
+			URL:          "https://example.com",
+			TotalSupply:  tokens(t, 1_000_000),
+			TRXNum:       10,
+			Num:          tokens(t, 1),
+			StartTime:    start,
+			EndTime:      start.Add(48 * time.Hour),
+			FrozenSupply: []client.FrozenSupply{{Amount: tokens(t, 100_000), Days: 1}},
+		})
+		require.NoError(t, err)
+		require.NoError(t, send(t, c, issue, issuer.PrivateKey))
+
+		issued, err := c.GetAssetIssueByAccount(t.Context(), issuer.Address)
+		require.NoError(t, err)
+		require.Len(t, issued.GetAssetIssue(), 1)
+		asset := issued.GetAssetIssue()[0]
+		assert.Equal(t, "Loyalty", string(asset.GetName()), "names cross the wire as text")
+		assert.Equal(t, "Partner points", string(asset.GetDescription()))
+		id := asset.GetId()
+		assert.Equal(t, int64(900_000), chain.AssetBalance(issuer.Address, id), "the frozen supply is held back")
+
+		sale := client.ParticipateAssetIssueRequest{Owner: buyer.Address, Issuer: issuer.Address, AssetID: id, Amount: 1_000_000}
+		_, err = c.ParticipateAssetIssue(t.Context(), sale)
+		require.ErrorContains(t, err, "No longer valid period", "the sale has not started")
+
+		chain.AdvanceTime(2 * time.Hour)
+		chain.NextBlock()
+		buy, err := c.ParticipateAssetIssue(t.Context(), sale)
+		require.NoError(t, err)
+		require.NoError(t, send(t, c, buy, buyer.PrivateKey))
+		assert.Equal(t, int64(100_000), chain.AssetBalance(buyer.Address, id))
+		assert.Equal(t, client.SUN(2_001_000_000), chain.Balance(issuer.Address))
+
+		_, err = c.UnfreezeAsset(t.Context(), issuer.Address)
+		require.ErrorContains(t, err, "It's not time to unfreeze asset supply")
+		chain.AdvanceTime(24 * time.Hour)
+		chain.NextBlock()
+		unfreeze, err := c.UnfreezeAsset(t.Context(), issuer.Address)
+		require.NoError(t, err)
+		require.NoError(t, send(t, c, unfreeze, issuer.PrivateKey))
+		assert.Equal(t, int64(900_000), chain.AssetBalance(issuer.Address, id))
+
+		update, err := c.UpdateAsset(t.Context(), client.UpdateAssetRequest{
+			Owner:             issuer.Address,
+			Description:       "Partner loyalty points",
+			URL:               "https://example.com/points",
+			FreeAssetNetLimit: 5000,
+		})
+		require.NoError(t, err)
+		require.NoError(t, send(t, c, update, issuer.PrivateKey))
+		updated, err := c.GetAssetIssueById(t.Context(), id)
+		require.NoError(t, err)
+		assert.Equal(t, "Partner loyalty points", string(updated.GetDescription()))
+		assert.Equal(t, int64(5000), updated.GetFreeAssetNetLimit())
+
+		page, err := c.GetPaginatedAssetIssueList(t.Context(), 0, 10)
+		require.NoError(t, err)
+		require.Len(t, page.GetAssetIssue(), 1)
+		all, err := c.GetAssetIssueList(t.Context())
+		require.NoError(t, err)
+		require.Len(t, all.GetAssetIssue(), 1)
+
+		_, err = c.CreateAssetIssue(t.Context(), client.AssetIssueRequest{
+			Owner: issuer.Address, Name: "Again", URL: "https://example.com",
+			TotalSupply: tokens(t, 1), TRXNum: 1, Num: tokens(t, 1),
+			StartTime: start.Add(72 * time.Hour), EndTime: start.Add(96 * time.Hour),
+		})
+		require.ErrorIs(t, err, client.ErrInvalidParams, "an account issues one token")
+	})
+}
+
+func TestServerWitness(t *testing.T) {
+	forEachProtocol(t, func(t *testing.T, chain *Chain, _ *Server, node client.NodeConfig) {
+		c := serverClient(t, node)
+		candidate := chain.NewAccount(10_000_000_000)
+
+		_, err := c.UpdateBrokerage(t.Context(), candidate.Address, 30)
+		require.ErrorContains(t, err, "Not existed witness")
+
+		apply, err := c.CreateWitness(t.Context(), candidate.Address, "https://sr.example")
+		require.NoError(t, err)
+		require.NoError(t, send(t, c, apply, candidate.PrivateKey))
+
+		witnesses, err := c.ListWitnesses(t.Context())
+		require.NoError(t, err)
+		require.Len(t, witnesses.GetWitnesses(), 2, "the block producer and the candidate")
+		assert.Equal(t, "https://sr.example", witnesses.GetWitnesses()[1].GetUrl(), "urls cross the wire as text")
+
+		_, err = c.CreateWitness(t.Context(), candidate.Address, "https://sr.example")
+		require.ErrorIs(t, err, client.ErrInvalidParams, "an account applies once")
+
+		update, err := c.UpdateWitness(t.Context(), candidate.Address, "https://sr.example/v2")
+		require.NoError(t, err)
+		require.NoError(t, send(t, c, update, candidate.PrivateKey))
+
+		brokerage, err := c.UpdateBrokerage(t.Context(), candidate.Address, 30)
+		require.NoError(t, err)
+		require.NoError(t, send(t, c, brokerage, candidate.PrivateKey))
+		got, err := c.GetWitnessBrokerage(t.Context(), candidate.Address)
+		require.NoError(t, err)
+		assert.Equal(t, int64(30), got)
+
+		page, err := c.GetPaginatedNowWitnessList(t.Context(), 1, 10)
+		require.NoError(t, err)
+		require.Len(t, page.GetWitnesses(), 1)
+		assert.Equal(t, "https://sr.example/v2", page.GetWitnesses()[0].GetUrl())
+	})
+}
+
+// Exchanges cross HTTP with their token ids as text and come back hex, "5f"
+// for TRX; a trade is priced by the same formula the quote uses.
+func TestServerExchange(t *testing.T) {
+	forEachProtocol(t, func(t *testing.T, chain *Chain, _ *Server, node client.NodeConfig) {
+		c := serverClient(t, node)
+		creator := chain.NewAccount(10_000_000_000)
+		trader := chain.NewAccount(1_000_000_000)
+		chain.FundAsset(creator.Address, "1000001", 1_000_000)
+
+		create, err := c.CreateExchange(t.Context(), client.ExchangeCreateRequest{
+			Owner:              creator.Address,
+			FirstTokenID:       client.ExchangeTRX,
+			FirstTokenBalance:  1_000_000_000,
+			SecondTokenID:      "1000001",
+			SecondTokenBalance: 100_000,
+		})
+		require.NoError(t, err)
+		require.NoError(t, send(t, c, create, creator.PrivateKey))
+
+		exchanges, err := c.ListExchanges(t.Context())
+		require.NoError(t, err)
+		require.Len(t, exchanges, 1)
+		exchange := exchanges[0]
+		assert.Equal(t, creator.Address, exchange.Creator)
+		assert.Equal(t, client.ExchangeTRX, exchange.FirstTokenID)
+		assert.Equal(t, "1000001", exchange.SecondTokenID)
+		assert.Equal(t, int64(900_000), chain.AssetBalance(creator.Address, "1000001"))
+
+		quote, err := c.QuoteExchange(t.Context(), exchange.ID, client.ExchangeTRX, 10_000_000)
+		require.NoError(t, err)
+
+		_, err = c.TradeExchange(t.Context(), client.ExchangeTradeRequest{
+			Owner: trader.Address, ExchangeID: exchange.ID, SellTokenID: client.ExchangeTRX,
+			Quant: 10_000_000, Expected: quote.Buy + 1,
+		})
+		require.ErrorContains(t, err, "token required must greater than expected")
+
+		trade, err := c.TradeExchange(t.Context(), client.ExchangeTradeRequest{
+			Owner: trader.Address, ExchangeID: exchange.ID, SellTokenID: client.ExchangeTRX,
+			Quant: 10_000_000, Expected: quote.MinExpected(0.01),
+		})
+		require.NoError(t, err)
+		require.NoError(t, send(t, c, trade, trader.PrivateKey))
+		assert.Equal(t, quote.Buy, chain.AssetBalance(trader.Address, "1000001"))
+		assert.Equal(t, client.SUN(990_000_000), chain.Balance(trader.Address))
+
+		inject, err := c.InjectExchange(t.Context(), creator.Address, exchange.ID, "1000001", 10_000)
+		require.NoError(t, err)
+		require.NoError(t, send(t, c, inject, creator.PrivateKey))
+
+		_, err = c.WithdrawExchange(t.Context(), trader.Address, exchange.ID, client.ExchangeTRX, 1_000_000)
+		require.ErrorContains(t, err, "is not creator")
+
+		withdraw, err := c.WithdrawExchange(t.Context(), creator.Address, exchange.ID, "1000001", 10_000)
+		require.NoError(t, err)
+		require.NoError(t, send(t, c, withdraw, creator.PrivateKey))
+
+		got, err := c.GetExchangeById(t.Context(), exchange.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(100_000)-quote.Buy, got.SecondTokenBalance)
+
+		_, err = c.GetExchangeById(t.Context(), exchange.ID+1)
+		require.ErrorIs(t, err, client.ErrExchangeNotFound)
+	})
+}
+
+// The chain makes no proposals, but the calls still have to cross both wires:
+// the parameter map as HTTP's key/value list, the id as gRPC's eight bytes.
+func TestServerProposals(t *testing.T) {
+	forEachProtocol(t, func(t *testing.T, chain *Chain, _ *Server, node client.NodeConfig) {
+		c := serverClient(t, node)
+		owner := chain.NewAccount(1_000_000)
+
+		_, err := c.CreateProposal(t.Context(), owner.Address, map[int64]int64{11: 210, 70: 14})
+		require.ErrorContains(t, err, "ProposalCreate")
+		_, err = c.ApproveProposal(t.Context(), owner.Address, 1, true)
+		require.ErrorContains(t, err, "ProposalApprove")
+
+		proposals, err := c.ListProposals(t.Context())
+		require.NoError(t, err)
+		assert.Empty(t, proposals)
+
+		_, err = c.GetProposalById(t.Context(), 1)
+		require.ErrorIs(t, err, client.ErrProposalNotFound)
+	})
+}
+
+func TestServerStaking(t *testing.T) {
+	forEachProtocol(t, func(t *testing.T, chain *Chain, _ *Server, node client.NodeConfig) {
+		c := serverClient(t, node)
+		alice := chain.NewAccount(100_000_000)
+		bob := chain.NewAccount(0)
+
+		stake, err := c.Stake(t.Context(), alice.Address, client.ResourceTypeEnergy, 50_000_000)
+		require.NoError(t, err)
+		require.NoError(t, send(t, c, stake, alice.PrivateKey))
+		delegate, err := c.DelegateResource(t.Context(), alice.Address, bob.Address, client.ResourceTypeEnergy, 20_000_000, false, 0)
+		require.NoError(t, err)
+		require.NoError(t, send(t, c, delegate, alice.PrivateKey))
+
+		delegations, err := c.GetDelegatedResourcesV2(t.Context(), alice.Address)
+		require.NoError(t, err)
+		require.Len(t, delegations, 1, "addresses cross the wire in base58")
+		assert.Equal(t, bob.Address, delegations[0].To)
+		assert.Equal(t, client.SUN(20_000_000), delegations[0].Energy)
+		res, err := c.GetAccountResource(t.Context(), bob.Address)
+		require.NoError(t, err)
+		assert.Equal(t, int64(200), res.GetEnergyLimit())
+		info, err := c.GetStakeInfo(t.Context(), alice.Address)
+		require.NoError(t, err)
+		assert.Equal(t, client.SUN(30_000_000), info.StakedEnergy, "what is delegated is no longer staked for oneself")
+	})
+}
+
+func TestServerHeaders(t *testing.T) {
+	forEachProtocol(t, func(t *testing.T, chain *Chain, srv *Server, node client.NodeConfig) {
+		node.Headers = map[string]string{"X-Team": "payments"}
+		node.APIKeys = []string{"key-1"}
+		c := serverClient(t, node)
+
+		_, err := c.GetLastBlock(t.Context())
+		require.NoError(t, err)
+		calls := srv.Calls()
+		require.Len(t, calls, 1)
+		assert.Equal(t, node.Protocol, calls[0].Protocol)
+		assert.Equal(t, "GetNowBlock", calls[0].Method)
+		assert.Equal(t, "payments", calls[0].Header.Get("X-Team"))
+		assert.Equal(t, "key-1", calls[0].Header.Get("Tron-Pro-Api-Key"))
+	})
+}
+
+func TestServerFaults(t *testing.T) {
+	forEachProtocol(t, func(t *testing.T, chain *Chain, srv *Server, node client.NodeConfig) {
+		tr, err := newNodeTransport(node)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = tr.Close() })
+		grpcCode := func(err error) codes.Code {
+			te, ok := errors.AsType[*client.TransportError](err)
+			require.True(t, ok, "want a TransportError, got %v", err)
+			return status.Code(te.Err)
+		}
+
+		srv.Inject(Fault{Methods: []string{"GetNowBlock"}, Times: 1, Status: http.StatusTooManyRequests, RetryAfter: 2 * time.Second})
+		_, err = tr.GetNowBlock(t.Context())
+		require.Error(t, err)
+		if node.Protocol == client.ProtocolHTTP {
+			statusErr, ok := errors.AsType[*client.HTTPStatusError](err)
+			require.True(t, ok, "want an HTTPStatusError, got %v", err)
+			assert.Equal(t, http.StatusTooManyRequests, statusErr.Code)
+			assert.Equal(t, 2*time.Second, statusErr.RetryAfter)
+		} else {
+			assert.Equal(t, codes.ResourceExhausted, grpcCode(err))
+		}
+		_, err = tr.GetNowBlock(t.Context())
+		require.NoError(t, err, "a fault with Times is spent")
+
+		srv.Inject(Fault{Times: 1, Status: http.StatusServiceUnavailable})
+		_, err = tr.GetNowBlock(t.Context())
+		if node.Protocol == client.ProtocolHTTP {
+			statusErr, ok := errors.AsType[*client.HTTPStatusError](err)
+			require.True(t, ok, "want an HTTPStatusError, got %v", err)
+			assert.Equal(t, http.StatusServiceUnavailable, statusErr.Code)
+		} else {
+			assert.Equal(t, codes.Unavailable, grpcCode(err))
+		}
+
+		srv.Inject(Fault{Times: 1, Malformed: true})
+		_, err = tr.GetNowBlock(t.Context())
+		require.Error(t, err, "a malformed answer fails the call")
+
+		srv.Inject(Fault{Times: 1, Disconnect: true})
+		_, err = tr.GetNowBlock(t.Context())
+		require.Error(t, err, "a dropped connection fails the call")
+		_, err = tr.GetNowBlock(t.Context())
+		require.NoError(t, err, "the next call reconnects")
+
+		srv.Inject(Fault{Latency: time.Second})
+		ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer cancel()
+		_, err = tr.GetNowBlock(ctx)
+		require.Error(t, err)
+		require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded, "a slow node outlasts the deadline")
+		srv.ClearFaults()
+		_, err = tr.GetNowBlock(t.Context())
+		require.NoError(t, err)
+	})
+}
+
+// newNodeTransport dials node without a Client, so that errors arrive
+// unwrapped by retries and health checking.
+func newNodeTransport(node client.NodeConfig) (client.Transport, error) {
+	if node.Protocol == client.ProtocolHTTP {
+		return client.NewHTTPTransport(node)
+	}
+	return client.NewGRPCTransport(node)
+}
+
+// TestServerFailoverOnStaleHead checks that a node whose head stops moving
+// is evicted by the health checker and the fallback tier takes over.
+func TestServerFailoverOnStaleHead(t *testing.T) {
+	chain := NewChain()
+	primary := newServer(t, chain, ServerConfig{})
+	fallback := newServer(t, chain, ServerConfig{})
+	primaryNode := primary.NodeConfig(client.ProtocolGRPC)
+	fallbackNode := fallback.NodeConfig(client.ProtocolHTTP)
+	fallbackNode.Tier = 1
+	c, err := client.New(client.Config{
+		Nodes: []client.NodeConfig{primaryNode, fallbackNode},
+		Health: client.HealthConfig{
+			HealthyInterval:      10 * time.Millisecond,
+			UnhealthyInterval:    10 * time.Millisecond,
+			InactiveTierInterval: 10 * time.Millisecond,
+			MaxLagBlocks:         2,
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	alice := chain.NewAccount(1_000_000)
+
+	servedBy := func(srv *Server) bool {
+		before := len(srv.Calls())
+		_, err := c.GetAccount(t.Context(), alice.Address)
+		require.NoError(t, err)
+		for _, call := range srv.Calls()[before:] {
+			if call.Method == "GetAccount" {
+				return true
+			}
+		}
+		return false
+	}
+	require.True(t, servedBy(primary), "the primary tier serves while healthy")
+
+	primary.SetHeadLag(5)
+	for range 5 {
+		chain.NextBlock()
+	}
+	require.Eventually(t, func() bool { return servedBy(fallback) }, 5*time.Second, 20*time.Millisecond,
+		"a lagging primary hands over to the fallback")
+
+	primary.SetHeadLag(0)
+	require.Eventually(t, func() bool { return servedBy(primary) }, 5*time.Second, 20*time.Millisecond,
+		"the primary takes over again once it has caught up")
+}
+
+// TestServerFailoverOnOutage checks that calls to a node that answers 503
+// are retried on another node.
+func TestServerFailoverOnOutage(t *testing.T) {
+	chain := NewChain()
+	down := newServer(t, chain, ServerConfig{})
+	up := newServer(t, chain, ServerConfig{})
+	down.Inject(Fault{Status: http.StatusServiceUnavailable})
+	c, err := client.New(client.Config{
+		Nodes: []client.NodeConfig{down.NodeConfig(client.ProtocolHTTP), up.NodeConfig(client.ProtocolHTTP)},
+		Health: client.HealthConfig{
+			Retry: client.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	alice := chain.NewAccount(1_000_000)
+
+	for range 4 {
+		balance, err := c.GetAccountBalance(t.Context(), alice.Address)
+		require.NoError(t, err)
+		assert.Equal(t, client.SUN(1_000_000), balance)
+	}
+}
@@ -0,0 +1,279 @@
+package clienttest
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/sxwebdev/gotron/pkg/tronutils"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+)
+
+// The network totals the chain reports. They are fixed rather than summed
+// over the stakes, so that every staked TRX is worth 1 bandwidth and 10 energy
+// however much else is staked.
+const (
+	freeNetLimit      = 600
+	totalNetLimit     = 43_200_000_000
+	totalNetWeight    = 43_200_000_000
+	totalEnergyLimit  = 180_000_000_000
+	totalEnergyWeight = 18_000_000_000
+)
+
+// stakedResource reports whether code is a resource Stake 2.0 freezes for.
+func stakedResource(code core.ResourceCode) bool {
+	return code == core.ResourceCode_BANDWIDTH || code == core.ResourceCode_ENERGY
+}
+
+// frozen returns the balance account has frozen for code and not delegated.
+func frozen(account *core.Account, code core.ResourceCode) int64 {
+	for _, f := range account.GetFrozenV2() {
+		if f.GetType() == code {
+			return f.GetAmount()
+		}
+	}
+	return 0
+}
+
+// addFrozen adds delta to the balance account has frozen for code.
+func addFrozen(account *core.Account, code core.ResourceCode, delta int64) {
+	for _, f := range account.GetFrozenV2() {
+		if f.GetType() == code {
+			f.Amount += delta
+			return
+		}
+	}
+	account.FrozenV2 = append(account.FrozenV2, &core.Account_FreezeV2{Type: code, Amount: delta})
+}
+
+// delegated returns the balances account has delegated and acquired for
+// code.
+func delegated(account *core.Account, code core.ResourceCode) (out, in *int64) {
+	if code == core.ResourceCode_BANDWIDTH {
+		return &account.DelegatedFrozenV2BalanceForBandwidth, &account.AcquiredDelegatedFrozenV2BalanceForBandwidth
+	}
+	if account.AccountResource == nil {
+		account.AccountResource = &core.Account_AccountResource{}
+	}
+	return &account.AccountResource.DelegatedFrozenV2BalanceForEnergy, &account.AccountResource.AcquiredDelegatedFrozenV2BalanceForEnergy
+}
+
+// delegationKey keys the delegation from one address to another.
+func delegationKey(from, to []byte) string {
+	return string(from) + string(to)
+}
+
+// withdrawExpired credits account with its unfreezes that expired by now and
+// returns their sum.
+func withdrawExpired(account *core.Account, now int64) int64 {
+	var amount int64
+	account.UnfrozenV2 = slices.DeleteFunc(account.UnfrozenV2, func(u *core.Account_UnFreezeV2) bool {
+		if u.GetUnfreezeExpireTime() > now {
+			return false
+		}
+		amount += u.GetUnfreezeAmount()
+		return true
+	})
+	account.Balance += amount
+	return amount
+}
+
+func (s *state) freeze(ct *core.FreezeBalanceV2Contract) (*core.TransactionInfo, error) {
+	account, err := s.owner(ct.GetOwnerAddress())
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case !stakedResource(ct.GetResource()):
+		return nil, invalid("resource must be BANDWIDTH or ENERGY")
+	case ct.GetFrozenBalance() < minStake:
+		return nil, invalid("frozenBalance must be greater than or equal to 1 TRX")
+	case ct.GetFrozenBalance() > account.GetBalance():
+		return nil, invalid("frozenBalance must be less than or equal to accountBalance")
+	}
+	account.Balance -= ct.GetFrozenBalance()
+	addFrozen(account, ct.GetResource(), ct.GetFrozenBalance())
+	return &core.TransactionInfo{}, nil
+}
+
+func (s *state) unfreeze(ct *core.UnfreezeBalanceV2Contract, now int64) (*core.TransactionInfo, error) {
+	account, err := s.owner(ct.GetOwnerAddress())
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case !stakedResource(ct.GetResource()):
+		return nil, invalid("resource must be BANDWIDTH or ENERGY")
+	case ct.GetUnfreezeBalance() <= 0 || ct.GetUnfreezeBalance() > frozen(account, ct.GetResource()):
+		return nil, invalid("invalid unfreeze_balance, [%d] is error", ct.GetUnfreezeBalance())
+	case len(account.GetUnfrozenV2()) >= maxUnfreezing:
+		return nil, invalid("it's not possible to unfreeze more than %d times", maxUnfreezing)
+	}
+	// As on chain, an unfreeze first withdraws the unfreezes that expired.
+	withdrawn := withdrawExpired(account, now)
+	addFrozen(account, ct.GetResource(), -ct.GetUnfreezeBalance())
+	account.UnfrozenV2 = append(account.UnfrozenV2, &core.Account_UnFreezeV2{
+		Type:               ct.GetResource(),
+		UnfreezeAmount:     ct.GetUnfreezeBalance(),
+		UnfreezeExpireTime: now + unfreezeDelay.Milliseconds(),
+	})
+	return &core.TransactionInfo{WithdrawExpireAmount: withdrawn}, nil
+}
+
+func (s *state) withdrawUnfrozen(ct *core.WithdrawExpireUnfreezeContract, now int64) (*core.TransactionInfo, error) {
+	account, err := s.owner(ct.GetOwnerAddress())
+	if err != nil {
+		return nil, err
+	}
+	amount := withdrawExpired(account, now)
+	if amount == 0 {
+		return nil, invalid("no unFreeze balance to withdraw")
+	}
+	return &core.TransactionInfo{WithdrawExpireAmount: amount}, nil
+}
+
+func (s *state) cancelUnfreezes(ct *core.CancelAllUnfreezeV2Contract, now int64) (*core.TransactionInfo, error) {
+	account, err := s.owner(ct.GetOwnerAddress())
+	if err != nil {
+		return nil, err
+	}
+	if len(account.GetUnfrozenV2()) == 0 {
+		return nil, invalid("no unfreezeV2 list to cancel")
+	}
+	withdrawn := withdrawExpired(account, now)
+	canceled := make(map[string]int64)
+	for _, u := range account.GetUnfrozenV2() {
+		addFrozen(account, u.GetType(), u.GetUnfreezeAmount())
+		canceled[u.GetType().String()] += u.GetUnfreezeAmount()
+	}
+	account.UnfrozenV2 = nil
+	return &core.TransactionInfo{WithdrawExpireAmount: withdrawn, CancelUnfreezeV2Amount: canceled}, nil
+}
+
+func (s *state) delegate(ct *core.DelegateResourceContract, now int64) (*core.TransactionInfo, error) {
+	owner, err := s.owner(ct.GetOwnerAddress())
+	if err != nil {
+		return nil, err
+	}
+	receiver, ok := s.accounts[string(ct.GetReceiverAddress())]
+	switch {
+	case !stakedResource(ct.GetResource()):
+		return nil, invalid("resource must be BANDWIDTH or ENERGY")
+	case ct.GetBalance() < minStake:
+		return nil, invalid("delegateBalance must be greater than or equal to 1 TRX")
+	case bytes.Equal(ct.GetOwnerAddress(), ct.GetReceiverAddress()):
+		return nil, invalid("receiverAddress must not be the same as ownerAddress")
+	case !ok:
+		return nil, invalid("account %s does not exist", tronutils.EncodeCheck(ct.GetReceiverAddress()))
+	case ct.GetBalance() > frozen(owner, ct.GetResource()):
+		return nil, invalid("delegateBalance must be less than or equal to available FreezeV2 balance")
+	}
+
+	key := delegationKey(ct.GetOwnerAddress(), ct.GetReceiverAddress())
+	d, ok := s.delegations[key]
+	if !ok {
+		d = &core.DelegatedResource{
+			From: bytes.Clone(ct.GetOwnerAddress()),
+			To:   bytes.Clone(ct.GetReceiverAddress()),
+		}
+		s.delegations[key] = d
+	}
+	var expire int64
+	if ct.GetLock() {
+		period := ct.GetLockPeriod()
+		if period <= 0 {
+			period = defaultLockPeriod
+		}
+		expire = now + period*BlockInterval.Milliseconds()
+	}
+	if ct.GetResource() == core.ResourceCode_BANDWIDTH {
+		d.FrozenBalanceForBandwidth += ct.GetBalance()
+		d.ExpireTimeForBandwidth = max(d.ExpireTimeForBandwidth, expire)
+	} else {
+		d.FrozenBalanceForEnergy += ct.GetBalance()
+		d.ExpireTimeForEnergy = max(d.ExpireTimeForEnergy, expire)
+	}
+
+	addFrozen(owner, ct.GetResource(), -ct.GetBalance())
+	out, _ := delegated(owner, ct.GetResource())
+	*out += ct.GetBalance()
+	_, in := delegated(receiver, ct.GetResource())
+	*in += ct.GetBalance()
+	return &core.TransactionInfo{}, nil
+}
+
+func (s *state) undelegate(ct *core.UnDelegateResourceContract, now int64) (*core.TransactionInfo, error) {
+	owner, err := s.owner(ct.GetOwnerAddress())
+	if err != nil {
+		return nil, err
+	}
+	if !stakedResource(ct.GetResource()) {
+		return nil, invalid("resource must be BANDWIDTH or ENERGY")
+	}
+	key := delegationKey(ct.GetOwnerAddress(), ct.GetReceiverAddress())
+	d, ok := s.delegations[key]
+	if !ok {
+		return nil, invalid("delegated Resource does not exist")
+	}
+	amount, expire := &d.FrozenBalanceForBandwidth, d.GetExpireTimeForBandwidth()
+	if ct.GetResource() == core.ResourceCode_ENERGY {
+		amount, expire = &d.FrozenBalanceForEnergy, d.GetExpireTimeForEnergy()
+	}
+	switch {
+	case ct.GetBalance() <= 0 || ct.GetBalance() > *amount:
+		return nil, invalid("insufficient delegatedFrozenBalance(%s), request=%d, unlock_balance=%d", ct.GetResource(), ct.GetBalance(), *amount)
+	case expire > now:
+		return nil, invalid("the delegation is locked until %d", expire)
+	}
+
+	*amount -= ct.GetBalance()
+	if d.GetFrozenBalanceForBandwidth() == 0 && d.GetFrozenBalanceForEnergy() == 0 {
+		delete(s.delegations, key)
+	}
+	addFrozen(owner, ct.GetResource(), ct.GetBalance())
+	out, _ := delegated(owner, ct.GetResource())
+	*out -= ct.GetBalance()
+	if receiver, ok := s.accounts[string(ct.GetReceiverAddress())]; ok {
+		_, in := delegated(receiver, ct.GetResource())
+		*in -= ct.GetBalance()
+	}
+	return &core.TransactionInfo{}, nil
+}
+
+// resources answers GetAccountResource for address.
+func (s *state) resources(address []byte) *api.AccountResourceMessage {
+	res := &api.AccountResourceMessage{
+		FreeNetLimit:      freeNetLimit,
+		TotalNetLimit:     totalNetLimit,
+		TotalNetWeight:    totalNetWeight,
+		TotalEnergyLimit:  totalEnergyLimit,
+		TotalEnergyWeight: totalEnergyWeight,
+	}
+	account, ok := s.accounts[string(address)]
+	if !ok {
+		return res
+	}
+	bandwidth := frozen(account, core.ResourceCode_BANDWIDTH) + account.GetAcquiredDelegatedFrozenV2BalanceForBandwidth()
+	energy := frozen(account, core.ResourceCode_ENERGY) + account.GetAccountResource().GetAcquiredDelegatedFrozenV2BalanceForEnergy()
+	res.NetLimit = bandwidth / minStake * totalNetLimit / totalNetWeight
+	res.EnergyLimit = energy / minStake * totalEnergyLimit / totalEnergyWeight
+	res.TronPowerLimit = (frozen(account, core.ResourceCode_BANDWIDTH) + frozen(account, core.ResourceCode_ENERGY) +
+		account.GetDelegatedFrozenV2BalanceForBandwidth() + account.GetAccountResource().GetDelegatedFrozenV2BalanceForEnergy()) / minStake
+	return res
+}
+
+// delegationIndex answers GetDelegatedResourceAccountIndexV2 for address.
+func (s *state) delegationIndex(address []byte) *core.DelegatedResourceAccountIndex {
+	index := &core.DelegatedResourceAccountIndex{Account: bytes.Clone(address)}
+	for _, d := range s.delegations {
+		if bytes.Equal(d.GetFrom(), address) {
+			index.ToAccounts = append(index.ToAccounts, bytes.Clone(d.GetTo()))
+		}
+		if bytes.Equal(d.GetTo(), address) {
+			index.FromAccounts = append(index.FromAccounts, bytes.Clone(d.GetFrom()))
+		}
+	}
+	slices.SortFunc(index.ToAccounts, bytes.Compare)
+	slices.SortFunc(index.FromAccounts, bytes.Compare)
+	return index
+}
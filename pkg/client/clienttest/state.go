@@ -0,0 +1,388 @@
+package clienttest
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/sxwebdev/gotron/pkg/client"
+	"github.com/sxwebdev/gotron/pkg/tronutils"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// minStake is the smallest freeze or delegation: 1 TRX.
+	minStake = 1_000_000
+	// unfreezeDelay is getUnfreezeDelayDays.
+	unfreezeDelay = 14 * 24 * time.Hour
+	// maxUnfreezing is how many unfreezes an account may have pending.
+	maxUnfreezing = 32
+	// defaultLockPeriod is the lock, in blocks, of a locked delegation that
+	// names none.
+	defaultLockPeriod = 86400
+	// maxActivePermissions is how many active permissions an account may have.
+	maxActivePermissions = 8
+)
+
+// state is everything a transaction can change. Transactions apply to a clone
+// that replaces the state only when they succeed, so a refused transaction
+// changes nothing.
+type state struct {
+	accounts map[string]*core.Account
+	// delegations holds the Stake 2.0 delegations by delegationKey.
+	delegations map[string]*core.DelegatedResource
+	tokens      map[string]*token
+	// assets holds the issued TRC10 tokens by id.
+	assets map[string]*core.AssetIssueContract
+	// witnesses holds the witnesses that applied, by address, and brokerages
+	// the brokerage those that set one keep.
+	witnesses  map[string]*core.Witness
+	brokerages map[string]int32
+	// exchanges holds the Bancor exchanges by id.
+	exchanges map[int64]*core.Exchange
+}
+
+func newState() *state {
+	return &state{
+		accounts:    make(map[string]*core.Account),
+		delegations: make(map[string]*core.DelegatedResource),
+		tokens:      make(map[string]*token),
+		assets:      make(map[string]*core.AssetIssueContract),
+		witnesses:   make(map[string]*core.Witness),
+		brokerages:  make(map[string]int32),
+		exchanges:   make(map[int64]*core.Exchange),
+	}
+}
+
+// clone returns a deep copy of s.
+func (s *state) clone() *state {
+	c := newState()
+	for k, v := range s.accounts {
+		c.accounts[k] = proto.Clone(v).(*core.Account)
+	}
+	for k, v := range s.delegations {
+		c.delegations[k] = proto.Clone(v).(*core.DelegatedResource)
+	}
+	for k, v := range s.tokens {
+		c.tokens[k] = v.clone()
+	}
+	for k, v := range s.assets {
+		c.assets[k] = proto.Clone(v).(*core.AssetIssueContract)
+	}
+	for k, v := range s.witnesses {
+		c.witnesses[k] = proto.Clone(v).(*core.Witness)
+	}
+	maps.Copy(c.brokerages, s.brokerages)
+	for k, v := range s.exchanges {
+		c.exchanges[k] = proto.Clone(v).(*core.Exchange)
+	}
+	return c
+}
+
+// account returns the account of address, creating it at createTime if it
+// does not exist.
+func (s *state) account(address []byte, createTime int64) *core.Account {
+	account, ok := s.accounts[string(address)]
+	if !ok {
+		account = &core.Account{
+			Address:    bytes.Clone(address),
+			CreateTime: createTime,
+		}
+		s.accounts[string(address)] = account
+	}
+	return account
+}
+
+// owner returns the existing account of address.
+func (s *state) owner(address []byte) (*core.Account, error) {
+	account, ok := s.accounts[string(address)]
+	if !ok {
+		return nil, invalid("account %s does not exist", tronutils.EncodeCheck(address))
+	}
+	return account, nil
+}
+
+// rejection is a refusal of a transaction, with the code a node reports it
+// under.
+type rejection struct {
+	code    api.ReturnResponseCode
+	message string
+}
+
+func (r *rejection) Error() string { return r.message }
+
+// invalid refuses a contract the state cannot apply.
+func invalid(format string, args ...any) error {
+	return &rejection{code: api.Return_CONTRACT_VALIDATE_ERROR, message: fmt.Sprintf(format, args...)}
+}
+
+// denied refuses a transaction whose signatures do not authorize it.
+func denied(format string, args ...any) error {
+	return &rejection{code: api.Return_SIGERROR, message: fmt.Sprintf(format, args...)}
+}
+
+// rejected answers a broadcast with err.
+func rejected(err error) *api.Return {
+	r := err.(*rejection)
+	return &api.Return{Code: r.code, Message: []byte(r.message)}
+}
+
+// refused answers a transaction-building call with err.
+func refused(err error) *api.TransactionExtention {
+	return &api.TransactionExtention{Result: rejected(err)}
+}
+
+// authorize checks signatures against the permission the contract names, as
+// java-tron's TransactionCapsule.checkPermission and validateSignature do.
+func (s *state) authorize(contract *core.Transaction_Contract, message proto.Message, id []byte, signatures [][]byte) error {
+	owned, ok := message.(interface{ GetOwnerAddress() []byte })
+	if !ok {
+		return invalid("%s has no owner", contract.GetType())
+	}
+	account, ok := s.accounts[string(owned.GetOwnerAddress())]
+	if !ok {
+		return denied("account %s does not exist", tronutils.EncodeCheck(owned.GetOwnerAddress()))
+	}
+
+	permissionID := contract.GetPermissionId()
+	permission := permissionByID(account, permissionID)
+	if permission == nil {
+		return denied("permission %d does not exist", permissionID)
+	}
+	if permissionID != client.OwnerPermissionID && permission.GetType() != core.Permission_Active {
+		return denied("permission %d is not an active permission", permissionID)
+	}
+	if !client.PermissionAllows(permission, contract.GetType()) {
+		return denied("permission %d does not allow %s", permissionID, contract.GetType())
+	}
+	if len(signatures) == 0 {
+		return denied("transaction is not signed")
+	}
+	if len(signatures) > len(permission.GetKeys()) {
+		return denied("%d signatures for %d keys", len(signatures), len(permission.GetKeys()))
+	}
+
+	var weight int64
+	signed := make(map[string]bool)
+	for _, signature := range signatures {
+		pub, err := crypto.SigToPub(id, signature)
+		if err != nil {
+			return denied("invalid signature: %v", err)
+		}
+		signer := tronutils.PubkeyToAddress(*pub)
+		if signed[string(signer)] {
+			return denied("%s signed twice", signer)
+		}
+		signed[string(signer)] = true
+		key := keyOf(permission, signer)
+		if key == nil {
+			return denied("%s is not a key of permission %d", signer, permissionID)
+		}
+		weight += key.GetWeight()
+	}
+	if weight < permission.GetThreshold() {
+		return denied("signature weight %d is below permission %d threshold %d", weight, permissionID, permission.GetThreshold())
+	}
+	return nil
+}
+
+// permissionByID returns the permission of account with id. An account that
+// never updated its permissions is owned by its own address.
+func permissionByID(account *core.Account, id int32) *core.Permission {
+	switch id {
+	case client.OwnerPermissionID:
+		if account.GetOwnerPermission() != nil {
+			return account.GetOwnerPermission()
+		}
+		return &core.Permission{
+			Type:      core.Permission_Owner,
+			Threshold: 1,
+			Keys:      []*core.Key{{Address: account.GetAddress(), Weight: 1}},
+		}
+	case client.WitnessPermissionID:
+		return account.GetWitnessPermission()
+	}
+	for _, permission := range account.GetActivePermission() {
+		if permission.GetId() == id {
+			return permission
+		}
+	}
+	return nil
+}
+
+// keyOf returns the key of permission for address.
+func keyOf(permission *core.Permission, address []byte) *core.Key {
+	for _, key := range permission.GetKeys() {
+		if bytes.Equal(key.GetAddress(), address) {
+			return key
+		}
+	}
+	return nil
+}
+
+// apply validates contract against s and applies it, at block time now in
+// milliseconds. The info it returns lacks the id and the block.
+func (s *state) apply(contract proto.Message, now int64) (*core.TransactionInfo, error) {
+	switch ct := contract.(type) {
+	case *core.TransferContract:
+		return s.transfer(ct, now)
+	case *core.TransferAssetContract:
+		return s.transferAsset(ct, now)
+	case *core.AssetIssueContract:
+		return s.issueAsset(ct, now)
+	case *core.ParticipateAssetIssueContract:
+		return s.participateAsset(ct, now)
+	case *core.UpdateAssetContract:
+		return s.updateAsset(ct)
+	case *core.UnfreezeAssetContract:
+		return s.unfreezeAsset(ct, now)
+	case *core.AccountCreateContract:
+		return s.createAccount(ct, now)
+	case *core.WitnessCreateContract:
+		return s.createWitness(ct)
+	case *core.WitnessUpdateContract:
+		return s.updateWitness(ct)
+	case *core.UpdateBrokerageContract:
+		return s.updateBrokerage(ct)
+	case *core.ExchangeCreateContract:
+		return s.createExchange(ct, now)
+	case *core.ExchangeInjectContract:
+		return s.injectExchange(ct)
+	case *core.ExchangeWithdrawContract:
+		return s.withdrawExchange(ct)
+	case *core.ExchangeTransactionContract:
+		return s.tradeExchange(ct)
+	case *core.AccountPermissionUpdateContract:
+		return s.updatePermissions(ct)
+	case *core.FreezeBalanceV2Contract:
+		return s.freeze(ct)
+	case *core.UnfreezeBalanceV2Contract:
+		return s.unfreeze(ct, now)
+	case *core.WithdrawExpireUnfreezeContract:
+		return s.withdrawUnfrozen(ct, now)
+	case *core.CancelAllUnfreezeV2Contract:
+		return s.cancelUnfreezes(ct, now)
+	case *core.DelegateResourceContract:
+		return s.delegate(ct, now)
+	case *core.UnDelegateResourceContract:
+		return s.undelegate(ct, now)
+	case *core.TriggerSmartContract:
+		return s.trigger(ct, false)
+	}
+	return nil, invalid("%T is not simulated", contract)
+}
+
+func (s *state) transfer(ct *core.TransferContract, now int64) (*core.TransactionInfo, error) {
+	owner, err := s.owner(ct.GetOwnerAddress())
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case ct.GetAmount() <= 0:
+		return nil, invalid("amount must be greater than 0")
+	case bytes.Equal(ct.GetOwnerAddress(), ct.GetToAddress()):
+		return nil, invalid("cannot transfer TRX to yourself")
+	case len(ct.GetToAddress()) != tronutils.AddressLength:
+		return nil, invalid("invalid to address")
+	case owner.GetBalance() < ct.GetAmount():
+		return nil, invalid("balance is not sufficient")
+	}
+	owner.Balance -= ct.GetAmount()
+	s.account(ct.GetToAddress(), now).Balance += ct.GetAmount()
+	return &core.TransactionInfo{}, nil
+}
+
+// transferAsset moves a TRC10 balance. The token need not have been issued
+// with CreateAssetIssue: FundAsset credits balances without one.
+func (s *state) transferAsset(ct *core.TransferAssetContract, now int64) (*core.TransactionInfo, error) {
+	owner, err := s.owner(ct.GetOwnerAddress())
+	if err != nil {
+		return nil, err
+	}
+	id := string(ct.GetAssetName())
+	balance, held := owner.GetAssetV2()[id]
+	switch {
+	case ct.GetAmount() <= 0:
+		return nil, invalid("amount must be greater than 0")
+	case bytes.Equal(ct.GetOwnerAddress(), ct.GetToAddress()):
+		return nil, invalid("cannot transfer asset to yourself")
+	case len(ct.GetToAddress()) != tronutils.AddressLength:
+		return nil, invalid("invalid to address")
+	case !held:
+		return nil, invalid("owner has no asset")
+	case balance < ct.GetAmount():
+		return nil, invalid("assetBalance is not sufficient")
+	}
+	owner.AssetV2[id] -= ct.GetAmount()
+	to := s.account(ct.GetToAddress(), now)
+	if to.AssetV2 == nil {
+		to.AssetV2 = make(map[string]int64)
+	}
+	to.AssetV2[id] += ct.GetAmount()
+	return &core.TransactionInfo{}, nil
+}
+
+func (s *state) createAccount(ct *core.AccountCreateContract, now int64) (*core.TransactionInfo, error) {
+	if _, err := s.owner(ct.GetOwnerAddress()); err != nil {
+		return nil, err
+	}
+	if len(ct.GetAccountAddress()) != tronutils.AddressLength {
+		return nil, invalid("invalid account address")
+	}
+	if _, ok := s.accounts[string(ct.GetAccountAddress())]; ok {
+		return nil, invalid("account has existed")
+	}
+	s.account(ct.GetAccountAddress(), now).Type = ct.GetType()
+	return &core.TransactionInfo{}, nil
+}
+
+func (s *state) updatePermissions(ct *core.AccountPermissionUpdateContract) (*core.TransactionInfo, error) {
+	account, err := s.owner(ct.GetOwnerAddress())
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case ct.GetOwner() == nil:
+		return nil, invalid("owner permission is missed")
+	case len(ct.GetActives()) == 0:
+		return nil, invalid("active permission is missed")
+	case len(ct.GetActives()) > maxActivePermissions:
+		return nil, invalid("active permission is too many")
+	case ct.GetWitness() != nil && !account.GetIsWitness():
+		return nil, invalid("account isn't witness can't set witness permission")
+	}
+	permissions := append([]*core.Permission{ct.GetOwner(), ct.GetWitness()}, ct.GetActives()...)
+	for _, permission := range permissions {
+		if permission == nil {
+			continue
+		}
+		var weight int64
+		for _, key := range permission.GetKeys() {
+			weight += key.GetWeight()
+		}
+		if len(permission.GetKeys()) == 0 || permission.GetThreshold() <= 0 || weight < permission.GetThreshold() {
+			return nil, invalid("permission %q: the keys' weight must reach a positive threshold", permission.GetPermissionName())
+		}
+	}
+
+	account.OwnerPermission = proto.Clone(ct.GetOwner()).(*core.Permission)
+	account.OwnerPermission.Type = core.Permission_Owner
+	account.OwnerPermission.Id = client.OwnerPermissionID
+	if ct.GetWitness() != nil {
+		account.WitnessPermission = proto.Clone(ct.GetWitness()).(*core.Permission)
+		account.WitnessPermission.Type = core.Permission_Witness
+		account.WitnessPermission.Id = client.WitnessPermissionID
+	}
+	account.ActivePermission = nil
+	for i, active := range ct.GetActives() {
+		permission := proto.Clone(active).(*core.Permission)
+		permission.Type = core.Permission_Active
+		permission.Id = client.FirstActivePermissionID + int32(i)
+		account.ActivePermission = append(account.ActivePermission, permission)
+	}
+	return &core.TransactionInfo{}, nil
+}
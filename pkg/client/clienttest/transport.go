@@ -0,0 +1,581 @@
+package clienttest
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/protobuf/proto"
+)
+
+// Every method answers with a copy of the chain's data, so a caller can change
+// what it gets back. Lookups of something the chain does not have answer with
+// an empty message, as a node does.
+
+// Account operations
+
+func (c *Chain) GetAccount(_ context.Context, account *core.Account) (*core.Account, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if a, ok := c.state.accounts[string(account.GetAddress())]; ok {
+		return proto.Clone(a).(*core.Account), nil
+	}
+	return &core.Account{}, nil
+}
+
+func (c *Chain) GetAccountResource(_ context.Context, account *core.Account) (*api.AccountResourceMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state.resources(account.GetAddress()), nil
+}
+
+func (c *Chain) CreateAccount(_ context.Context, contract *core.AccountCreateContract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_AccountCreateContract, contract)
+}
+
+func (c *Chain) AccountPermissionUpdate(_ context.Context, contract *core.AccountPermissionUpdateContract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_AccountPermissionUpdateContract, contract)
+}
+
+// Block operations
+
+func (c *Chain) GetNowBlock(context.Context) (*api.BlockExtention, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return proto.Clone(c.head()).(*api.BlockExtention), nil
+}
+
+func (c *Chain) GetBlockByNum(_ context.Context, num int64) (*api.BlockExtention, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if num < 0 || num >= int64(len(c.blocks)) {
+		return &api.BlockExtention{}, nil
+	}
+	return proto.Clone(c.blocks[num]).(*api.BlockExtention), nil
+}
+
+func (c *Chain) GetBlockById(_ context.Context, id []byte) (*core.Block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, block := range c.blocks {
+		if string(block.GetBlockid()) != string(id) {
+			continue
+		}
+		b := &core.Block{BlockHeader: proto.Clone(block.GetBlockHeader()).(*core.BlockHeader)}
+		for _, tx := range block.GetTransactions() {
+			b.Transactions = append(b.Transactions, proto.Clone(tx.GetTransaction()).(*core.Transaction))
+		}
+		return b, nil
+	}
+	return &core.Block{}, nil
+}
+
+func (c *Chain) GetBlockByLimitNext(_ context.Context, start, end int64) (*api.BlockListExtention, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	list := &api.BlockListExtention{}
+	for num := max(start, 0); num < min(end, int64(len(c.blocks))); num++ {
+		list.Block = append(list.Block, proto.Clone(c.blocks[num]).(*api.BlockExtention))
+	}
+	return list, nil
+}
+
+func (c *Chain) GetBlockByLatestNum(_ context.Context, num int64) (*api.BlockListExtention, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	list := &api.BlockListExtention{}
+	for n := max(int64(len(c.blocks))-num, 0); n < int64(len(c.blocks)); n++ {
+		list.Block = append(list.Block, proto.Clone(c.blocks[n]).(*api.BlockExtention))
+	}
+	return list, nil
+}
+
+func (c *Chain) GetTransactionInfoByBlockNum(_ context.Context, num int64) (*api.TransactionInfoList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	list := &api.TransactionInfoList{}
+	if num < 0 || num >= int64(len(c.blocks)) {
+		return list, nil
+	}
+	for _, tx := range c.blocks[num].GetTransactions() {
+		list.TransactionInfo = append(list.TransactionInfo, proto.Clone(c.txs[string(tx.GetTxid())].info).(*core.TransactionInfo))
+	}
+	return list, nil
+}
+
+// Transaction operations
+
+func (c *Chain) GetTransactionById(_ context.Context, id []byte) (*core.Transaction, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.txs[string(id)]; ok && e.sealed {
+		return proto.Clone(e.tx).(*core.Transaction), nil
+	}
+	return &core.Transaction{}, nil
+}
+
+func (c *Chain) GetTransactionInfoById(_ context.Context, id []byte) (*core.TransactionInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.txs[string(id)]; ok && e.sealed {
+		return proto.Clone(e.info).(*core.TransactionInfo), nil
+	}
+	return &core.TransactionInfo{}, nil
+}
+
+func (c *Chain) BroadcastTransaction(_ context.Context, tx *core.Transaction) (*api.Return, error) {
+	return c.broadcast(tx), nil
+}
+
+func (c *Chain) CreateTransaction(_ context.Context, contract *core.TransferContract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_TransferContract, contract)
+}
+
+// Smart contract operations
+
+func (c *Chain) TriggerContract(_ context.Context, contract *core.TriggerSmartContract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_TriggerSmartContract, contract)
+}
+
+func (c *Chain) TriggerConstantContract(_ context.Context, contract *core.TriggerSmartContract) (*api.TransactionExtention, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	info, err := c.state.clone().trigger(contract, true)
+	if err != nil {
+		return refused(err), nil
+	}
+	tx, err := c.transaction(core.Transaction_Contract_TriggerSmartContract, contract)
+	if err != nil {
+		return nil, err
+	}
+	return &api.TransactionExtention{
+		Transaction:    tx,
+		Txid:           txID(tx.GetRawData()),
+		ConstantResult: info.GetContractResult(),
+		EnergyUsed:     info.GetReceipt().GetEnergyUsageTotal(),
+		Result:         &api.Return{Result: true, Message: info.GetResMessage()},
+	}, nil
+}
+
+func (c *Chain) EstimateEnergy(_ context.Context, contract *core.TriggerSmartContract) (*api.EstimateEnergyMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	info, err := c.state.clone().trigger(contract, true)
+	if err != nil {
+		return &api.EstimateEnergyMessage{Result: rejected(err)}, nil
+	}
+	if info.GetResult() == core.TransactionInfo_FAILED {
+		return &api.EstimateEnergyMessage{Result: &api.Return{Code: api.Return_CONTRACT_EXE_ERROR, Message: info.GetResMessage()}}, nil
+	}
+	return &api.EstimateEnergyMessage{
+		Result:         &api.Return{Result: true},
+		EnergyRequired: info.GetReceipt().GetEnergyUsageTotal(),
+	}, nil
+}
+
+func (c *Chain) DeployContract(context.Context, *core.CreateSmartContract) (*api.TransactionExtention, error) {
+	return nil, unsupported("DeployContract")
+}
+
+func (c *Chain) GetContract(_ context.Context, address []byte) (*core.SmartContract, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t, ok := c.state.tokens[string(address)]
+	if !ok {
+		return &core.SmartContract{}, nil
+	}
+	return &core.SmartContract{
+		OriginAddress:              t.origin,
+		ContractAddress:            t.address,
+		Name:                       t.Name,
+		ConsumeUserResourcePercent: 100,
+	}, nil
+}
+
+func (c *Chain) UpdateSetting(context.Context, *core.UpdateSettingContract) (*api.TransactionExtention, error) {
+	return nil, unsupported("UpdateSetting")
+}
+
+func (c *Chain) UpdateEnergyLimit(context.Context, *core.UpdateEnergyLimitContract) (*api.TransactionExtention, error) {
+	return nil, unsupported("UpdateEnergyLimit")
+}
+
+// Resource operations
+
+func (c *Chain) GetAccountResourceMessage(ctx context.Context, account *core.Account) (*api.AccountResourceMessage, error) {
+	return c.GetAccountResource(ctx, account)
+}
+
+// GetDelegatedResource answers for Stake 1.0, under which nothing is ever
+// delegated on this chain.
+func (c *Chain) GetDelegatedResource(context.Context, *api.DelegatedResourceMessage) (*api.DelegatedResourceList, error) {
+	return &api.DelegatedResourceList{}, nil
+}
+
+func (c *Chain) GetDelegatedResourceV2(_ context.Context, msg *api.DelegatedResourceMessage) (*api.DelegatedResourceList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	list := &api.DelegatedResourceList{}
+	if d, ok := c.state.delegations[delegationKey(msg.GetFromAddress(), msg.GetToAddress())]; ok {
+		list.DelegatedResource = []*core.DelegatedResource{proto.Clone(d).(*core.DelegatedResource)}
+	}
+	return list, nil
+}
+
+// GetDelegatedResourceAccountIndex answers for Stake 1.0, under which nothing
+// is ever delegated on this chain.
+func (c *Chain) GetDelegatedResourceAccountIndex(_ context.Context, address []byte) (*core.DelegatedResourceAccountIndex, error) {
+	return &core.DelegatedResourceAccountIndex{Account: address}, nil
+}
+
+func (c *Chain) GetDelegatedResourceAccountIndexV2(_ context.Context, address []byte) (*core.DelegatedResourceAccountIndex, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state.delegationIndex(address), nil
+}
+
+func (c *Chain) GetCanDelegatedMaxSize(_ context.Context, msg *api.CanDelegatedMaxSizeRequestMessage) (*api.CanDelegatedMaxSizeResponseMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	account := c.state.accounts[string(msg.GetOwnerAddress())]
+	return &api.CanDelegatedMaxSizeResponseMessage{
+		MaxSize: frozen(account, core.ResourceCode(msg.GetType())),
+	}, nil
+}
+
+func (c *Chain) DelegateResource(_ context.Context, contract *core.DelegateResourceContract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_DelegateResourceContract, contract)
+}
+
+func (c *Chain) UnDelegateResource(_ context.Context, contract *core.UnDelegateResourceContract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_UnDelegateResourceContract, contract)
+}
+
+// Stake 2.0 operations
+
+func (c *Chain) FreezeBalanceV2(_ context.Context, contract *core.FreezeBalanceV2Contract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_FreezeBalanceV2Contract, contract)
+}
+
+func (c *Chain) UnfreezeBalanceV2(_ context.Context, contract *core.UnfreezeBalanceV2Contract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_UnfreezeBalanceV2Contract, contract)
+}
+
+func (c *Chain) WithdrawExpireUnfreeze(_ context.Context, contract *core.WithdrawExpireUnfreezeContract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_WithdrawExpireUnfreezeContract, contract)
+}
+
+func (c *Chain) CancelAllUnfreezeV2(_ context.Context, contract *core.CancelAllUnfreezeV2Contract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_CancelAllUnfreezeV2Contract, contract)
+}
+
+func (c *Chain) GetAvailableUnfreezeCount(_ context.Context, msg *api.GetAvailableUnfreezeCountRequestMessage) (*api.GetAvailableUnfreezeCountResponseMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	account := c.state.accounts[string(msg.GetOwnerAddress())]
+	return &api.GetAvailableUnfreezeCountResponseMessage{
+		Count: int64(maxUnfreezing - len(account.GetUnfrozenV2())),
+	}, nil
+}
+
+// GetCanWithdrawUnfreezeAmount answers at the request's timestamp, or at the
+// head block when that is later: after AdvanceTime the chain's clock runs
+// ahead of the wall clock the Client stamps its requests with.
+func (c *Chain) GetCanWithdrawUnfreezeAmount(_ context.Context, msg *api.CanWithdrawUnfreezeAmountRequestMessage) (*api.CanWithdrawUnfreezeAmountResponseMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	at := max(msg.GetTimestamp(), c.headTime())
+	var amount int64
+	for _, u := range c.state.accounts[string(msg.GetOwnerAddress())].GetUnfrozenV2() {
+		if u.GetUnfreezeExpireTime() <= at {
+			amount += u.GetUnfreezeAmount()
+		}
+	}
+	return &api.CanWithdrawUnfreezeAmountResponseMessage{Amount: amount}, nil
+}
+
+// Witness operations
+
+func (c *Chain) VoteWitnessAccount(context.Context, *core.VoteWitnessContract) (*api.TransactionExtention, error) {
+	return nil, unsupported("VoteWitnessAccount")
+}
+
+func (c *Chain) WithdrawBalance(context.Context, *core.WithdrawBalanceContract) (*api.TransactionExtention, error) {
+	return nil, unsupported("WithdrawBalance")
+}
+
+func (c *Chain) ListWitnesses(context.Context) (*api.WitnessList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &api.WitnessList{Witnesses: c.witnesses()}, nil
+}
+
+// witnesses returns the block producer, then the witnesses that applied.
+// Votes are not simulated, so none of them has any.
+func (c *Chain) witnesses() []*core.Witness {
+	producer := &core.Witness{
+		Address:        witnessAddress,
+		Url:            "clienttest",
+		TotalProduced:  int64(len(c.blocks)),
+		LatestBlockNum: int64(len(c.blocks)) - 1,
+		IsJobs:         true,
+	}
+	return append([]*core.Witness{producer}, c.state.candidates()...)
+}
+
+func (c *Chain) GetRewardInfo(context.Context, []byte) (*api.NumberMessage, error) {
+	return &api.NumberMessage{}, nil
+}
+
+func (c *Chain) GetBrokerageInfo(_ context.Context, address []byte) (*api.NumberMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &api.NumberMessage{Num: c.state.brokerage(address)}, nil
+}
+
+func (c *Chain) CreateWitness(_ context.Context, contract *core.WitnessCreateContract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_WitnessCreateContract, contract)
+}
+
+func (c *Chain) UpdateWitness(_ context.Context, contract *core.WitnessUpdateContract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_WitnessUpdateContract, contract)
+}
+
+func (c *Chain) UpdateBrokerage(_ context.Context, contract *core.UpdateBrokerageContract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_UpdateBrokerageContract, contract)
+}
+
+// GetPaginatedNowWitnessList ranks the witnesses by vote count, ties in the
+// order ListWitnesses gives, and pages them as GetPaginatedAssetIssueList does.
+func (c *Chain) GetPaginatedNowWitnessList(_ context.Context, msg *api.PaginatedMessage) (*api.WitnessList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	list := c.witnesses()
+	slices.SortStableFunc(list, func(a, b *core.Witness) int {
+		return cmp.Compare(b.GetVoteCount(), a.GetVoteCount())
+	})
+	offset, limit := msg.GetOffset(), min(msg.GetLimit(), maxWitnessPage)
+	if offset < 0 || limit < 0 || offset >= int64(len(list)) {
+		return &api.WitnessList{}, nil
+	}
+	return &api.WitnessList{Witnesses: list[offset:min(offset+limit, int64(len(list)))]}, nil
+}
+
+// Asset operations
+
+// GetAssetIssueById answers with an empty message for a token FundAsset
+// credited without issuing it.
+func (c *Chain) GetAssetIssueById(_ context.Context, id []byte) (*core.AssetIssueContract, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if asset, ok := c.state.assets[string(id)]; ok {
+		return proto.Clone(asset).(*core.AssetIssueContract), nil
+	}
+	return &core.AssetIssueContract{}, nil
+}
+
+func (c *Chain) GetAssetIssueListByName(_ context.Context, name []byte) (*api.AssetIssueList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &api.AssetIssueList{AssetIssue: c.state.assetIssues(func(asset *core.AssetIssueContract) bool {
+		return string(asset.GetName()) == string(name)
+	})}, nil
+}
+
+func (c *Chain) GetAssetIssueByAccount(_ context.Context, address []byte) (*api.AssetIssueList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &api.AssetIssueList{AssetIssue: c.state.assetIssues(func(asset *core.AssetIssueContract) bool {
+		return string(asset.GetOwnerAddress()) == string(address)
+	})}, nil
+}
+
+func (c *Chain) GetAssetIssueList(context.Context) (*api.AssetIssueList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &api.AssetIssueList{AssetIssue: c.state.assetIssues(nil)}, nil
+}
+
+// GetPaginatedAssetIssueList answers a page past the end, or one with a
+// negative bound, with an empty list, and caps a page at 1000 assets.
+func (c *Chain) GetPaginatedAssetIssueList(_ context.Context, msg *api.PaginatedMessage) (*api.AssetIssueList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	list := c.state.assetIssues(nil)
+	offset, limit := msg.GetOffset(), min(msg.GetLimit(), maxAssetPage)
+	if offset < 0 || limit < 0 || offset >= int64(len(list)) {
+		return &api.AssetIssueList{}, nil
+	}
+	return &api.AssetIssueList{AssetIssue: list[offset:min(offset+limit, int64(len(list)))]}, nil
+}
+
+func (c *Chain) TransferAsset(_ context.Context, contract *core.TransferAssetContract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_TransferAssetContract, contract)
+}
+
+func (c *Chain) CreateAssetIssue(_ context.Context, contract *core.AssetIssueContract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_AssetIssueContract, contract)
+}
+
+func (c *Chain) ParticipateAssetIssue(_ context.Context, contract *core.ParticipateAssetIssueContract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_ParticipateAssetIssueContract, contract)
+}
+
+func (c *Chain) UpdateAsset(_ context.Context, contract *core.UpdateAssetContract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_UpdateAssetContract, contract)
+}
+
+func (c *Chain) UnfreezeAsset(_ context.Context, contract *core.UnfreezeAssetContract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_UnfreezeAssetContract, contract)
+}
+
+// Proposal operations
+//
+// Only an active witness may propose or vote. The chain's block producer has
+// no key to sign with, and the witnesses that apply never become active since
+// votes are not simulated, so no proposal is ever made: the writes are refused
+// and the reads find nothing.
+
+func (c *Chain) ProposalCreate(context.Context, *core.ProposalCreateContract) (*api.TransactionExtention, error) {
+	return nil, unsupported("ProposalCreate")
+}
+
+func (c *Chain) ProposalApprove(context.Context, *core.ProposalApproveContract) (*api.TransactionExtention, error) {
+	return nil, unsupported("ProposalApprove")
+}
+
+func (c *Chain) ProposalDelete(context.Context, *core.ProposalDeleteContract) (*api.TransactionExtention, error) {
+	return nil, unsupported("ProposalDelete")
+}
+
+func (c *Chain) ListProposals(context.Context) (*api.ProposalList, error) {
+	return &api.ProposalList{}, nil
+}
+
+func (c *Chain) GetPaginatedProposalList(context.Context, *api.PaginatedMessage) (*api.ProposalList, error) {
+	return &api.ProposalList{}, nil
+}
+
+func (c *Chain) GetProposalById(context.Context, int64) (*core.Proposal, error) {
+	return &core.Proposal{}, nil
+}
+
+// Exchange operations
+
+func (c *Chain) ExchangeCreate(_ context.Context, contract *core.ExchangeCreateContract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_ExchangeCreateContract, contract)
+}
+
+func (c *Chain) ExchangeInject(_ context.Context, contract *core.ExchangeInjectContract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_ExchangeInjectContract, contract)
+}
+
+func (c *Chain) ExchangeWithdraw(_ context.Context, contract *core.ExchangeWithdrawContract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_ExchangeWithdrawContract, contract)
+}
+
+func (c *Chain) ExchangeTransaction(_ context.Context, contract *core.ExchangeTransactionContract) (*api.TransactionExtention, error) {
+	return c.build(core.Transaction_Contract_ExchangeTransactionContract, contract)
+}
+
+func (c *Chain) ListExchanges(context.Context) (*api.ExchangeList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &api.ExchangeList{Exchanges: c.state.exchangeList()}, nil
+}
+
+func (c *Chain) GetPaginatedExchangeList(_ context.Context, msg *api.PaginatedMessage) (*api.ExchangeList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	list := c.state.exchangeList()
+	offset, limit := msg.GetOffset(), min(msg.GetLimit(), maxExchangePage)
+	if offset < 0 || limit < 0 || offset >= int64(len(list)) {
+		return &api.ExchangeList{}, nil
+	}
+	return &api.ExchangeList{Exchanges: list[offset:min(offset+limit, int64(len(list)))]}, nil
+}
+
+// GetExchangeById answers an unknown id with an empty exchange, as a node
+// does.
+func (c *Chain) GetExchangeById(_ context.Context, id int64) (*core.Exchange, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	exchange, ok := c.state.exchanges[id]
+	if !ok {
+		return &core.Exchange{}, nil
+	}
+	return proto.Clone(exchange).(*core.Exchange), nil
+}
+
+// Network operations
+
+func (c *Chain) ListNodes(context.Context) (*api.NodeList, error) {
+	return &api.NodeList{}, nil
+}
+
+func (c *Chain) GetNodeInfo(context.Context) (*core.NodeInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	head := c.head()
+	return &core.NodeInfo{
+		BeginSyncNum: head.GetBlockHeader().GetRawData().GetNumber(),
+		Block:        fmt.Sprintf("Num:%d,ID:%x", head.GetBlockHeader().GetRawData().GetNumber(), head.GetBlockid()),
+	}, nil
+}
+
+func (c *Chain) GetChainParameters(context.Context) (*core.ChainParameters, error) {
+	return &core.ChainParameters{ChainParameter: []*core.ChainParameters_ChainParameter{
+		{Key: "getMaintenanceTimeInterval", Value: maintenanceInterval.Milliseconds()},
+		{Key: "getAccountUpgradeCost", Value: 9_999_000_000},
+		{Key: "getCreateAccountFee", Value: 100_000},
+		{Key: "getTransactionFee", Value: 1000},
+		{Key: "getAssetIssueFee", Value: 1_024_000_000},
+		{Key: "getExchangeCreateFee", Value: 1_024_000_000},
+		{Key: "getWitnessPayPerBlock", Value: 16_000_000},
+		{Key: "getWitnessStandbyAllowance", Value: 115_200_000_000},
+		{Key: "getCreateNewAccountFeeInSystemContract", Value: 1_000_000},
+		{Key: "getCreateNewAccountBandwidthRate", Value: 1},
+		{Key: "getEnergyFee", Value: 100},
+		{Key: "getFreeNetLimit", Value: freeNetLimit},
+		{Key: "getTotalNetLimit", Value: totalNetLimit},
+		{Key: "getTotalEnergyLimit", Value: totalEnergyLimit},
+		{Key: "getTotalEnergyCurrentLimit", Value: totalEnergyLimit},
+		{Key: "getUpdateAccountPermissionFee", Value: 100_000_000},
+		{Key: "getMultiSignFee", Value: 1_000_000},
+		{Key: "getAllowMultiSign", Value: 1},
+		{Key: "getAllowDelegateResource", Value: 1},
+		{Key: "getUnfreezeDelayDays", Value: int64(unfreezeDelay.Hours() / 24)},
+		{Key: "getMemoFee", Value: 1_000_000},
+	}}, nil
+}
+
+func (c *Chain) GetNextMaintenanceTime(context.Context) (*api.NumberMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	interval := maintenanceInterval.Milliseconds()
+	elapsed := c.headTime() - c.genesis.UnixMilli()
+	return &api.NumberMessage{Num: c.genesis.UnixMilli() + (elapsed/interval+1)*interval}, nil
+}
+
+func (c *Chain) TotalTransaction(context.Context) (*api.NumberMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var total int64
+	for _, block := range c.blocks {
+		total += int64(len(block.GetTransactions()))
+	}
+	return &api.NumberMessage{Num: total}, nil
+}
+
+// Close does nothing: the chain holds no resources.
+func (c *Chain) Close() error {
+	return nil
+}
+
+// unsupported is the error of a method the chain does not simulate.
+func unsupported(method string) error {
+	return fmt.Errorf("%w: %s", ErrUnsupported, method)
+}
@@ -0,0 +1,281 @@
+package clienttest
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"maps"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sxwebdev/gotron/pkg/client"
+	"github.com/sxwebdev/gotron/pkg/tronutils"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+)
+
+// Function selectors of the TRC20 calls a token answers, and the topics of
+// its events.
+const (
+	selectorName         = "06fdde03" // name()
+	selectorSymbol       = "95d89b41" // symbol()
+	selectorDecimals     = "313ce567" // decimals()
+	selectorTotalSupply  = "18160ddd" // totalSupply()
+	selectorBalanceOf    = "70a08231" // balanceOf(address)
+	selectorAllowance    = "dd62ed3e" // allowance(address,address)
+	selectorTransfer     = "a9059cbb" // transfer(address,uint256)
+	selectorApprove      = "095ea7b3" // approve(address,uint256)
+	selectorTransferFrom = "23b872dd" // transferFrom(address,address,uint256)
+
+	topicApproval = "8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"
+)
+
+// TokenCallEnergy is the energy every state-changing token call reports
+// using. Reads report none.
+const TokenCallEnergy = 14650
+
+// revertMessage is how a node reports a reverted call.
+const revertMessage = "REVERT opcode executed"
+
+// TRC20 describes a token for DeployTRC20.
+type TRC20 struct {
+	Name     string
+	Symbol   string
+	Decimals uint8
+	// Owner, a base58check address, is reported as the contract's origin.
+	// Left empty, the contract has no origin and its callers pay for every
+	// call.
+	Owner string
+}
+
+// token is the ledger of a TRC20 contract.
+type token struct {
+	TRC20
+	address    []byte
+	origin     []byte
+	supply     *big.Int
+	balances   map[string]*big.Int
+	allowances map[string]*big.Int
+}
+
+// clone returns a copy of t. Amounts are replaced rather than changed in
+// place, so the maps can share them.
+func (t *token) clone() *token {
+	c := *t
+	c.balances = maps.Clone(t.balances)
+	c.allowances = maps.Clone(t.allowances)
+	return &c
+}
+
+// DeployTRC20 registers a token that keeps its ledger in the chain, and
+// returns its contract address. The token answers name, symbol, decimals,
+// totalSupply, balanceOf, allowance, transfer, approve and transferFrom, and
+// reverts any other call.
+func (c *Chain) DeployTRC20(token TRC20) string {
+	var origin []byte
+	if token.Owner != "" {
+		origin = mustDecode(token.Owner)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.deployed++
+	seed := binary.BigEndian.AppendUint64([]byte("clienttest token"), c.deployed)
+	address := append([]byte{tronutils.TronBytePrefix}, tronutils.Keccak256(seed)[12:]...)
+	c.state.tokens[string(address)] = newToken(token, address, origin)
+	return tronutils.EncodeCheck(address)
+}
+
+func newToken(desc TRC20, address, origin []byte) *token {
+	return &token{
+		TRC20:      desc,
+		address:    address,
+		origin:     origin,
+		supply:     new(big.Int),
+		balances:   make(map[string]*big.Int),
+		allowances: make(map[string]*big.Int),
+	}
+}
+
+// MintTRC20 credits amount of the token at contract to address and adds it
+// to the total supply. It panics when no token was deployed at contract.
+func (c *Chain) MintTRC20(contract, address string, amount client.TokenAmount) {
+	to := mustDecode(address)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := c.token(contract)
+	t.supply = new(big.Int).Add(t.supply, amount.TokenUnits())
+	t.balances[string(to)] = new(big.Int).Add(t.balance(to), amount.TokenUnits())
+}
+
+// TRC20Balance returns the balance of address in the token at contract.
+func (c *Chain) TRC20Balance(contract, address string) client.TokenAmount {
+	addr := mustDecode(address)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	balance, _ := client.FromTokenUnits(c.token(contract).balance(addr))
+	return balance
+}
+
+// token returns the token at contract or panics.
+func (c *Chain) token(contract string) *token {
+	t, ok := c.state.tokens[string(mustDecode(contract))]
+	if !ok {
+		panic("clienttest: no token deployed at " + contract)
+	}
+	return t
+}
+
+func (t *token) balance(address []byte) *big.Int {
+	if b, ok := t.balances[string(address)]; ok {
+		return b
+	}
+	return new(big.Int)
+}
+
+func (t *token) allowance(owner, spender []byte) *big.Int {
+	if a, ok := t.allowances[string(owner)+string(spender)]; ok {
+		return a
+	}
+	return new(big.Int)
+}
+
+// trigger runs a contract call against s. A call that reverts is not an
+// error: it is mined like any other, with a failed result. A constant call
+// may come from an address that has no account.
+func (s *state) trigger(ct *core.TriggerSmartContract, constant bool) (*core.TransactionInfo, error) {
+	if !constant {
+		if _, err := s.owner(ct.GetOwnerAddress()); err != nil {
+			return nil, err
+		}
+	}
+	t, ok := s.tokens[string(ct.GetContractAddress())]
+	if !ok {
+		return nil, invalid("no contract or not a smart contract")
+	}
+
+	info := &core.TransactionInfo{ContractAddress: t.address}
+	var result []byte
+	var logs []*core.TransactionInfo_Log
+	var energy int64
+	reverted := ct.GetCallValue() != 0 || ct.GetCallTokenValue() != 0
+	if !reverted {
+		result, logs, energy, reverted = t.call(ct.GetOwnerAddress(), ct.GetData())
+	}
+	if reverted {
+		info.Result = core.TransactionInfo_FAILED
+		info.ResMessage = []byte(revertMessage)
+		info.Receipt = &core.ResourceReceipt{Result: core.Transaction_Result_REVERT}
+		return info, nil
+	}
+	info.ContractResult = [][]byte{result}
+	info.Log = logs
+	info.Receipt = &core.ResourceReceipt{
+		Result:           core.Transaction_Result_SUCCESS,
+		EnergyUsageTotal: energy,
+	}
+	return info, nil
+}
+
+// call executes data as a call from caller, changing the ledger only when it
+// does not revert.
+func (t *token) call(caller, data []byte) (result []byte, logs []*core.TransactionInfo_Log, energy int64, reverted bool) {
+	if len(data) < 4 {
+		return nil, nil, 0, true
+	}
+	args := data[4:]
+	switch hex.EncodeToString(data[:4]) {
+	case selectorName:
+		return abiString(t.Name), nil, 0, false
+	case selectorSymbol:
+		return abiString(t.Symbol), nil, 0, false
+	case selectorDecimals:
+		return word(big.NewInt(int64(t.Decimals))), nil, 0, false
+	case selectorTotalSupply:
+		return word(t.supply), nil, 0, false
+	case selectorBalanceOf:
+		if len(args) < 32 {
+			break
+		}
+		return word(t.balance(argAddress(args, 0))), nil, 0, false
+	case selectorAllowance:
+		if len(args) < 64 {
+			break
+		}
+		return word(t.allowance(argAddress(args, 0), argAddress(args, 1))), nil, 0, false
+	case selectorTransfer:
+		if len(args) < 64 || !t.move(caller, argAddress(args, 0), argAmount(args, 1)) {
+			break
+		}
+		return word(big.NewInt(1)), []*core.TransactionInfo_Log{t.transferLog(caller, argAddress(args, 0), argAmount(args, 1))}, TokenCallEnergy, false
+	case selectorApprove:
+		if len(args) < 64 {
+			break
+		}
+		spender, amount := argAddress(args, 0), argAmount(args, 1)
+		t.allowances[string(caller)+string(spender)] = amount
+		return word(big.NewInt(1)), []*core.TransactionInfo_Log{t.log(topicApproval, caller, spender, amount)}, TokenCallEnergy, false
+	case selectorTransferFrom:
+		if len(args) < 96 {
+			break
+		}
+		from, to, amount := argAddress(args, 0), argAddress(args, 1), argAmount(args, 2)
+		allowance := t.allowance(from, caller)
+		if allowance.Cmp(amount) < 0 || !t.move(from, to, amount) {
+			break
+		}
+		t.allowances[string(from)+string(caller)] = new(big.Int).Sub(allowance, amount)
+		return word(big.NewInt(1)), []*core.TransactionInfo_Log{t.transferLog(from, to, amount)}, TokenCallEnergy, false
+	}
+	return nil, nil, 0, true
+}
+
+// move transfers amount from one holder to another, reporting false when the
+// sender's balance does not cover it.
+func (t *token) move(from, to []byte, amount *big.Int) bool {
+	balance := t.balance(from)
+	if balance.Cmp(amount) < 0 {
+		return false
+	}
+	t.balances[string(from)] = new(big.Int).Sub(balance, amount)
+	t.balances[string(to)] = new(big.Int).Add(t.balance(to), amount)
+	return true
+}
+
+func (t *token) transferLog(from, to []byte, amount *big.Int) *core.TransactionInfo_Log {
+	return t.log(client.Trc20TransferEventSignature[2:], from, to, amount)
+}
+
+// log builds an event of the token with two indexed addresses and an amount,
+// in the form a node reports: addresses without the 0x41 prefix.
+func (t *token) log(topic string, a, b []byte, amount *big.Int) *core.TransactionInfo_Log {
+	return &core.TransactionInfo_Log{
+		Address: t.address[1:],
+		Topics: [][]byte{
+			common.FromHex(topic),
+			common.LeftPadBytes(a[1:], 32),
+			common.LeftPadBytes(b[1:], 32),
+		},
+		Data: word(amount),
+	}
+}
+
+// argAddress decodes the i-th argument of a call as an address.
+func argAddress(args []byte, i int) []byte {
+	return append([]byte{tronutils.TronBytePrefix}, args[i*32+12:(i+1)*32]...)
+}
+
+// argAmount decodes the i-th argument of a call as a uint256.
+func argAmount(args []byte, i int) *big.Int {
+	return new(big.Int).SetBytes(args[i*32 : (i+1)*32])
+}
+
+// word ABI-encodes n as a uint256.
+func word(n *big.Int) []byte {
+	return common.LeftPadBytes(n.Bytes(), 32)
+}
+
+// abiString ABI-encodes s as a string return value.
+func abiString(s string) []byte {
+	out := word(big.NewInt(32))
+	out = append(out, word(big.NewInt(int64(len(s))))...)
+	return append(out, common.RightPadBytes([]byte(s), (len(s)+31)/32*32)...)
+}
@@ -0,0 +1,117 @@
+package clienttest
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/pkg/client"
+	"github.com/sxwebdev/gotron/pkg/tronutils"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+)
+
+func tokens(t *testing.T, n int64) client.TokenAmount {
+	t.Helper()
+	amount, err := client.FromTokenUnits(big.NewInt(n))
+	require.NoError(t, err)
+	return amount
+}
+
+func TestTRC20(t *testing.T) {
+	chain := NewChain()
+	c := newClient(t, chain)
+	alice := chain.NewAccount(100_000_000)
+	bob := chain.NewAccount(0)
+	usdt := chain.DeployTRC20(TRC20{Name: "Tether USD", Symbol: "USDT", Decimals: 6})
+	chain.MintTRC20(usdt, alice.Address, tokens(t, 1000))
+
+	symbol, err := c.TRC20GetSymbol(t.Context(), usdt)
+	require.NoError(t, err)
+	assert.Equal(t, "USDT", symbol)
+	decimals, err := c.TRC20GetDecimals(t.Context(), usdt)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), decimals.Int64())
+	balance, err := c.TRC20ContractBalance(t.Context(), alice.Address, usdt)
+	require.NoError(t, err)
+	assert.Equal(t, "1000", balance.String())
+
+	tx, err := c.TRC20Send(t.Context(), alice.Address, bob.Address, usdt, tokens(t, 250), 10_000_000)
+	require.NoError(t, err)
+	require.NoError(t, send(t, c, tx, alice.PrivateKey))
+	chain.NextBlock()
+
+	assert.Equal(t, "750", chain.TRC20Balance(usdt, alice.Address).String())
+	assert.Equal(t, "250", chain.TRC20Balance(usdt, bob.Address).String())
+
+	info, err := c.GetTransactionInfoByHash(t.Context(), tronutils.BytesToHexString(tx.GetTxid()))
+	require.NoError(t, err)
+	assert.Equal(t, core.Transaction_Result_SUCCESS, info.GetReceipt().GetResult())
+	assert.Equal(t, int64(TokenCallEnergy), info.GetReceipt().GetEnergyUsageTotal())
+	require.Len(t, info.GetLog(), 1)
+	transfer := info.GetLog()[0]
+	assert.Equal(t, client.Trc20TransferEventSignature[2:], tronutils.Bytes2Hex(transfer.GetTopics()[0]))
+	assert.Equal(t, mustDecode(bob.Address)[1:], transfer.GetTopics()[2][12:])
+	assert.Equal(t, int64(250), new(big.Int).SetBytes(transfer.GetData()).Int64())
+}
+
+func TestTRC20Revert(t *testing.T) {
+	chain := NewChain()
+	c := newClient(t, chain)
+	alice := chain.NewAccount(100_000_000)
+	bob := chain.NewAccount(0)
+	usdt := chain.DeployTRC20(TRC20{Symbol: "USDT"})
+	chain.MintTRC20(usdt, alice.Address, tokens(t, 10))
+
+	_, err := c.EstimateTRC20Transfer(t.Context(), alice.Address, bob.Address, usdt, tokens(t, 11))
+	require.ErrorIs(t, err, client.ErrContractCallFailed, "the constant call reverts")
+
+	tx, err := c.TRC20Send(t.Context(), alice.Address, bob.Address, usdt, tokens(t, 11), 10_000_000)
+	require.NoError(t, err, "building does not run the call")
+	require.NoError(t, send(t, c, tx, alice.PrivateKey), "a reverting call is still mined")
+	chain.NextBlock()
+
+	info, err := c.GetTransactionInfoByHash(t.Context(), tronutils.BytesToHexString(tx.GetTxid()))
+	require.NoError(t, err)
+	assert.Equal(t, core.TransactionInfo_FAILED, info.GetResult())
+	assert.Equal(t, core.Transaction_Result_REVERT, info.GetReceipt().GetResult())
+	assert.Equal(t, "10", chain.TRC20Balance(usdt, alice.Address).String())
+}
+
+func TestTRC20TransferFrom(t *testing.T) {
+	chain := NewChain()
+	c := newClient(t, chain)
+	alice := chain.NewAccount(100_000_000)
+	spender := chain.NewAccount(100_000_000)
+	bob := chain.NewAccount(0)
+	usdt := chain.DeployTRC20(TRC20{Symbol: "USDT"})
+	chain.MintTRC20(usdt, alice.Address, tokens(t, 100))
+
+	approve, err := c.TRC20Approve(t.Context(), alice.Address, spender.Address, usdt, tokens(t, 60), 10_000_000)
+	require.NoError(t, err)
+	require.NoError(t, send(t, c, approve, alice.PrivateKey))
+
+	pull, err := c.TRC20TransferFrom(t.Context(), spender.Address, alice.Address, bob.Address, usdt, tokens(t, 40), 10_000_000)
+	require.NoError(t, err)
+	require.NoError(t, send(t, c, pull, spender.PrivateKey))
+	assert.Equal(t, "40", chain.TRC20Balance(usdt, bob.Address).String())
+
+	pull, err = c.TRC20TransferFrom(t.Context(), spender.Address, alice.Address, bob.Address, usdt, tokens(t, 40), 10_000_000)
+	require.NoError(t, err)
+	require.NoError(t, send(t, c, pull, spender.PrivateKey))
+	assert.Equal(t, "40", chain.TRC20Balance(usdt, bob.Address).String(), "the remaining allowance is 20")
+}
+
+func TestEstimateTRC20Transfer(t *testing.T) {
+	chain := NewChain()
+	c := newClient(t, chain)
+	alice := chain.NewAccount(100_000_000)
+	bob := chain.NewAccount(0)
+	usdt := chain.DeployTRC20(TRC20{Symbol: "USDT"})
+	chain.MintTRC20(usdt, alice.Address, tokens(t, 10))
+
+	estimate, err := c.EstimateTRC20Transfer(t.Context(), alice.Address, bob.Address, usdt, tokens(t, 5))
+	require.NoError(t, err)
+	assert.Equal(t, int64(TokenCallEnergy), estimate.Usage.Energy.IntPart())
+	assert.Positive(t, estimate.Fee.Int64(), "the energy is burned at getEnergyFee")
+}
@@ -0,0 +1,288 @@
+package clienttest
+
+import (
+	"cmp"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/sxwebdev/gotron/pkg/tronutils"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// java-tron's HTTP API speaks a JSON dialect of its own, which protojson can
+// neither write nor read: fields go by their proto names, bytes are hex - or
+// base58 for an address when the request asked for "visible" ones - enums go
+// by name, a map is a list of key/value objects and an Any is a "type_url"
+// and a "value" object. Server writes its answers in it and reads requests
+// from it with the functions below.
+
+// textFields are the bytes fields java-tron reads and writes as plain text
+// rather than hex when "visible" is set: the names that
+// HttpSelfFormatFieldName lists alongside the addresses.
+var textFields = map[protoreflect.FullName]bool{
+	"protocol.TransferAssetContract.asset_name":         true,
+	"protocol.ParticipateAssetIssueContract.asset_name": true,
+	"protocol.AssetIssueContract.name":                  true,
+	"protocol.AssetIssueContract.abbr":                  true,
+	"protocol.AssetIssueContract.description":           true,
+	"protocol.AssetIssueContract.url":                   true,
+	"protocol.UpdateAssetContract.description":          true,
+	"protocol.UpdateAssetContract.url":                  true,
+	"protocol.Account.asset_issued_name":                true,
+	"protocol.Account.asset_issued_ID":                  true,
+	"protocol.WitnessCreateContract.url":                true,
+	"protocol.WitnessUpdateContract.update_url":         true,
+	"protocol.ExchangeCreateContract.first_token_id":    true,
+	"protocol.ExchangeCreateContract.second_token_id":   true,
+	"protocol.ExchangeInjectContract.token_id":          true,
+	"protocol.ExchangeWithdrawContract.token_id":        true,
+	"protocol.ExchangeTransactionContract.token_id":     true,
+	"protocol.Exchange.first_token_id":                  true,
+	"protocol.Exchange.second_token_id":                 true,
+}
+
+// tronJSON renders m in Tron's dialect. Fields at their zero value are left
+// out, as java-tron leaves them out.
+func tronJSON(m proto.Message, visible bool) map[string]any {
+	out := map[string]any{}
+	m.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		out[string(fd.Name())] = tronField(fd, v, visible)
+		return true
+	})
+	return out
+}
+
+func tronField(fd protoreflect.FieldDescriptor, v protoreflect.Value, visible bool) any {
+	switch {
+	case fd.IsMap():
+		var entries []map[string]any
+		v.Map().Range(func(k protoreflect.MapKey, e protoreflect.Value) bool {
+			entries = append(entries, map[string]any{
+				"key":   k.Interface(),
+				"value": tronValue(fd.MapValue(), e, visible),
+			})
+			return true
+		})
+		// Sorted, so that the same message always renders the same way.
+		slices.SortFunc(entries, func(a, b map[string]any) int {
+			return cmp.Compare(fmt.Sprint(a["key"]), fmt.Sprint(b["key"]))
+		})
+		return entries
+	case fd.IsList():
+		list := v.List()
+		out := make([]any, list.Len())
+		for i := range list.Len() {
+			out[i] = tronValue(fd, list.Get(i), visible)
+		}
+		return out
+	}
+	return tronValue(fd, v, visible)
+}
+
+func tronValue(fd protoreflect.FieldDescriptor, v protoreflect.Value, visible bool) any {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		m := v.Message().Interface()
+		if a, ok := m.(*anypb.Any); ok {
+			inner, err := a.UnmarshalNew()
+			if err != nil {
+				return map[string]any{"type_url": a.GetTypeUrl()}
+			}
+			return map[string]any{"type_url": a.GetTypeUrl(), "value": tronJSON(inner, visible)}
+		}
+		return tronJSON(m, visible)
+	case protoreflect.BytesKind:
+		if visible && textFields[fd.FullName()] {
+			return string(v.Bytes())
+		}
+		return tronBytes(v.Bytes(), visible)
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return int32(v.Enum())
+	}
+	return v.Interface()
+}
+
+// tronBytes renders a bytes field: base58 for a Tron address when visible is
+// set, hex otherwise.
+func tronBytes(b []byte, visible bool) string {
+	if visible && len(b) == 21 && b[0] == tronutils.TronBytePrefix {
+		return tronutils.EncodeCheck(b)
+	}
+	return hex.EncodeToString(b)
+}
+
+// fromTronJSON reads body, a request in Tron's dialect decoded with
+// json.Decoder.UseNumber, into m. Keys m has no field for, "visible" among
+// them, are skipped.
+func fromTronJSON(body map[string]any, m proto.Message, visible bool) error {
+	r := m.ProtoReflect()
+	fields := r.Descriptor().Fields()
+	for key, value := range body {
+		fd := fields.ByName(protoreflect.Name(key))
+		if fd == nil {
+			fd = fields.ByJSONName(key)
+		}
+		if fd == nil || value == nil {
+			continue
+		}
+		if err := setTronField(r, fd, value, visible); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func setTronField(r protoreflect.Message, fd protoreflect.FieldDescriptor, value any, visible bool) error {
+	switch {
+	case fd.IsMap():
+		// A map arrives as the list of key/value entries tronJSON renders.
+		entries, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("want a list of entries, got %T", value)
+		}
+		if fd.MapValue().Kind() == protoreflect.MessageKind {
+			return errors.New("map fields of messages are not read")
+		}
+		m := r.Mutable(fd).Map()
+		for _, entry := range entries {
+			obj, ok := entry.(map[string]any)
+			if !ok {
+				return fmt.Errorf("want an entry object, got %T", entry)
+			}
+			k, err := parseTronValue(fd.MapKey(), obj["key"], visible)
+			if err != nil {
+				return fmt.Errorf("key: %w", err)
+			}
+			v, err := parseTronValue(fd.MapValue(), obj["value"], visible)
+			if err != nil {
+				return fmt.Errorf("value: %w", err)
+			}
+			m.Set(k.MapKey(), v)
+		}
+		return nil
+	case fd.IsList():
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("want a list, got %T", value)
+		}
+		list := r.Mutable(fd).List()
+		for _, item := range items {
+			if fd.Kind() == protoreflect.MessageKind {
+				elem := list.NewElement()
+				if err := fromTronObject(item, elem.Message().Interface(), visible); err != nil {
+					return err
+				}
+				list.Append(elem)
+				continue
+			}
+			v, err := parseTronValue(fd, item, visible)
+			if err != nil {
+				return err
+			}
+			list.Append(v)
+		}
+		return nil
+	case fd.Kind() == protoreflect.MessageKind:
+		return fromTronObject(value, r.Mutable(fd).Message().Interface(), visible)
+	}
+	v, err := parseTronValue(fd, value, visible)
+	if err != nil {
+		return err
+	}
+	r.Set(fd, v)
+	return nil
+}
+
+func fromTronObject(value any, m proto.Message, visible bool) error {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("want an object, got %T", value)
+	}
+	if _, ok := m.(*anypb.Any); ok {
+		return errors.New("Any fields are not read")
+	}
+	return fromTronJSON(obj, m, visible)
+}
+
+func parseTronValue(fd protoreflect.FieldDescriptor, value any, visible bool) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		if b, ok := value.(bool); ok {
+			return protoreflect.ValueOfBool(b), nil
+		}
+	case protoreflect.StringKind:
+		if s, ok := value.(string); ok {
+			return protoreflect.ValueOfString(s), nil
+		}
+	case protoreflect.BytesKind:
+		if s, ok := value.(string); ok {
+			if visible && textFields[fd.FullName()] {
+				return protoreflect.ValueOfBytes([]byte(s)), nil
+			}
+			b, err := parseTronBytes(s, visible)
+			return protoreflect.ValueOfBytes(b), err
+		}
+	case protoreflect.EnumKind:
+		if s, ok := value.(string); ok {
+			ev := fd.Enum().Values().ByName(protoreflect.Name(s))
+			if ev == nil {
+				return protoreflect.Value{}, fmt.Errorf("unknown %s %q", fd.Enum().Name(), s)
+			}
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		n, err := tronInt(value)
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := tronInt(value)
+		return protoreflect.ValueOfInt32(int32(n)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := tronInt(value)
+		return protoreflect.ValueOfInt64(n), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := tronInt(value)
+		return protoreflect.ValueOfUint32(uint32(n)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := tronInt(value)
+		return protoreflect.ValueOfUint64(uint64(n)), err
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		if n, ok := value.(json.Number); ok {
+			f, err := n.Float64()
+			if fd.Kind() == protoreflect.FloatKind {
+				return protoreflect.ValueOfFloat32(float32(f)), err
+			}
+			return protoreflect.ValueOfFloat64(f), err
+		}
+	}
+	return protoreflect.Value{}, fmt.Errorf("unexpected %T for a %s field", value, fd.Kind())
+}
+
+// parseTronBytes reads a bytes field: a base58 address when visible is set
+// and s is one, hex otherwise.
+func parseTronBytes(s string, visible bool) ([]byte, error) {
+	if visible {
+		if b, err := tronutils.DecodeCheck(s); err == nil {
+			return b, nil
+		}
+	}
+	return hex.DecodeString(s)
+}
+
+// tronInt reads an integer, which java-tron also accepts as a string.
+func tronInt(value any) (int64, error) {
+	switch v := value.(type) {
+	case json.Number:
+		return v.Int64()
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	}
+	return 0, fmt.Errorf("want a number, got %T", value)
+}
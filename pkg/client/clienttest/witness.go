@@ -0,0 +1,108 @@
+package clienttest
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+
+	"github.com/sxwebdev/gotron/pkg/client"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// maxWitnessURL is the longest witness url, TransactionUtil.validUrl's
+	// bound.
+	maxWitnessURL = 256
+	// defaultBrokerage is the brokerage, in percent, of a witness that never
+	// set one.
+	defaultBrokerage = 20
+	// maxWitnessPage is the most witnesses a page of
+	// GetPaginatedNowWitnessList holds.
+	maxWitnessPage = 1000
+)
+
+func validWitnessURL(url []byte) bool {
+	return len(url) > 0 && len(url) <= maxWitnessURL
+}
+
+// createWitness makes the owner a witness candidate, as WitnessCreateActuator
+// does. With multi-sign allowed the account gets the default witness
+// permission, its own key alone.
+func (s *state) createWitness(ct *core.WitnessCreateContract) (*core.TransactionInfo, error) {
+	owner, err := s.owner(ct.GetOwnerAddress())
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case !validWitnessURL(ct.GetUrl()):
+		return nil, invalid("Invalid url")
+	case s.witnesses[string(ct.GetOwnerAddress())] != nil:
+		return nil, invalid("Witness[%x] has existed", ct.GetOwnerAddress())
+	}
+
+	s.witnesses[string(ct.GetOwnerAddress())] = &core.Witness{
+		Address: bytes.Clone(ct.GetOwnerAddress()),
+		Url:     string(ct.GetUrl()),
+	}
+	owner.IsWitness = true
+	owner.WitnessPermission = &core.Permission{
+		Type:           core.Permission_Witness,
+		Id:             client.WitnessPermissionID,
+		PermissionName: "witness",
+		Threshold:      1,
+		Keys:           []*core.Key{{Address: bytes.Clone(ct.GetOwnerAddress()), Weight: 1}},
+	}
+	return &core.TransactionInfo{}, nil
+}
+
+// updateWitness replaces a witness's url.
+func (s *state) updateWitness(ct *core.WitnessUpdateContract) (*core.TransactionInfo, error) {
+	if _, err := s.owner(ct.GetOwnerAddress()); err != nil {
+		return nil, err
+	}
+	witness := s.witnesses[string(ct.GetOwnerAddress())]
+	switch {
+	case !validWitnessURL(ct.GetUpdateUrl()):
+		return nil, invalid("Invalid url")
+	case witness == nil:
+		return nil, invalid("Witness does not exist")
+	}
+	witness.Url = string(ct.GetUpdateUrl())
+	return &core.TransactionInfo{}, nil
+}
+
+// updateBrokerage sets the share of its voters' rewards a witness keeps.
+func (s *state) updateBrokerage(ct *core.UpdateBrokerageContract) (*core.TransactionInfo, error) {
+	switch {
+	case ct.GetBrokerage() < 0 || ct.GetBrokerage() > 100:
+		return nil, invalid("Invalid brokerage")
+	case s.witnesses[string(ct.GetOwnerAddress())] == nil:
+		return nil, invalid("Not existed witness:%x", ct.GetOwnerAddress())
+	}
+	if _, err := s.owner(ct.GetOwnerAddress()); err != nil {
+		return nil, err
+	}
+	s.brokerages[string(ct.GetOwnerAddress())] = ct.GetBrokerage()
+	return &core.TransactionInfo{}, nil
+}
+
+// brokerage returns the brokerage of address.
+func (s *state) brokerage(address []byte) int64 {
+	if b, ok := s.brokerages[string(address)]; ok {
+		return int64(b)
+	}
+	return defaultBrokerage
+}
+
+// candidates returns copies of the witnesses that applied, by address.
+func (s *state) candidates() []*core.Witness {
+	list := make([]*core.Witness, 0, len(s.witnesses))
+	for _, witness := range s.witnesses {
+		list = append(list, proto.Clone(witness).(*core.Witness))
+	}
+	slices.SortFunc(list, func(a, b *core.Witness) int {
+		return cmp.Compare(string(a.GetAddress()), string(b.GetAddress()))
+	})
+	return list
+}
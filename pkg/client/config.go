@@ -2,10 +2,14 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
+	"slices"
 	"time"
 
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 )
 
@@ -31,31 +35,85 @@ const (
 	ProtocolGRPC Protocol = "grpc"
 	// ProtocolHTTP uses HTTP REST API transport
 	ProtocolHTTP Protocol = "http"
+	// ProtocolJSONRPC uses the Ethereum-compatible JSON-RPC endpoint, which
+	// serves blocks, transaction info and contract calls only; see
+	// JSONRPCTransport
+	ProtocolJSONRPC Protocol = "jsonrpc"
 )
 
 // NodeConfig represents configuration for a single node
 type NodeConfig struct {
-	// Protocol specifies the transport protocol (grpc or http)
+	// Protocol specifies the transport protocol: grpc, http, jsonrpc, or one
+	// added with RegisterProtocol
 	// Default: grpc
 	Protocol Protocol
 
 	// Address is the node address
 	// For gRPC: "grpc.trongrid.io:50051"
 	// For HTTP: "https://api.trongrid.io"
+	// For JSON-RPC: "https://api.trongrid.io/jsonrpc"
 	Address string
 
+	// Alias names the node in the per-node metrics labels instead of its
+	// Address - a provider name, say, or a shorter label than a URL.
+	// Default: Address
+	Alias string
+
 	// UseTLS enables TLS for gRPC connections
 	UseTLS bool
 
+	// TLSConfig replaces the default TLS settings - the system roots, TLS 1.3
+	// for gRPC - to trust a private CA or present a client certificate. It
+	// applies to gRPC when UseTLS is set, and to HTTP when HTTPClient is not.
+	TLSConfig *tls.Config
+
 	// DialOptions are additional gRPC dial options (gRPC only)
 	DialOptions []grpc.DialOption
 
-	// HTTPClient allows providing a custom HTTP client (HTTP only)
+	// HTTPClient allows providing a custom HTTP client (HTTP and JSON-RPC only)
 	HTTPClient *http.Client
 
 	// Headers are custom headers/metadata for requests (HTTP headers and gRPC metadata)
 	Headers map[string]string
 
+	// RateLimit caps the requests per second sent to this node. Calls over
+	// the limit wait for their turn; a call whose deadline would pass first
+	// fails at once with ErrRateLimited. 0 means no limit.
+	RateLimit float64
+
+	// RateBurst is the number of requests that may be sent back to back
+	// before RateLimit applies. Default: RateLimit rounded up, at least 1.
+	RateBurst int
+
+	// APIKeys are API keys for this node, sent one at a time in the
+	// APIKeyHeader header (gRPC metadata). The same key is used until the
+	// node rate-limits it - HTTP 429 or gRPC ResourceExhausted - and then
+	// rests for the Retry-After the node asked for (1s when it did not say)
+	// while the next key takes over; the rate-limited request is sent again
+	// at once with it. Only when every key rests does the call fail as
+	// rate-limited. A key in APIKeys replaces one set for the same header in
+	// Headers.
+	APIKeys []string
+
+	// APIKeyHeader is the header APIKeys are sent in.
+	// Default: "TRON-PRO-API-KEY", the header TronGrid reads.
+	APIKeyHeader string
+
+	// Solidity marks the node as a solidity endpoint, serving only
+	// solidified - irreversible - state: the WalletSolidity gRPC service
+	// (port 50061 on java-tron) or the /walletsolidity HTTP API. Solidity
+	// nodes form a pool of their own, reached through Client.Confirmed, with
+	// the same health checking and tiers as the full nodes.
+	//
+	// Of the Transport methods, a solidity node serves GetAccount,
+	// GetNowBlock, GetBlockByNum, GetTransactionById, GetTransactionInfoById,
+	// GetTransactionInfoByBlockNum, TriggerConstantContract, EstimateEnergy,
+	// the GetDelegatedResource* and unfreeze queries, GetCanDelegatedMaxSize,
+	// ListWitnesses, GetRewardInfo, GetBrokerageInfo and the asset lookups;
+	// over HTTP also GetBlockById, GetBlockByLimitNext and GetBlockByLatestNum.
+	// Every other call fails with ErrUnsupportedBySolidity.
+	Solidity bool
+
 	// Tier is the priority of this node. 0 = primary, 1 = fallback, 2+ = next.
 	// Lower-numbered tiers are preferred: requests are routed to the
 	// minimum-numbered tier that has at least one healthy node. A higher tier is
@@ -83,6 +141,29 @@ type Config struct {
 	// or provide a custom MetricsCollector implementation.
 	Metrics MetricsCollector
 
+	// Coalesce merges identical read requests that are in flight at the same
+	// time into one upstream call, through CoalescingTransport. Useful when
+	// many goroutines ask for the same thing at once - the chain parameters,
+	// the head block, a token contract. Merged calls are not sent, so they do
+	// not appear in the request metrics; they are counted separately when
+	// Metrics implements CoalesceRecorder.
+	Coalesce bool
+
+	// Cache keeps the answers of reads whose result rarely or never changes -
+	// contracts, token metadata, chain parameters, solidified blocks -
+	// through CachingTransport. Disabled unless Cache.Enabled is set.
+	Cache CacheConfig
+
+	// Tracing opens OpenTelemetry spans for every call, through
+	// OTelTransport. Disabled while Tracing.Provider is nil.
+	Tracing TracingConfig
+
+	// Middlewares run, in order, around every Transport call of the Client,
+	// through MiddlewareTransport. They see each call once, before the cache
+	// and retries, and inside its tracing span. The full-node and the
+	// solidity pool each run them.
+	Middlewares []Middleware
+
 	// Health configures the per-node health-checker and tier-based fallback.
 	// Zero value means "use sane defaults": health-checker is enabled,
 	// FailureThreshold/SuccessThreshold = 2, HealthyInterval = 30s,
@@ -93,6 +174,42 @@ type Config struct {
 	Health HealthConfig
 }
 
+// TracingConfig configures the OpenTelemetry spans of OTelTransport.
+type TracingConfig struct {
+	// Provider creates the tracer spans are opened with, such as an SDK
+	// TracerProvider. nil disables tracing.
+	Provider trace.TracerProvider
+
+	// Propagator writes the trace context into the gRPC metadata and HTTP
+	// headers sent to the nodes.
+	// Default: otel.GetTextMapPropagator()
+	Propagator propagation.TextMapPropagator
+}
+
+// CacheForever, as a CacheConfig.TTL value, keeps an answer until it is
+// evicted or invalidated.
+const CacheForever time.Duration = -1
+
+// CacheConfig configures the read cache of CachingTransport.
+type CacheConfig struct {
+	// Enabled turns the cache on.
+	Enabled bool
+
+	// MaxEntries bounds the number of answers kept per node pool; the least
+	// recently used one is evicted first. Default 10000.
+	MaxEntries int
+
+	// TTL sets how long the answers of a Transport method, by name, are kept:
+	// CacheForever for no expiry, 0 to not cache the method at all. It is
+	// merged over the defaults: GetContract, GetAssetIssueById, GetBlockById
+	// and the TRC20 name, symbol and decimals reads are kept forever,
+	// GetChainParameters for 10 minutes. In the solidity pool
+	// GetBlockByNum, GetTransactionById, GetTransactionInfoById and
+	// GetTransactionInfoByBlockNum are kept forever too. Methods that build or
+	// broadcast a transaction are never cached.
+	TTL map[string]time.Duration
+}
+
 // HealthConfig configures the health-checker and tier-based fallback behaviour.
 // Zero value is valid — defaults are filled in by NewHealthAwareTransport.
 type HealthConfig struct {
@@ -133,12 +250,103 @@ type HealthConfig struct {
 	// ClassifyErr decides whether a given error counts as a network-level
 	// failure. By default, isNetworkError covers gRPC status codes,
 	// context.DeadlineExceeded, net.Error, io.EOF, net.ErrClosed and the
-	// HTTPStatusError 5xx/408/429 family.
+	// HTTPStatusError 5xx/408 family. Rate limits (HTTP 429, gRPC
+	// ResourceExhausted) are not network-level failures, but are retried on
+	// another node whatever ClassifyErr says.
 	ClassifyErr func(err error) bool
 
+	// MaxLagBlocks marks a node unhealthy when the head block its probe
+	// reports is more than MaxLagBlocks behind the highest head any probe has
+	// seen across the pool. The node is taken out at once rather than after
+	// FailureThreshold, and returns after SuccessThreshold probes find it
+	// caught up. A custom Probe is followed by a GetNowBlock call to read the
+	// head. 0 disables the check.
+	MaxLagBlocks int64
+
+	// MaxLagTime is MaxLagBlocks measured in time: the node is unhealthy when
+	// its head block's timestamp is more than MaxLagTime older than the best
+	// head's. 0 disables the check.
+	MaxLagTime time.Duration
+
 	// Logger receives informational events on node state transitions and tier
 	// shifts via Infof. nil means a no-op logger is used (silent).
 	Logger Logger
+
+	// Selection chooses which healthy node of the active tier serves each
+	// call. nil means NewRoundRobinStrategy. NewLeastOutstandingStrategy and
+	// NewEWMAStrategy steer traffic away from busy or slow nodes, using the
+	// in-flight count and latency of live calls.
+	Selection SelectionStrategy
+
+	// Retry configures automatic retries of failed live calls on another
+	// healthy node. The zero value disables retries.
+	Retry RetryPolicy
+
+	// Hedge configures hedged reads: a slow read is sent to a second node of
+	// the same tier and the first good answer wins. The zero value disables
+	// hedging.
+	Hedge HedgePolicy
+}
+
+// HedgePolicy configures hedged reads in HealthAwareTransport.
+//
+// When the node serving a read-only call has not answered within the
+// method's recent Percentile latency, the same request is sent to another
+// healthy node of the active tier. The first successful answer is returned and
+// the other request's context is cancelled. Methods that build or broadcast a
+// transaction are never hedged. Fired hedges are reported to the
+// MetricsCollector when it implements HedgeRecorder.
+type HedgePolicy struct {
+	// Enabled turns hedging on.
+	Enabled bool
+
+	// Percentile of the method's recent successful latencies after which the
+	// hedge is sent, in (0, 1). Default 0.95: roughly one read in twenty is
+	// hedged while the pool behaves.
+	Percentile float64
+
+	// MinDelay is the shortest wait before hedging, so that a method that is
+	// always fast is not hedged on noise. Default 10ms.
+	MinDelay time.Duration
+
+	// MaxDelay is the longest wait before hedging. It is also the delay used
+	// until a method has enough latency samples for a percentile. Default 1s.
+	MaxDelay time.Duration
+}
+
+// RetryPolicy configures how HealthAwareTransport retries a failed call.
+//
+// Only idempotent methods are retried - reads and BroadcastTransaction, whose
+// signed bytes are rejected as a duplicate if the first attempt did land - and
+// only when ClassifyErr reports a network-level failure. A request error such
+// as ContractValidateError is returned at once: another node gives the same
+// answer. Every retry goes to a node that has not been tried for this call and
+// is reported through MetricsCollector.RecordRetry. A retried broadcast that
+// the node rejects as DUP_TRANSACTION_ERROR is returned as a success, since
+// the attempt that timed out had already reached the network.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts per call, the first one
+	// included. 0 or 1 disables retries.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry; it doubles for each
+	// further retry up to MaxBackoff. Half of every delay is randomized, so
+	// callers that failed together do not retry in lockstep. Default 50ms.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between two attempts. Default 1s.
+	MaxBackoff time.Duration
+
+	// BudgetRatio is the share of a method's calls that may be retried over
+	// time: every call of the method earns BudgetRatio retry tokens and every
+	// retry spends one. It keeps a pool-wide outage from multiplying the load
+	// by MaxAttempts. Default 0.1.
+	BudgetRatio float64
+
+	// BudgetBurst is the number of retry tokens a method can accumulate, and
+	// starts with, so that isolated failures are retried before the method has
+	// earned any. Default 10.
+	BudgetBurst int
 }
 
 // Validate validates the client configuration
@@ -147,12 +355,43 @@ func (c Config) Validate() error {
 		return fmt.Errorf("%w: at least one node is required", ErrInvalidConfig)
 	}
 
+	full := 0
 	for i, node := range c.Nodes {
 		if err := node.Validate(); err != nil {
 			return fmt.Errorf("%w: node %d: %v", ErrInvalidConfig, i, err)
 		}
+		if !node.Solidity {
+			full++
+		}
+	}
+	if full == 0 {
+		return fmt.Errorf("%w: at least one full (non-solidity) node is required", ErrInvalidConfig)
 	}
 
+	return c.validateLayers()
+}
+
+// validateLayers validates the configuration of everything but the nodes.
+func (c Config) validateLayers() error {
+	if err := c.Cache.Validate(); err != nil {
+		return fmt.Errorf("%w: cache: %v", ErrInvalidConfig, err)
+	}
+	return nil
+}
+
+// Validate validates the cache configuration
+func (c CacheConfig) Validate() error {
+	if c.MaxEntries < 0 {
+		return fmt.Errorf("max entries must not be negative")
+	}
+	for method, ttl := range c.TTL {
+		if kind, ok := transportMethods[method]; !ok || kind != methodRead {
+			return fmt.Errorf("%s is not a read method of Transport", method)
+		}
+		if ttl < 0 && ttl != CacheForever {
+			return fmt.Errorf("ttl of %s must not be negative", method)
+		}
+	}
 	return nil
 }
 
@@ -162,13 +401,17 @@ func (n NodeConfig) Validate() error {
 		return fmt.Errorf("address is required")
 	}
 
-	switch n.GetProtocol() {
-	case ProtocolGRPC, ProtocolHTTP:
-		// valid protocols
-	default:
+	if _, ok := protocolFactory(n.GetProtocol()); !ok {
 		return fmt.Errorf("invalid protocol %s", n.Protocol)
 	}
 
+	if n.RateLimit < 0 || n.RateBurst < 0 {
+		return fmt.Errorf("rate limit and burst must not be negative")
+	}
+	if slices.Contains(n.APIKeys, "") {
+		return fmt.Errorf("api keys must not be empty")
+	}
+
 	return nil
 }
 
@@ -179,3 +422,12 @@ func (n NodeConfig) GetProtocol() Protocol {
 	}
 	return n.Protocol
 }
+
+// metricsLabel returns the name of the node in metrics: Alias, or Address
+// when it has none.
+func (n NodeConfig) metricsLabel() string {
+	if n.Alias != "" {
+		return n.Alias
+	}
+	return n.Address
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -17,6 +18,17 @@ func TestConfigValidate(t *testing.T) {
 		{"valid http", Config{Nodes: []NodeConfig{{Protocol: ProtocolHTTP, Address: "https://example"}}}, false},
 		{"node missing address", Config{Nodes: []NodeConfig{{Address: ""}}}, true},
 		{"invalid protocol", Config{Nodes: []NodeConfig{{Protocol: "ftp", Address: "x"}}}, true},
+		{"only solidity nodes", Config{Nodes: []NodeConfig{{Address: "x:50061", Solidity: true}}}, true},
+		{"full and solidity", Config{Nodes: []NodeConfig{{Address: "x:50051"}, {Address: "x:50061", Solidity: true}}}, false},
+		{"rate limited with keys", Config{Nodes: []NodeConfig{{Address: "x", RateLimit: 0.5, APIKeys: []string{"k"}}}}, false},
+		{"negative rate limit", Config{Nodes: []NodeConfig{{Address: "x", RateLimit: -1}}}, true},
+		{"negative burst", Config{Nodes: []NodeConfig{{Address: "x", RateBurst: -1}}}, true},
+		{"empty api key", Config{Nodes: []NodeConfig{{Address: "x", APIKeys: []string{"k", ""}}}}, true},
+		{"cache ttls", Config{Nodes: []NodeConfig{{Address: "x"}}, Cache: CacheConfig{Enabled: true, TTL: map[string]time.Duration{"GetAccount": time.Second, "GetContract": CacheForever, "GetBlockByNum": 0}}}, false},
+		{"negative cache size", Config{Nodes: []NodeConfig{{Address: "x"}}, Cache: CacheConfig{MaxEntries: -1}}, true},
+		{"negative cache ttl", Config{Nodes: []NodeConfig{{Address: "x"}}, Cache: CacheConfig{TTL: map[string]time.Duration{"GetAccount": -time.Second}}}, true},
+		{"cached write", Config{Nodes: []NodeConfig{{Address: "x"}}, Cache: CacheConfig{TTL: map[string]time.Duration{"CreateTransaction": time.Second}}}, true},
+		{"cached unknown method", Config{Nodes: []NodeConfig{{Address: "x"}}, Cache: CacheConfig{TTL: map[string]time.Duration{"GetNothing": time.Second}}}, true},
 	}
 
 	for _, tt := range tests {
@@ -3,6 +3,7 @@ package client
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/sxwebdev/gotron/pkg/units"
 	"github.com/sxwebdev/gotron/schema/pb/api"
@@ -35,14 +36,19 @@ func (e *TransportError) Unwrap() error {
 // caller, so use errors.AsType[*HTTPStatusError](err) to inspect the
 // status code.
 //
-// The health-checker's default classifier treats 5xx, 408 and 429 as
-// network-level failures (count toward the unhealthy threshold) and other
-// 4xx codes as logical errors (do not affect node health).
+// The health-checker's default classifier treats 5xx and 408 as network-level
+// failures (count toward the unhealthy threshold) and other 4xx codes as
+// logical errors (do not affect node health). A 429 is a rate limit: it leaves
+// node health untouched, rests the API key that drew it, and is retried on
+// another node when RetryPolicy allows.
 type HTTPStatusError struct {
 	// Code is the HTTP status code (e.g. 503).
 	Code int
 	// Body is the raw response body — kept for diagnostics.
 	Body string
+	// RetryAfter is the wait the node asked for with a 429, zero when it did
+	// not say.
+	RetryAfter time.Duration
 }
 
 func (e *HTTPStatusError) Error() string {
@@ -140,6 +146,13 @@ var (
 	ErrInvalidPrivateKey       = errors.New("invalid private key")
 	ErrTransactionNotFound     = errors.New("transaction not found")
 	ErrTransactionInfoNotFound = errors.New("transaction info not found")
+	ErrInsufficientBalance     = errors.New("insufficient balance")
+
+	// Governance errors
+	ErrProposalNotFound = errors.New("proposal not found")
+
+	// Exchange errors
+	ErrExchangeNotFound = errors.New("exchange not found")
 
 	// Resources errors
 	ErrInvalidResourceType = errors.New("invalid resource type")
@@ -172,4 +185,45 @@ var (
 	// nodes to the pool as soon as they recover; callers should retry with
 	// backoff. Use errors.Is(err, client.ErrNoHealthyNodes) to detect it.
 	ErrNoHealthyNodes = errors.New("no healthy nodes available in any tier")
+
+	// ErrUnsupportedBySolidity is returned for a call a solidity node does
+	// not serve: anything that builds or broadcasts a transaction, and the
+	// reads missing from the WalletSolidity API (NodeConfig.Solidity lists
+	// them). Use the full-node Client for those.
+	ErrUnsupportedBySolidity = errors.New("method is not served by solidity nodes")
+
+	// ErrUnsupportedByJSONRPC is returned for a call the eth_* methods of a
+	// ProtocolJSONRPC node cannot express: anything that builds or
+	// broadcasts a transaction, and the reads of Tron-only state - accounts,
+	// resources, staking, witnesses. JSONRPCTransport lists what it serves.
+	ErrUnsupportedByJSONRPC = errors.New("method is not served over JSON-RPC")
+
+	// ErrNoSolidityNodes is returned by Client.Confirmed when no node has
+	// NodeConfig.Solidity set.
+	ErrNoSolidityNodes = errors.New("no solidity nodes configured")
+
+	// ErrNodeNotFound is returned when no configured node matches the
+	// CallNode address or CallTier tier of a call.
+	ErrNodeNotFound = errors.New("no configured node matches the call options")
+
+	// ErrRateLimited is returned, without sending anything, when
+	// NodeConfig.RateLimit or the Retry-After of a rate-limited API key would
+	// hold a call past its context deadline. Like a 429 it does not count
+	// against node health, and HealthAwareTransport retries it on another
+	// node when RetryPolicy allows.
+	ErrRateLimited = errors.New("node rate limit would exceed the call deadline")
+
+	// ErrNodeLagging is the probe verdict for a node whose head block is more
+	// than HealthConfig.MaxLagBlocks or MaxLagTime behind the best head seen
+	// across the pool. It appears in the health log, never as a call result.
+	ErrNodeLagging = errors.New("node head block lags the pool")
+
+	// ErrMessageType is returned when a Middleware passes on a request, or
+	// answers with a message, of another type than the Transport method
+	// takes or returns, or names a method Transport does not have.
+	ErrMessageType = errors.New("message type does not match the transport method")
+
+	// ErrNotRecorded is returned by ReplayTransport for a call its cassette
+	// has no recording of. The error names the method and the request.
+	ErrNotRecorded = errors.New("no recorded answer for the call")
 )
@@ -35,12 +35,14 @@ func (p *deployProbe) client(t *testing.T) *Client {
 			// returned something unrelated would hide that.
 			return deployTxFor(t, ct), nil
 		},
-		triggerConstantContract: func(_ context.Context, ct *core.TriggerSmartContract) (*api.TransactionExtention, error) {
+		triggerConstantContract: func(ctx context.Context, ct *core.TriggerSmartContract) (*api.TransactionExtention, error) {
 			p.simulate = ct
-			return &api.TransactionExtention{
-				Result:     &api.Return{Result: true},
-				EnergyUsed: p.energy,
-			}, nil
+			res := &api.TransactionExtention{Result: &api.Return{Result: true}}
+			// Like a JSON-RPC node, report the energy only when asked for it.
+			if callOptionsFrom(ctx).energyUsed {
+				res.EnergyUsed = p.energy
+			}
+			return res, nil
 		},
 		getAccountResource: func(context.Context, *core.Account) (*api.AccountResourceMessage, error) {
 			return &api.AccountResourceMessage{FreeNetLimit: 600}, nil
@@ -0,0 +1,394 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sxwebdev/gotron/pkg/tronutils"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+)
+
+const (
+	// ExchangeTRX is the token id an exchange gives TRX.
+	ExchangeTRX = "_"
+	// MaxExchangeBalance is the most of either token an exchange may hold,
+	// java-tron's ExchangeBalanceLimit.
+	MaxExchangeBalance = 1_000_000_000_000_000
+	// MaxExchangePage is the most exchanges GetPaginatedExchangeList returns
+	// in one page.
+	MaxExchangePage = 1000
+)
+
+// Exchange is one of the chain's built-in Bancor exchanges between two
+// tokens, TRX among them as ExchangeTRX. Balances are in SUN for TRX and in
+// minimal units for a token.
+type Exchange struct {
+	ID int64 `json:"id"`
+	// Creator is the base58 address of the account that created the
+	// exchange; only it may inject or withdraw.
+	Creator            string    `json:"creator"`
+	CreateTime         time.Time `json:"create_time"`
+	FirstTokenID       string    `json:"first_token_id"`
+	FirstTokenBalance  int64     `json:"first_token_balance"`
+	SecondTokenID      string    `json:"second_token_id"`
+	SecondTokenBalance int64     `json:"second_token_balance"`
+}
+
+// Closed reports whether the creator withdrew one side of the exchange
+// entirely. A closed exchange takes no trades or injections.
+func (e Exchange) Closed() bool {
+	return e.FirstTokenBalance == 0 || e.SecondTokenBalance == 0
+}
+
+// exchangeFromProto decodes an exchange as the chain returns it.
+func exchangeFromProto(e *core.Exchange) Exchange {
+	return Exchange{
+		ID:                 e.GetExchangeId(),
+		Creator:            tronutils.EncodeCheck(e.GetCreatorAddress()),
+		CreateTime:         time.UnixMilli(e.GetCreateTime()),
+		FirstTokenID:       string(e.GetFirstTokenId()),
+		FirstTokenBalance:  e.GetFirstTokenBalance(),
+		SecondTokenID:      string(e.GetSecondTokenId()),
+		SecondTokenBalance: e.GetSecondTokenBalance(),
+	}
+}
+
+// exchangesFromProto decodes a list of exchanges.
+func exchangesFromProto(list *api.ExchangeList) []Exchange {
+	out := make([]Exchange, 0, len(list.GetExchanges()))
+	for _, e := range list.GetExchanges() {
+		out = append(out, exchangeFromProto(e))
+	}
+
+	return out
+}
+
+// ExchangeCreateRequest describes an exchange to open between two tokens,
+// funded with the creator's own balances. Their ratio sets the opening price.
+type ExchangeCreateRequest struct {
+	// Owner is the creating account in base58check form. It pays the
+	// getExchangeCreateFee chain parameter on top of the balances.
+	Owner string
+	// FirstTokenID and SecondTokenID are ExchangeTRX or TRC10 token ids, and
+	// differ.
+	FirstTokenID  string
+	SecondTokenID string
+	// FirstTokenBalance and SecondTokenBalance are what the owner puts in, each
+	// positive and at most MaxExchangeBalance.
+	FirstTokenBalance  int64
+	SecondTokenBalance int64
+}
+
+// Validate reports whether the request is one the chain would accept, short
+// of the fee and the owner's balances; CreateExchange checks those.
+func (r ExchangeCreateRequest) Validate() error {
+	_, err := r.build()
+	return err
+}
+
+func (r ExchangeCreateRequest) build() (*core.ExchangeCreateContract, error) {
+	owner, err := decodeOwner(r.Owner)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateExchangeTokenID(r.FirstTokenID); err != nil {
+		return nil, err
+	}
+	if err := validateExchangeTokenID(r.SecondTokenID); err != nil {
+		return nil, err
+	}
+	if r.FirstTokenID == r.SecondTokenID {
+		return nil, fmt.Errorf("%w: an exchange needs two different tokens", ErrInvalidParams)
+	}
+	if err := validateExchangeBalance(r.FirstTokenBalance); err != nil {
+		return nil, err
+	}
+	if err := validateExchangeBalance(r.SecondTokenBalance); err != nil {
+		return nil, err
+	}
+
+	return &core.ExchangeCreateContract{
+		OwnerAddress:       owner,
+		FirstTokenId:       []byte(r.FirstTokenID),
+		FirstTokenBalance:  r.FirstTokenBalance,
+		SecondTokenId:      []byte(r.SecondTokenID),
+		SecondTokenBalance: r.SecondTokenBalance,
+	}, nil
+}
+
+// ExchangeCreateFee returns what opening an exchange costs, the
+// getExchangeCreateFee chain parameter.
+func (c *Client) ExchangeCreateFee(ctx context.Context) (SUN, error) {
+	param, err := c.ChainParam(ctx, "getExchangeCreateFee")
+	if err != nil {
+		return 0, err
+	}
+
+	return SUN(param.GetValue()), nil
+}
+
+// CreateExchange builds a transaction opening an exchange (ExchangeCreate).
+// The transaction is unsigned and is not broadcast.
+//
+// Before asking the node it checks that the owner holds the fee and both
+// balances, the fee and any TRX side together; a shortfall is returned as
+// ErrInsufficientBalance. The exchange's id is only known once the
+// transaction is confirmed, as the ExchangeId of its TransactionInfo.
+func (c *Client) CreateExchange(ctx context.Context, req ExchangeCreateRequest) (*api.TransactionExtention, error) {
+	ct, err := req.build()
+	if err != nil {
+		return nil, err
+	}
+
+	fee, err := c.ExchangeCreateFee(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get exchange create fee: %w", err)
+	}
+
+	owner, err := c.GetAccount(ctx, req.Owner)
+	if err != nil {
+		return nil, err
+	}
+
+	needTRX := fee.Int64()
+	sides := []struct {
+		id      string
+		balance int64
+	}{{req.FirstTokenID, req.FirstTokenBalance}, {req.SecondTokenID, req.SecondTokenBalance}}
+	for _, side := range sides {
+		if side.id == ExchangeTRX {
+			needTRX += side.balance
+			continue
+		}
+		if held := owner.GetAssetV2()[side.id]; held < side.balance {
+			return nil, fmt.Errorf("%w: %s holds %d of token %s, the exchange needs %d", ErrInsufficientBalance, req.Owner, held, side.id, side.balance)
+		}
+	}
+	if owner.GetBalance() < needTRX {
+		return nil, fmt.Errorf("%w: creating the exchange takes %s, %s holds %s", ErrInsufficientBalance, SUN(needTRX), req.Owner, SUN(owner.GetBalance()))
+	}
+
+	tx, err := c.transport.ExchangeCreate(ctx, ct)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// InjectExchange builds a transaction by which the creator of exchange id adds
+// quant of tokenID (ExchangeInject). The node takes the other token in
+// proportion, so that the price does not move.
+func (c *Client) InjectExchange(ctx context.Context, owner string, id int64, tokenID string, quant int64) (*api.TransactionExtention, error) {
+	addr, err := decodeExchangeFunds(owner, id, tokenID, quant)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := c.transport.ExchangeInject(ctx, &core.ExchangeInjectContract{
+		OwnerAddress: addr,
+		ExchangeId:   id,
+		TokenId:      []byte(tokenID),
+		Quant:        quant,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// WithdrawExchange builds a transaction by which the creator of exchange id
+// takes out quant of tokenID (ExchangeWithdraw), and the other token in
+// proportion. Withdrawing a whole side closes the exchange.
+func (c *Client) WithdrawExchange(ctx context.Context, owner string, id int64, tokenID string, quant int64) (*api.TransactionExtention, error) {
+	addr, err := decodeExchangeFunds(owner, id, tokenID, quant)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := c.transport.ExchangeWithdraw(ctx, &core.ExchangeWithdrawContract{
+		OwnerAddress: addr,
+		ExchangeId:   id,
+		TokenId:      []byte(tokenID),
+		Quant:        quant,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// ExchangeTradeRequest sells Quant of one token of an exchange's pair for at
+// least Expected of the other.
+type ExchangeTradeRequest struct {
+	// Owner is the selling account in base58check form; anyone may trade.
+	Owner      string
+	ExchangeID int64
+	// SellTokenID is the token sold, one of the exchange's pair.
+	SellTokenID string
+	// Quant is how much of SellTokenID is sold.
+	Quant int64
+	// Expected is the least of the other token the trade may buy; the node
+	// refuses it otherwise. ExchangeQuote.MinExpected derives it from a quote.
+	Expected int64
+}
+
+// Validate reports whether the request is one the chain would accept, short
+// of the exchange and the owner's balance.
+func (r ExchangeTradeRequest) Validate() error {
+	_, err := r.build()
+	return err
+}
+
+func (r ExchangeTradeRequest) build() (*core.ExchangeTransactionContract, error) {
+	owner, err := decodeExchangeFunds(r.Owner, r.ExchangeID, r.SellTokenID, r.Quant)
+	if err != nil {
+		return nil, err
+	}
+	if r.Expected <= 0 {
+		return nil, fmt.Errorf("%w: expected must be greater than zero", ErrInvalidAmount)
+	}
+
+	return &core.ExchangeTransactionContract{
+		OwnerAddress: owner,
+		ExchangeId:   r.ExchangeID,
+		TokenId:      []byte(r.SellTokenID),
+		Quant:        r.Quant,
+		Expected:     r.Expected,
+	}, nil
+}
+
+// TradeExchange builds a transaction selling into an exchange
+// (ExchangeTransaction). The transaction is unsigned and is not broadcast.
+// QuoteExchange prices the trade first.
+func (c *Client) TradeExchange(ctx context.Context, req ExchangeTradeRequest) (*api.TransactionExtention, error) {
+	ct, err := req.build()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := c.transport.ExchangeTransaction(ctx, ct)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// QuoteExchange reads exchange id and prices selling quant of sellTokenID into
+// it; see Exchange.Quote.
+func (c *Client) QuoteExchange(ctx context.Context, id int64, sellTokenID string, quant int64) (*ExchangeQuote, error) {
+	exchange, err := c.GetExchangeById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return exchange.Quote(sellTokenID, quant)
+}
+
+// ListExchanges returns every exchange, closed ones included.
+func (c *Client) ListExchanges(ctx context.Context) ([]Exchange, error) {
+	list, err := c.transport.ListExchanges(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return exchangesFromProto(list), nil
+}
+
+// GetPaginatedExchangeList returns up to limit exchanges starting at offset.
+// limit is 1 to MaxExchangePage.
+func (c *Client) GetPaginatedExchangeList(ctx context.Context, offset, limit int64) ([]Exchange, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: offset cannot be negative", ErrInvalidParams)
+	}
+	if limit < 1 || limit > MaxExchangePage {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidParams, MaxExchangePage)
+	}
+
+	list, err := c.transport.GetPaginatedExchangeList(ctx, GetPaginatedMessage(offset, limit))
+	if err != nil {
+		return nil, err
+	}
+
+	return exchangesFromProto(list), nil
+}
+
+// GetExchangeById returns exchange id, or ErrExchangeNotFound when there is
+// none.
+func (c *Client) GetExchangeById(ctx context.Context, id int64) (*Exchange, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: exchange id must be positive", ErrInvalidParams)
+	}
+
+	e, err := c.transport.GetExchangeById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// A node answers an id it does not know with an empty exchange.
+	if e.GetExchangeId() == 0 {
+		return nil, fmt.Errorf("%w: %d", ErrExchangeNotFound, id)
+	}
+
+	exchange := exchangeFromProto(e)
+	return &exchange, nil
+}
+
+// decodeExchangeFunds checks the fields an injection, a withdrawal and a trade
+// share and decodes the owner.
+func decodeExchangeFunds(owner string, id int64, tokenID string, quant int64) ([]byte, error) {
+	addr, err := decodeOwner(owner)
+	if err != nil {
+		return nil, err
+	}
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: exchange id must be positive", ErrInvalidParams)
+	}
+	if err := validateExchangeTokenID(tokenID); err != nil {
+		return nil, err
+	}
+	if quant <= 0 {
+		return nil, fmt.Errorf("%w: quant must be greater than zero", ErrInvalidAmount)
+	}
+
+	return addr, nil
+}
+
+// validateExchangeTokenID checks that id is ExchangeTRX or a TRC10 token id.
+func validateExchangeTokenID(id string) error {
+	if id == ExchangeTRX {
+		return nil
+	}
+
+	return validateAssetID(id)
+}
+
+// validateExchangeBalance checks an opening balance of an exchange.
+func validateExchangeBalance(balance int64) error {
+	if balance <= 0 || balance > MaxExchangeBalance {
+		return fmt.Errorf("%w: exchange balance must be between 1 and %d, got %d", ErrInvalidAmount, int64(MaxExchangeBalance), balance)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"fmt"
+	"math"
+)
+
+// exchangeSupply is the relay supply java-tron's ExchangeProcessor starts
+// every trade from.
+const exchangeSupply = 1_000_000_000_000_000_000
+
+// BancorExchange returns how much of the other token selling sellQuant into
+// an exchange holding sellBalance and buyBalance buys, by the formula of
+// java-tron's ExchangeProcessor: the sold tokens issue relay supply at a
+// connector weight of 0.0005, and the supply is redeemed for the other token.
+// The trade is close to a constant product one, with no fee.
+//
+// The node works in float64 and truncates each step as done here. It takes
+// the powers with StrictMath once getAllowStrictMath is on, and math.Pow may
+// differ from it in the last bit, which can move the result by a unit; leave
+// that margin in the expected amount of a trade.
+func BancorExchange(sellBalance, buyBalance, sellQuant int64) int64 {
+	newBalance := sellBalance + sellQuant
+	issued := int64(-float64(exchangeSupply) * (1.0 - math.Pow(1.0+float64(sellQuant)/float64(newBalance), 0.0005)))
+
+	// The redemption takes the issued supply back out before pricing it, so
+	// it too divides by the starting supply.
+	return int64(float64(buyBalance) * (math.Pow(1.0+float64(issued)/float64(exchangeSupply), 2000.0) - 1.0))
+}
+
+// ExchangeQuote is what a trade on an exchange would pay at its current
+// balances. Amounts are in SUN for TRX and in minimal units for a token.
+type ExchangeQuote struct {
+	SellTokenID string `json:"sell_token_id"`
+	Sell        int64  `json:"sell"`
+	BuyTokenID  string `json:"buy_token_id"`
+	Buy         int64  `json:"buy"`
+	// SpotPrice is the buy token the exchange gives per sell token for a trade
+	// too small to move it: the ratio of its balances.
+	SpotPrice float64 `json:"spot_price"`
+	// Price is what this trade gets per sell token, Buy / Sell.
+	Price float64 `json:"price"`
+	// Slippage is how far Price falls short of SpotPrice, as a fraction:
+	// 0.01 is 1%.
+	Slippage float64 `json:"slippage"`
+}
+
+// MinExpected returns the least the trade may buy for a tolerance, a fraction
+// from 0 to 1: pass it as the Expected of an ExchangeTradeRequest so the node
+// refuses the trade if the exchange moved further against it. It is at least
+// 1, as the node requires.
+func (q ExchangeQuote) MinExpected(tolerance float64) int64 {
+	tolerance = min(max(tolerance, 0), 1)
+	return max(int64(math.Floor(float64(q.Buy)*(1-tolerance))), 1)
+}
+
+// Quote prices selling quant of sellTokenID into the exchange, with the checks
+// ExchangeTransactionActuator makes of the exchange: the token is one of its
+// pair, it is not closed and the sale keeps it within MaxExchangeBalance.
+// A sale too small to buy anything is refused with ErrInvalidAmount.
+func (e Exchange) Quote(sellTokenID string, quant int64) (*ExchangeQuote, error) {
+	if quant <= 0 {
+		return nil, fmt.Errorf("%w: quant must be greater than zero", ErrInvalidAmount)
+	}
+
+	var (
+		buyTokenID              string
+		sellBalance, buyBalance int64
+	)
+	switch sellTokenID {
+	case e.FirstTokenID:
+		buyTokenID, sellBalance, buyBalance = e.SecondTokenID, e.FirstTokenBalance, e.SecondTokenBalance
+	case e.SecondTokenID:
+		buyTokenID, sellBalance, buyBalance = e.FirstTokenID, e.SecondTokenBalance, e.FirstTokenBalance
+	default:
+		return nil, fmt.Errorf("%w: token %q is not in exchange %d", ErrInvalidParams, sellTokenID, e.ID)
+	}
+
+	if e.Closed() {
+		return nil, fmt.Errorf("%w: exchange %d is closed", ErrInvalidParams, e.ID)
+	}
+	if quant > MaxExchangeBalance-sellBalance {
+		return nil, fmt.Errorf("%w: exchange %d would hold more than %d of token %q", ErrInvalidParams, e.ID, int64(MaxExchangeBalance), sellTokenID)
+	}
+
+	buy := BancorExchange(sellBalance, buyBalance, quant)
+	if buy <= 0 {
+		return nil, fmt.Errorf("%w: selling %d of token %q buys nothing", ErrInvalidAmount, quant, sellTokenID)
+	}
+
+	spot := float64(buyBalance) / float64(sellBalance)
+	price := float64(buy) / float64(quant)
+
+	return &ExchangeQuote{
+		SellTokenID: sellTokenID,
+		Sell:        quant,
+		BuyTokenID:  buyTokenID,
+		Buy:         buy,
+		SpotPrice:   spot,
+		Price:       price,
+		Slippage:    1 - price/spot,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBancorExchange(t *testing.T) {
+	// java-tron's ExchangeProcessorTest: 2 million TRX into an exchange
+	// holding 100 million TRX and 128Gi of a token.
+	require.Equal(t, int64(2_694_881_440), BancorExchange(100_000_000_000_000, 128*1024*1024*1024, 2_000_000_000_000))
+
+	// Close to a constant product trade: 1e10 * 1e11 / 1.1e12.
+	require.Equal(t, int64(909_090_909), BancorExchange(1_000_000_000_000, 10_000_000_000, 100_000_000_000))
+}
+
+func testExchange() Exchange {
+	return Exchange{
+		ID:                 5,
+		FirstTokenID:       ExchangeTRX,
+		FirstTokenBalance:  1_000_000_000_000,
+		SecondTokenID:      "1000001",
+		SecondTokenBalance: 10_000_000_000,
+	}
+}
+
+func TestExchangeQuote(t *testing.T) {
+	e := testExchange()
+
+	q, err := e.Quote(ExchangeTRX, 100_000_000_000)
+	require.NoError(t, err)
+	require.Equal(t, "1000001", q.BuyTokenID)
+	require.Equal(t, int64(909_090_909), q.Buy)
+	require.InDelta(t, 0.01, q.SpotPrice, 1e-12)
+	require.InDelta(t, 0.00909090909, q.Price, 1e-9)
+	require.InDelta(t, 1.0/11, q.Slippage, 1e-6, "selling a tenth of the pool moves the price by about 1/11")
+
+	small, err := e.Quote(ExchangeTRX, 1_000_000_000)
+	require.NoError(t, err)
+	require.Less(t, small.Slippage, q.Slippage, "a smaller sale slips less")
+
+	back, err := e.Quote("1000001", 1_000_000)
+	require.NoError(t, err)
+	require.Equal(t, ExchangeTRX, back.BuyTokenID)
+	require.InDelta(t, 100.0, back.SpotPrice, 1e-9)
+
+	_, err = e.Quote("1000002", 1)
+	require.ErrorIs(t, err, ErrInvalidParams, "not one of the pair")
+	_, err = e.Quote(ExchangeTRX, 0)
+	require.ErrorIs(t, err, ErrInvalidAmount)
+	_, err = e.Quote(ExchangeTRX, 1)
+	require.ErrorIs(t, err, ErrInvalidAmount, "a sale too small to buy anything")
+	_, err = e.Quote(ExchangeTRX, MaxExchangeBalance)
+	require.ErrorIs(t, err, ErrInvalidParams, "over the balance limit")
+
+	closed := e
+	closed.SecondTokenBalance = 0
+	require.True(t, closed.Closed())
+	_, err = closed.Quote(ExchangeTRX, 1_000_000)
+	require.ErrorIs(t, err, ErrInvalidParams)
+}
+
+func TestExchangeQuoteMinExpected(t *testing.T) {
+	q := ExchangeQuote{Buy: 1000}
+
+	require.Equal(t, int64(1000), q.MinExpected(0))
+	require.Equal(t, int64(990), q.MinExpected(0.01))
+	require.Equal(t, int64(1000), q.MinExpected(-1), "a negative tolerance is none")
+	require.Equal(t, int64(1), q.MinExpected(1), "the node wants at least 1")
+}
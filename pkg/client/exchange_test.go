@@ -0,0 +1,197 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+)
+
+func TestExchangeCreateRequestValidate(t *testing.T) {
+	valid := ExchangeCreateRequest{
+		Owner:              testAddr,
+		FirstTokenID:       ExchangeTRX,
+		FirstTokenBalance:  1_000_000,
+		SecondTokenID:      "1000001",
+		SecondTokenBalance: 100,
+	}
+	require.NoError(t, valid.Validate())
+
+	cases := []struct {
+		name    string
+		edit    func(*ExchangeCreateRequest)
+		wantErr error
+	}{
+		{"no owner", func(r *ExchangeCreateRequest) { r.Owner = "" }, ErrEmptyAddress},
+		{"token name", func(r *ExchangeCreateRequest) { r.SecondTokenID = "BTT" }, ErrInvalidParams},
+		{"same tokens", func(r *ExchangeCreateRequest) { r.SecondTokenID = ExchangeTRX }, ErrInvalidParams},
+		{"zero balance", func(r *ExchangeCreateRequest) { r.FirstTokenBalance = 0 }, ErrInvalidAmount},
+		{"over the limit", func(r *ExchangeCreateRequest) { r.SecondTokenBalance = MaxExchangeBalance + 1 }, ErrInvalidAmount},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := valid
+			tc.edit(&req)
+			require.ErrorIs(t, req.Validate(), tc.wantErr)
+		})
+	}
+}
+
+func TestCreateExchangeChecksOwner(t *testing.T) {
+	params := &core.ChainParameters{ChainParameter: []*core.ChainParameters_ChainParameter{
+		{Key: "getExchangeCreateFee", Value: 1_024_000_000},
+	}}
+	req := ExchangeCreateRequest{
+		Owner:              testAddr,
+		FirstTokenID:       ExchangeTRX,
+		FirstTokenBalance:  1_000_000,
+		SecondTokenID:      "1000001",
+		SecondTokenBalance: 100,
+	}
+
+	cases := []struct {
+		name    string
+		account *core.Account
+		wantErr error
+	}{
+		{"fee and TRX side together", &core.Account{Balance: 1_024_999_999, AssetV2: map[string]int64{"1000001": 100}}, ErrInsufficientBalance},
+		{"token side", &core.Account{Balance: 2_000_000_000, AssetV2: map[string]int64{"1000001": 99}}, ErrInsufficientBalance},
+		{"enough", &core.Account{Balance: 1_025_000_000, AssetV2: map[string]int64{"1000001": 100}}, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var sent *core.ExchangeCreateContract
+			c := newTestClient(&fakeTransport{
+				getChainParameters: func(context.Context) (*core.ChainParameters, error) { return params, nil },
+				getAccount: func(_ context.Context, a *core.Account) (*core.Account, error) {
+					tc.account.Address = a.GetAddress()
+					return tc.account, nil
+				},
+				exchangeCreate: func(_ context.Context, ct *core.ExchangeCreateContract) (*api.TransactionExtention, error) {
+					sent = ct
+					return okTx(), nil
+				},
+			})
+
+			_, err := c.CreateExchange(t.Context(), req)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				require.Nil(t, sent, "an exchange the owner cannot fund is not sent")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []byte(ExchangeTRX), sent.GetFirstTokenId())
+			require.Equal(t, []byte("1000001"), sent.GetSecondTokenId())
+		})
+	}
+}
+
+func TestInjectExchangeValidates(t *testing.T) {
+	var sent *core.ExchangeInjectContract
+	c := newTestClient(&fakeTransport{
+		exchangeInject: func(_ context.Context, ct *core.ExchangeInjectContract) (*api.TransactionExtention, error) {
+			sent = ct
+			return okTx(), nil
+		},
+	})
+
+	_, err := c.InjectExchange(t.Context(), testAddr, 5, ExchangeTRX, 1_000_000)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), sent.GetExchangeId())
+	require.Equal(t, int64(1_000_000), sent.GetQuant())
+
+	_, err = c.InjectExchange(t.Context(), testAddr, 0, ExchangeTRX, 1)
+	require.ErrorIs(t, err, ErrInvalidParams)
+	_, err = c.InjectExchange(t.Context(), testAddr, 5, "trx", 1)
+	require.ErrorIs(t, err, ErrInvalidParams)
+	_, err = c.InjectExchange(t.Context(), testAddr, 5, ExchangeTRX, -1)
+	require.ErrorIs(t, err, ErrInvalidAmount)
+}
+
+func TestTradeExchangeBuildsContract(t *testing.T) {
+	var sent *core.ExchangeTransactionContract
+	c := newTestClient(&fakeTransport{
+		exchangeTransaction: func(_ context.Context, ct *core.ExchangeTransactionContract) (*api.TransactionExtention, error) {
+			sent = ct
+			return okTx(), nil
+		},
+	})
+
+	req := ExchangeTradeRequest{Owner: testAddr, ExchangeID: 5, SellTokenID: "1000001", Quant: 300, Expected: 29_000}
+	_, err := c.TradeExchange(t.Context(), req)
+	require.NoError(t, err)
+	require.Equal(t, mustDecode(t, testAddr), sent.GetOwnerAddress())
+	require.Equal(t, []byte("1000001"), sent.GetTokenId())
+	require.Equal(t, int64(300), sent.GetQuant())
+	require.Equal(t, int64(29_000), sent.GetExpected())
+
+	sent = nil
+	req.Expected = 0
+	_, err = c.TradeExchange(t.Context(), req)
+	require.ErrorIs(t, err, ErrInvalidAmount, "the node wants a positive expected amount")
+	require.Nil(t, sent)
+}
+
+func TestGetExchangeById(t *testing.T) {
+	created := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newTestClient(&fakeTransport{
+		getExchangeById: func(_ context.Context, id int64) (*core.Exchange, error) {
+			if id != 5 {
+				return &core.Exchange{}, nil
+			}
+			return &core.Exchange{
+				ExchangeId:         5,
+				CreatorAddress:     mustDecode(t, testAddr),
+				CreateTime:         created.UnixMilli(),
+				FirstTokenId:       []byte("1000001"),
+				FirstTokenBalance:  100,
+				SecondTokenId:      []byte(ExchangeTRX),
+				SecondTokenBalance: 1_000_000,
+			}, nil
+		},
+	})
+
+	e, err := c.GetExchangeById(t.Context(), 5)
+	require.NoError(t, err)
+	require.Equal(t, testAddr, e.Creator)
+	require.True(t, created.Equal(e.CreateTime))
+	require.Equal(t, "1000001", e.FirstTokenID)
+	require.Equal(t, ExchangeTRX, e.SecondTokenID)
+
+	q, err := c.QuoteExchange(t.Context(), 5, "1000001", 10)
+	require.NoError(t, err)
+	require.Equal(t, BancorExchange(100, 1_000_000, 10), q.Buy)
+
+	_, err = c.GetExchangeById(t.Context(), 6)
+	require.ErrorIs(t, err, ErrExchangeNotFound)
+	_, err = c.QuoteExchange(t.Context(), 6, ExchangeTRX, 10)
+	require.ErrorIs(t, err, ErrExchangeNotFound)
+
+	_, err = c.GetExchangeById(t.Context(), -1)
+	require.ErrorIs(t, err, ErrInvalidParams)
+}
+
+func TestGetPaginatedExchangeListLimits(t *testing.T) {
+	var got *api.PaginatedMessage
+	c := newTestClient(&fakeTransport{
+		getPaginatedExchangeList: func(_ context.Context, m *api.PaginatedMessage) (*api.ExchangeList, error) {
+			got = m
+			return &api.ExchangeList{Exchanges: []*core.Exchange{{ExchangeId: 3}}}, nil
+		},
+	})
+
+	list, err := c.GetPaginatedExchangeList(t.Context(), 10, 5)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, int64(10), got.GetOffset())
+	require.Equal(t, int64(5), got.GetLimit())
+
+	_, err = c.GetPaginatedExchangeList(t.Context(), 0, MaxExchangePage+1)
+	require.ErrorIs(t, err, ErrInvalidParams)
+	_, err = c.GetPaginatedExchangeList(t.Context(), -1, 10)
+	require.ErrorIs(t, err, ErrInvalidParams)
+}
@@ -49,6 +49,33 @@ type fakeTransport struct {
 	getRewardInfo      func(ctx context.Context, address []byte) (*api.NumberMessage, error)
 	getBrokerageInfo   func(ctx context.Context, address []byte) (*api.NumberMessage, error)
 
+	createWitness              func(ctx context.Context, c *core.WitnessCreateContract) (*api.TransactionExtention, error)
+	updateBrokerage            func(ctx context.Context, c *core.UpdateBrokerageContract) (*api.TransactionExtention, error)
+	getPaginatedNowWitnessList func(ctx context.Context, m *api.PaginatedMessage) (*api.WitnessList, error)
+
+	getAssetIssueById func(ctx context.Context, id []byte) (*core.AssetIssueContract, error)
+	transferAsset     func(ctx context.Context, c *core.TransferAssetContract) (*api.TransactionExtention, error)
+
+	getAssetIssueByAccount     func(ctx context.Context, address []byte) (*api.AssetIssueList, error)
+	getPaginatedAssetIssueList func(ctx context.Context, m *api.PaginatedMessage) (*api.AssetIssueList, error)
+	createAssetIssue           func(ctx context.Context, c *core.AssetIssueContract) (*api.TransactionExtention, error)
+	participateAssetIssue      func(ctx context.Context, c *core.ParticipateAssetIssueContract) (*api.TransactionExtention, error)
+	updateAsset                func(ctx context.Context, c *core.UpdateAssetContract) (*api.TransactionExtention, error)
+	unfreezeAsset              func(ctx context.Context, c *core.UnfreezeAssetContract) (*api.TransactionExtention, error)
+
+	proposalCreate           func(ctx context.Context, c *core.ProposalCreateContract) (*api.TransactionExtention, error)
+	proposalApprove          func(ctx context.Context, c *core.ProposalApproveContract) (*api.TransactionExtention, error)
+	listProposals            func(ctx context.Context) (*api.ProposalList, error)
+	getPaginatedProposalList func(ctx context.Context, m *api.PaginatedMessage) (*api.ProposalList, error)
+	getProposalById          func(ctx context.Context, id int64) (*core.Proposal, error)
+
+	exchangeCreate           func(ctx context.Context, c *core.ExchangeCreateContract) (*api.TransactionExtention, error)
+	exchangeInject           func(ctx context.Context, c *core.ExchangeInjectContract) (*api.TransactionExtention, error)
+	exchangeTransaction      func(ctx context.Context, c *core.ExchangeTransactionContract) (*api.TransactionExtention, error)
+	listExchanges            func(ctx context.Context) (*api.ExchangeList, error)
+	getPaginatedExchangeList func(ctx context.Context, m *api.PaginatedMessage) (*api.ExchangeList, error)
+	getExchangeById          func(ctx context.Context, id int64) (*core.Exchange, error)
+
 	closeFn func() error
 
 	closeCalls int
@@ -331,13 +358,179 @@ func (f *fakeTransport) GetBrokerageInfo(ctx context.Context, address []byte) (*
 	return nil, nil
 }
 
-func (f *fakeTransport) GetAssetIssueById(context.Context, []byte) (*core.AssetIssueContract, error) {
+func (f *fakeTransport) CreateWitness(ctx context.Context, c *core.WitnessCreateContract) (*api.TransactionExtention, error) {
+	if f.createWitness != nil {
+		return f.createWitness(ctx, c)
+	}
+	return nil, nil
+}
+
+func (f *fakeTransport) UpdateWitness(context.Context, *core.WitnessUpdateContract) (*api.TransactionExtention, error) {
+	return nil, nil
+}
+
+func (f *fakeTransport) UpdateBrokerage(ctx context.Context, c *core.UpdateBrokerageContract) (*api.TransactionExtention, error) {
+	if f.updateBrokerage != nil {
+		return f.updateBrokerage(ctx, c)
+	}
+	return nil, nil
+}
+
+func (f *fakeTransport) GetPaginatedNowWitnessList(ctx context.Context, m *api.PaginatedMessage) (*api.WitnessList, error) {
+	if f.getPaginatedNowWitnessList != nil {
+		return f.getPaginatedNowWitnessList(ctx, m)
+	}
+	return nil, nil
+}
+
+func (f *fakeTransport) GetAssetIssueById(ctx context.Context, id []byte) (*core.AssetIssueContract, error) {
+	if f.getAssetIssueById != nil {
+		return f.getAssetIssueById(ctx, id)
+	}
 	return nil, nil
 }
 
 func (f *fakeTransport) GetAssetIssueListByName(context.Context, []byte) (*api.AssetIssueList, error) {
 	return nil, nil
 }
+
+func (f *fakeTransport) GetAssetIssueByAccount(ctx context.Context, address []byte) (*api.AssetIssueList, error) {
+	if f.getAssetIssueByAccount != nil {
+		return f.getAssetIssueByAccount(ctx, address)
+	}
+	return nil, nil
+}
+
+func (f *fakeTransport) GetAssetIssueList(context.Context) (*api.AssetIssueList, error) {
+	return nil, nil
+}
+
+func (f *fakeTransport) GetPaginatedAssetIssueList(ctx context.Context, m *api.PaginatedMessage) (*api.AssetIssueList, error) {
+	if f.getPaginatedAssetIssueList != nil {
+		return f.getPaginatedAssetIssueList(ctx, m)
+	}
+	return nil, nil
+}
+
+func (f *fakeTransport) TransferAsset(ctx context.Context, c *core.TransferAssetContract) (*api.TransactionExtention, error) {
+	if f.transferAsset != nil {
+		return f.transferAsset(ctx, c)
+	}
+	return nil, nil
+}
+
+func (f *fakeTransport) CreateAssetIssue(ctx context.Context, c *core.AssetIssueContract) (*api.TransactionExtention, error) {
+	if f.createAssetIssue != nil {
+		return f.createAssetIssue(ctx, c)
+	}
+	return nil, nil
+}
+
+func (f *fakeTransport) ParticipateAssetIssue(ctx context.Context, c *core.ParticipateAssetIssueContract) (*api.TransactionExtention, error) {
+	if f.participateAssetIssue != nil {
+		return f.participateAssetIssue(ctx, c)
+	}
+	return nil, nil
+}
+
+func (f *fakeTransport) UpdateAsset(ctx context.Context, c *core.UpdateAssetContract) (*api.TransactionExtention, error) {
+	if f.updateAsset != nil {
+		return f.updateAsset(ctx, c)
+	}
+	return nil, nil
+}
+
+func (f *fakeTransport) UnfreezeAsset(ctx context.Context, c *core.UnfreezeAssetContract) (*api.TransactionExtention, error) {
+	if f.unfreezeAsset != nil {
+		return f.unfreezeAsset(ctx, c)
+	}
+	return nil, nil
+}
+
+func (f *fakeTransport) ProposalCreate(ctx context.Context, c *core.ProposalCreateContract) (*api.TransactionExtention, error) {
+	if f.proposalCreate != nil {
+		return f.proposalCreate(ctx, c)
+	}
+	return nil, nil
+}
+
+func (f *fakeTransport) ProposalApprove(ctx context.Context, c *core.ProposalApproveContract) (*api.TransactionExtention, error) {
+	if f.proposalApprove != nil {
+		return f.proposalApprove(ctx, c)
+	}
+	return nil, nil
+}
+
+func (f *fakeTransport) ProposalDelete(context.Context, *core.ProposalDeleteContract) (*api.TransactionExtention, error) {
+	return nil, nil
+}
+
+func (f *fakeTransport) ListProposals(ctx context.Context) (*api.ProposalList, error) {
+	if f.listProposals != nil {
+		return f.listProposals(ctx)
+	}
+	return nil, nil
+}
+
+func (f *fakeTransport) GetPaginatedProposalList(ctx context.Context, m *api.PaginatedMessage) (*api.ProposalList, error) {
+	if f.getPaginatedProposalList != nil {
+		return f.getPaginatedProposalList(ctx, m)
+	}
+	return nil, nil
+}
+
+func (f *fakeTransport) GetProposalById(ctx context.Context, id int64) (*core.Proposal, error) {
+	if f.getProposalById != nil {
+		return f.getProposalById(ctx, id)
+	}
+	return nil, nil
+}
+
+func (f *fakeTransport) ExchangeCreate(ctx context.Context, c *core.ExchangeCreateContract) (*api.TransactionExtention, error) {
+	if f.exchangeCreate != nil {
+		return f.exchangeCreate(ctx, c)
+	}
+	return nil, nil
+}
+
+func (f *fakeTransport) ExchangeInject(ctx context.Context, c *core.ExchangeInjectContract) (*api.TransactionExtention, error) {
+	if f.exchangeInject != nil {
+		return f.exchangeInject(ctx, c)
+	}
+	return nil, nil
+}
+
+func (f *fakeTransport) ExchangeWithdraw(context.Context, *core.ExchangeWithdrawContract) (*api.TransactionExtention, error) {
+	return nil, nil
+}
+
+func (f *fakeTransport) ExchangeTransaction(ctx context.Context, c *core.ExchangeTransactionContract) (*api.TransactionExtention, error) {
+	if f.exchangeTransaction != nil {
+		return f.exchangeTransaction(ctx, c)
+	}
+	return nil, nil
+}
+
+func (f *fakeTransport) ListExchanges(ctx context.Context) (*api.ExchangeList, error) {
+	if f.listExchanges != nil {
+		return f.listExchanges(ctx)
+	}
+	return nil, nil
+}
+
+func (f *fakeTransport) GetPaginatedExchangeList(ctx context.Context, m *api.PaginatedMessage) (*api.ExchangeList, error) {
+	if f.getPaginatedExchangeList != nil {
+		return f.getPaginatedExchangeList(ctx, m)
+	}
+	return nil, nil
+}
+
+func (f *fakeTransport) GetExchangeById(ctx context.Context, id int64) (*core.Exchange, error) {
+	if f.getExchangeById != nil {
+		return f.getExchangeById(ctx, id)
+	}
+	return nil, nil
+}
 func (f *fakeTransport) ListNodes(context.Context) (*api.NodeList, error)    { return nil, nil }
 func (f *fakeTransport) GetNodeInfo(context.Context) (*core.NodeInfo, error) { return nil, nil }
 
@@ -3,32 +3,97 @@ package client
 import (
 	"context"
 	"fmt"
-	"sort"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/sxwebdev/gotron/schema/pb/api"
-	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/protobuf/proto"
 )
 
 // nodeState holds the runtime state of a single node managed by HealthAwareTransport.
-// All mutable fields are guarded by mu; transport, address and tier are immutable
-// after construction.
+// Mutable fields are guarded by mu, except the atomics and tier, which the
+// transport's poolMu guards; transport and address are immutable after
+// construction.
 type nodeState struct {
 	transport Transport
 	address   string
 	tier      int
+	// alias is NodeConfig.Alias; label names the node in metrics: the alias
+	// or the address.
+	alias string
+	label string
 
 	mu                 sync.Mutex
 	healthy            bool
 	consecutiveSuccess int
 	consecutiveFailure int
+	// latency is the moving average of live call latency, fed by
+	// recordOutcome and read by the SelectionStrategy.
+	latency time.Duration
+	// head is the head block reported to the last probe, when heads are
+	// tracked.
+	head blockHead
+	// lastErr is the last failure counted against the node, by a probe or
+	// a live call; lastProbe is when the last probe finished.
+	lastErr   error
+	lastProbe time.Time
+
+	// inFlight counts the live calls currently running on the node.
+	inFlight atomic.Int64
+
+	// refs counts the calls that chose the node and have not finished with
+	// it yet, so that RemoveNode can wait for them. Unlike inFlight it covers
+	// a call from the moment the node is chosen, while the pool is locked.
+	refs atomic.Int64
+	// removed is set when the node is taken out of the pool; drained then
+	// receives once refs drops to zero.
+	removed atomic.Bool
+	drained chan struct{}
 
 	// notifyCh is a size-1 buffered channel used by the parent transport to
 	// wake the per-node health-loop when the active tier changes (so the loop
 	// can recompute its probe interval without waiting for the current timer).
 	notifyCh chan struct{}
+
+	// stopCh is closed when the node is removed, stopping its health-loop;
+	// loopDone is closed when the loop has exited, and is nil when there is
+	// none.
+	stopCh   chan struct{}
+	loopDone chan struct{}
+}
+
+// newNodeState creates the state of a node, healthy until shown otherwise.
+func newNodeState(transport Transport, nc NodeConfig) *nodeState {
+	return &nodeState{
+		transport: transport,
+		address:   nc.Address,
+		tier:      nc.Tier,
+		alias:     nc.Alias,
+		label:     nc.metricsLabel(),
+		healthy:   true,
+		drained:   make(chan struct{}, 1),
+		notifyCh:  make(chan struct{}, 1),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// hold takes a reference on n for a call. It is only called while the pool is
+// locked or while the caller already holds a reference, so that a node being
+// removed never gains one.
+func (n *nodeState) hold() {
+	n.refs.Add(1)
+}
+
+// release gives back a reference taken by hold, and signals drained when it
+// was the last one of a removed node.
+func (n *nodeState) release() {
+	if n.refs.Add(-1) == 0 && n.removed.Load() {
+		select {
+		case n.drained <- struct{}{}:
+		default:
+		}
+	}
 }
 
 // HealthAwareTransport selects the next underlying Transport across multiple
@@ -38,22 +103,65 @@ type nodeState struct {
 // marked unhealthy.
 //
 // A background health-checker probes every node periodically (one goroutine
-// per node, started in the constructor and stopped by Close). Probe cadence
-// depends on node state and tier: HealthyInterval for the active tier,
+// per node, started with the node and stopped by RemoveNode or Close). Probe
+// cadence depends on node state and tier: HealthyInterval for the active tier,
 // InactiveTierInterval for healthy fallbacks, UnhealthyInterval for any
 // unhealthy node. Live RPC outcomes also feed the per-node thresholds, so
 // reality and probes converge quickly.
+//
+// A failed call is returned to the caller unless HealthConfig.Retry enables
+// retries, in which case idempotent calls that failed at the network level are
+// repeated on other healthy nodes (see RetryPolicy). HealthConfig.Hedge can
+// additionally duplicate slow reads to a second node of the same tier (see
+// HedgePolicy).
+//
+// Nodes can be added, removed and moved between tiers at runtime with AddNode,
+// RemoveNode and SetNodeTier, without disturbing the calls in progress.
 type HealthAwareTransport struct {
+	methodSet
 	cfg HealthConfig
 
+	// factory creates the transport of a node added with AddNode.
+	factory func(NodeConfig) (Transport, error)
+
+	// poolMu guards the membership of the pool: nodes, tiers, tierKeys,
+	// closed and the tier of every node. Calls take it for reading to choose
+	// a node; AddNode, RemoveNode, SetNodeTier and Close take it for writing.
+	poolMu   sync.RWMutex
 	nodes    []*nodeState
 	tiers    [][]*nodeState
 	tierKeys []int
-	counters []atomic.Uint64
+	closed   bool
+
+	// budgets holds the retry budget of every idempotent method; nil when
+	// retries are disabled.
+	budgets map[string]*retryBudget
+
+	// latencies holds the recent latencies of every read method, from which
+	// the hedge delay is derived; nil when hedging is disabled.
+	latencies map[string]*latencyTracker
+
+	// trackHeads is set when probes fetch the head block: always with the
+	// default probe, and with a custom one when lag detection is on.
+	// customProbe is set when cfg.Probe was supplied by the caller.
+	trackHeads  bool
+	customProbe bool
+
+	// bestHead is the highest head block any probe has seen.
+	headMu   sync.Mutex
+	bestHead blockHead
 
 	metrics    MetricsCollector
 	blockchain string
 	publishMu  sync.Mutex
+	// publishedTiers are the tiers last reported to a TierHealthRecorder,
+	// guarded by publishMu.
+	publishedTiers []int
+
+	// subs are the functions registered with Subscribe. The slice is
+	// replaced, never modified in place, so emit reads it without the lock.
+	subsMu sync.Mutex
+	subs   []*healthSubscriber
 
 	// activeTier is the lowest-numbered tier that currently has at least one
 	// healthy node, or -1 when every node is unhealthy. Stored atomically so
@@ -86,6 +194,7 @@ func NewHealthAwareTransport(
 	if len(nodes) == 0 {
 		return nil, fmt.Errorf("%w: at least one node is required", ErrInvalidConfig)
 	}
+	customProbe := cfg.Probe != nil
 	cfg = cfg.withDefaults()
 	if blockchain == "" {
 		blockchain = "tron"
@@ -102,49 +211,81 @@ func NewHealthAwareTransport(
 			return nil, fmt.Errorf("failed to create transport for node %d: %w", i, err)
 		}
 		created = append(created, tr)
-		states = append(states, &nodeState{
-			transport: tr,
-			address:   nc.Address,
-			tier:      nc.Tier,
-			healthy:   true,
-			notifyCh:  make(chan struct{}, 1),
-		})
+		states = append(states, newNodeState(tr, nc))
 	}
 
-	tierMap := make(map[int][]*nodeState)
+	h := &HealthAwareTransport{
+		cfg:         cfg,
+		factory:     factory,
+		budgets:     newRetryBudgets(cfg.Retry),
+		latencies:   newLatencyTrackers(cfg.Hedge),
+		trackHeads:  !customProbe || cfg.detectsLag(),
+		customProbe: customProbe,
+		metrics:     metrics,
+		blockchain:  blockchain,
+		stopCh:      make(chan struct{}),
+	}
+	h.handle = h.call
 	for _, s := range states {
-		tierMap[s.tier] = append(tierMap[s.tier], s)
+		h.attach(s)
 	}
-	keys := make([]int, 0, len(tierMap))
-	for k := range tierMap {
-		keys = append(keys, k)
+	h.activeTier.Store(int64(h.tierKeys[0])) // all healthy at start → lowest tier is active
+	h.publishPoolMetrics()
+
+	for _, s := range states {
+		h.startLoop(s)
 	}
-	sort.Ints(keys)
-	tiers := make([][]*nodeState, len(keys))
-	for i, k := range keys {
-		tiers[i] = tierMap[k]
+	return h, nil
+}
+
+// attach adds n to nodes and to the tier it belongs to. It must be called
+// with poolMu held for writing.
+func (h *HealthAwareTransport) attach(n *nodeState) {
+	h.nodes = append(h.nodes, n)
+	h.joinTier(n)
+}
+
+// detach takes n out of nodes and out of its tier. It must be called with
+// poolMu held for writing.
+func (h *HealthAwareTransport) detach(n *nodeState) {
+	h.nodes = slices.DeleteFunc(h.nodes, func(x *nodeState) bool { return x == n })
+	h.leaveTier(n)
+}
+
+// joinTier adds n to the tier numbered n.tier, creating the tier when it is
+// new.
+func (h *HealthAwareTransport) joinTier(n *nodeState) {
+	i, ok := slices.BinarySearch(h.tierKeys, n.tier)
+	if !ok {
+		h.tierKeys = slices.Insert(h.tierKeys, i, n.tier)
+		h.tiers = slices.Insert(h.tiers, i, []*nodeState(nil))
 	}
+	h.tiers[i] = append(h.tiers[i], n)
+}
 
-	h := &HealthAwareTransport{
-		cfg:        cfg,
-		nodes:      states,
-		tiers:      tiers,
-		tierKeys:   keys,
-		counters:   make([]atomic.Uint64, len(keys)),
-		metrics:    metrics,
-		blockchain: blockchain,
-		stopCh:     make(chan struct{}),
-	}
-	h.activeTier.Store(int64(keys[0])) // all healthy at start → lowest tier is active
-	h.publishPoolMetrics()
+// leaveTier takes n out of the tier numbered n.tier, dropping the tier when
+// n was its last node.
+func (h *HealthAwareTransport) leaveTier(n *nodeState) {
+	i, ok := slices.BinarySearch(h.tierKeys, n.tier)
+	if !ok {
+		return
+	}
+	h.tiers[i] = slices.DeleteFunc(h.tiers[i], func(x *nodeState) bool { return x == n })
+	if len(h.tiers[i]) == 0 {
+		h.tierKeys = slices.Delete(h.tierKeys, i, i+1)
+		h.tiers = slices.Delete(h.tiers, i, i+1)
+	}
+}
 
-	if !cfg.Disabled {
-		for _, s := range states {
-			h.wg.Add(1)
-			go h.healthCheckLoop(s)
-		}
+// startLoop starts the health-loop of n unless health checking is disabled.
+// It must be called before Close, or with poolMu held and closed unset.
+func (h *HealthAwareTransport) startLoop(n *nodeState) {
+	if h.cfg.Disabled {
+		return
 	}
-	return h, nil
+	n.loopDone = make(chan struct{})
+	h.wg.Add(1)
+	go h.healthCheckLoop(n)
 }
 
 // withDefaults fills zero-valued fields with sensible defaults. Returns a copy.
@@ -179,33 +320,139 @@ func (c HealthConfig) withDefaults() HealthConfig {
 	if c.Logger == nil {
 		c.Logger = noopLogger{}
 	}
+	if c.Selection == nil {
+		c.Selection = NewRoundRobinStrategy()
+	}
+	c.Retry = c.Retry.withDefaults()
+	c.Hedge = c.Hedge.withDefaults()
 	return c
 }
 
-// next picks the next healthy node according to tier priority + round-robin
-// within a tier. Returns ErrNoHealthyNodes when nothing is available.
-func (h *HealthAwareTransport) next() (*nodeState, error) {
-	for tierIdx, group := range h.tiers {
-		startIdx := h.counters[tierIdx].Add(1) - 1
-		groupLen := uint64(len(group))
-		for i := range groupLen {
-			n := group[(startIdx+i)%groupLen]
-			n.mu.Lock()
-			ok := n.healthy
-			n.mu.Unlock()
-			if ok {
-				return n, nil
-			}
+// next picks the next healthy node according to tier priority, and
+// cfg.Selection within a tier, skipping the nodes in exclude (those a retry already tried).
+// Returns ErrNoHealthyNodes when nothing is available. It must be called with
+// poolMu held for reading.
+func (h *HealthAwareTransport) next(exclude []*nodeState) (*nodeState, error) {
+	for tierIdx := range h.tiers {
+		if n := h.pick(tierIdx, exclude); n != nil {
+			return n, nil
 		}
 	}
 	return nil, ErrNoHealthyNodes
 }
 
+// choose picks the node for a call with options o, skipping the nodes in
+// exclude: the pinned node regardless of its health, the next healthy node of
+// the requested tier, or the next healthy node by tier priority. The node is
+// returned with a reference held, which the caller releases once done with it.
+func (h *HealthAwareTransport) choose(o callOptions, exclude []*nodeState) (*nodeState, error) {
+	h.poolMu.RLock()
+	defer h.poolMu.RUnlock()
+	n, err := h.chooseLocked(o, exclude)
+	if err != nil {
+		return nil, err
+	}
+	n.hold()
+	return n, nil
+}
+
+func (h *HealthAwareTransport) chooseLocked(o callOptions, exclude []*nodeState) (*nodeState, error) {
+	switch {
+	case o.node != "":
+		n := h.find(o.node)
+		if n == nil {
+			return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, o.node)
+		}
+		if slices.Contains(exclude, n) {
+			// Already tried: a pinned call has nowhere else to go.
+			return nil, ErrNoHealthyNodes
+		}
+		return n, nil
+	case o.hasTier:
+		tierIdx, ok := slices.BinarySearch(h.tierKeys, o.tier)
+		if !ok {
+			return nil, fmt.Errorf("%w: tier %d", ErrNodeNotFound, o.tier)
+		}
+		if n := h.pick(tierIdx, exclude); n != nil {
+			return n, nil
+		}
+		return nil, ErrNoHealthyNodes
+	default:
+		return h.next(exclude)
+	}
+}
+
+// find returns the node with address, nil when there is none. It must be
+// called with poolMu held.
+func (h *HealthAwareTransport) find(address string) *nodeState {
+	for _, n := range h.nodes {
+		if n.address == address {
+			return n
+		}
+	}
+	return nil
+}
+
+// nextInTier is next restricted to the tier of n, for a hedge that must stay
+// in the tier of the request it duplicates. Like choose, it returns the node
+// with a reference held.
+func (h *HealthAwareTransport) nextInTier(n *nodeState, exclude []*nodeState) (*nodeState, error) {
+	h.poolMu.RLock()
+	defer h.poolMu.RUnlock()
+	tierIdx, ok := slices.BinarySearch(h.tierKeys, n.tier)
+	if !ok {
+		return nil, ErrNoHealthyNodes
+	}
+	next := h.pick(tierIdx, exclude)
+	if next == nil {
+		return nil, ErrNoHealthyNodes
+	}
+	next.hold()
+	return next, nil
+}
+
+// pick lets cfg.Selection choose among the healthy nodes of h.tiers[tierIdx]
+// that are not in exclude, and returns nil when there is none. It must be
+// called with poolMu held for reading.
+func (h *HealthAwareTransport) pick(tierIdx int, exclude []*nodeState) *nodeState {
+	group := h.tiers[tierIdx]
+	candidates := make([]*nodeState, 0, len(group))
+	loads := make([]NodeLoad, 0, len(group))
+	for _, n := range group {
+		if slices.Contains(exclude, n) {
+			continue
+		}
+		n.mu.Lock()
+		ok, latency := n.healthy, n.latency
+		n.mu.Unlock()
+		if !ok {
+			continue
+		}
+		candidates = append(candidates, n)
+		loads = append(loads, NodeLoad{
+			Address:  n.address,
+			InFlight: int(n.inFlight.Load()),
+			Latency:  latency,
+		})
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	i := h.cfg.Selection.Select(loads)
+	if i < 0 || i >= len(candidates) {
+		// A custom strategy returned garbage; keep serving rather than panic.
+		i = 0
+	}
+	return candidates[i]
+}
+
 // recordOutcome feeds the result of a live RPC call into the per-node counters.
-// Successes always count toward consecutiveSuccess; only network-level errors
-// (per cfg.ClassifyErr) count toward consecutiveFailure — logical errors
-// leave node health untouched.
-func (h *HealthAwareTransport) recordOutcome(n *nodeState, err error) {
+// Its latency always updates the node's moving average. Successes always count
+// toward consecutiveSuccess; only network-level errors (per cfg.ClassifyErr)
+// count toward consecutiveFailure — logical errors leave node health
+// untouched.
+func (h *HealthAwareTransport) recordOutcome(n *nodeState, err error, latency time.Duration) {
+	recordLatency(n, latency)
 	if err == nil {
 		h.markSuccess(n)
 		return
@@ -228,7 +475,7 @@ func (h *HealthAwareTransport) markSuccess(n *nodeState) {
 	}
 	n.mu.Unlock()
 	if transition {
-		h.logTransition(n, "healthy", nil)
+		h.reportTransition(n, true, nil)
 		h.onStateChange()
 	}
 }
@@ -240,25 +487,42 @@ func (h *HealthAwareTransport) markFailure(n *nodeState, cause error) {
 	n.mu.Lock()
 	n.consecutiveSuccess = 0
 	n.consecutiveFailure++
+	n.lastErr = cause
 	transition := n.healthy && n.consecutiveFailure >= h.cfg.FailureThreshold
 	if transition {
 		n.healthy = false
 	}
 	n.mu.Unlock()
 	if transition {
-		h.logTransition(n, "unhealthy", cause)
+		h.reportTransition(n, false, cause)
 		h.onStateChange()
 	}
 }
 
 // onStateChange recomputes activeTier, publishes pool-health metrics, and
 // pings every node's notifyCh when the active tier has shifted so the
-// per-node loops can recompute their probe interval immediately.
+// per-node loops can recompute their probe interval immediately. A shift is
+// also reported to the subscribers, once the locks are released.
 func (h *HealthAwareTransport) onStateChange() {
+	old, newActive := h.recomputeActiveTier()
+	if old != newActive {
+		h.emit(HealthEvent{
+			Kind:         EventTierShift,
+			Tier:         int(newActive),
+			PreviousTier: int(old),
+		})
+	}
+}
+
+// recomputeActiveTier is onStateChange under the locks; it returns the active
+// tier before and after.
+func (h *HealthAwareTransport) recomputeActiveTier() (old, newActive int64) {
 	h.publishMu.Lock()
 	defer h.publishMu.Unlock()
+	h.poolMu.RLock()
+	defer h.poolMu.RUnlock()
 
-	newActive := int64(-1)
+	newActive = -1
 	for tierIdx, group := range h.tiers {
 		found := false
 		for _, n := range group {
@@ -275,7 +539,7 @@ func (h *HealthAwareTransport) onStateChange() {
 			break
 		}
 	}
-	old := h.activeTier.Swap(newActive)
+	old = h.activeTier.Swap(newActive)
 	h.publishPoolMetricsLocked()
 
 	if old != newActive {
@@ -288,16 +552,20 @@ func (h *HealthAwareTransport) onStateChange() {
 			}
 		}
 	}
+	return old, newActive
 }
 
-// publishPoolMetrics is the public entry-point — takes the lock.
+// publishPoolMetrics is the public entry-point — takes the locks.
 func (h *HealthAwareTransport) publishPoolMetrics() {
 	h.publishMu.Lock()
 	defer h.publishMu.Unlock()
+	h.poolMu.RLock()
+	defer h.poolMu.RUnlock()
 	h.publishPoolMetricsLocked()
 }
 
-// publishPoolMetricsLocked must be called with publishMu held.
+// publishPoolMetricsLocked must be called with publishMu held, and poolMu
+// held for reading.
 func (h *HealthAwareTransport) publishPoolMetricsLocked() {
 	if h.metrics == nil {
 		return
@@ -312,21 +580,50 @@ func (h *HealthAwareTransport) publishPoolMetricsLocked() {
 		n.mu.Unlock()
 	}
 	h.metrics.SetPoolHealth(h.blockchain, total, healthy, total-healthy)
+
+	r, ok := h.metrics.(TierHealthRecorder)
+	if !ok {
+		return
+	}
+	active := int(h.activeTier.Load())
+	for i, group := range h.tiers {
+		healthy := 0
+		for _, n := range group {
+			n.mu.Lock()
+			if n.healthy {
+				healthy++
+			}
+			n.mu.Unlock()
+		}
+		r.SetTierHealth(h.blockchain, h.tierKeys[i], len(group), healthy, h.tierKeys[i] == active)
+	}
+	// A tier emptied since the last time is reported once more, as empty,
+	// so that its gauges do not keep their last values.
+	for _, tier := range h.publishedTiers {
+		if !slices.Contains(h.tierKeys, tier) {
+			r.SetTierHealth(h.blockchain, tier, 0, 0, false)
+		}
+	}
+	h.publishedTiers = slices.Clone(h.tierKeys)
 }
 
 // healthCheckLoop is the per-node background probing goroutine. It exits when
-// stopCh is closed. Three wakeup sources:
+// stopCh or the node's stopCh is closed. Four wakeup sources:
 //   - stopCh: shutdown
+//   - n.stopCh: the node was removed
 //   - notifyCh: active tier shifted, recompute interval without probing now
 //   - timer.C: time to probe
 func (h *HealthAwareTransport) healthCheckLoop(n *nodeState) {
 	defer h.wg.Done()
+	defer close(n.loopDone)
 	timer := time.NewTimer(h.intervalFor(n))
 	defer timer.Stop()
 	for {
 		select {
 		case <-h.stopCh:
 			return
+		case <-n.stopCh:
+			return
 		case <-n.notifyCh:
 			if !timer.Stop() {
 				select {
@@ -343,8 +640,8 @@ func (h *HealthAwareTransport) healthCheckLoop(n *nodeState) {
 }
 
 // probeOnce runs a single health-probe under ProbeTimeout, with a context that
-// is also cancelled when stopCh closes — so Close() never has to wait for an
-// in-flight probe to finish naturally.
+// is also cancelled when stopCh or the node's stopCh closes — so neither Close()
+// nor RemoveNode ever has to wait for an in-flight probe to finish naturally.
 func (h *HealthAwareTransport) probeOnce(n *nodeState) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -364,16 +661,24 @@ func (h *HealthAwareTransport) probeOnce(n *nodeState) {
 		select {
 		case <-h.stopCh:
 			cancel()
+		case <-n.stopCh:
+			cancel()
 		case <-done:
 		}
 	}()
 
-	err := h.cfg.Probe(ctx, n.transport)
-	if err == nil {
+	start := time.Now()
+	err := h.probe(ctx, n)
+	h.recordProbe(n, err, time.Since(start))
+	n.mu.Lock()
+	n.lastProbe = time.Now()
+	n.mu.Unlock()
+	switch {
+	case err == nil:
 		h.markSuccess(n)
-		return
-	}
-	if h.cfg.ClassifyErr(err) {
+	case isLagging(err):
+		h.markLagging(n, err)
+	case h.cfg.ClassifyErr(err):
 		h.markFailure(n, err)
 	}
 }
@@ -389,31 +694,84 @@ func (h *HealthAwareTransport) intervalFor(n *nodeState) time.Duration {
 	if !healthy {
 		return h.cfg.UnhealthyInterval
 	}
-	if int64(n.tier) == h.activeTier.Load() {
+	if int64(h.tierOf(n)) == h.activeTier.Load() {
 		return h.cfg.HealthyInterval
 	}
 	return h.cfg.InactiveTierInterval
 }
 
-func (h *HealthAwareTransport) logTransition(n *nodeState, to string, cause error) {
+// tierOf returns the tier of n, which SetNodeTier may change.
+func (h *HealthAwareTransport) tierOf(n *nodeState) int {
+	h.poolMu.RLock()
+	defer h.poolMu.RUnlock()
+	return n.tier
+}
+
+// reportTransition logs that n turned healthy or unhealthy and tells the
+// subscribers.
+func (h *HealthAwareTransport) reportTransition(n *nodeState, healthy bool, cause error) {
+	tier := h.tierOf(n)
+	event := HealthEvent{Kind: EventNodeUnhealthy, Address: n.address, Tier: tier, PreviousTier: tier, Cause: cause}
+	to := "unhealthy"
+	if healthy {
+		event.Kind, to = EventNodeHealthy, "healthy"
+	}
 	if cause != nil {
 		h.cfg.Logger.Infof("gotron: node state transition blockchain=%s address=%s tier=%d to=%s cause=%v",
-			h.blockchain, n.address, n.tier, to, cause)
+			h.blockchain, n.address, tier, to, cause)
+	} else {
+		h.cfg.Logger.Infof("gotron: node state transition blockchain=%s address=%s tier=%d to=%s",
+			h.blockchain, n.address, tier, to)
+	}
+	if r, ok := h.metrics.(NodeMetricsRecorder); ok {
+		r.RecordNodeTransition(h.blockchain, n.label, healthy)
+	}
+	h.emit(event)
+}
+
+// recordNodeRequest reports one attempt of method on n when the collector
+// breaks metrics down by node.
+func (h *HealthAwareTransport) recordNodeRequest(n *nodeState, method string, res any, err error, latency time.Duration) {
+	r, ok := h.metrics.(NodeMetricsRecorder)
+	if !ok {
 		return
 	}
-	h.cfg.Logger.Infof("gotron: node state transition blockchain=%s address=%s tier=%d to=%s",
-		h.blockchain, n.address, n.tier, to)
+	class := errorClass(res, err, h.cfg.ClassifyErr)
+	status := "success"
+	if class != "" {
+		status = "error"
+	}
+	r.RecordNodeRequest(h.blockchain, n.label, method, status, class, latency)
+}
+
+// recordProbe reports a probe of n when the collector breaks metrics down by
+// node.
+func (h *HealthAwareTransport) recordProbe(n *nodeState, err error, d time.Duration) {
+	r, ok := h.metrics.(NodeMetricsRecorder)
+	if !ok {
+		return
+	}
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	r.RecordProbe(h.blockchain, n.label, status, d)
 }
 
 // Close stops the health-check loops, waits for them to exit, and closes every
 // underlying transport. Safe to call multiple times.
 func (h *HealthAwareTransport) Close() error {
+	h.poolMu.Lock()
+	h.closed = true
+	nodes := slices.Clone(h.nodes)
+	h.poolMu.Unlock()
+
 	h.closeOnce.Do(func() {
 		close(h.stopCh)
 	})
 	h.wg.Wait()
 	var lastErr error
-	for _, n := range h.nodes {
+	for _, n := range nodes {
 		if err := n.transport.Close(); err != nil {
 			lastErr = err
 		}
@@ -421,492 +779,10 @@ func (h *HealthAwareTransport) Close() error {
 	return lastErr
 }
 
-// ===== Transport interface methods =====
-
-// Account operations
-
-func (h *HealthAwareTransport) GetAccount(ctx context.Context, account *core.Account) (*core.Account, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetAccount(ctx, account)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) GetAccountResource(ctx context.Context, account *core.Account) (*api.AccountResourceMessage, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetAccountResource(ctx, account)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) CreateAccount(ctx context.Context, contract *core.AccountCreateContract) (*api.TransactionExtention, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.CreateAccount(ctx, contract)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) AccountPermissionUpdate(ctx context.Context, contract *core.AccountPermissionUpdateContract) (*api.TransactionExtention, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.AccountPermissionUpdate(ctx, contract)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-// Block operations
-
-func (h *HealthAwareTransport) GetNowBlock(ctx context.Context) (*api.BlockExtention, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetNowBlock(ctx)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) GetBlockByNum(ctx context.Context, num int64) (*api.BlockExtention, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetBlockByNum(ctx, num)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) GetBlockById(ctx context.Context, id []byte) (*core.Block, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetBlockById(ctx, id)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) GetBlockByLimitNext(ctx context.Context, start, end int64) (*api.BlockListExtention, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetBlockByLimitNext(ctx, start, end)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) GetBlockByLatestNum(ctx context.Context, num int64) (*api.BlockListExtention, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetBlockByLatestNum(ctx, num)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) GetTransactionInfoByBlockNum(ctx context.Context, num int64) (*api.TransactionInfoList, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetTransactionInfoByBlockNum(ctx, num)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-// Transaction operations
-
-func (h *HealthAwareTransport) GetTransactionById(ctx context.Context, id []byte) (*core.Transaction, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetTransactionById(ctx, id)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) GetTransactionInfoById(ctx context.Context, id []byte) (*core.TransactionInfo, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetTransactionInfoById(ctx, id)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) BroadcastTransaction(ctx context.Context, tx *core.Transaction) (*api.Return, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.BroadcastTransaction(ctx, tx)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) CreateTransaction(ctx context.Context, contract *core.TransferContract) (*api.TransactionExtention, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.CreateTransaction(ctx, contract)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-// Contract operations
-
-func (h *HealthAwareTransport) TriggerContract(ctx context.Context, contract *core.TriggerSmartContract) (*api.TransactionExtention, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.TriggerContract(ctx, contract)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) TriggerConstantContract(ctx context.Context, contract *core.TriggerSmartContract) (*api.TransactionExtention, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.TriggerConstantContract(ctx, contract)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) EstimateEnergy(ctx context.Context, contract *core.TriggerSmartContract) (*api.EstimateEnergyMessage, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.EstimateEnergy(ctx, contract)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) DeployContract(ctx context.Context, contract *core.CreateSmartContract) (*api.TransactionExtention, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.DeployContract(ctx, contract)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) GetContract(ctx context.Context, address []byte) (*core.SmartContract, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetContract(ctx, address)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) UpdateSetting(ctx context.Context, contract *core.UpdateSettingContract) (*api.TransactionExtention, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.UpdateSetting(ctx, contract)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) UpdateEnergyLimit(ctx context.Context, contract *core.UpdateEnergyLimitContract) (*api.TransactionExtention, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.UpdateEnergyLimit(ctx, contract)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-// Resource operations
-
-func (h *HealthAwareTransport) GetAccountResourceMessage(ctx context.Context, account *core.Account) (*api.AccountResourceMessage, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetAccountResourceMessage(ctx, account)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) GetDelegatedResource(ctx context.Context, msg *api.DelegatedResourceMessage) (*api.DelegatedResourceList, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetDelegatedResource(ctx, msg)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) GetDelegatedResourceV2(ctx context.Context, msg *api.DelegatedResourceMessage) (*api.DelegatedResourceList, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetDelegatedResourceV2(ctx, msg)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) GetDelegatedResourceAccountIndex(ctx context.Context, address []byte) (*core.DelegatedResourceAccountIndex, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetDelegatedResourceAccountIndex(ctx, address)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) GetDelegatedResourceAccountIndexV2(ctx context.Context, address []byte) (*core.DelegatedResourceAccountIndex, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetDelegatedResourceAccountIndexV2(ctx, address)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) GetCanDelegatedMaxSize(ctx context.Context, msg *api.CanDelegatedMaxSizeRequestMessage) (*api.CanDelegatedMaxSizeResponseMessage, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetCanDelegatedMaxSize(ctx, msg)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) DelegateResource(ctx context.Context, contract *core.DelegateResourceContract) (*api.TransactionExtention, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.DelegateResource(ctx, contract)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) UnDelegateResource(ctx context.Context, contract *core.UnDelegateResourceContract) (*api.TransactionExtention, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.UnDelegateResource(ctx, contract)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-// Staking operations (Stake 2.0)
-
-func (h *HealthAwareTransport) FreezeBalanceV2(ctx context.Context, contract *core.FreezeBalanceV2Contract) (*api.TransactionExtention, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.FreezeBalanceV2(ctx, contract)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) UnfreezeBalanceV2(ctx context.Context, contract *core.UnfreezeBalanceV2Contract) (*api.TransactionExtention, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.UnfreezeBalanceV2(ctx, contract)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) WithdrawExpireUnfreeze(ctx context.Context, contract *core.WithdrawExpireUnfreezeContract) (*api.TransactionExtention, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.WithdrawExpireUnfreeze(ctx, contract)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) CancelAllUnfreezeV2(ctx context.Context, contract *core.CancelAllUnfreezeV2Contract) (*api.TransactionExtention, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.CancelAllUnfreezeV2(ctx, contract)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) GetAvailableUnfreezeCount(ctx context.Context, msg *api.GetAvailableUnfreezeCountRequestMessage) (*api.GetAvailableUnfreezeCountResponseMessage, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetAvailableUnfreezeCount(ctx, msg)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) GetCanWithdrawUnfreezeAmount(ctx context.Context, msg *api.CanWithdrawUnfreezeAmountRequestMessage) (*api.CanWithdrawUnfreezeAmountResponseMessage, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetCanWithdrawUnfreezeAmount(ctx, msg)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-// Witness operations
-
-func (h *HealthAwareTransport) VoteWitnessAccount(ctx context.Context, contract *core.VoteWitnessContract) (*api.TransactionExtention, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.VoteWitnessAccount(ctx, contract)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) WithdrawBalance(ctx context.Context, contract *core.WithdrawBalanceContract) (*api.TransactionExtention, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.WithdrawBalance(ctx, contract)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) ListWitnesses(ctx context.Context) (*api.WitnessList, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.ListWitnesses(ctx)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) GetRewardInfo(ctx context.Context, address []byte) (*api.NumberMessage, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetRewardInfo(ctx, address)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) GetBrokerageInfo(ctx context.Context, address []byte) (*api.NumberMessage, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetBrokerageInfo(ctx, address)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-// Asset operations
-
-func (h *HealthAwareTransport) GetAssetIssueById(ctx context.Context, id []byte) (*core.AssetIssueContract, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetAssetIssueById(ctx, id)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) GetAssetIssueListByName(ctx context.Context, name []byte) (*api.AssetIssueList, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetAssetIssueListByName(ctx, name)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-// Network operations
-
-func (h *HealthAwareTransport) ListNodes(ctx context.Context) (*api.NodeList, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.ListNodes(ctx)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) GetNodeInfo(ctx context.Context) (*core.NodeInfo, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetNodeInfo(ctx)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) GetChainParameters(ctx context.Context) (*core.ChainParameters, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetChainParameters(ctx)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) GetNextMaintenanceTime(ctx context.Context) (*api.NumberMessage, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.GetNextMaintenanceTime(ctx)
-	h.recordOutcome(n, callErr)
-	return res, callErr
-}
-
-func (h *HealthAwareTransport) TotalTransaction(ctx context.Context) (*api.NumberMessage, error) {
-	n, err := h.next()
-	if err != nil {
-		return nil, err
-	}
-	res, callErr := n.transport.TotalTransaction(ctx)
-	h.recordOutcome(n, callErr)
-	return res, callErr
+// call sends a call to a healthy node, retrying and hedging it as the
+// HealthConfig says.
+func (h *HealthAwareTransport) call(ctx context.Context, method string, request proto.Message) (proto.Message, error) {
+	return invoke(h, ctx, method, func(ctx context.Context, t Transport) (proto.Message, error) {
+		return dispatch(ctx, t, method, request)
+	})
 }
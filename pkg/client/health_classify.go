@@ -5,9 +5,11 @@ import (
 	"errors"
 	"io"
 	"net"
+	"net/http"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 // isNetworkError reports whether err looks like a transport/network failure
@@ -29,6 +31,12 @@ func isNetworkError(err error) bool {
 		err = te.Err
 	}
 
+	// A rate limit says the caller is going too fast, not that the node is
+	// broken.
+	if isRateLimited(err) {
+		return false
+	}
+
 	// Context: a deadline that fired locally still indicates the network
 	// did not respond in time, but explicit Cancel is not the node's fault.
 	if errors.Is(err, context.DeadlineExceeded) {
@@ -44,7 +52,6 @@ func isNetworkError(err error) bool {
 		case codes.Unavailable,
 			codes.DeadlineExceeded,
 			codes.Aborted,
-			codes.ResourceExhausted,
 			codes.Internal,
 			codes.Unknown:
 			return true
@@ -60,10 +67,10 @@ func isNetworkError(err error) bool {
 		}
 	}
 
-	// HTTP status: 5xx + 408 (request timeout) + 429 (rate limit) point at the
-	// node having trouble; everything else (4xx) is a client-side logical issue.
+	// HTTP status: 5xx + 408 (request timeout) point at the node having
+	// trouble; everything else (4xx) is a client-side logical issue.
 	if he, ok := errors.AsType[*HTTPStatusError](err); ok {
-		if he.Code >= 500 || he.Code == 408 || he.Code == 429 {
+		if he.Code >= 500 || he.Code == 408 {
 			return true
 		}
 		return false
@@ -87,3 +94,63 @@ func isNetworkError(err error) bool {
 
 	return false
 }
+
+// isRateLimited reports whether err is a node turning a call down for going
+// over a rate limit - HTTP 429, gRPC ResourceExhausted - or ErrRateLimited,
+// the limiter giving up before sending it. The node did not process such a
+// call, so it is safe to retry elsewhere, but it says nothing about node
+// health.
+func isRateLimited(err error) bool {
+	if errors.Is(err, ErrRateLimited) {
+		return true
+	}
+	if he, ok := errors.AsType[*HTTPStatusError](err); ok {
+		return he.Code == http.StatusTooManyRequests
+	}
+	if te, ok := errors.AsType[*TransportError](err); ok {
+		err = te.Err
+	}
+	st, ok := status.FromError(err)
+	return ok && st.Code() == codes.ResourceExhausted
+}
+
+// errorClass returns the ErrorClass constant for err, as NodeMetricsRecorder
+// reports it; isNetwork is the pool's HealthConfig.ClassifyErr. res is the
+// node's answer, for the refusals that arrive as one rather than as an error.
+func errorClass(res any, err error, isNetwork func(error) bool) string {
+	if err == nil {
+		if msg, ok := res.(proto.Message); ok && msg != nil && msg.ProtoReflect().IsValid() {
+			err = refusal(msg)
+		}
+		if err == nil {
+			return ""
+		}
+	}
+
+	switch {
+	case isRateLimited(err):
+		return ErrorClassRateLimited
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case isNetwork(err):
+		return ErrorClassNetwork
+	}
+	if _, ok := errors.AsType[*BroadcastError](err); ok {
+		return ErrorClassBroadcastRejected
+	}
+	if errors.Is(err, ErrInvalidTransaction) || errors.Is(err, ErrNodeRefusedRequest) {
+		return ErrorClassValidation
+	}
+	if te, ok := errors.AsType[*TransportError](err); ok {
+		err = te.Err
+	}
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.InvalidArgument, codes.FailedPrecondition:
+			return ErrorClassValidation
+		case codes.Canceled:
+			return ErrorClassCanceled
+		}
+	}
+	return ErrorClassOther
+}
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// healthReport is the JSON body served by HealthHandler.
+type healthReport struct {
+	// Status is "ok" when calls can be served, "unavailable" when no node is
+	// healthy.
+	Status     string       `json:"status"`
+	Blockchain string       `json:"blockchain"`
+	ActiveTier int          `json:"active_tier"`
+	Tiers      []tierReport `json:"tiers"`
+	Nodes      []nodeReport `json:"nodes"`
+}
+
+type tierReport struct {
+	Tier    int  `json:"tier"`
+	Total   int  `json:"total"`
+	Healthy int  `json:"healthy"`
+	Active  bool `json:"active"`
+}
+
+type nodeReport struct {
+	// Node is the alias of the node; without one, the host of its address,
+	// or the whole address with HealthReportAddresses.
+	Node      string    `json:"node"`
+	Tier      int       `json:"tier"`
+	Healthy   bool      `json:"healthy"`
+	Head      int64     `json:"head,omitzero"`
+	Lag       int64     `json:"lag_blocks"`
+	LatencyMS int64     `json:"latency_ms"`
+	InFlight  int       `json:"in_flight"`
+	LastProbe time.Time `json:"last_probe,omitzero"`
+	// LastErrorClass is the ErrorClass of the node's last error. LastError
+	// is its message, reported with HealthReportErrors only.
+	LastErrorClass string `json:"last_error_class,omitempty"`
+	LastError      string `json:"last_error,omitempty"`
+}
+
+// HealthHandlerOption adds to what HealthHandler reports.
+type HealthHandlerOption func(*healthHandlerOptions)
+
+type healthHandlerOptions struct {
+	addresses bool
+	errors    bool
+}
+
+// HealthReportAddresses names the nodes that have no NodeConfig.Alias by
+// their full address, path and query included, rather than by its host.
+// Only use it where a node address carrying an API key may be seen.
+func HealthReportAddresses() HealthHandlerOption {
+	return func(o *healthHandlerOptions) { o.addresses = true }
+}
+
+// HealthReportErrors adds the message of each node's last error to the
+// report. A transport failure names the node's address, and a node's own
+// message may echo the request, so only use it where those may be seen.
+func HealthReportErrors() HealthHandlerOption {
+	return func(o *healthHandlerOptions) { o.errors = true }
+}
+
+// HealthHandler returns an http.Handler that reports the pool as JSON: the
+// active tier, the number of healthy nodes of every tier, and each node's
+// health, head block and lag. It answers 200 while some node is healthy and
+// 503 once none is - when calls would fail with ErrNoHealthyNodes - so it can
+// serve as a readiness check, taking a pod that lost every node out of
+// rotation.
+//
+// Nodes are named by NodeConfig.Alias when they have one, and by the host of
+// their address otherwise, so that an API key in the path or query of an
+// address is not served; the last error of a node is reported as its
+// ErrorClass only. HealthReportAddresses and HealthReportErrors add the full
+// address and the error message, for a handler served where they may be
+// seen.
+func (h *HealthAwareTransport) HealthHandler(opts ...HealthHandlerOption) http.Handler {
+	var o healthHandlerOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		report := newHealthReport(h.blockchain, h.Status(), o, h.cfg.ClassifyErr)
+		code := http.StatusOK
+		if report.Status != "ok" {
+			code = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(code)
+		if r.Method == http.MethodGet {
+			_ = json.NewEncoder(w).Encode(report)
+		}
+	})
+}
+
+// newHealthReport builds the report of a pool from its status; isNetwork is
+// the pool's HealthConfig.ClassifyErr. Tiers are listed in ascending order,
+// like the pool keeps them.
+func newHealthReport(blockchain string, status PoolStatus, o healthHandlerOptions, isNetwork func(error) bool) healthReport {
+	report := healthReport{
+		Status:     "ok",
+		Blockchain: blockchain,
+		ActiveTier: status.ActiveTier,
+		Tiers:      []tierReport{},
+		Nodes:      make([]nodeReport, 0, len(status.Nodes)),
+	}
+	if status.ActiveTier < 0 {
+		report.Status = "unavailable"
+	}
+
+	tiers := make(map[int]int) // tier -> index in report.Tiers
+	for _, n := range status.Nodes {
+		name := n.Alias
+		switch {
+		case name != "":
+		case o.addresses:
+			name = n.Address
+		default:
+			name = addressHost(n.Address)
+		}
+		node := nodeReport{
+			Node:      name,
+			Tier:      n.Tier,
+			Healthy:   n.Healthy,
+			Head:      n.Head,
+			Lag:       n.Lag,
+			LatencyMS: n.Latency.Milliseconds(),
+			InFlight:  n.InFlight,
+			LastProbe: n.LastProbe,
+		}
+		if n.LastError != nil {
+			node.LastErrorClass = errorClass(nil, n.LastError, isNetwork)
+			if o.errors {
+				node.LastError = n.LastError.Error()
+			}
+		}
+		report.Nodes = append(report.Nodes, node)
+
+		i, ok := tiers[n.Tier]
+		if !ok {
+			i = len(report.Tiers)
+			tiers[n.Tier] = i
+			report.Tiers = append(report.Tiers, tierReport{Tier: n.Tier, Active: n.Tier == status.ActiveTier})
+		}
+		report.Tiers[i].Total++
+		if n.Healthy {
+			report.Tiers[i].Healthy++
+		}
+	}
+	slices.SortFunc(report.Tiers, func(a, b tierReport) int { return a.Tier - b.Tier })
+	return report
+}
+
+// addressHost returns the host, and port, of a node address: without the
+// scheme, credentials, path and query, any of which may carry an API key.
+func addressHost(address string) string {
+	if _, rest, ok := strings.Cut(address, "://"); ok {
+		address = strings.TrimLeft(rest, "/")
+	}
+	if i := strings.IndexAny(address, "/?#"); i >= 0 {
+		address = address[:i]
+	}
+	if i := strings.LastIndex(address, "@"); i >= 0 {
+		address = address[i+1:]
+	}
+	return address
+}
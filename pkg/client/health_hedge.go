@@ -0,0 +1,191 @@
+package client
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// latencyWindow is the number of recent latencies kept per method.
+	latencyWindow = 128
+	// latencyWarmup is the number of samples a method needs before its
+	// percentile replaces HedgePolicy.MaxDelay.
+	latencyWarmup = 20
+)
+
+// withDefaults fills zero-valued fields with sensible defaults. Returns a copy.
+func (p HedgePolicy) withDefaults() HedgePolicy {
+	if p.Percentile <= 0 || p.Percentile >= 1 {
+		p.Percentile = 0.95
+	}
+	if p.MinDelay <= 0 {
+		p.MinDelay = 10 * time.Millisecond
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = time.Second
+	}
+	if p.MaxDelay < p.MinDelay {
+		p.MaxDelay = p.MinDelay
+	}
+	return p
+}
+
+// latencyTracker keeps the last latencyWindow successful latencies of one
+// method in a ring buffer.
+type latencyTracker struct {
+	mu      sync.Mutex
+	samples [latencyWindow]time.Duration
+	next    int
+	count   int
+}
+
+func (l *latencyTracker) observe(d time.Duration) {
+	l.mu.Lock()
+	l.samples[l.next] = d
+	l.next = (l.next + 1) % latencyWindow
+	l.count = min(l.count+1, latencyWindow)
+	l.mu.Unlock()
+}
+
+// percentile returns the p-th percentile of the recorded latencies, and false
+// while fewer than latencyWarmup samples are recorded.
+func (l *latencyTracker) percentile(p float64) (time.Duration, bool) {
+	l.mu.Lock()
+	if l.count < latencyWarmup {
+		l.mu.Unlock()
+		return 0, false
+	}
+	sorted := slices.Clone(l.samples[:l.count])
+	l.mu.Unlock()
+
+	slices.Sort(sorted)
+	return sorted[int(p*float64(len(sorted)-1))], true
+}
+
+// newLatencyTrackers builds one tracker per read method. The map is never
+// written after construction, so it is read without a lock.
+func newLatencyTrackers(p HedgePolicy) map[string]*latencyTracker {
+	if !p.Enabled {
+		return nil
+	}
+	trackers := make(map[string]*latencyTracker)
+	for method, kind := range transportMethods {
+		if kind == methodRead {
+			trackers[method] = &latencyTracker{}
+		}
+	}
+	return trackers
+}
+
+// hedgeDelay returns how long a call of the tracked method waits for its
+// first node before hedging.
+func (h *HealthAwareTransport) hedgeDelay(l *latencyTracker) time.Duration {
+	p := h.cfg.Hedge
+	d, ok := l.percentile(p.Percentile)
+	if !ok {
+		return p.MaxDelay
+	}
+	return min(max(d, p.MinDelay), p.MaxDelay)
+}
+
+// hedgeResult is the outcome of one of the two requests of a hedged call.
+type hedgeResult[T any] struct {
+	res   T
+	err   error
+	hedge bool
+}
+
+// attempt runs one attempt of a call on n and returns its result together
+// with every node it used. Read methods are hedged when HedgePolicy is
+// enabled, unless the call is pinned to n; everything else is a single call.
+// The caller holds a reference on n for the duration.
+func attempt[T any](h *HealthAwareTransport, ctx context.Context, method string, opts callOptions, n *nodeState, call func(context.Context, Transport) (T, error)) (T, []*nodeState, error) {
+	tracker := h.latencies[method]
+	if tracker == nil || opts.node != "" {
+		res, latency, err := callNode(ctx, n, call)
+		h.recordOutcome(n, err, latency)
+		h.recordNodeRequest(n, method, res, err, latency)
+		return res, []*nodeState{n}, err
+	}
+
+	// Both requests report on a channel with room for both, so the one that
+	// loses never blocks after the call has returned.
+	results := make(chan hedgeResult[T], 2)
+	// Each request holds its own reference on its node, since the one that
+	// loses may still be running after the call has returned.
+	launch := func(n *nodeState, hedge bool) context.CancelFunc {
+		ctx, cancel := context.WithCancel(ctx)
+		n.hold()
+		go func() {
+			defer n.release()
+			res, latency, err := callNode(ctx, n, call)
+			// A request cancelled because the other one won says nothing
+			// about this node's health, but it did take at least latency.
+			if ctx.Err() == nil || err == nil {
+				h.recordOutcome(n, err, latency)
+			} else {
+				recordLatency(n, latency)
+			}
+			h.recordNodeRequest(n, method, res, err, latency)
+			if err == nil {
+				tracker.observe(latency)
+			}
+			results <- hedgeResult[T]{res: res, err: err, hedge: hedge}
+		}()
+		return cancel
+	}
+
+	used := []*nodeState{n}
+	cancels := []context.CancelFunc{launch(n, false)}
+	defer func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}()
+
+	timer := time.NewTimer(h.hedgeDelay(tracker))
+	defer timer.Stop()
+
+	var first hedgeResult[T]
+	failed := 0
+	for {
+		select {
+		case <-timer.C:
+			second, err := h.nextInTier(n, used)
+			if err != nil {
+				continue
+			}
+			used = append(used, second)
+			cancels = append(cancels, launch(second, true))
+			second.release()
+		case r := <-results:
+			if r.err == nil {
+				if len(used) > 1 {
+					h.recordHedge(method, r.hedge)
+				}
+				return r.res, used, nil
+			}
+			failed++
+			if failed == 1 {
+				first = r
+			}
+			if failed == len(used) {
+				// Every request failed. The first error is the primary cause,
+				// a later one usually a consequence of the same outage.
+				if len(used) > 1 {
+					h.recordHedge(method, false)
+				}
+				return first.res, used, first.err
+			}
+		}
+	}
+}
+
+// recordHedge reports a fired hedge when the collector supports it.
+func (h *HealthAwareTransport) recordHedge(method string, won bool) {
+	if r, ok := h.metrics.(HedgeRecorder); ok {
+		r.RecordHedge(h.blockchain, method, won)
+	}
+}
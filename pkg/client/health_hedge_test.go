@@ -0,0 +1,165 @@
+package client
+
+import (
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/grpc/codes"
+)
+
+func hedgeConfig() HealthConfig {
+	return quietProbes(HealthConfig{
+		FailureThreshold: 5,
+		Hedge: HedgePolicy{
+			Enabled:  true,
+			MinDelay: 10 * time.Millisecond,
+			MaxDelay: 100 * time.Millisecond,
+		},
+	})
+}
+
+func TestHedge_SlowNodeIsHedged(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0}, hedgeConfig())
+		h.nodes[0].setDelay(time.Hour)
+
+		start := time.Now()
+		_, err := h.transport.GetAccount(bgctx(), &core.Account{})
+		require.NoError(t, err)
+
+		assert.Equal(t, 100*time.Millisecond, time.Since(start), "the hedge is sent after MaxDelay while warming up")
+		assert.Equal(t, int64(1), h.nodes[1].liveCallCount.Load())
+		require.Len(t, h.metrics.hedges, 1)
+		assert.Equal(t, recordedHedge{"tron", "GetAccount", true}, h.metrics.hedges[0])
+
+		// The slow request was cancelled, and its cancellation is not held
+		// against the node.
+		synctest.Wait()
+		assert.True(t, h.nodeHealthy(0))
+		h.transport.nodes[0].mu.Lock()
+		assert.Zero(t, h.transport.nodes[0].consecutiveFailure)
+		h.transport.nodes[0].mu.Unlock()
+	})
+}
+
+func TestHedge_FastNodeIsNotHedged(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0}, hedgeConfig())
+		h.nodes[0].setDelay(time.Millisecond)
+		h.nodes[1].setDelay(time.Millisecond)
+
+		for range 10 {
+			_, err := h.transport.GetNowBlock(bgctx())
+			require.NoError(t, err)
+		}
+		assert.Empty(t, h.metrics.hedges)
+		assert.Equal(t, int64(10), h.nodes[0].liveCallCount.Load()+h.nodes[1].liveCallCount.Load())
+	})
+}
+
+func TestHedge_WritesAreNeverHedged(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0}, hedgeConfig())
+		h.nodes[0].setDelay(time.Second)
+
+		_, err := h.transport.CreateTransaction(bgctx(), &core.TransferContract{})
+		require.NoError(t, err)
+		_, err = h.transport.BroadcastTransaction(bgctx(), &core.Transaction{})
+		require.NoError(t, err)
+
+		assert.Empty(t, h.metrics.hedges)
+		assert.Equal(t, int64(1), h.nodes[0].liveCallCount.Load())
+		assert.Equal(t, int64(1), h.nodes[1].liveCallCount.Load())
+	})
+}
+
+func TestHedge_StaysInTier(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 1}, hedgeConfig())
+		h.nodes[0].setDelay(time.Second)
+
+		_, err := h.transport.GetAccount(bgctx(), &core.Account{})
+		require.NoError(t, err)
+
+		assert.Empty(t, h.metrics.hedges)
+		assert.Equal(t, int64(0), h.nodes[1].liveCallCount.Load(), "a fallback tier must not receive hedges")
+	})
+}
+
+func TestHedge_PrimaryWinsAfterHedgeSent(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0}, hedgeConfig())
+		h.nodes[0].setDelay(150 * time.Millisecond)
+		h.nodes[1].setDelay(time.Second)
+
+		start := time.Now()
+		_, err := h.transport.GetAccount(bgctx(), &core.Account{})
+		require.NoError(t, err)
+
+		assert.Equal(t, 150*time.Millisecond, time.Since(start))
+		require.Len(t, h.metrics.hedges, 1)
+		assert.False(t, h.metrics.hedges[0].won)
+	})
+}
+
+func TestHedge_BothFailReturnsFirstError(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0}, hedgeConfig())
+		h.nodes[0].setDelay(150 * time.Millisecond)
+		h.nodes[0].setNextErr(grpcErr(codes.Unavailable))
+		h.nodes[1].setDelay(time.Second)
+		h.nodes[1].setNextErr(grpcErr(codes.Internal))
+
+		_, err := h.transport.GetAccount(bgctx(), &core.Account{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Unavailable")
+		require.Len(t, h.metrics.hedges, 1)
+		assert.False(t, h.metrics.hedges[0].won)
+	})
+}
+
+func TestHedge_DelayFollowsPercentile(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0}, hedgeConfig())
+		tracker := h.transport.latencies["GetAccount"]
+		require.NotNil(t, tracker)
+
+		for range latencyWarmup - 1 {
+			tracker.observe(20 * time.Millisecond)
+		}
+		assert.Equal(t, 100*time.Millisecond, h.transport.hedgeDelay(tracker), "MaxDelay until warmed up")
+
+		tracker.observe(20 * time.Millisecond)
+		assert.Equal(t, 20*time.Millisecond, h.transport.hedgeDelay(tracker))
+
+		for range latencyWindow {
+			tracker.observe(time.Millisecond)
+		}
+		assert.Equal(t, 10*time.Millisecond, h.transport.hedgeDelay(tracker), "clamped to MinDelay")
+	})
+}
+
+func TestLatencyTracker_Percentile(t *testing.T) {
+	var l latencyTracker
+	for i := range 100 {
+		l.observe(time.Duration(i+1) * time.Millisecond)
+	}
+
+	p50, ok := l.percentile(0.5)
+	require.True(t, ok)
+	assert.Equal(t, 50*time.Millisecond, p50)
+
+	p95, _ := l.percentile(0.95)
+	assert.Equal(t, 95*time.Millisecond, p95)
+
+	// The window keeps only the latest samples.
+	for range latencyWindow {
+		l.observe(time.Second)
+	}
+	p50, _ = l.percentile(0.5)
+	assert.Equal(t, time.Second, p50)
+}
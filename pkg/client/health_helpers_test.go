@@ -7,6 +7,7 @@ import (
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/sxwebdev/gotron/schema/pb/api"
@@ -24,6 +25,10 @@ type controllableTransport struct {
 	mu       sync.Mutex
 	nextErr  error
 	probeErr error
+	delay    time.Duration
+	head     *api.BlockExtention
+	// broadcast is what BroadcastTransaction answers; nil for an empty Return.
+	broadcast *api.Return
 
 	liveCallCount atomic.Int64
 	probeCount    atomic.Int64
@@ -40,6 +45,30 @@ func (c *controllableTransport) setNextErr(err error) {
 	c.mu.Unlock()
 }
 
+// setDelay makes every live call take d, or less if its context ends first.
+func (c *controllableTransport) setDelay(d time.Duration) {
+	c.mu.Lock()
+	c.delay = d
+	c.mu.Unlock()
+}
+
+// setHead makes GetNowBlock report block number, produced at ts.
+func (c *controllableTransport) setHead(number int64, ts time.Time) {
+	c.mu.Lock()
+	c.head = &api.BlockExtention{BlockHeader: &core.BlockHeader{RawData: &core.BlockHeaderRaw{
+		Number:    number,
+		Timestamp: ts.UnixMilli(),
+	}}}
+	c.mu.Unlock()
+}
+
+// setBroadcast makes BroadcastTransaction answer ret.
+func (c *controllableTransport) setBroadcast(ret *api.Return) {
+	c.mu.Lock()
+	c.broadcast = ret
+	c.mu.Unlock()
+}
+
 func (c *controllableTransport) setProbeErr(err error) {
 	c.mu.Lock()
 	c.probeErr = err
@@ -58,200 +87,324 @@ func (c *controllableTransport) currentProbeErr() error {
 	return c.probeErr
 }
 
-// Live entry-points for the Transport interface. All increment liveCallCount
-// and return the currently configured nextErr.
+// Live entry-points for the Transport interface. All increment liveCallCount,
+// wait for the configured delay and return the currently configured nextErr.
 
-func (c *controllableTransport) live() error {
+func (c *controllableTransport) live(ctx context.Context) error {
 	c.liveCallCount.Add(1)
+	c.mu.Lock()
+	delay := c.delay
+	c.mu.Unlock()
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
 	return c.currentNextErr()
 }
 
 func (c *controllableTransport) GetAccount(ctx context.Context, account *core.Account) (*core.Account, error) {
-	return &core.Account{}, c.live()
+	return &core.Account{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetAccountResource(ctx context.Context, account *core.Account) (*api.AccountResourceMessage, error) {
-	return &api.AccountResourceMessage{}, c.live()
+	return &api.AccountResourceMessage{}, c.live(ctx)
 }
 
 func (c *controllableTransport) CreateAccount(ctx context.Context, contract *core.AccountCreateContract) (*api.TransactionExtention, error) {
-	return &api.TransactionExtention{}, c.live()
+	return &api.TransactionExtention{}, c.live(ctx)
 }
 
 func (c *controllableTransport) AccountPermissionUpdate(ctx context.Context, contract *core.AccountPermissionUpdateContract) (*api.TransactionExtention, error) {
-	return &api.TransactionExtention{}, c.live()
+	return &api.TransactionExtention{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetNowBlock(ctx context.Context) (*api.BlockExtention, error) {
-	return &api.BlockExtention{}, c.live()
+	c.mu.Lock()
+	head := c.head
+	c.mu.Unlock()
+	if head == nil {
+		head = &api.BlockExtention{}
+	}
+	return head, c.live(ctx)
 }
 
 func (c *controllableTransport) GetBlockByNum(ctx context.Context, num int64) (*api.BlockExtention, error) {
-	return &api.BlockExtention{}, c.live()
+	return &api.BlockExtention{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetBlockById(ctx context.Context, id []byte) (*core.Block, error) {
-	return &core.Block{}, c.live()
+	return &core.Block{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetBlockByLimitNext(ctx context.Context, start, end int64) (*api.BlockListExtention, error) {
-	return &api.BlockListExtention{}, c.live()
+	return &api.BlockListExtention{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetBlockByLatestNum(ctx context.Context, num int64) (*api.BlockListExtention, error) {
-	return &api.BlockListExtention{}, c.live()
+	return &api.BlockListExtention{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetTransactionInfoByBlockNum(ctx context.Context, num int64) (*api.TransactionInfoList, error) {
-	return &api.TransactionInfoList{}, c.live()
+	return &api.TransactionInfoList{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetTransactionById(ctx context.Context, id []byte) (*core.Transaction, error) {
-	return &core.Transaction{}, c.live()
+	return &core.Transaction{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetTransactionInfoById(ctx context.Context, id []byte) (*core.TransactionInfo, error) {
-	return &core.TransactionInfo{}, c.live()
+	return &core.TransactionInfo{}, c.live(ctx)
 }
 
 func (c *controllableTransport) BroadcastTransaction(ctx context.Context, tx *core.Transaction) (*api.Return, error) {
-	return &api.Return{}, c.live()
+	c.mu.Lock()
+	ret := c.broadcast
+	c.mu.Unlock()
+	if ret == nil {
+		ret = &api.Return{}
+	}
+	return ret, c.live(ctx)
 }
 
 func (c *controllableTransport) CreateTransaction(ctx context.Context, contract *core.TransferContract) (*api.TransactionExtention, error) {
-	return &api.TransactionExtention{}, c.live()
+	return &api.TransactionExtention{}, c.live(ctx)
 }
 
 func (c *controllableTransport) TriggerContract(ctx context.Context, contract *core.TriggerSmartContract) (*api.TransactionExtention, error) {
-	return &api.TransactionExtention{}, c.live()
+	return &api.TransactionExtention{}, c.live(ctx)
 }
 
 func (c *controllableTransport) TriggerConstantContract(ctx context.Context, contract *core.TriggerSmartContract) (*api.TransactionExtention, error) {
-	return &api.TransactionExtention{}, c.live()
+	return &api.TransactionExtention{}, c.live(ctx)
 }
 
 func (c *controllableTransport) EstimateEnergy(ctx context.Context, contract *core.TriggerSmartContract) (*api.EstimateEnergyMessage, error) {
-	return &api.EstimateEnergyMessage{}, c.live()
+	return &api.EstimateEnergyMessage{}, c.live(ctx)
 }
 
 func (c *controllableTransport) DeployContract(ctx context.Context, contract *core.CreateSmartContract) (*api.TransactionExtention, error) {
-	return &api.TransactionExtention{}, c.live()
+	return &api.TransactionExtention{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetContract(ctx context.Context, address []byte) (*core.SmartContract, error) {
-	return &core.SmartContract{}, c.live()
+	return &core.SmartContract{}, c.live(ctx)
 }
 
 func (c *controllableTransport) UpdateSetting(ctx context.Context, contract *core.UpdateSettingContract) (*api.TransactionExtention, error) {
-	return &api.TransactionExtention{}, c.live()
+	return &api.TransactionExtention{}, c.live(ctx)
 }
 
 func (c *controllableTransport) UpdateEnergyLimit(ctx context.Context, contract *core.UpdateEnergyLimitContract) (*api.TransactionExtention, error) {
-	return &api.TransactionExtention{}, c.live()
+	return &api.TransactionExtention{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetAccountResourceMessage(ctx context.Context, account *core.Account) (*api.AccountResourceMessage, error) {
-	return &api.AccountResourceMessage{}, c.live()
+	return &api.AccountResourceMessage{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetDelegatedResource(ctx context.Context, msg *api.DelegatedResourceMessage) (*api.DelegatedResourceList, error) {
-	return &api.DelegatedResourceList{}, c.live()
+	return &api.DelegatedResourceList{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetDelegatedResourceV2(ctx context.Context, msg *api.DelegatedResourceMessage) (*api.DelegatedResourceList, error) {
-	return &api.DelegatedResourceList{}, c.live()
+	return &api.DelegatedResourceList{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetDelegatedResourceAccountIndex(ctx context.Context, address []byte) (*core.DelegatedResourceAccountIndex, error) {
-	return &core.DelegatedResourceAccountIndex{}, c.live()
+	return &core.DelegatedResourceAccountIndex{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetDelegatedResourceAccountIndexV2(ctx context.Context, address []byte) (*core.DelegatedResourceAccountIndex, error) {
-	return &core.DelegatedResourceAccountIndex{}, c.live()
+	return &core.DelegatedResourceAccountIndex{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetCanDelegatedMaxSize(ctx context.Context, msg *api.CanDelegatedMaxSizeRequestMessage) (*api.CanDelegatedMaxSizeResponseMessage, error) {
-	return &api.CanDelegatedMaxSizeResponseMessage{}, c.live()
+	return &api.CanDelegatedMaxSizeResponseMessage{}, c.live(ctx)
 }
 
 func (c *controllableTransport) DelegateResource(ctx context.Context, contract *core.DelegateResourceContract) (*api.TransactionExtention, error) {
-	return &api.TransactionExtention{}, c.live()
+	return &api.TransactionExtention{}, c.live(ctx)
 }
 
 func (c *controllableTransport) UnDelegateResource(ctx context.Context, contract *core.UnDelegateResourceContract) (*api.TransactionExtention, error) {
-	return &api.TransactionExtention{}, c.live()
+	return &api.TransactionExtention{}, c.live(ctx)
 }
 
 func (c *controllableTransport) FreezeBalanceV2(ctx context.Context, contract *core.FreezeBalanceV2Contract) (*api.TransactionExtention, error) {
-	return &api.TransactionExtention{}, c.live()
+	return &api.TransactionExtention{}, c.live(ctx)
 }
 
 func (c *controllableTransport) UnfreezeBalanceV2(ctx context.Context, contract *core.UnfreezeBalanceV2Contract) (*api.TransactionExtention, error) {
-	return &api.TransactionExtention{}, c.live()
+	return &api.TransactionExtention{}, c.live(ctx)
 }
 
 func (c *controllableTransport) WithdrawExpireUnfreeze(ctx context.Context, contract *core.WithdrawExpireUnfreezeContract) (*api.TransactionExtention, error) {
-	return &api.TransactionExtention{}, c.live()
+	return &api.TransactionExtention{}, c.live(ctx)
 }
 
 func (c *controllableTransport) CancelAllUnfreezeV2(ctx context.Context, contract *core.CancelAllUnfreezeV2Contract) (*api.TransactionExtention, error) {
-	return &api.TransactionExtention{}, c.live()
+	return &api.TransactionExtention{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetAvailableUnfreezeCount(ctx context.Context, msg *api.GetAvailableUnfreezeCountRequestMessage) (*api.GetAvailableUnfreezeCountResponseMessage, error) {
-	return &api.GetAvailableUnfreezeCountResponseMessage{}, c.live()
+	return &api.GetAvailableUnfreezeCountResponseMessage{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetCanWithdrawUnfreezeAmount(ctx context.Context, msg *api.CanWithdrawUnfreezeAmountRequestMessage) (*api.CanWithdrawUnfreezeAmountResponseMessage, error) {
-	return &api.CanWithdrawUnfreezeAmountResponseMessage{}, c.live()
+	return &api.CanWithdrawUnfreezeAmountResponseMessage{}, c.live(ctx)
 }
 
 func (c *controllableTransport) VoteWitnessAccount(ctx context.Context, contract *core.VoteWitnessContract) (*api.TransactionExtention, error) {
-	return &api.TransactionExtention{}, c.live()
+	return &api.TransactionExtention{}, c.live(ctx)
 }
 
 func (c *controllableTransport) WithdrawBalance(ctx context.Context, contract *core.WithdrawBalanceContract) (*api.TransactionExtention, error) {
-	return &api.TransactionExtention{}, c.live()
+	return &api.TransactionExtention{}, c.live(ctx)
 }
 
 func (c *controllableTransport) ListWitnesses(ctx context.Context) (*api.WitnessList, error) {
-	return &api.WitnessList{}, c.live()
+	return &api.WitnessList{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetRewardInfo(ctx context.Context, address []byte) (*api.NumberMessage, error) {
-	return &api.NumberMessage{}, c.live()
+	return &api.NumberMessage{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetBrokerageInfo(ctx context.Context, address []byte) (*api.NumberMessage, error) {
-	return &api.NumberMessage{}, c.live()
+	return &api.NumberMessage{}, c.live(ctx)
+}
+
+func (c *controllableTransport) CreateWitness(ctx context.Context, contract *core.WitnessCreateContract) (*api.TransactionExtention, error) {
+	return &api.TransactionExtention{}, c.live(ctx)
+}
+
+func (c *controllableTransport) UpdateWitness(ctx context.Context, contract *core.WitnessUpdateContract) (*api.TransactionExtention, error) {
+	return &api.TransactionExtention{}, c.live(ctx)
+}
+
+func (c *controllableTransport) UpdateBrokerage(ctx context.Context, contract *core.UpdateBrokerageContract) (*api.TransactionExtention, error) {
+	return &api.TransactionExtention{}, c.live(ctx)
+}
+
+func (c *controllableTransport) GetPaginatedNowWitnessList(ctx context.Context, msg *api.PaginatedMessage) (*api.WitnessList, error) {
+	return &api.WitnessList{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetAssetIssueById(ctx context.Context, id []byte) (*core.AssetIssueContract, error) {
-	return &core.AssetIssueContract{}, c.live()
+	return &core.AssetIssueContract{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetAssetIssueListByName(ctx context.Context, name []byte) (*api.AssetIssueList, error) {
-	return &api.AssetIssueList{}, c.live()
+	return &api.AssetIssueList{}, c.live(ctx)
+}
+
+func (c *controllableTransport) GetAssetIssueByAccount(ctx context.Context, address []byte) (*api.AssetIssueList, error) {
+	return &api.AssetIssueList{}, c.live(ctx)
+}
+
+func (c *controllableTransport) GetAssetIssueList(ctx context.Context) (*api.AssetIssueList, error) {
+	return &api.AssetIssueList{}, c.live(ctx)
+}
+
+func (c *controllableTransport) GetPaginatedAssetIssueList(ctx context.Context, msg *api.PaginatedMessage) (*api.AssetIssueList, error) {
+	return &api.AssetIssueList{}, c.live(ctx)
+}
+
+func (c *controllableTransport) TransferAsset(ctx context.Context, contract *core.TransferAssetContract) (*api.TransactionExtention, error) {
+	return &api.TransactionExtention{}, c.live(ctx)
+}
+
+func (c *controllableTransport) CreateAssetIssue(ctx context.Context, contract *core.AssetIssueContract) (*api.TransactionExtention, error) {
+	return &api.TransactionExtention{}, c.live(ctx)
+}
+
+func (c *controllableTransport) ParticipateAssetIssue(ctx context.Context, contract *core.ParticipateAssetIssueContract) (*api.TransactionExtention, error) {
+	return &api.TransactionExtention{}, c.live(ctx)
+}
+
+func (c *controllableTransport) UpdateAsset(ctx context.Context, contract *core.UpdateAssetContract) (*api.TransactionExtention, error) {
+	return &api.TransactionExtention{}, c.live(ctx)
+}
+
+func (c *controllableTransport) UnfreezeAsset(ctx context.Context, contract *core.UnfreezeAssetContract) (*api.TransactionExtention, error) {
+	return &api.TransactionExtention{}, c.live(ctx)
+}
+
+func (c *controllableTransport) ProposalCreate(ctx context.Context, contract *core.ProposalCreateContract) (*api.TransactionExtention, error) {
+	return &api.TransactionExtention{}, c.live(ctx)
+}
+
+func (c *controllableTransport) ProposalApprove(ctx context.Context, contract *core.ProposalApproveContract) (*api.TransactionExtention, error) {
+	return &api.TransactionExtention{}, c.live(ctx)
+}
+
+func (c *controllableTransport) ProposalDelete(ctx context.Context, contract *core.ProposalDeleteContract) (*api.TransactionExtention, error) {
+	return &api.TransactionExtention{}, c.live(ctx)
+}
+
+func (c *controllableTransport) ListProposals(ctx context.Context) (*api.ProposalList, error) {
+	return &api.ProposalList{}, c.live(ctx)
+}
+
+func (c *controllableTransport) GetPaginatedProposalList(ctx context.Context, msg *api.PaginatedMessage) (*api.ProposalList, error) {
+	return &api.ProposalList{}, c.live(ctx)
+}
+
+func (c *controllableTransport) GetProposalById(ctx context.Context, id int64) (*core.Proposal, error) {
+	return &core.Proposal{}, c.live(ctx)
+}
+
+func (c *controllableTransport) ExchangeCreate(ctx context.Context, contract *core.ExchangeCreateContract) (*api.TransactionExtention, error) {
+	return &api.TransactionExtention{}, c.live(ctx)
+}
+
+func (c *controllableTransport) ExchangeInject(ctx context.Context, contract *core.ExchangeInjectContract) (*api.TransactionExtention, error) {
+	return &api.TransactionExtention{}, c.live(ctx)
+}
+
+func (c *controllableTransport) ExchangeWithdraw(ctx context.Context, contract *core.ExchangeWithdrawContract) (*api.TransactionExtention, error) {
+	return &api.TransactionExtention{}, c.live(ctx)
+}
+
+func (c *controllableTransport) ExchangeTransaction(ctx context.Context, contract *core.ExchangeTransactionContract) (*api.TransactionExtention, error) {
+	return &api.TransactionExtention{}, c.live(ctx)
+}
+
+func (c *controllableTransport) ListExchanges(ctx context.Context) (*api.ExchangeList, error) {
+	return &api.ExchangeList{}, c.live(ctx)
+}
+
+func (c *controllableTransport) GetPaginatedExchangeList(ctx context.Context, msg *api.PaginatedMessage) (*api.ExchangeList, error) {
+	return &api.ExchangeList{}, c.live(ctx)
+}
+
+func (c *controllableTransport) GetExchangeById(ctx context.Context, id int64) (*core.Exchange, error) {
+	return &core.Exchange{}, c.live(ctx)
 }
 
 func (c *controllableTransport) ListNodes(ctx context.Context) (*api.NodeList, error) {
-	return &api.NodeList{}, c.live()
+	return &api.NodeList{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetNodeInfo(ctx context.Context) (*core.NodeInfo, error) {
-	return &core.NodeInfo{}, c.live()
+	return &core.NodeInfo{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetChainParameters(ctx context.Context) (*core.ChainParameters, error) {
-	return &core.ChainParameters{}, c.live()
+	return &core.ChainParameters{}, c.live(ctx)
 }
 
 func (c *controllableTransport) GetNextMaintenanceTime(ctx context.Context) (*api.NumberMessage, error) {
-	return &api.NumberMessage{}, c.live()
+	return &api.NumberMessage{}, c.live(ctx)
 }
 
 func (c *controllableTransport) TotalTransaction(ctx context.Context) (*api.NumberMessage, error) {
-	return &api.NumberMessage{}, c.live()
+	return &api.NumberMessage{}, c.live(ctx)
 }
 
 func (c *controllableTransport) Close() error {
@@ -284,8 +437,9 @@ func newHarness(t *testing.T, tiers []int, cfg HealthConfig) *testHarness {
 		}
 	}
 
+	th := &testHarness{t: t, nodes: nodes, metrics: &mockMetricsCollector{}}
 	factory := func(nc NodeConfig) (Transport, error) {
-		for _, n := range nodes {
+		for _, n := range th.nodes {
 			if n.name == nc.Address {
 				return n, nil
 			}
@@ -301,13 +455,20 @@ func newHarness(t *testing.T, tiers []int, cfg HealthConfig) *testHarness {
 		}
 	}
 
-	metrics := &mockMetricsCollector{}
-	ht, err := NewHealthAwareTransport(cfgs, factory, cfg, metrics, "tron")
+	ht, err := NewHealthAwareTransport(cfgs, factory, cfg, th.metrics, "tron")
 	require.NoError(t, err)
 	t.Cleanup(func() {
 		_ = ht.Close()
 	})
-	return &testHarness{t: t, nodes: nodes, transport: ht, metrics: metrics}
+	th.transport = ht
+	return th
+}
+
+// newNode creates a node the harness factory knows, for AddNode to add.
+func (h *testHarness) newNode(name string) *controllableTransport {
+	ct := newCT(name)
+	h.nodes = append(h.nodes, ct)
+	return ct
 }
 
 // nodeHealthy returns the current healthy flag of node i.
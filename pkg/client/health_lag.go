@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// blockHead is the head block a node reported to its last probe.
+type blockHead struct {
+	number    int64
+	timestamp time.Time
+}
+
+// detectsLag reports whether MaxLagBlocks or MaxLagTime is set.
+func (c HealthConfig) detectsLag() bool {
+	return c.MaxLagBlocks > 0 || c.MaxLagTime > 0
+}
+
+// probe runs one health probe on n. When heads are tracked it also fetches
+// the node's head block, compares it with the best head of the pool and
+// returns an error wrapping ErrNodeLagging when the node is too far behind.
+// The default probe is GetNowBlock, so it is made only once.
+func (h *HealthAwareTransport) probe(ctx context.Context, n *nodeState) error {
+	if !h.trackHeads {
+		return h.cfg.Probe(ctx, n.transport)
+	}
+	if h.customProbe {
+		if err := h.cfg.Probe(ctx, n.transport); err != nil {
+			return err
+		}
+	}
+	block, err := n.transport.GetNowBlock(ctx)
+	if err != nil {
+		return err
+	}
+	raw := block.GetBlockHeader().GetRawData()
+	if raw == nil {
+		// Nothing to compare: the node answered, which is all the probe
+		// checked before lag detection existed.
+		return nil
+	}
+	return h.observeHead(n, blockHead{
+		number:    raw.GetNumber(),
+		timestamp: time.UnixMilli(raw.GetTimestamp()),
+	})
+}
+
+// observeHead records head as n's latest head, raises the pool's best head
+// when head is higher, and publishes n's lag. The best head only ever comes
+// from a probe, so a lag measured against it is never overstated.
+func (h *HealthAwareTransport) observeHead(n *nodeState, head blockHead) error {
+	n.mu.Lock()
+	n.head = head
+	n.mu.Unlock()
+
+	h.headMu.Lock()
+	if head.number > h.bestHead.number {
+		h.bestHead = head
+	}
+	best := h.bestHead
+	h.headMu.Unlock()
+
+	blocks := best.number - head.number
+	behind := best.timestamp.Sub(head.timestamp)
+	if r, ok := h.metrics.(NodeLagRecorder); ok {
+		r.SetNodeLag(h.blockchain, n.label, blocks)
+	}
+
+	if (h.cfg.MaxLagBlocks > 0 && blocks > h.cfg.MaxLagBlocks) ||
+		(h.cfg.MaxLagTime > 0 && behind > h.cfg.MaxLagTime) {
+		return fmt.Errorf("%w: head %d is %d blocks (%s) behind %d",
+			ErrNodeLagging, head.number, blocks, behind, best.number)
+	}
+	return nil
+}
+
+// markLagging takes a lagging node out of the pool at once. A lagging node
+// answers every call successfully, so live successes would keep resetting its
+// failure count and FailureThreshold would never be reached. It comes back
+// through SuccessThreshold probes that find it caught up.
+func (h *HealthAwareTransport) markLagging(n *nodeState, cause error) {
+	n.mu.Lock()
+	n.consecutiveSuccess = 0
+	n.consecutiveFailure++
+	n.lastErr = cause
+	transition := n.healthy
+	n.healthy = false
+	n.mu.Unlock()
+	if transition {
+		h.reportTransition(n, false, cause)
+		h.onStateChange()
+	}
+}
+
+// isLagging reports whether err is a probe's lag verdict.
+func isLagging(err error) bool {
+	return errors.Is(err, ErrNodeLagging)
+}
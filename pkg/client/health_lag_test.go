@@ -0,0 +1,126 @@
+package client
+
+import (
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/grpc/codes"
+)
+
+func lagConfig() HealthConfig {
+	return quietProbes(HealthConfig{
+		SuccessThreshold: 2,
+		MaxLagBlocks:     10,
+	})
+}
+
+func TestLag_LaggingNodeMarkedUnhealthy(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0}, lagConfig())
+		now := time.Now()
+		h.nodes[0].setHead(1000, now)
+		h.nodes[1].setHead(900, now.Add(-300*time.Second))
+
+		h.transport.probeOnce(h.transport.nodes[0])
+		h.transport.probeOnce(h.transport.nodes[1])
+
+		assert.True(t, h.nodeHealthy(0))
+		assert.False(t, h.nodeHealthy(1), "a single lagging probe is enough")
+
+		lag, ok := h.metrics.nodeLag(h.nodes[1].name)
+		require.True(t, ok)
+		assert.Equal(t, int64(100), lag)
+		lag, _ = h.metrics.nodeLag(h.nodes[0].name)
+		assert.Zero(t, lag)
+
+		// Traffic avoids the lagging node.
+		before := h.nodes[1].liveCallCount.Load()
+		for range 4 {
+			_, err := h.transport.GetAccount(bgctx(), &core.Account{})
+			require.NoError(t, err)
+		}
+		assert.Equal(t, before, h.nodes[1].liveCallCount.Load())
+	})
+}
+
+func TestLag_NodeReturnsOnceCaughtUp(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0}, lagConfig())
+		now := time.Now()
+		h.nodes[0].setHead(1000, now)
+		h.nodes[1].setHead(900, now)
+		h.transport.probeOnce(h.transport.nodes[0])
+		h.transport.probeOnce(h.transport.nodes[1])
+		require.False(t, h.nodeHealthy(1))
+
+		h.nodes[1].setHead(995, now)
+		h.transport.probeOnce(h.transport.nodes[1])
+		assert.False(t, h.nodeHealthy(1), "SuccessThreshold still applies")
+		h.transport.probeOnce(h.transport.nodes[1])
+		assert.True(t, h.nodeHealthy(1))
+
+		lag, _ := h.metrics.nodeLag(h.nodes[1].name)
+		assert.Equal(t, int64(5), lag)
+	})
+}
+
+func TestLag_MaxLagTime(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		cfg := quietProbes(HealthConfig{MaxLagTime: time.Minute})
+		h := newHarness(t, []int{0, 0}, cfg)
+		now := time.Now()
+		h.nodes[0].setHead(1000, now)
+		h.nodes[1].setHead(990, now.Add(-2*time.Minute))
+
+		h.transport.probeOnce(h.transport.nodes[0])
+		h.transport.probeOnce(h.transport.nodes[1])
+
+		assert.True(t, h.nodeHealthy(0))
+		assert.False(t, h.nodeHealthy(1))
+	})
+}
+
+func TestLag_WithinLimitStaysHealthy(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0}, lagConfig())
+		now := time.Now()
+		h.nodes[0].setHead(1000, now)
+		h.nodes[1].setHead(990, now)
+
+		h.transport.probeOnce(h.transport.nodes[0])
+		h.transport.probeOnce(h.transport.nodes[1])
+
+		assert.True(t, h.nodeHealthy(1), "exactly MaxLagBlocks behind is tolerated")
+	})
+}
+
+func TestLag_CustomProbeFailureSkipsHeadCheck(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0}, lagConfig())
+		h.nodes[0].setHead(1000, time.Now())
+		h.nodes[0].setProbeErr(grpcErr(codes.Unavailable))
+
+		h.transport.probeOnce(h.transport.nodes[0])
+
+		assert.Equal(t, int64(1), h.nodes[0].probeCount.Load())
+		assert.Zero(t, h.nodes[0].liveCallCount.Load(), "no GetNowBlock after a failed probe")
+		_, ok := h.metrics.nodeLag(h.nodes[0].name)
+		assert.False(t, ok)
+	})
+}
+
+func TestLag_DisabledWithCustomProbe(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0}, quietProbes(HealthConfig{}))
+		h.nodes[1].setHead(1, time.Now())
+
+		h.transport.probeOnce(h.transport.nodes[1])
+
+		assert.Zero(t, h.nodes[1].liveCallCount.Load(), "a custom probe is not followed by GetNowBlock")
+		assert.True(t, h.nodeHealthy(1))
+	})
+}
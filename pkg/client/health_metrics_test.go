@@ -0,0 +1,207 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/grpc/codes"
+)
+
+// nodeMetricsCollector records the per-node and per-tier metrics. Probes
+// and hedges report from their own goroutines, hence the lock.
+type nodeMetricsCollector struct {
+	mockMetricsCollector
+
+	mu          sync.Mutex
+	requests    []string
+	probes      []string
+	transitions []string
+	tiers       map[int]string
+}
+
+func (m *nodeMetricsCollector) RecordNodeRequest(_, node, method, status, errorClass string, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, fmt.Sprintf("%s %s %s %s", node, method, status, errorClass))
+}
+
+func (m *nodeMetricsCollector) RecordProbe(_, node, status string, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.probes = append(m.probes, node+" "+status)
+}
+
+func (m *nodeMetricsCollector) RecordNodeTransition(_, node string, healthy bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transitions = append(m.transitions, fmt.Sprintf("%s healthy=%t", node, healthy))
+}
+
+func (m *nodeMetricsCollector) SetTierHealth(_ string, tier, total, healthy int, active bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tiers == nil {
+		m.tiers = make(map[int]string)
+	}
+	m.tiers[tier] = fmt.Sprintf("%d/%d active=%t", healthy, total, active)
+}
+
+func (m *nodeMetricsCollector) snapshot() (requests, probes, transitions []string, tiers map[int]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tiers = make(map[int]string, len(m.tiers))
+	for k, v := range m.tiers {
+		tiers[k] = v
+	}
+	return append([]string(nil), m.requests...), append([]string(nil), m.probes...),
+		append([]string(nil), m.transitions...), tiers
+}
+
+var (
+	_ NodeMetricsRecorder = (*nodeMetricsCollector)(nil)
+	_ TierHealthRecorder  = (*nodeMetricsCollector)(nil)
+)
+
+// newNodeMetricsPool builds a pool of two nodes, "primary" in tier 0 with
+// the alias "provider-a" and "fallback" in tier 1, reporting to m.
+func newNodeMetricsPool(t *testing.T, m *nodeMetricsCollector, cfg HealthConfig) (*HealthAwareTransport, *controllableTransport, *controllableTransport) {
+	t.Helper()
+	primary, fallback := newCT("primary"), newCT("fallback")
+	factory := func(nc NodeConfig) (Transport, error) {
+		if nc.Address == "primary" {
+			return primary, nil
+		}
+		return fallback, nil
+	}
+	cfg.Probe = func(ctx context.Context, tr Transport) error {
+		return tr.(*controllableTransport).currentProbeErr()
+	}
+	h, err := NewHealthAwareTransport([]NodeConfig{
+		{Address: "primary", Alias: "provider-a"},
+		{Address: "fallback", Tier: 1},
+	}, factory, cfg, m, "tron")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = h.Close() })
+	return h, primary, fallback
+}
+
+func TestHealthAware_NodeMetrics(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		m := &nodeMetricsCollector{}
+		h, primary, _ := newNodeMetricsPool(t, m, HealthConfig{
+			HealthyInterval:      time.Minute,
+			UnhealthyInterval:    time.Hour,
+			InactiveTierInterval: time.Hour,
+		})
+		_, _, _, tiers := m.snapshot()
+		assert.Equal(t, map[int]string{0: "1/1 active=true", 1: "1/1 active=false"}, tiers)
+
+		_, err := h.GetAccount(bgctx(), &core.Account{})
+		require.NoError(t, err)
+		_, err = h.BroadcastTransaction(bgctx(), &core.Transaction{})
+		require.NoError(t, err, "a rejected broadcast is an answer, not an error")
+		primary.setNextErr(&ContractValidateError{Message: "balance is not sufficient"})
+		_, _ = h.CreateTransaction(bgctx(), &core.TransferContract{})
+		primary.setNextErr(grpcErr(codes.Unavailable))
+		for range 2 {
+			_, _ = h.GetAccount(bgctx(), &core.Account{})
+		}
+
+		requests, _, transitions, tiers := m.snapshot()
+		assert.Equal(t, []string{
+			"provider-a GetAccount success ",
+			"provider-a BroadcastTransaction error broadcast_rejected",
+			"provider-a CreateTransaction error validation",
+			"provider-a GetAccount error network",
+			"provider-a GetAccount error network",
+		}, requests, "the node is labelled with its alias")
+		assert.Equal(t, []string{"provider-a healthy=false"}, transitions)
+		assert.Equal(t, map[int]string{0: "0/1 active=false", 1: "1/1 active=true"}, tiers)
+
+		primary.setProbeErr(errors.New("probe failed"))
+		time.Sleep(time.Hour)
+		synctest.Wait()
+		_, probes, _, _ := m.snapshot()
+		assert.Contains(t, probes, "provider-a error")
+		assert.Contains(t, probes, "fallback success", "a node without an alias is labelled with its address")
+	})
+}
+
+func TestHealthAware_NodeMetricsAliasChange(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		m := &nodeMetricsCollector{}
+		h, _, _ := newNodeMetricsPool(t, m, HealthConfig{HealthyInterval: time.Hour})
+
+		require.NoError(t, h.RemoveNode(bgctx(), "primary"))
+		assert.Equal(t, []string{"provider-a"}, m.forgotten, "the series go by the alias they were labelled with")
+
+		require.NoError(t, h.AddNode(NodeConfig{Address: "primary", Alias: "provider-b"}))
+		_, err := h.GetAccount(bgctx(), &core.Account{})
+		require.NoError(t, err)
+		requests, _, _, _ := m.snapshot()
+		assert.Equal(t, "provider-b GetAccount success ", requests[len(requests)-1], "the node comes back under its new alias")
+	})
+}
+
+func TestHealthAware_NodeMetricsHedge(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		m := &nodeMetricsCollector{}
+		primary, second := newCT("primary"), newCT("second")
+		factory := func(nc NodeConfig) (Transport, error) {
+			if nc.Address == "primary" {
+				return primary, nil
+			}
+			return second, nil
+		}
+		h, err := NewHealthAwareTransport([]NodeConfig{{Address: "primary"}, {Address: "second"}}, factory, HealthConfig{
+			HealthyInterval: time.Hour,
+			Hedge:           HedgePolicy{Enabled: true, MinDelay: 10 * time.Millisecond, MaxDelay: 10 * time.Millisecond},
+			Probe:           func(context.Context, Transport) error { return nil },
+		}, m, "tron")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = h.Close() })
+		primary.setDelay(time.Second)
+
+		_, err = h.GetAccount(bgctx(), &core.Account{})
+		require.NoError(t, err)
+		synctest.Wait()
+		requests, _, _, _ := m.snapshot()
+		assert.ElementsMatch(t, []string{
+			"second GetAccount success ",
+			"primary GetAccount error canceled",
+		}, requests, "the losing request is reported as canceled")
+	})
+}
+
+func TestErrorClass(t *testing.T) {
+	for _, tc := range []struct {
+		res  any
+		err  error
+		want string
+	}{
+		{&api.Return{Result: true}, nil, ""},
+		{&api.Return{Code: api.Return_DUP_TRANSACTION_ERROR}, nil, ErrorClassBroadcastRejected},
+		{&api.TransactionExtention{Result: &api.Return{Code: api.Return_CONTRACT_VALIDATE_ERROR}}, nil, ErrorClassValidation},
+		{(*api.Return)(nil), nil, ""},
+		{nil, grpcErr(codes.Unavailable), ErrorClassNetwork},
+		{nil, &TransportError{Err: &HTTPStatusError{Code: 429}}, ErrorClassRateLimited},
+		{nil, ErrRateLimited, ErrorClassRateLimited},
+		{nil, &ContractValidateError{Message: "no"}, ErrorClassValidation},
+		{nil, fmt.Errorf("%w: bad address", ErrNodeRefusedRequest), ErrorClassValidation},
+		{nil, grpcErr(codes.InvalidArgument), ErrorClassValidation},
+		{nil, context.Canceled, ErrorClassCanceled},
+		{nil, grpcErr(codes.Canceled), ErrorClassCanceled},
+		{nil, grpcErr(codes.NotFound), ErrorClassOther},
+	} {
+		assert.Equal(t, tc.want, errorClass(tc.res, tc.err, isNetworkError), "res=%v err=%v", tc.res, tc.err)
+	}
+}
@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// AddNode adds a node to the pool at runtime. Its transport is created by the
+// factory the pool was built with, it starts out healthy - so a node added to
+// a lower tier than the active one takes the traffic at once - and its
+// health-loop starts right away. The address must not already be in the pool.
+func (h *HealthAwareTransport) AddNode(nc NodeConfig) error {
+	if err := nc.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	// Checked before the transport is created as well, so that a duplicate
+	// is not dialled for nothing.
+	h.poolMu.RLock()
+	err := h.addable(nc.Address)
+	h.poolMu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	tr, err := h.factory(nc)
+	if err != nil {
+		return fmt.Errorf("failed to create transport for node %s: %w", nc.Address, err)
+	}
+	n := newNodeState(tr, nc)
+
+	h.poolMu.Lock()
+	if err := h.addable(nc.Address); err != nil {
+		h.poolMu.Unlock()
+		_ = tr.Close()
+		return err
+	}
+	h.attach(n)
+	h.startLoop(n)
+	h.poolMu.Unlock()
+
+	h.cfg.Logger.Infof("gotron: node added blockchain=%s address=%s tier=%d",
+		h.blockchain, n.address, nc.Tier)
+	h.emit(HealthEvent{Kind: EventNodeAdded, Address: n.address, Tier: nc.Tier, PreviousTier: nc.Tier})
+	h.onStateChange()
+	return nil
+}
+
+// addable reports why a node with address cannot join the pool, nil when it
+// can. It must be called with poolMu held.
+func (h *HealthAwareTransport) addable(address string) error {
+	if h.closed {
+		return ErrNotConnected
+	}
+	if h.find(address) != nil {
+		return fmt.Errorf("%w: node %s is already in the pool", ErrInvalidConfig, address)
+	}
+	return nil
+}
+
+// RemoveNode takes the node with address out of the pool gracefully: it gets
+// no new calls from the moment RemoveNode is called, its health-loop stops,
+// and once the calls already running on it have finished its transport is
+// closed. If ctx ends first the transport is closed anyway, cutting those
+// calls short, and ctx.Err() is returned.
+//
+// RemoveNode returns ErrNodeNotFound for an address not in the pool, and
+// refuses to remove the last node: add its replacement first.
+func (h *HealthAwareTransport) RemoveNode(ctx context.Context, address string) error {
+	h.poolMu.Lock()
+	n := h.find(address)
+	switch {
+	case h.closed:
+		h.poolMu.Unlock()
+		return ErrNotConnected
+	case n == nil:
+		h.poolMu.Unlock()
+		return fmt.Errorf("%w: %s", ErrNodeNotFound, address)
+	case len(h.nodes) == 1:
+		h.poolMu.Unlock()
+		return fmt.Errorf("%w: the last node of the pool cannot be removed", ErrInvalidConfig)
+	}
+	h.detach(n)
+	n.removed.Store(true)
+	close(n.stopCh)
+	h.poolMu.Unlock()
+
+	h.cfg.Logger.Infof("gotron: node removed blockchain=%s address=%s tier=%d",
+		h.blockchain, n.address, n.tier)
+	h.emit(HealthEvent{Kind: EventNodeRemoved, Address: n.address, Tier: n.tier, PreviousTier: n.tier})
+	h.onStateChange()
+
+	// Calls hold a reference from the moment they chose the node, under
+	// poolMu, so none is taken after the node left the pool above.
+	var err error
+	if n.refs.Load() > 0 {
+		select {
+		case <-n.drained:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+	if n.loopDone != nil {
+		<-n.loopDone
+	}
+	if f, ok := h.metrics.(NodeForgetter); ok {
+		f.ForgetNode(h.blockchain, n.label)
+	}
+	if closeErr := n.transport.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// SetNodeTier moves the node with address to tier, which is created when no
+// other node has it. Traffic follows at once: the active tier is recomputed
+// and the node's health-loop picks its probe interval again. It returns
+// ErrNodeNotFound for an address not in the pool.
+func (h *HealthAwareTransport) SetNodeTier(address string, tier int) error {
+	h.poolMu.Lock()
+	n := h.find(address)
+	if n == nil {
+		h.poolMu.Unlock()
+		return fmt.Errorf("%w: %s", ErrNodeNotFound, address)
+	}
+	from := n.tier
+	if from == tier {
+		h.poolMu.Unlock()
+		return nil
+	}
+	h.leaveTier(n)
+	n.tier = tier
+	h.joinTier(n)
+	h.poolMu.Unlock()
+
+	h.cfg.Logger.Infof("gotron: node tier changed blockchain=%s address=%s from=%d to=%d",
+		h.blockchain, address, from, tier)
+	h.emit(HealthEvent{Kind: EventNodeTierChanged, Address: address, Tier: tier, PreviousTier: from})
+	h.onStateChange()
+	// The active tier may not have moved, but the node may have joined or
+	// left it, so its loop recomputes its interval.
+	select {
+	case n.notifyCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+)
+
+func TestHealthAware_AddNode(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{1}, HealthConfig{HealthyInterval: time.Minute})
+		added := h.newNode("added")
+
+		require.NoError(t, h.transport.AddNode(NodeConfig{Address: "added"}))
+		assert.Equal(t, int64(0), h.activeTier(), "a healthy node in a lower tier takes over")
+		assert.Equal(t, recordedPool{blockchain: "tron", total: 2, healthy: 2}, h.lastPool())
+
+		_, err := h.transport.GetAccount(bgctx(), &core.Account{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), added.liveCallCount.Load())
+		assert.Zero(t, h.nodes[0].liveCallCount.Load())
+
+		time.Sleep(time.Minute)
+		synctest.Wait()
+		assert.Equal(t, int64(1), added.probeCount.Load(), "the node is probed from the moment it joins")
+
+		require.ErrorIs(t, h.transport.AddNode(NodeConfig{Address: "added"}), ErrInvalidConfig, "addresses are unique")
+		require.ErrorIs(t, h.transport.AddNode(NodeConfig{}), ErrInvalidConfig)
+		require.Error(t, h.transport.AddNode(NodeConfig{Address: "unknown"}), "the factory fails")
+
+		require.NoError(t, h.transport.Close())
+		h.newNode("late")
+		require.ErrorIs(t, h.transport.AddNode(NodeConfig{Address: "late"}), ErrNotConnected)
+	})
+}
+
+func TestHealthAware_RemoveNodeDrains(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0}, HealthConfig{HealthyInterval: time.Minute})
+		h.nodes[0].setDelay(time.Second)
+
+		callDone := make(chan error, 1)
+		go func() {
+			ctx := WithCallOptions(bgctx(), CallNode(h.nodes[0].name))
+			_, err := h.transport.GetAccount(ctx, &core.Account{})
+			callDone <- err
+		}()
+		synctest.Wait()
+
+		removed := make(chan error, 1)
+		go func() { removed <- h.transport.RemoveNode(bgctx(), h.nodes[0].name) }()
+		synctest.Wait()
+		select {
+		case <-removed:
+			t.Fatal("RemoveNode returned while a call was still running on the node")
+		default:
+		}
+		assert.False(t, h.nodes[0].closed.Load())
+
+		for range 3 {
+			_, err := h.transport.GetAccount(bgctx(), &core.Account{})
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int64(3), h.nodes[1].liveCallCount.Load(), "a draining node gets no new calls")
+		ctx := WithCallOptions(bgctx(), CallNode(h.nodes[0].name))
+		_, err := h.transport.GetAccount(ctx, &core.Account{})
+		require.ErrorIs(t, err, ErrNodeNotFound)
+
+		time.Sleep(time.Second)
+		require.NoError(t, <-callDone, "the running call completes")
+		require.NoError(t, <-removed)
+		assert.True(t, h.nodes[0].closed.Load())
+		assert.Equal(t, recordedPool{blockchain: "tron", total: 1, healthy: 1}, h.lastPool())
+
+		probes := h.nodes[0].probeCount.Load()
+		time.Sleep(time.Hour)
+		synctest.Wait()
+		assert.Equal(t, probes, h.nodes[0].probeCount.Load(), "a removed node is no longer probed")
+	})
+}
+
+func TestHealthAware_RemoveNodeDeadline(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0}, HealthConfig{HealthyInterval: time.Hour})
+		h.nodes[0].setDelay(time.Hour)
+
+		callCtx, stopCall := context.WithCancel(WithCallOptions(bgctx(), CallNode(h.nodes[0].name)))
+		defer stopCall()
+		go func() { _, _ = h.transport.GetAccount(callCtx, &core.Account{}) }()
+		synctest.Wait()
+
+		ctx, cancel := context.WithTimeout(bgctx(), time.Second)
+		defer cancel()
+		err := h.transport.RemoveNode(ctx, h.nodes[0].name)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.True(t, h.nodes[0].closed.Load(), "the node is closed anyway")
+	})
+}
+
+func TestHealthAware_RemoveNodeErrors(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 1}, HealthConfig{})
+
+		require.ErrorIs(t, h.transport.RemoveNode(bgctx(), "unknown"), ErrNodeNotFound)
+		require.NoError(t, h.transport.RemoveNode(bgctx(), h.nodes[0].name))
+		assert.Equal(t, int64(1), h.activeTier(), "the fallback tier is all that is left")
+		require.ErrorIs(t, h.transport.RemoveNode(bgctx(), h.nodes[1].name), ErrInvalidConfig, "the last node stays")
+		require.ErrorIs(t, h.transport.RemoveNode(bgctx(), h.nodes[0].name), ErrNodeNotFound, "removed once")
+	})
+}
+
+func TestHealthAware_RemoveNodeForgetsMetrics(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0}, HealthConfig{})
+		h.metrics.SetNodeLag("tron", h.nodes[0].name, 5)
+
+		require.NoError(t, h.transport.RemoveNode(bgctx(), h.nodes[0].name))
+		assert.Equal(t, []string{h.nodes[0].name}, h.metrics.forgotten)
+		_, ok := h.metrics.nodeLag(h.nodes[0].name)
+		assert.False(t, ok, "the removed node's series are gone")
+	})
+}
+
+func TestHealthAware_SetNodeTier(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 1}, HealthConfig{
+			HealthyInterval:      time.Minute,
+			InactiveTierInterval: time.Hour,
+		})
+
+		require.NoError(t, h.transport.SetNodeTier(h.nodes[0].name, 2))
+		assert.Equal(t, int64(1), h.activeTier())
+		_, err := h.transport.GetAccount(bgctx(), &core.Account{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), h.nodes[1].liveCallCount.Load(), "traffic follows the tier change")
+
+		// n1 now serves the active tier and is probed at HealthyInterval; n0
+		// waits for InactiveTierInterval.
+		time.Sleep(time.Minute)
+		synctest.Wait()
+		assert.Equal(t, int64(1), h.nodes[1].probeCount.Load())
+		assert.Zero(t, h.nodes[0].probeCount.Load())
+
+		require.NoError(t, h.transport.SetNodeTier(h.nodes[0].name, 1))
+		for range 4 {
+			_, err := h.transport.GetAccount(bgctx(), &core.Account{})
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int64(2), h.nodes[0].liveCallCount.Load(), "nodes of one tier share its traffic")
+		assert.Equal(t, int64(3), h.nodes[1].liveCallCount.Load())
+
+		ctx := WithCallOptions(bgctx(), CallTier(2))
+		_, err = h.transport.GetAccount(ctx, &core.Account{})
+		require.ErrorIs(t, err, ErrNodeNotFound, "an emptied tier is gone")
+		require.ErrorIs(t, h.transport.SetNodeTier("unknown", 0), ErrNodeNotFound)
+	})
+}
+
+func TestClient_ManageNodes(t *testing.T) {
+	created := registerFake(t, "fake")
+	c, err := New(Config{
+		Nodes: []NodeConfig{
+			{Protocol: "fake", Address: "primary"},
+			{Protocol: "fake", Address: "solidity", Solidity: true},
+		},
+		Health: HealthConfig{HealthyInterval: time.Hour},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	require.NoError(t, c.AddNode(NodeConfig{Protocol: "fake", Address: "rotated"}))
+	require.NoError(t, c.RemoveNode(t.Context(), "primary"))
+	assert.True(t, created["primary"].closed.Load())
+	_, err = c.GetLastBlock(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), created["rotated"].liveCallCount.Load())
+
+	require.NoError(t, c.AddNode(NodeConfig{Protocol: "fake", Address: "solidity-2", Solidity: true}))
+	require.NoError(t, c.SetNodeTier("solidity", 1), "solidity nodes are found too")
+	confirmed, err := c.Confirmed()
+	require.NoError(t, err)
+	_, err = confirmed.GetLastBlock(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), created["solidity-2"].liveCallCount.Load())
+	assert.Zero(t, created["solidity"].liveCallCount.Load())
+	require.ErrorIs(t, c.RemoveNode(t.Context(), "unknown"), ErrNodeNotFound)
+
+	custom, err := NewWithTransport(newCT("custom"), Config{Health: HealthConfig{HealthyInterval: time.Hour}})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = custom.Close() })
+	require.NoError(t, custom.AddNode(NodeConfig{Protocol: "fake", Address: "beside"}))
+	assert.Contains(t, created, "beside", "nodes added beside a custom transport are created from their protocol")
+
+	plain, err := New(Config{
+		Nodes:  []NodeConfig{{Protocol: "fake", Address: "plain"}},
+		Health: HealthConfig{Disabled: true},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = plain.Close() })
+	require.ErrorIs(t, plain.AddNode(NodeConfig{Protocol: "fake", Address: "other"}), ErrInvalidConfig)
+	require.ErrorIs(t, plain.AddNode(NodeConfig{Protocol: "fake", Address: "other", Solidity: true}), ErrNoSolidityNodes)
+}
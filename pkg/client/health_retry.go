@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/sxwebdev/gotron/schema/pb/api"
+)
+
+// withDefaults fills zero-valued fields with sensible defaults. Returns a copy.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 1
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = 50 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = time.Second
+	}
+	if p.MaxBackoff < p.InitialBackoff {
+		p.MaxBackoff = p.InitialBackoff
+	}
+	if p.BudgetRatio <= 0 {
+		p.BudgetRatio = 0.1
+	}
+	if p.BudgetBurst <= 0 {
+		p.BudgetBurst = 10
+	}
+	return p
+}
+
+// backoff returns the delay before retry number retry (1-based): the doubled
+// InitialBackoff capped at MaxBackoff, of which the upper half is random.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < retry && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, p.MaxBackoff)
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+// retryBudget is a token bucket shared by every call of one method. Calls
+// deposit ratio tokens, retries withdraw one, and the balance never exceeds
+// burst.
+type retryBudget struct {
+	mu     sync.Mutex
+	tokens float64
+	ratio  float64
+	burst  float64
+}
+
+func newRetryBudget(p RetryPolicy) *retryBudget {
+	return &retryBudget{
+		tokens: float64(p.BudgetBurst),
+		ratio:  p.BudgetRatio,
+		burst:  float64(p.BudgetBurst),
+	}
+}
+
+// deposit credits one call.
+func (b *retryBudget) deposit() {
+	b.mu.Lock()
+	b.tokens = min(b.tokens+b.ratio, b.burst)
+	b.mu.Unlock()
+}
+
+// withdraw spends a token for one retry and reports whether there was one.
+func (b *retryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// newRetryBudgets builds one budget per idempotent method. The map is never
+// written after construction, so it is read without a lock.
+func newRetryBudgets(p RetryPolicy) map[string]*retryBudget {
+	if p.MaxAttempts <= 1 {
+		return nil
+	}
+	budgets := make(map[string]*retryBudget)
+	for method := range transportMethods {
+		if isIdempotent(method) {
+			budgets[method] = newRetryBudget(p)
+		}
+	}
+	return budgets
+}
+
+// invoke runs call on the next healthy node and feeds the outcome into that
+// node's health counters. When a call of an idempotent method fails at the
+// network level or is rate-limited, it is retried on nodes not yet tried for
+// this call, within RetryPolicy.MaxAttempts and the method's retry budget.
+// The error of the last attempt is returned when every attempt failed. The
+// CallOptions in ctx restrict which nodes are eligible and bound the whole
+// call in time.
+//
+// A broadcast that timed out may still have reached the network, so a retry
+// answered with DUP_TRANSACTION_ERROR is the earlier attempt's success: the
+// same signed bytes carry the same txid.
+//
+// It is a function rather than a method because Go methods cannot have type
+// parameters.
+func invoke[T any](h *HealthAwareTransport, ctx context.Context, method string, call func(context.Context, Transport) (T, error)) (T, error) {
+	opts := callOptionsFrom(ctx)
+	ctx, cancel := opts.withTimeout(ctx)
+	defer cancel()
+
+	n, err := h.choose(opts, nil)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	budget := h.budgets[method]
+	if budget != nil {
+		budget.deposit()
+	}
+
+	res, tried, err := attempt(h, ctx, method, opts, n, call)
+	n.release()
+	if err == nil || budget == nil || !h.retryable(err) {
+		return res, err
+	}
+
+	for retry := 1; retry < h.cfg.Retry.MaxAttempts; retry++ {
+		next, nextErr := h.choose(opts, tried)
+		if nextErr != nil {
+			break
+		}
+		if !budget.withdraw() || !h.sleep(ctx, h.cfg.Retry.backoff(retry)) {
+			next.release()
+			break
+		}
+		if h.metrics != nil {
+			h.metrics.RecordRetry(h.blockchain, method)
+		}
+
+		var used []*nodeState
+		res, used, err = attempt(h, ctx, method, opts, next, call)
+		next.release()
+		tried = append(tried, used...)
+		if err == nil {
+			return retriedBroadcast(res), nil
+		}
+		if !h.retryable(err) {
+			break
+		}
+	}
+	return res, err
+}
+
+// retriedBroadcast turns the duplicate rejection of a retried broadcast into
+// the success the first attempt had. Any other answer is returned as it is.
+func retriedBroadcast[T any](res T) T {
+	ret, ok := any(res).(*api.Return)
+	if !ok || ret.GetCode() != api.Return_DUP_TRANSACTION_ERROR {
+		return res
+	}
+	return any(&api.Return{Result: true, Code: api.Return_SUCCESS}).(T)
+}
+
+// retryable reports whether a failed attempt may be retried on another node:
+// a network-level failure, or a rate limit the next node may not share.
+func (h *HealthAwareTransport) retryable(err error) bool {
+	return h.cfg.ClassifyErr(err) || isRateLimited(err)
+}
+
+// sleep waits for d and reports whether it elapsed; it returns false early
+// when ctx is done or the transport is closing.
+func (h *HealthAwareTransport) sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-h.stopCh:
+		return false
+	}
+}
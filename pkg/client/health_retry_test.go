@@ -0,0 +1,228 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/grpc/codes"
+)
+
+// quietProbes keeps background probes out of a retry test.
+func quietProbes(cfg HealthConfig) HealthConfig {
+	cfg.HealthyInterval = time.Hour
+	cfg.UnhealthyInterval = time.Hour
+	cfg.InactiveTierInterval = time.Hour
+	return cfg
+}
+
+func TestRetry_NetworkErrorRetriedOnAnotherNode(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0}, quietProbes(HealthConfig{
+			FailureThreshold: 5,
+			Retry:            RetryPolicy{MaxAttempts: 3},
+		}))
+		h.nodes[0].setNextErr(grpcErr(codes.Unavailable))
+
+		_, err := h.transport.GetAccount(bgctx(), &core.Account{})
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(1), h.nodes[0].liveCallCount.Load())
+		assert.Equal(t, int64(1), h.nodes[1].liveCallCount.Load())
+		require.Len(t, h.metrics.retries, 1)
+		assert.Equal(t, recordedRetry{"tron", "GetAccount"}, h.metrics.retries[0])
+	})
+}
+
+func TestRetry_FallsOverToNextTier(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 1}, quietProbes(HealthConfig{
+			FailureThreshold: 5,
+			Retry:            RetryPolicy{MaxAttempts: 2},
+		}))
+		h.nodes[0].setNextErr(grpcErr(codes.Unavailable))
+
+		_, err := h.transport.GetNowBlock(bgctx())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), h.nodes[1].liveCallCount.Load())
+	})
+}
+
+func TestRetry_BroadcastIsRetried(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0}, quietProbes(HealthConfig{
+			FailureThreshold: 5,
+			Retry:            RetryPolicy{MaxAttempts: 2},
+		}))
+		h.nodes[0].setNextErr(grpcErr(codes.DeadlineExceeded))
+
+		_, err := h.transport.BroadcastTransaction(bgctx(), &core.Transaction{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), h.nodes[1].liveCallCount.Load())
+		assert.Len(t, h.metrics.retries, 1)
+	})
+}
+
+// The first broadcast timed out after reaching the network, so the node the
+// retry goes to already has the transaction.
+func TestRetry_BroadcastDuplicateAfterRetryIsSuccess(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0}, quietProbes(HealthConfig{
+			FailureThreshold: 5,
+			Retry:            RetryPolicy{MaxAttempts: 2},
+		}))
+		h.nodes[0].setNextErr(grpcErr(codes.DeadlineExceeded))
+		dup := &api.Return{Code: api.Return_DUP_TRANSACTION_ERROR, Message: []byte("Dup transaction.")}
+		h.nodes[1].setBroadcast(dup)
+
+		ret, err := h.transport.BroadcastTransaction(bgctx(), &core.Transaction{})
+		require.NoError(t, err)
+		assert.True(t, ret.GetResult())
+		assert.Equal(t, api.Return_SUCCESS, ret.GetCode())
+		assert.Equal(t, int64(1), h.nodes[1].liveCallCount.Load())
+
+		c, err := NewWithTransport(h.transport, Config{Health: HealthConfig{Disabled: true}})
+		require.NoError(t, err)
+		h.nodes[0].setNextErr(grpcErr(codes.DeadlineExceeded))
+		_, err = c.BroadcastTransaction(bgctx(), &core.Transaction{})
+		require.NoError(t, err, "the caller must not sign and send again")
+	})
+}
+
+// A duplicate on the first attempt is the caller sending twice.
+func TestRetry_BroadcastDuplicateOnFirstAttemptIsRejected(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0}, quietProbes(HealthConfig{
+			FailureThreshold: 5,
+			Retry:            RetryPolicy{MaxAttempts: 2},
+		}))
+		h.nodes[0].setBroadcast(&api.Return{Code: api.Return_DUP_TRANSACTION_ERROR})
+
+		ret, err := h.transport.BroadcastTransaction(bgctx(), &core.Transaction{})
+		require.NoError(t, err)
+		assert.Equal(t, api.Return_DUP_TRANSACTION_ERROR, ret.GetCode())
+	})
+}
+
+func TestRetry_WriteIsNotRetried(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0}, quietProbes(HealthConfig{
+			FailureThreshold: 5,
+			Retry:            RetryPolicy{MaxAttempts: 3},
+		}))
+		h.nodes[0].setNextErr(grpcErr(codes.Unavailable))
+
+		_, err := h.transport.CreateTransaction(bgctx(), &core.TransferContract{})
+		require.Error(t, err)
+		assert.Equal(t, int64(0), h.nodes[1].liveCallCount.Load())
+		assert.Empty(t, h.metrics.retries)
+	})
+}
+
+func TestRetry_RequestErrorIsNotRetried(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0}, quietProbes(HealthConfig{
+			Retry: RetryPolicy{MaxAttempts: 3},
+		}))
+		refusal := &ContractValidateError{Message: "balance is not sufficient"}
+		h.nodes[0].setNextErr(refusal)
+		h.nodes[1].setNextErr(refusal)
+
+		_, err := h.transport.GetAccount(bgctx(), &core.Account{})
+		require.ErrorIs(t, err, refusal)
+		assert.Equal(t, int64(1), h.nodes[0].liveCallCount.Load()+h.nodes[1].liveCallCount.Load())
+		assert.Empty(t, h.metrics.retries)
+	})
+}
+
+func TestRetry_StopsAtMaxAttempts(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0, 0, 0}, quietProbes(HealthConfig{
+			FailureThreshold: 5,
+			Retry:            RetryPolicy{MaxAttempts: 3},
+		}))
+		for _, n := range h.nodes {
+			n.setNextErr(grpcErr(codes.Unavailable))
+		}
+
+		_, err := h.transport.GetAccount(bgctx(), &core.Account{})
+		require.Error(t, err)
+
+		var calls int64
+		for _, n := range h.nodes {
+			assert.LessOrEqual(t, n.liveCallCount.Load(), int64(1), "a call must not revisit a node")
+			calls += n.liveCallCount.Load()
+		}
+		assert.Equal(t, int64(3), calls)
+		assert.Len(t, h.metrics.retries, 2)
+	})
+}
+
+func TestRetry_BudgetLimitsRetries(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0}, quietProbes(HealthConfig{
+			FailureThreshold: 100,
+			Retry: RetryPolicy{
+				MaxAttempts: 2,
+				BudgetBurst: 2,
+				BudgetRatio: 0.01,
+			},
+		}))
+		h.nodes[0].setNextErr(grpcErr(codes.Unavailable))
+		h.nodes[1].setNextErr(grpcErr(codes.Unavailable))
+
+		for range 10 {
+			_, _ = h.transport.GetAccount(bgctx(), &core.Account{})
+		}
+		assert.Len(t, h.metrics.retries, 2, "retries beyond the budget must be dropped")
+
+		// The budget is per method: another method still has its own.
+		_, _ = h.transport.GetNowBlock(bgctx())
+		assert.Len(t, h.metrics.retries, 3)
+	})
+}
+
+func TestRetry_CancelledContextStopsBackoff(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0}, quietProbes(HealthConfig{
+			FailureThreshold: 5,
+			Retry: RetryPolicy{
+				MaxAttempts:    2,
+				InitialBackoff: time.Minute,
+				MaxBackoff:     time.Minute,
+			},
+		}))
+		h.nodes[0].setNextErr(grpcErr(codes.Unavailable))
+
+		ctx, cancel := context.WithCancel(bgctx())
+		time.AfterFunc(time.Second, cancel)
+
+		_, err := h.transport.GetAccount(ctx, &core.Account{})
+		require.Error(t, err)
+		assert.Equal(t, int64(0), h.nodes[1].liveCallCount.Load())
+		assert.Empty(t, h.metrics.retries)
+	})
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}.withDefaults()
+
+	for range 100 {
+		d := p.backoff(1)
+		assert.GreaterOrEqual(t, d, 50*time.Millisecond)
+		assert.LessOrEqual(t, d, 100*time.Millisecond)
+
+		d = p.backoff(2)
+		assert.GreaterOrEqual(t, d, 100*time.Millisecond)
+		assert.LessOrEqual(t, d, 200*time.Millisecond)
+
+		d = p.backoff(10)
+		assert.GreaterOrEqual(t, d, 150*time.Millisecond)
+		assert.LessOrEqual(t, d, 300*time.Millisecond)
+	}
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+// ewmaWeight is the weight of the newest sample in a node's latency average.
+// 0.2 follows a shift in latency within about ten calls without letting one
+// outlier dominate.
+const ewmaWeight = 0.2
+
+// NodeLoad is the live load of one node, as a SelectionStrategy sees it.
+type NodeLoad struct {
+	// Address is the node's NodeConfig.Address.
+	Address string
+	// InFlight is the number of calls currently running on the node.
+	InFlight int
+	// Latency is the exponentially weighted moving average of the node's live
+	// call latency. Zero until the node has served its first call.
+	Latency time.Duration
+}
+
+// SelectionStrategy chooses which node of a tier serves a call.
+// HealthAwareTransport calls Select with the healthy candidates of the tier it
+// routes to - never an empty slice - in configuration order. Select must
+// return an index into nodes and be safe for concurrent use.
+type SelectionStrategy interface {
+	Select(nodes []NodeLoad) int
+}
+
+// NewRoundRobinStrategy returns the default strategy: candidates take turns,
+// regardless of load.
+func NewRoundRobinStrategy() SelectionStrategy {
+	return &roundRobinStrategy{}
+}
+
+type roundRobinStrategy struct {
+	counter atomic.Uint64
+}
+
+func (s *roundRobinStrategy) Select(nodes []NodeLoad) int {
+	return int((s.counter.Add(1) - 1) % uint64(len(nodes)))
+}
+
+// NewLeastOutstandingStrategy returns a strategy that picks the candidate
+// with the fewest calls in flight. Ties are broken in turn, so that sequential
+// traffic, which leaves every node idle, is still spread over the tier.
+func NewLeastOutstandingStrategy() SelectionStrategy {
+	return &leastOutstandingStrategy{}
+}
+
+type leastOutstandingStrategy struct {
+	counter atomic.Uint64
+}
+
+func (s *leastOutstandingStrategy) Select(nodes []NodeLoad) int {
+	start := int((s.counter.Add(1) - 1) % uint64(len(nodes)))
+	best := start
+	for i := range nodes {
+		j := (start + i) % len(nodes)
+		if nodes[j].InFlight < nodes[best].InFlight {
+			best = j
+		}
+	}
+	return best
+}
+
+// NewEWMAStrategy returns a power-of-two-choices strategy: it draws two
+// candidates at random and keeps the one with the lower expected wait, its
+// average latency multiplied by its calls in flight plus one. Sampling two
+// instead of scanning all keeps a burst of calls from piling onto the single
+// node that looked fastest a moment ago. A node with no latency sample yet
+// looks free, so every node is tried early on.
+func NewEWMAStrategy() SelectionStrategy {
+	return ewmaStrategy{}
+}
+
+type ewmaStrategy struct{}
+
+func (ewmaStrategy) Select(nodes []NodeLoad) int {
+	if len(nodes) == 1 {
+		return 0
+	}
+	a := rand.IntN(len(nodes))
+	b := rand.IntN(len(nodes) - 1)
+	if b >= a {
+		b++
+	}
+	if nodes[b].cost() < nodes[a].cost() {
+		return b
+	}
+	return a
+}
+
+// cost is the expected wait for a new call on the node.
+func (l NodeLoad) cost() float64 {
+	return float64(l.Latency) * float64(l.InFlight+1)
+}
+
+// callNode runs call on n and returns its latency. The call counts towards
+// n's in-flight load while it runs.
+func callNode[T any](ctx context.Context, n *nodeState, call func(context.Context, Transport) (T, error)) (T, time.Duration, error) {
+	n.inFlight.Add(1)
+	defer n.inFlight.Add(-1)
+	start := time.Now()
+	res, err := call(ctx, n.transport)
+	return res, time.Since(start), err
+}
+
+// recordLatency folds one call's latency into n's moving average.
+func recordLatency(n *nodeState, d time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.latency == 0 {
+		n.latency = d
+		return
+	}
+	n.latency = time.Duration(ewmaWeight*float64(d) + (1-ewmaWeight)*float64(n.latency))
+}
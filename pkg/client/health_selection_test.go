@@ -0,0 +1,135 @@
+package client
+
+import (
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+)
+
+func TestRoundRobinStrategy_TakesTurns(t *testing.T) {
+	s := NewRoundRobinStrategy()
+	nodes := make([]NodeLoad, 3)
+	var got []int
+	for range 6 {
+		got = append(got, s.Select(nodes))
+	}
+	assert.Equal(t, []int{0, 1, 2, 0, 1, 2}, got)
+}
+
+func TestLeastOutstandingStrategy_PicksIdlestNode(t *testing.T) {
+	s := NewLeastOutstandingStrategy()
+	nodes := []NodeLoad{{InFlight: 4}, {InFlight: 1}, {InFlight: 3}}
+	for range 5 {
+		assert.Equal(t, 1, s.Select(nodes))
+	}
+
+	// Idle nodes are used in turn.
+	idle := make([]NodeLoad, 3)
+	seen := map[int]int{}
+	for range 6 {
+		seen[s.Select(idle)]++
+	}
+	assert.Equal(t, map[int]int{0: 2, 1: 2, 2: 2}, seen)
+}
+
+func TestEWMAStrategy_PrefersLowerExpectedWait(t *testing.T) {
+	s := NewEWMAStrategy()
+	assert.Equal(t, 0, s.Select([]NodeLoad{{Latency: time.Second}}))
+
+	// With two candidates both are always sampled, so the cheaper one wins.
+	slow := NodeLoad{Latency: 100 * time.Millisecond}
+	fast := NodeLoad{Latency: 10 * time.Millisecond}
+	for range 20 {
+		assert.Equal(t, 1, s.Select([]NodeLoad{slow, fast}))
+	}
+
+	// A fast node buried under calls costs more than an idle slower one.
+	busy := NodeLoad{Latency: 10 * time.Millisecond, InFlight: 20}
+	for range 20 {
+		assert.Equal(t, 0, s.Select([]NodeLoad{slow, busy}))
+	}
+
+	// Across many nodes the most expensive one is never chosen.
+	nodes := []NodeLoad{fast, fast, fast, {Latency: time.Hour}}
+	for range 100 {
+		assert.NotEqual(t, 3, s.Select(nodes))
+	}
+}
+
+func TestRecordLatency_MovingAverage(t *testing.T) {
+	n := &nodeState{}
+	recordLatency(n, 100*time.Millisecond)
+	assert.Equal(t, 100*time.Millisecond, n.latency, "the first sample is taken as is")
+
+	recordLatency(n, 200*time.Millisecond)
+	assert.Equal(t, 120*time.Millisecond, n.latency)
+}
+
+func TestSelection_EWMASteersAwayFromSlowNode(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		cfg := quietProbes(HealthConfig{Selection: NewEWMAStrategy()})
+		h := newHarness(t, []int{0, 0}, cfg)
+		h.nodes[0].setDelay(200 * time.Millisecond)
+		h.nodes[1].setDelay(10 * time.Millisecond)
+
+		// Warm up until both nodes have a latency sample.
+		for h.nodes[0].liveCallCount.Load() == 0 || h.nodes[1].liveCallCount.Load() == 0 {
+			_, err := h.transport.GetNowBlock(bgctx())
+			require.NoError(t, err)
+		}
+
+		slowBefore := h.nodes[0].liveCallCount.Load()
+		for range 50 {
+			_, err := h.transport.GetAccount(bgctx(), &core.Account{})
+			require.NoError(t, err)
+		}
+		assert.Equal(t, slowBefore, h.nodes[0].liveCallCount.Load(), "the slow node must not be picked once measured")
+	})
+}
+
+func TestSelection_LeastOutstandingSpreadsConcurrentCalls(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		cfg := quietProbes(HealthConfig{Selection: NewLeastOutstandingStrategy()})
+		h := newHarness(t, []int{0, 0, 0}, cfg)
+		for _, n := range h.nodes {
+			n.setDelay(time.Second)
+		}
+
+		var wg sync.WaitGroup
+		for range 9 {
+			wg.Go(func() {
+				_, err := h.transport.GetNowBlock(bgctx())
+				assert.NoError(t, err)
+			})
+		}
+		synctest.Wait()
+		for i, n := range h.transport.nodes {
+			assert.Equal(t, int64(3), n.inFlight.Load(), "node %d", i)
+		}
+		wg.Wait()
+
+		for i, n := range h.transport.nodes {
+			assert.Zero(t, n.inFlight.Load(), "node %d", i)
+		}
+	})
+}
+
+func TestSelection_InvalidIndexFallsBackToFirstCandidate(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		cfg := quietProbes(HealthConfig{Selection: badStrategy{}})
+		h := newHarness(t, []int{0, 0}, cfg)
+
+		_, err := h.transport.GetNowBlock(bgctx())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), h.nodes[0].liveCallCount.Load())
+	})
+}
+
+type badStrategy struct{}
+
+func (badStrategy) Select([]NodeLoad) int { return 7 }
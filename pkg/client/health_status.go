@@ -0,0 +1,167 @@
+package client
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// PoolStatus is a snapshot of a pool of nodes, as HealthAwareTransport.Status
+// and Client.PoolStatus report it.
+type PoolStatus struct {
+	// ActiveTier is the tier calls are sent to: the lowest one with a healthy
+	// node, or -1 when no node is healthy.
+	ActiveTier int
+	// Nodes lists every node of the pool, in the order they were configured
+	// or added.
+	Nodes []NodeStatus
+}
+
+// NodeStatus is the state of one node of a pool.
+type NodeStatus struct {
+	Address string
+	// Alias is NodeConfig.Alias, empty when the node has none.
+	Alias   string
+	Tier    int
+	Healthy bool
+	// ConsecutiveSuccesses and ConsecutiveFailures count the probes and live
+	// calls since the last one with the other outcome; the node changes state
+	// when one of them reaches its threshold. Logical errors count for
+	// neither.
+	ConsecutiveSuccesses int
+	ConsecutiveFailures  int
+	// LastError is the last failure counted against the node, nil when there
+	// was none. It is kept after the node recovers.
+	LastError error
+	// LastProbe is when the last health probe finished, zero before the
+	// first one.
+	LastProbe time.Time
+	// Latency is the moving average of the node's live call latency, zero
+	// before its first call.
+	Latency time.Duration
+	// InFlight is the number of live calls running on the node.
+	InFlight int
+	// Head is the number of the head block reported to the node's last
+	// probe, and Lag how many blocks it trails the best head any probe has
+	// seen. Both are zero before the first probe, and when a custom
+	// HealthConfig.Probe is used without lag detection.
+	Head int64
+	Lag  int64
+}
+
+// HealthEventKind says what a HealthEvent reports.
+type HealthEventKind string
+
+const (
+	// EventNodeHealthy reports a node that recovered and is back in the pool.
+	EventNodeHealthy HealthEventKind = "node_healthy"
+	// EventNodeUnhealthy reports a node taken out of rotation; Cause says why.
+	EventNodeUnhealthy HealthEventKind = "node_unhealthy"
+	// EventTierShift reports that calls moved to another tier: Tier is the
+	// new active tier and PreviousTier the old one, -1 for none.
+	EventTierShift HealthEventKind = "tier_shift"
+	// EventNodeAdded and EventNodeRemoved report AddNode and RemoveNode.
+	EventNodeAdded   HealthEventKind = "node_added"
+	EventNodeRemoved HealthEventKind = "node_removed"
+	// EventNodeTierChanged reports SetNodeTier moving a node from
+	// PreviousTier to Tier.
+	EventNodeTierChanged HealthEventKind = "node_tier_changed"
+)
+
+// HealthEvent is a change in a pool, delivered to the functions registered
+// with Subscribe.
+type HealthEvent struct {
+	Kind HealthEventKind
+	// Blockchain is the pool's label, as in metrics: "tron", or
+	// "tron-solidity" for the solidity pool.
+	Blockchain string
+	// Address is the node the event is about; empty for EventTierShift.
+	Address string
+	// Tier is the node's tier, or the new active tier for EventTierShift.
+	Tier int
+	// PreviousTier is the tier before an EventTierShift or an
+	// EventNodeTierChanged, and Tier otherwise.
+	PreviousTier int
+	// Cause is the error that made a node unhealthy.
+	Cause error
+	Time  time.Time
+}
+
+// healthSubscriber is one function registered with Subscribe. It is a
+// pointer so that unsubscribing removes this registration, not an equal one.
+type healthSubscriber struct {
+	fn func(HealthEvent)
+}
+
+// Status returns a snapshot of the pool.
+func (h *HealthAwareTransport) Status() PoolStatus {
+	h.headMu.Lock()
+	best := h.bestHead.number
+	h.headMu.Unlock()
+
+	h.poolMu.RLock()
+	status := PoolStatus{
+		ActiveTier: int(h.activeTier.Load()),
+		Nodes:      make([]NodeStatus, len(h.nodes)),
+	}
+	for i, n := range h.nodes {
+		n.mu.Lock()
+		status.Nodes[i] = NodeStatus{
+			Address:              n.address,
+			Alias:                n.alias,
+			Tier:                 n.tier,
+			Healthy:              n.healthy,
+			ConsecutiveSuccesses: n.consecutiveSuccess,
+			ConsecutiveFailures:  n.consecutiveFailure,
+			LastError:            n.lastErr,
+			LastProbe:            n.lastProbe,
+			Latency:              n.latency,
+			InFlight:             int(n.inFlight.Load()),
+			Head:                 n.head.number,
+		}
+		if n.head.number > 0 {
+			status.Nodes[i].Lag = max(best-n.head.number, 0)
+		}
+		n.mu.Unlock()
+	}
+	h.poolMu.RUnlock()
+	return status
+}
+
+// Subscribe registers fn to be called with every HealthEvent of the pool
+// from now on, and returns the function that unregisters it. fn is called
+// synchronously by the goroutine that caused the event - a live call, a
+// probe, AddNode - possibly by several at once, so it must be safe for
+// concurrent use and return quickly; hand slow work to a goroutine or a
+// buffered channel. PoolStatus can be called from fn.
+func (h *HealthAwareTransport) Subscribe(fn func(HealthEvent)) (unsubscribe func()) {
+	sub := &healthSubscriber{fn: fn}
+	h.subsMu.Lock()
+	h.subs = append(h.subs, sub)
+	h.subsMu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			h.subsMu.Lock()
+			h.subs = slices.DeleteFunc(slices.Clone(h.subs), func(s *healthSubscriber) bool { return s == sub })
+			h.subsMu.Unlock()
+		})
+	}
+}
+
+// emit stamps e and hands it to every subscriber. It must not be called with
+// poolMu or a node's mu held, so that subscribers can read the status.
+func (h *HealthAwareTransport) emit(e HealthEvent) {
+	h.subsMu.Lock()
+	subs := h.subs
+	h.subsMu.Unlock()
+	if len(subs) == 0 {
+		return
+	}
+	e.Blockchain = h.blockchain
+	e.Time = time.Now()
+	for _, s := range subs {
+		s.fn(e)
+	}
+}
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/grpc/codes"
+)
+
+func TestHealthAware_Status(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 1}, HealthConfig{
+			HealthyInterval:      time.Minute,
+			UnhealthyInterval:    time.Hour,
+			InactiveTierInterval: time.Hour,
+		})
+		h.nodes[0].setDelay(10 * time.Millisecond)
+
+		_, err := h.transport.GetAccount(bgctx(), &core.Account{})
+		require.NoError(t, err)
+		start := time.Now()
+		time.Sleep(time.Minute)
+		synctest.Wait()
+
+		status := h.transport.Status()
+		assert.Equal(t, 0, status.ActiveTier)
+		require.Len(t, status.Nodes, 2)
+		assert.WithinRange(t, status.Nodes[0].LastProbe, start, time.Now())
+		status.Nodes[0].LastProbe = time.Time{}
+		assert.Equal(t, NodeStatus{
+			Address:              h.nodes[0].name,
+			Tier:                 0,
+			Healthy:              true,
+			ConsecutiveSuccesses: 2,
+			Latency:              10 * time.Millisecond,
+		}, status.Nodes[0])
+		assert.True(t, status.Nodes[1].LastProbe.IsZero(), "the fallback has not been probed yet")
+
+		failure := grpcErr(codes.Unavailable)
+		h.nodes[0].setNextErr(failure)
+		for range 2 {
+			_, _ = h.transport.GetAccount(bgctx(), &core.Account{})
+		}
+		status = h.transport.Status()
+		assert.Equal(t, 1, status.ActiveTier)
+		assert.False(t, status.Nodes[0].Healthy)
+		assert.Equal(t, 2, status.Nodes[0].ConsecutiveFailures)
+		assert.Zero(t, status.Nodes[0].ConsecutiveSuccesses)
+		assert.Equal(t, failure, status.Nodes[0].LastError)
+	})
+}
+
+// eventLog collects the events of a subscription.
+type eventLog struct {
+	mu     sync.Mutex
+	events []HealthEvent
+}
+
+func (l *eventLog) add(e HealthEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, e)
+}
+
+// kinds returns the kinds received so far, with their address or tier.
+func (l *eventLog) kinds() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make([]string, len(l.events))
+	for i, e := range l.events {
+		out[i] = string(e.Kind) + " " + e.Address
+		if e.Kind == EventTierShift || e.Kind == EventNodeTierChanged {
+			out[i] += fmt.Sprintf(" %d->%d", e.PreviousTier, e.Tier)
+		}
+	}
+	return out
+}
+
+func TestHealthAware_Subscribe(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 1}, HealthConfig{
+			HealthyInterval:      time.Hour,
+			UnhealthyInterval:    time.Minute,
+			InactiveTierInterval: time.Hour,
+		})
+		log := &eventLog{}
+		unsubscribe := h.transport.Subscribe(log.add)
+
+		failure := grpcErr(codes.Unavailable)
+		h.nodes[0].setNextErr(failure)
+		for range 2 {
+			_, _ = h.transport.GetAccount(bgctx(), &core.Account{})
+		}
+		// Two probes at UnhealthyInterval bring it back.
+		time.Sleep(2 * time.Minute)
+		synctest.Wait()
+
+		added := h.newNode("added")
+		require.NoError(t, h.transport.AddNode(NodeConfig{Address: added.name, Tier: 1}))
+		require.NoError(t, h.transport.SetNodeTier(added.name, 2))
+		require.NoError(t, h.transport.RemoveNode(bgctx(), added.name))
+
+		assert.Equal(t, []string{
+			"node_unhealthy " + h.nodes[0].name,
+			"tier_shift  0->1",
+			"node_healthy " + h.nodes[0].name,
+			"tier_shift  1->0",
+			"node_added added",
+			"node_tier_changed added 1->2",
+			"node_removed added",
+		}, log.kinds())
+
+		log.mu.Lock()
+		first := log.events[0]
+		log.mu.Unlock()
+		assert.Equal(t, "tron", first.Blockchain)
+		assert.Equal(t, failure, first.Cause)
+		assert.False(t, first.Time.IsZero())
+
+		unsubscribe()
+		unsubscribe()
+		h.nodes[1].setNextErr(failure)
+		for range 2 {
+			_, _ = h.transport.GetAccount(WithCallOptions(bgctx(), CallTier(1)), &core.Account{})
+		}
+		assert.Len(t, log.kinds(), 7, "nothing is delivered after unsubscribing")
+	})
+}
+
+func TestClient_PoolStatus(t *testing.T) {
+	registerFake(t, "fake")
+	c, err := New(Config{
+		Nodes: []NodeConfig{
+			{Protocol: "fake", Address: "primary"},
+			{Protocol: "fake", Address: "solidity", Solidity: true},
+		},
+		Health: HealthConfig{HealthyInterval: time.Hour},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	log := &eventLog{}
+	unsubscribe, err := c.SubscribeHealth(log.add)
+	require.NoError(t, err)
+	defer unsubscribe()
+	require.NoError(t, c.AddNode(NodeConfig{Protocol: "fake", Address: "fallback", Tier: 1}))
+	assert.Equal(t, []string{"node_added fallback"}, log.kinds())
+
+	status, err := c.PoolStatus()
+	require.NoError(t, err)
+	require.Len(t, status.Nodes, 2)
+	assert.Equal(t, "primary", status.Nodes[0].Address)
+	assert.Equal(t, "fallback", status.Nodes[1].Address)
+
+	confirmed, err := c.Confirmed()
+	require.NoError(t, err)
+	status, err = confirmed.PoolStatus()
+	require.NoError(t, err)
+	require.Len(t, status.Nodes, 1)
+	assert.Equal(t, "solidity", status.Nodes[0].Address)
+
+	plain, err := New(Config{
+		Nodes:  []NodeConfig{{Protocol: "fake", Address: "plain"}},
+		Health: HealthConfig{Disabled: true},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = plain.Close() })
+	_, err = plain.PoolStatus()
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	_, err = plain.SubscribeHealth(log.add)
+	require.ErrorIs(t, err, ErrInvalidConfig)
+}
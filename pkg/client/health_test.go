@@ -452,9 +452,11 @@ func TestHealthAware_DisabledFallsBackToRoundRobin(t *testing.T) {
 	require.NoError(t, err)
 	defer func() { _ = c.Close() }()
 
-	// The transport stack should be RoundRobinTransport (no Metrics → no wrapping).
-	_, ok := c.transport.(*RoundRobinTransport)
-	assert.True(t, ok, "expected RoundRobinTransport when Health.Disabled, got %T", c.transport)
+	// The full-node pool should be RoundRobinTransport (no Metrics → no wrapping).
+	router, ok := c.transport.(*solidifiedRouter)
+	require.True(t, ok, "got %T", c.transport)
+	_, ok = router.full.(*RoundRobinTransport)
+	assert.True(t, ok, "expected RoundRobinTransport when Health.Disabled, got %T", router.full)
 }
 
 // (s) Только tier 0, fallback нет: при падении всех primary → ErrNoHealthyNodes.
@@ -599,7 +601,7 @@ func TestHealthAware_TierShift_DemotesFallbackProbing(t *testing.T) {
 func TestIsNetworkError_GRPCCodes(t *testing.T) {
 	for _, c := range []codes.Code{
 		codes.Unavailable, codes.DeadlineExceeded, codes.Aborted,
-		codes.ResourceExhausted, codes.Internal, codes.Unknown,
+		codes.Internal, codes.Unknown,
 	} {
 		assert.True(t, isNetworkError(grpcErr(c)), "code=%s should be network", c)
 	}
@@ -607,6 +609,7 @@ func TestIsNetworkError_GRPCCodes(t *testing.T) {
 		codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
 		codes.PermissionDenied, codes.Unauthenticated,
 		codes.FailedPrecondition, codes.OutOfRange, codes.Unimplemented,
+		codes.ResourceExhausted,
 	} {
 		assert.False(t, isNetworkError(grpcErr(c)), "code=%s should be logical", c)
 	}
@@ -619,7 +622,7 @@ func TestIsNetworkError_HTTPStatus(t *testing.T) {
 	assert.True(t, isNetworkError(wrap(503)))
 	assert.True(t, isNetworkError(wrap(500)))
 	assert.True(t, isNetworkError(wrap(408)))
-	assert.True(t, isNetworkError(wrap(429)))
+	assert.False(t, isNetworkError(wrap(429)), "a rate limit is not the node's fault")
 	assert.False(t, isNetworkError(wrap(400)))
 	assert.False(t, isNetworkError(wrap(404)))
 }
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -23,6 +24,113 @@ type MetricsCollector interface {
 	SetPoolHealth(blockchain string, total, healthy, disabled int)
 }
 
+// HedgeRecorder is implemented by collectors that count hedged reads. It is a
+// separate interface so that existing MetricsCollector implementations keep
+// compiling; HealthAwareTransport uses it only when the collector has it.
+type HedgeRecorder interface {
+	// RecordHedge records a hedge that was sent for method. won reports
+	// whether the hedge, rather than the original request, produced the
+	// answer.
+	RecordHedge(blockchain, method string, won bool)
+}
+
+// NodeLagRecorder is implemented by collectors that export how far each
+// node's head block trails the pool. HealthAwareTransport calls SetNodeLag
+// after every probe that read a head block, which the default probe does.
+type NodeLagRecorder interface {
+	// SetNodeLag sets the number of blocks between node's head and the best
+	// head seen across the pool; 0 for the node that has the best head.
+	SetNodeLag(blockchain, node string, blocks int64)
+}
+
+// NodeForgetter is implemented by collectors that keep series per node.
+// HealthAwareTransport calls ForgetNode when RemoveNode takes a node out of
+// the pool, so that the node's series stop being exported; a node added back
+// later starts them afresh.
+type NodeForgetter interface {
+	// ForgetNode drops every series of node.
+	ForgetNode(blockchain, node string)
+}
+
+// NodeMetricsRecorder is implemented by collectors that break the metrics
+// down by node, to tell which node is slow or failing. node is the node's
+// NodeConfig.Alias, or its address when it has none. HealthAwareTransport
+// calls it for every attempt of a call on a node - a call that is retried or
+// hedged makes several - for every health probe, and for every change of a
+// node's health.
+type NodeMetricsRecorder interface {
+	// RecordNodeRequest records one attempt of method on node. status is
+	// "success" or "error", and errorClass says what went wrong: one of the
+	// ErrorClass constants, empty on success. An answer by which the node
+	// refused the request - a rejected broadcast, a transaction it would not
+	// build - is an error here, although the call itself returned one.
+	RecordNodeRequest(blockchain, node, method, status, errorClass string, duration time.Duration)
+
+	// RecordProbe records a health probe of node. status is "success" or
+	// "error"; a probe that found the node lagging is an error.
+	RecordProbe(blockchain, node, status string, duration time.Duration)
+
+	// RecordNodeTransition records node turning healthy or unhealthy.
+	RecordNodeTransition(blockchain, node string, healthy bool)
+}
+
+// The classes of error RecordNodeRequest reports.
+const (
+	// ErrorClassNetwork is a failure the health checker counts against the
+	// node: a timeout, a broken connection, a 5xx or an unavailable node.
+	ErrorClassNetwork = "network"
+	// ErrorClassRateLimited is a 429, a gRPC ResourceExhausted or
+	// ErrRateLimited.
+	ErrorClassRateLimited = "rate_limited"
+	// ErrorClassValidation is a request the node refused as invalid: a
+	// ContractValidateError, ErrNodeRefusedRequest, or a gRPC InvalidArgument
+	// or FailedPrecondition.
+	ErrorClassValidation = "validation"
+	// ErrorClassBroadcastRejected is a broadcast the node rejected, which
+	// the Client reports as a BroadcastError.
+	ErrorClassBroadcastRejected = "broadcast_rejected"
+	// ErrorClassCanceled is a call whose context was canceled, including a
+	// hedged request that lost the race.
+	ErrorClassCanceled = "canceled"
+	// ErrorClassOther is any other error.
+	ErrorClassOther = "other"
+)
+
+// TierHealthRecorder is implemented by collectors that export the health of
+// each tier of a pool. HealthAwareTransport calls SetTierHealth for every tier
+// whenever it publishes the pool health, and once with zero nodes for a tier
+// that SetNodeTier or RemoveNode left empty.
+type TierHealthRecorder interface {
+	// SetTierHealth sets the number of nodes in tier and how many of them
+	// are healthy. active reports whether tier is the one calls are sent to.
+	SetTierHealth(blockchain string, tier, total, healthy int, active bool)
+}
+
+// RateLimitRecorder is implemented by collectors that track how long calls
+// wait for a node's NodeConfig.RateLimit or for its rate-limited API keys to
+// rest. RecordRateLimitWait is called only for calls that had to wait.
+type RateLimitRecorder interface {
+	// RecordRateLimitWait records that a call to node waited d before it
+	// was sent.
+	RecordRateLimitWait(blockchain, node string, d time.Duration)
+}
+
+// CoalesceRecorder is implemented by collectors that count the reads
+// CoalescingTransport merged into a call already in flight.
+type CoalesceRecorder interface {
+	// RecordCoalesced records a call of method that was answered by an
+	// identical call in flight rather than sent upstream.
+	RecordCoalesced(blockchain, method string)
+}
+
+// CacheRecorder is implemented by collectors that count the lookups of
+// CachingTransport. Only calls the cache policy covers are looked up.
+type CacheRecorder interface {
+	// RecordCacheLookup records a call of method that was answered from
+	// the cache (hit) or had to be sent upstream.
+	RecordCacheLookup(blockchain, method string, hit bool)
+}
+
 // Metrics contains built-in Prometheus metrics for RPC monitoring.
 // It implements MetricsCollector.
 type Metrics struct {
@@ -30,12 +138,34 @@ type Metrics struct {
 	requestDuration  *prometheus.HistogramVec
 	requestsInFlight prometheus.Gauge
 	retriesTotal     *prometheus.CounterVec
+	hedgesTotal      *prometheus.CounterVec
+	nodeLag          *prometheus.GaugeVec
+	rateLimitWait    *prometheus.HistogramVec
+	coalescedTotal   *prometheus.CounterVec
+	cacheLookups     *prometheus.CounterVec
+	nodeRequests     *prometheus.CounterVec
+	nodeDuration     *prometheus.HistogramVec
+	probeDuration    *prometheus.HistogramVec
+	nodeTransitions  *prometheus.CounterVec
+	tierTotal        *prometheus.GaugeVec
+	tierHealthy      *prometheus.GaugeVec
+	tierActive       *prometheus.GaugeVec
 	poolTotal        *prometheus.GaugeVec
 	poolHealthy      *prometheus.GaugeVec
 	poolDisabled     *prometheus.GaugeVec
 }
 
-var _ MetricsCollector = (*Metrics)(nil)
+var (
+	_ MetricsCollector    = (*Metrics)(nil)
+	_ HedgeRecorder       = (*Metrics)(nil)
+	_ NodeLagRecorder     = (*Metrics)(nil)
+	_ NodeForgetter       = (*Metrics)(nil)
+	_ NodeMetricsRecorder = (*Metrics)(nil)
+	_ TierHealthRecorder  = (*Metrics)(nil)
+	_ RateLimitRecorder   = (*Metrics)(nil)
+	_ CoalesceRecorder    = (*Metrics)(nil)
+	_ CacheRecorder       = (*Metrics)(nil)
+)
 
 // NewMetrics creates and registers Prometheus metrics.
 func NewMetrics(reg prometheus.Registerer) *Metrics {
@@ -68,6 +198,93 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 			},
 			[]string{"blockchain", "method"},
 		),
+		hedgesTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gotron_rpc_hedges_total",
+				Help: "Total number of hedged read requests, by which request answered",
+			},
+			[]string{"blockchain", "method", "result"},
+		),
+		nodeLag: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "gotron_rpc_node_lag_blocks",
+				Help: "Number of blocks the node's head trails the best head in the pool",
+			},
+			[]string{"blockchain", "node"},
+		),
+		rateLimitWait: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "gotron_rpc_rate_limit_wait_seconds",
+				Help:    "Time calls waited for the node rate limit or a rate-limited API key",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"blockchain", "node"},
+		),
+		coalescedTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gotron_rpc_coalesced_total",
+				Help: "Total number of reads merged into an identical call in flight",
+			},
+			[]string{"blockchain", "method"},
+		),
+		cacheLookups: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gotron_rpc_cache_lookups_total",
+				Help: "Total number of read cache lookups, by whether the cache had the answer",
+			},
+			[]string{"blockchain", "method", "result"},
+		),
+		nodeRequests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gotron_rpc_node_requests_total",
+				Help: "Total number of RPC attempts sent to each node, by outcome and error class",
+			},
+			[]string{"blockchain", "node", "method", "status", "error_class"},
+		),
+		nodeDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "gotron_rpc_node_duration_seconds",
+				Help:    "RPC attempt duration on each node in seconds",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"blockchain", "node"},
+		),
+		probeDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "gotron_rpc_probe_duration_seconds",
+				Help:    "Health probe duration in seconds",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"blockchain", "node", "status"},
+		),
+		nodeTransitions: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gotron_rpc_node_transitions_total",
+				Help: "Total number of node health transitions, by the state entered",
+			},
+			[]string{"blockchain", "node", "to"},
+		),
+		tierTotal: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "gotron_rpc_tier_total",
+				Help: "Total number of nodes in the tier",
+			},
+			[]string{"blockchain", "tier"},
+		),
+		tierHealthy: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "gotron_rpc_tier_healthy",
+				Help: "Number of healthy nodes in the tier",
+			},
+			[]string{"blockchain", "tier"},
+		),
+		tierActive: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "gotron_rpc_tier_active",
+				Help: "1 for the tier calls are sent to, 0 for the others",
+			},
+			[]string{"blockchain", "tier"},
+		),
 		poolTotal: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name: "gotron_rpc_pool_total",
@@ -96,6 +313,18 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 		m.requestDuration,
 		m.requestsInFlight,
 		m.retriesTotal,
+		m.hedgesTotal,
+		m.nodeLag,
+		m.rateLimitWait,
+		m.coalescedTotal,
+		m.cacheLookups,
+		m.nodeRequests,
+		m.nodeDuration,
+		m.probeDuration,
+		m.nodeTransitions,
+		m.tierTotal,
+		m.tierHealthy,
+		m.tierActive,
 		m.poolTotal,
 		m.poolHealthy,
 		m.poolDisabled,
@@ -124,6 +353,85 @@ func (m *Metrics) RecordRetry(blockchain, method string) {
 	m.retriesTotal.WithLabelValues(blockchain, method).Inc()
 }
 
+// RecordHedge records a hedged read. result is "won" when the hedge answered
+// first and "lost" otherwise.
+func (m *Metrics) RecordHedge(blockchain, method string, won bool) {
+	result := "lost"
+	if won {
+		result = "won"
+	}
+	m.hedgesTotal.WithLabelValues(blockchain, method, result).Inc()
+}
+
+// SetNodeLag updates the head-block lag of a node.
+func (m *Metrics) SetNodeLag(blockchain, node string, blocks int64) {
+	m.nodeLag.WithLabelValues(blockchain, node).Set(float64(blocks))
+}
+
+// ForgetNode deletes the series of a node that left the pool.
+func (m *Metrics) ForgetNode(blockchain, node string) {
+	labels := prometheus.Labels{"blockchain": blockchain, "node": node}
+	m.nodeLag.DeletePartialMatch(labels)
+	m.rateLimitWait.DeletePartialMatch(labels)
+	m.nodeRequests.DeletePartialMatch(labels)
+	m.nodeDuration.DeletePartialMatch(labels)
+	m.probeDuration.DeletePartialMatch(labels)
+	m.nodeTransitions.DeletePartialMatch(labels)
+}
+
+// RecordNodeRequest records an RPC attempt on a node.
+func (m *Metrics) RecordNodeRequest(blockchain, node, method, status, errorClass string, duration time.Duration) {
+	m.nodeRequests.WithLabelValues(blockchain, node, method, status, errorClass).Inc()
+	m.nodeDuration.WithLabelValues(blockchain, node).Observe(duration.Seconds())
+}
+
+// RecordProbe records a health probe of a node.
+func (m *Metrics) RecordProbe(blockchain, node, status string, duration time.Duration) {
+	m.probeDuration.WithLabelValues(blockchain, node, status).Observe(duration.Seconds())
+}
+
+// RecordNodeTransition records a node health transition. to is "healthy" or
+// "unhealthy".
+func (m *Metrics) RecordNodeTransition(blockchain, node string, healthy bool) {
+	to := "unhealthy"
+	if healthy {
+		to = "healthy"
+	}
+	m.nodeTransitions.WithLabelValues(blockchain, node, to).Inc()
+}
+
+// SetTierHealth updates the health metrics of a tier.
+func (m *Metrics) SetTierHealth(blockchain string, tier, total, healthy int, active bool) {
+	label := strconv.Itoa(tier)
+	m.tierTotal.WithLabelValues(blockchain, label).Set(float64(total))
+	m.tierHealthy.WithLabelValues(blockchain, label).Set(float64(healthy))
+	value := 0.0
+	if active {
+		value = 1
+	}
+	m.tierActive.WithLabelValues(blockchain, label).Set(value)
+}
+
+// RecordRateLimitWait records how long a call waited for a node's rate limit.
+func (m *Metrics) RecordRateLimitWait(blockchain, node string, d time.Duration) {
+	m.rateLimitWait.WithLabelValues(blockchain, node).Observe(d.Seconds())
+}
+
+// RecordCoalesced records a read merged into an identical call in flight.
+func (m *Metrics) RecordCoalesced(blockchain, method string) {
+	m.coalescedTotal.WithLabelValues(blockchain, method).Inc()
+}
+
+// RecordCacheLookup records a read cache lookup. result is "hit" when the
+// cache had the answer and "miss" otherwise.
+func (m *Metrics) RecordCacheLookup(blockchain, method string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cacheLookups.WithLabelValues(blockchain, method, result).Inc()
+}
+
 // SetPoolHealth updates node pool health metrics.
 func (m *Metrics) SetPoolHealth(blockchain string, total, healthy, disabled int) {
 	m.poolTotal.WithLabelValues(blockchain).Set(float64(total))
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
@@ -16,7 +17,13 @@ import (
 type mockMetricsCollector struct {
 	requests []recordedRequest
 	retries  []recordedRetry
+	hedges   []recordedHedge
 	pools    []recordedPool
+
+	// lags is written by probe goroutines, hence its own lock.
+	lagMu     sync.Mutex
+	lags      map[string]int64
+	forgotten []string
 }
 
 type recordedRequest struct {
@@ -28,6 +35,11 @@ type recordedRetry struct {
 	blockchain, method string
 }
 
+type recordedHedge struct {
+	blockchain, method string
+	won                bool
+}
+
 type recordedPool struct {
 	blockchain               string
 	total, healthy, disabled int
@@ -41,11 +53,43 @@ func (m *mockMetricsCollector) RecordRetry(blockchain, method string) {
 	m.retries = append(m.retries, recordedRetry{blockchain, method})
 }
 
+func (m *mockMetricsCollector) RecordHedge(blockchain, method string, won bool) {
+	m.hedges = append(m.hedges, recordedHedge{blockchain, method, won})
+}
+
+func (m *mockMetricsCollector) SetNodeLag(_, node string, blocks int64) {
+	m.lagMu.Lock()
+	defer m.lagMu.Unlock()
+	if m.lags == nil {
+		m.lags = make(map[string]int64)
+	}
+	m.lags[node] = blocks
+}
+
+func (m *mockMetricsCollector) ForgetNode(_, node string) {
+	m.lagMu.Lock()
+	defer m.lagMu.Unlock()
+	delete(m.lags, node)
+	m.forgotten = append(m.forgotten, node)
+}
+
+func (m *mockMetricsCollector) nodeLag(node string) (int64, bool) {
+	m.lagMu.Lock()
+	defer m.lagMu.Unlock()
+	blocks, ok := m.lags[node]
+	return blocks, ok
+}
+
 func (m *mockMetricsCollector) SetPoolHealth(blockchain string, total, healthy, disabled int) {
 	m.pools = append(m.pools, recordedPool{blockchain, total, healthy, disabled})
 }
 
-var _ MetricsCollector = (*mockMetricsCollector)(nil)
+var (
+	_ MetricsCollector = (*mockMetricsCollector)(nil)
+	_ HedgeRecorder    = (*mockMetricsCollector)(nil)
+	_ NodeLagRecorder  = (*mockMetricsCollector)(nil)
+	_ NodeForgetter    = (*mockMetricsCollector)(nil)
+)
 
 // mockTransport is a minimal Transport for testing MetricsTransport.
 type mockTransport struct {
@@ -211,6 +255,22 @@ func (m *mockTransport) GetBrokerageInfo(context.Context, []byte) (*api.NumberMe
 	return nil, m.err
 }
 
+func (m *mockTransport) CreateWitness(context.Context, *core.WitnessCreateContract) (*api.TransactionExtention, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) UpdateWitness(context.Context, *core.WitnessUpdateContract) (*api.TransactionExtention, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) UpdateBrokerage(context.Context, *core.UpdateBrokerageContract) (*api.TransactionExtention, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) GetPaginatedNowWitnessList(context.Context, *api.PaginatedMessage) (*api.WitnessList, error) {
+	return nil, m.err
+}
+
 func (m *mockTransport) GetAssetIssueById(context.Context, []byte) (*core.AssetIssueContract, error) {
 	return nil, m.err
 }
@@ -218,6 +278,90 @@ func (m *mockTransport) GetAssetIssueById(context.Context, []byte) (*core.AssetI
 func (m *mockTransport) GetAssetIssueListByName(context.Context, []byte) (*api.AssetIssueList, error) {
 	return nil, m.err
 }
+
+func (m *mockTransport) GetAssetIssueByAccount(context.Context, []byte) (*api.AssetIssueList, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) GetAssetIssueList(context.Context) (*api.AssetIssueList, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) GetPaginatedAssetIssueList(context.Context, *api.PaginatedMessage) (*api.AssetIssueList, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) TransferAsset(context.Context, *core.TransferAssetContract) (*api.TransactionExtention, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) CreateAssetIssue(context.Context, *core.AssetIssueContract) (*api.TransactionExtention, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) ParticipateAssetIssue(context.Context, *core.ParticipateAssetIssueContract) (*api.TransactionExtention, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) UpdateAsset(context.Context, *core.UpdateAssetContract) (*api.TransactionExtention, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) UnfreezeAsset(context.Context, *core.UnfreezeAssetContract) (*api.TransactionExtention, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) ProposalCreate(context.Context, *core.ProposalCreateContract) (*api.TransactionExtention, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) ProposalApprove(context.Context, *core.ProposalApproveContract) (*api.TransactionExtention, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) ProposalDelete(context.Context, *core.ProposalDeleteContract) (*api.TransactionExtention, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) ListProposals(context.Context) (*api.ProposalList, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) GetPaginatedProposalList(context.Context, *api.PaginatedMessage) (*api.ProposalList, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) GetProposalById(context.Context, int64) (*core.Proposal, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) ExchangeCreate(context.Context, *core.ExchangeCreateContract) (*api.TransactionExtention, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) ExchangeInject(context.Context, *core.ExchangeInjectContract) (*api.TransactionExtention, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) ExchangeWithdraw(context.Context, *core.ExchangeWithdrawContract) (*api.TransactionExtention, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) ExchangeTransaction(context.Context, *core.ExchangeTransactionContract) (*api.TransactionExtention, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) ListExchanges(context.Context) (*api.ExchangeList, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) GetPaginatedExchangeList(context.Context, *api.PaginatedMessage) (*api.ExchangeList, error) {
+	return nil, m.err
+}
+
+func (m *mockTransport) GetExchangeById(context.Context, int64) (*core.Exchange, error) {
+	return nil, m.err
+}
 func (m *mockTransport) ListNodes(context.Context) (*api.NodeList, error) { return nil, m.err }
 func (m *mockTransport) GetNodeInfo(context.Context) (*core.NodeInfo, error) {
 	return nil, m.err
@@ -276,6 +420,175 @@ func TestMetricsRecordRetry(t *testing.T) {
 	}
 }
 
+func TestMetricsRecordHedge(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetrics(reg)
+
+	m.RecordHedge("tron", "GetAccount", true)
+	m.RecordHedge("tron", "GetAccount", false)
+	m.RecordHedge("tron", "GetAccount", false)
+
+	if v := testutil.ToFloat64(m.hedgesTotal.WithLabelValues("tron", "GetAccount", "won")); v != 1 {
+		t.Errorf("hedgesTotal won: got %v, want 1", v)
+	}
+	if v := testutil.ToFloat64(m.hedgesTotal.WithLabelValues("tron", "GetAccount", "lost")); v != 2 {
+		t.Errorf("hedgesTotal lost: got %v, want 2", v)
+	}
+}
+
+func TestMetricsSetNodeLag(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetrics(reg)
+
+	m.SetNodeLag("tron", "grpc.trongrid.io:50051", 300)
+
+	if v := testutil.ToFloat64(m.nodeLag.WithLabelValues("tron", "grpc.trongrid.io:50051")); v != 300 {
+		t.Errorf("nodeLag: got %v, want 300", v)
+	}
+}
+
+func TestMetricsForgetNode(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetrics(reg)
+
+	m.SetNodeLag("tron", "provider-a", 3)
+	m.SetNodeLag("tron", "provider-b", 0)
+	m.RecordRateLimitWait("tron", "provider-a", time.Second)
+	m.RecordRateLimitWait("tron", "provider-b", time.Second)
+	m.RecordNodeRequest("tron", "provider-a", "GetAccount", "success", "", time.Millisecond)
+	m.RecordNodeRequest("tron", "provider-a", "GetAccount", "error", ErrorClassNetwork, time.Millisecond)
+	m.RecordProbe("tron", "provider-a", "success", time.Millisecond)
+	m.RecordNodeTransition("tron", "provider-a", false)
+	m.RecordNodeRequest("tron", "provider-b", "GetAccount", "success", "", time.Millisecond)
+
+	m.ForgetNode("tron", "provider-a")
+
+	if n := testutil.CollectAndCount(m.nodeLag); n != 1 {
+		t.Errorf("nodeLag: got %d series, want 1", n)
+	}
+	if n := testutil.CollectAndCount(m.rateLimitWait); n != 1 {
+		t.Errorf("rateLimitWait: got %d series, want 1", n)
+	}
+	if n := testutil.CollectAndCount(m.nodeRequests); n != 1 {
+		t.Errorf("nodeRequests: got %d series, want 1", n)
+	}
+	if n := testutil.CollectAndCount(m.nodeDuration); n != 1 {
+		t.Errorf("nodeDuration: got %d series, want 1", n)
+	}
+	if n := testutil.CollectAndCount(m.probeDuration); n != 0 {
+		t.Errorf("probeDuration: got %d series, want 0", n)
+	}
+	if n := testutil.CollectAndCount(m.nodeTransitions); n != 0 {
+		t.Errorf("nodeTransitions: got %d series, want 0", n)
+	}
+	if v := testutil.ToFloat64(m.nodeLag.WithLabelValues("tron", "provider-b")); v != 0 {
+		t.Errorf("nodeLag of the node left in the pool: got %v, want 0", v)
+	}
+}
+
+func TestMetricsRecordNodeRequest(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetrics(reg)
+
+	m.RecordNodeRequest("tron", "provider-a", "GetAccount", "success", "", 100*time.Millisecond)
+	m.RecordNodeRequest("tron", "provider-a", "BroadcastTransaction", "error", ErrorClassBroadcastRejected, 50*time.Millisecond)
+
+	if v := testutil.ToFloat64(m.nodeRequests.WithLabelValues("tron", "provider-a", "BroadcastTransaction", "error", "broadcast_rejected")); v != 1 {
+		t.Errorf("nodeRequests: got %v, want 1", v)
+	}
+	if n := testutil.CollectAndCount(m.nodeDuration); n != 1 {
+		t.Errorf("nodeDuration: got %d series, want 1", n)
+	}
+}
+
+func TestMetricsRecordProbe(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetrics(reg)
+
+	m.RecordProbe("tron", "provider-a", "success", 20*time.Millisecond)
+	m.RecordProbe("tron", "provider-a", "error", time.Second)
+
+	if n := testutil.CollectAndCount(m.probeDuration); n != 2 {
+		t.Errorf("probeDuration: got %d series, want 2", n)
+	}
+}
+
+func TestMetricsRecordNodeTransition(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetrics(reg)
+
+	m.RecordNodeTransition("tron", "provider-a", false)
+	m.RecordNodeTransition("tron", "provider-a", true)
+	m.RecordNodeTransition("tron", "provider-a", false)
+
+	if v := testutil.ToFloat64(m.nodeTransitions.WithLabelValues("tron", "provider-a", "unhealthy")); v != 2 {
+		t.Errorf("nodeTransitions unhealthy: got %v, want 2", v)
+	}
+	if v := testutil.ToFloat64(m.nodeTransitions.WithLabelValues("tron", "provider-a", "healthy")); v != 1 {
+		t.Errorf("nodeTransitions healthy: got %v, want 1", v)
+	}
+}
+
+func TestMetricsSetTierHealth(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetrics(reg)
+
+	m.SetTierHealth("tron", 0, 3, 0, false)
+	m.SetTierHealth("tron", 1, 2, 2, true)
+
+	if v := testutil.ToFloat64(m.tierTotal.WithLabelValues("tron", "0")); v != 3 {
+		t.Errorf("tierTotal: got %v, want 3", v)
+	}
+	if v := testutil.ToFloat64(m.tierHealthy.WithLabelValues("tron", "0")); v != 0 {
+		t.Errorf("tierHealthy: got %v, want 0", v)
+	}
+	if v := testutil.ToFloat64(m.tierActive.WithLabelValues("tron", "0")); v != 0 {
+		t.Errorf("tierActive tier 0: got %v, want 0", v)
+	}
+	if v := testutil.ToFloat64(m.tierActive.WithLabelValues("tron", "1")); v != 1 {
+		t.Errorf("tierActive tier 1: got %v, want 1", v)
+	}
+}
+
+func TestMetricsRecordRateLimitWait(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetrics(reg)
+
+	m.RecordRateLimitWait("tron", "https://api.trongrid.io", 250*time.Millisecond)
+
+	if n := testutil.CollectAndCount(m.rateLimitWait); n != 1 {
+		t.Errorf("rateLimitWait: got %d series, want 1", n)
+	}
+}
+
+func TestMetricsRecordCoalesced(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetrics(reg)
+
+	m.RecordCoalesced("tron", "GetChainParameters")
+	m.RecordCoalesced("tron", "GetChainParameters")
+
+	if v := testutil.ToFloat64(m.coalescedTotal.WithLabelValues("tron", "GetChainParameters")); v != 2 {
+		t.Errorf("coalescedTotal: got %v, want 2", v)
+	}
+}
+
+func TestMetricsRecordCacheLookup(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetrics(reg)
+
+	m.RecordCacheLookup("tron", "GetContract", false)
+	m.RecordCacheLookup("tron", "GetContract", true)
+	m.RecordCacheLookup("tron", "GetContract", true)
+
+	if v := testutil.ToFloat64(m.cacheLookups.WithLabelValues("tron", "GetContract", "hit")); v != 2 {
+		t.Errorf("cacheLookups hit: got %v, want 2", v)
+	}
+	if v := testutil.ToFloat64(m.cacheLookups.WithLabelValues("tron", "GetContract", "miss")); v != 1 {
+		t.Errorf("cacheLookups miss: got %v, want 1", v)
+	}
+}
+
 func TestMetricsSetPoolHealth(t *testing.T) {
 	reg := prometheus.NewRegistry()
 	m := NewMetrics(reg)
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Invoker continues a Transport call: the next Middleware, or the wrapped
+// Transport after the last one.
+type Invoker func(ctx context.Context, request proto.Message) (proto.Message, error)
+
+// Middleware intercepts every call made through a Transport. method is the
+// name of the Transport method ("GetAccount"), and request carries its
+// arguments as a single proto message: the message itself for a method that
+// takes one, and otherwise an *api.EmptyMessage for no argument, an
+// *api.NumberMessage for an int64, an *api.BytesMessage for a []byte, or an
+// *api.BlockLimit for the start and end of GetBlockByLimitNext. The answer is
+// the message the method returns.
+//
+// A Middleware calls next to continue the call, with a different context or
+// request if it likes, or answers without it. request may be the caller's
+// own message: clone it with proto.Clone before changing it. A request or
+// answer of another type than the method takes or returns fails the call
+// with ErrMessageType.
+type Middleware func(ctx context.Context, method string, request proto.Message, next Invoker) (proto.Message, error)
+
+// MiddlewareTransport wraps a Transport in an ordered list of Middleware.
+// The first Middleware is the outermost: it sees a call first and its answer
+// last.
+type MiddlewareTransport struct {
+	methodSet
+	transport   Transport
+	middlewares []Middleware
+}
+
+var _ Transport = (*MiddlewareTransport)(nil)
+
+// NewMiddlewareTransport creates a transport that runs every call through
+// middlewares, in order, before it reaches transport.
+func NewMiddlewareTransport(transport Transport, middlewares ...Middleware) *MiddlewareTransport {
+	t := &MiddlewareTransport{
+		transport:   transport,
+		middlewares: middlewares,
+	}
+	t.handle = t.call
+	return t
+}
+
+// call runs a call through the middlewares.
+func (t *MiddlewareTransport) call(ctx context.Context, method string, request proto.Message) (proto.Message, error) {
+	return t.invoker(method, 0)(ctx, request)
+}
+
+// invoker returns the Invoker that continues method at the i-th middleware.
+func (t *MiddlewareTransport) invoker(method string, i int) Invoker {
+	if i == len(t.middlewares) {
+		return func(ctx context.Context, request proto.Message) (proto.Message, error) {
+			return dispatch(ctx, t.transport, method, request)
+		}
+	}
+	return func(ctx context.Context, request proto.Message) (proto.Message, error) {
+		return t.middlewares[i](ctx, method, request, t.invoker(method, i+1))
+	}
+}
+
+// Close closes the underlying transport.
+func (t *MiddlewareTransport) Close() error {
+	return t.transport.Close()
+}
+
+// MetricsMiddleware returns the Middleware of MetricsTransport, which
+// records the duration and outcome of every call through metrics.
+func MetricsMiddleware(metrics MetricsCollector, blockchain string) Middleware {
+	if blockchain == "" {
+		blockchain = "tron"
+	}
+	return func(ctx context.Context, method string, request proto.Message, next Invoker) (proto.Message, error) {
+		start := time.Now()
+		res, err := next(ctx, request)
+		status := "success"
+		if err != nil {
+			status = "error"
+		}
+		metrics.RecordRequest(blockchain, method, status, time.Since(start))
+		return res, err
+	}
+}
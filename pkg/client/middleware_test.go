@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/protobuf/proto"
+)
+
+// handlerTransport is a Transport that hands every call to handle, the way
+// the wrapping transports do.
+type handlerTransport struct {
+	methodSet
+}
+
+func newHandlerTransport(handle handler) *handlerTransport {
+	return &handlerTransport{methodSet{handle: handle}}
+}
+
+func (*handlerTransport) Close() error { return nil }
+
+// recordingMiddleware appends name to calls before passing a call on.
+func recordingMiddleware(name string, calls *[]string) Middleware {
+	return func(ctx context.Context, method string, request proto.Message, next Invoker) (proto.Message, error) {
+		*calls = append(*calls, name+" "+method)
+		return next(ctx, request)
+	}
+}
+
+// TestMiddlewareCoversEveryMethod calls every Transport method through a
+// middleware and checks that the call reaches the same method underneath.
+func TestMiddlewareCoversEveryMethod(t *testing.T) {
+	var seen, reached []string
+	inner := newHandlerTransport(func(_ context.Context, method string, _ proto.Message) (proto.Message, error) {
+		reached = append(reached, method)
+		return nil, nil
+	})
+	tr := NewMiddlewareTransport(inner, func(ctx context.Context, method string, request proto.Message, next Invoker) (proto.Message, error) {
+		seen = append(seen, method)
+		return next(ctx, request)
+	})
+
+	typ := reflect.TypeFor[Transport]()
+	v := reflect.ValueOf(tr)
+	var names []string
+	for i := range typ.NumMethod() {
+		m := typ.Method(i)
+		if m.Name == "Close" {
+			continue
+		}
+		names = append(names, m.Name)
+		args := []reflect.Value{reflect.ValueOf(t.Context())}
+		for j := 1; j < m.Type.NumIn(); j++ {
+			args = append(args, reflect.Zero(m.Type.In(j)))
+		}
+		out := v.MethodByName(m.Name).Call(args)
+		assert.Nil(t, out[1].Interface(), m.Name)
+	}
+	assert.Equal(t, names, seen)
+	assert.Equal(t, names, reached)
+}
+
+func TestMiddlewareRequests(t *testing.T) {
+	var requests []proto.Message
+	inner := newHandlerTransport(func(_ context.Context, _ string, request proto.Message) (proto.Message, error) {
+		requests = append(requests, request)
+		return nil, nil
+	})
+	tr := NewMiddlewareTransport(inner)
+
+	account := &core.Account{Address: []byte{0x41, 1}}
+	_, _ = tr.GetAccount(t.Context(), account)
+	_, _ = tr.GetNowBlock(t.Context())
+	_, _ = tr.GetBlockByNum(t.Context(), 5)
+	_, _ = tr.GetContract(t.Context(), []byte{0x41, 2})
+	_, _ = tr.GetBlockByLimitNext(t.Context(), 3, 7)
+
+	require.Len(t, requests, 5)
+	assert.Same(t, account, requests[0], "a proto argument is passed as it is")
+	assert.True(t, proto.Equal(&api.EmptyMessage{}, requests[1]))
+	assert.True(t, proto.Equal(&api.NumberMessage{Num: 5}, requests[2]))
+	assert.True(t, proto.Equal(&api.BytesMessage{Value: []byte{0x41, 2}}, requests[3]))
+	assert.True(t, proto.Equal(&api.BlockLimit{StartNum: 3, EndNum: 7}, requests[4]))
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	var calls []string
+	tr := NewMiddlewareTransport(newCT("a"),
+		recordingMiddleware("outer", &calls),
+		recordingMiddleware("inner", &calls),
+	)
+
+	_, err := tr.GetNowBlock(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"outer GetNowBlock", "inner GetNowBlock"}, calls)
+}
+
+func TestMiddlewareCanChangeOrAnswerACall(t *testing.T) {
+	ct := newCT("a")
+	injected := errors.New("injected")
+	tr := NewMiddlewareTransport(answeringTransport{ct}, func(ctx context.Context, method string, request proto.Message, next Invoker) (proto.Message, error) {
+		switch method {
+		case "BroadcastTransaction":
+			return nil, injected
+		case "GetBlockByNum":
+			return next(ctx, &api.NumberMessage{Num: request.(*api.NumberMessage).GetNum() + 1})
+		}
+		return next(ctx, request)
+	})
+
+	_, err := tr.BroadcastTransaction(t.Context(), &core.Transaction{})
+	require.ErrorIs(t, err, injected)
+	assert.Zero(t, ct.liveCallCount.Load(), "an answered call is not sent")
+
+	block, err := tr.GetBlockByNum(t.Context(), 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), block.GetBlockHeader().GetRawData().GetNumber())
+}
+
+func TestMiddlewareMessageTypes(t *testing.T) {
+	wrongAnswer := NewMiddlewareTransport(newCT("a"), func(context.Context, string, proto.Message, Invoker) (proto.Message, error) {
+		return &core.Account{}, nil
+	})
+	_, err := wrongAnswer.GetNowBlock(t.Context())
+	require.ErrorIs(t, err, ErrMessageType)
+
+	wrongRequest := NewMiddlewareTransport(newCT("a"), func(ctx context.Context, _ string, _ proto.Message, next Invoker) (proto.Message, error) {
+		return next(ctx, &core.Account{})
+	})
+	_, err = wrongRequest.GetBlockByNum(t.Context(), 1)
+	require.ErrorIs(t, err, ErrMessageType)
+
+	_, err = dispatch(t.Context(), newCT("a"), "NoSuchMethod", nil)
+	require.ErrorIs(t, err, ErrMessageType)
+}
+
+func TestClientMiddlewares(t *testing.T) {
+	var calls []string
+	injected := errors.New("injected")
+	c, err := New(Config{
+		Nodes:  []NodeConfig{{Protocol: ProtocolHTTP, Address: "http://full.invalid"}},
+		Health: HealthConfig{Disabled: true},
+		Cache:  CacheConfig{Enabled: true},
+		Middlewares: []Middleware{
+			recordingMiddleware("audit", &calls),
+			func(context.Context, string, proto.Message, Invoker) (proto.Message, error) {
+				return nil, injected
+			},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	router, ok := c.transport.(*solidifiedRouter)
+	require.True(t, ok)
+	mt, ok := router.full.(*MiddlewareTransport)
+	require.True(t, ok)
+	cache, ok := mt.transport.(*CachingTransport)
+	require.True(t, ok)
+	cache.put("k", "GetContract", &core.SmartContract{}, CacheForever)
+	c.InvalidateCache()
+	assert.Zero(t, cache.lru.Len(), "InvalidateCache reaches a cache under middlewares")
+
+	_, err = c.GetLastBlock(t.Context())
+	require.ErrorIs(t, err, injected)
+	assert.Equal(t, []string{"audit GetNowBlock"}, calls)
+}
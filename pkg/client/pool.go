@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// AddNode adds a node to the Client at runtime, without disturbing the calls
+// in progress - to follow a provider that rotates its endpoints, say. A node
+// with NodeConfig.Solidity set joins the solidity pool, which must exist
+// (ErrNoSolidityNodes otherwise); any other joins the full nodes. See
+// HealthAwareTransport.AddNode.
+//
+// Nodes can only be managed with health checking on: with Health.Disabled,
+// AddNode, RemoveNode and SetNodeTier return ErrInvalidConfig.
+func (c *Client) AddNode(nc NodeConfig) error {
+	full, solidity := c.pools()
+	if nc.Solidity {
+		if solidity == nil {
+			return ErrNoSolidityNodes
+		}
+		full = solidity
+	}
+	pool, err := healthPool(full)
+	if err != nil {
+		return err
+	}
+	return pool.AddNode(nc)
+}
+
+// RemoveNode takes the node with address out of the Client, full node or
+// solidity node, once the calls running on it have finished or ctx has
+// ended. See HealthAwareTransport.RemoveNode.
+func (c *Client) RemoveNode(ctx context.Context, address string) error {
+	return c.onNode(address, func(pool *HealthAwareTransport) error {
+		return pool.RemoveNode(ctx, address)
+	})
+}
+
+// SetNodeTier moves the node with address to tier. See
+// HealthAwareTransport.SetNodeTier.
+func (c *Client) SetNodeTier(address string, tier int) error {
+	return c.onNode(address, func(pool *HealthAwareTransport) error {
+		return pool.SetNodeTier(address, tier)
+	})
+}
+
+// PoolStatus returns a snapshot of the Client's full nodes: the active tier
+// and, for every node, its tier, health, failure counts, last error, last
+// probe and latency. Confirmed().PoolStatus() reports the solidity nodes. It
+// returns ErrInvalidConfig with Health.Disabled, as there is no health to
+// report.
+func (c *Client) PoolStatus() (PoolStatus, error) {
+	full, _ := c.pools()
+	pool, err := healthPool(full)
+	if err != nil {
+		return PoolStatus{}, err
+	}
+	return pool.Status(), nil
+}
+
+// SubscribeHealth calls fn with every HealthEvent of the Client's full nodes -
+// a node turning unhealthy or healthy, a tier shift, a node added, removed or
+// moved - until unsubscribe is called. Confirmed().SubscribeHealth follows the
+// solidity nodes. See HealthAwareTransport.Subscribe for how fn is called.
+func (c *Client) SubscribeHealth(fn func(HealthEvent)) (unsubscribe func(), err error) {
+	full, _ := c.pools()
+	pool, err := healthPool(full)
+	if err != nil {
+		return nil, err
+	}
+	return pool.Subscribe(fn), nil
+}
+
+// HealthHandler returns an http.Handler reporting the Client's full nodes as
+// JSON, answering 503 when none is healthy - a readiness check for the
+// orchestrator. Confirmed().HealthHandler() reports the solidity nodes. See
+// HealthAwareTransport.HealthHandler for what it reports and for opts. It
+// returns ErrInvalidConfig with Health.Disabled.
+//
+//	handler, err := tron.HealthHandler()
+//	mux.Handle("/readyz", handler)
+func (c *Client) HealthHandler(opts ...HealthHandlerOption) (http.Handler, error) {
+	full, _ := c.pools()
+	pool, err := healthPool(full)
+	if err != nil {
+		return nil, err
+	}
+	return pool.HealthHandler(opts...), nil
+}
+
+// onNode runs fn on the full pool, and on the solidity pool when the full
+// pool has no node with address.
+func (c *Client) onNode(address string, fn func(*HealthAwareTransport) error) error {
+	full, solidity := c.pools()
+	pool, err := healthPool(full)
+	if err != nil {
+		return err
+	}
+	err = fn(pool)
+	if solidity == nil || !errors.Is(err, ErrNodeNotFound) {
+		return err
+	}
+	if pool, err = healthPool(solidity); err != nil {
+		return err
+	}
+	return fn(pool)
+}
+
+// pools returns the transport stacks of the full pool and of the solidity
+// pool, nil when there is none.
+func (c *Client) pools() (full, solidity Transport) {
+	if r, ok := c.transport.(*solidifiedRouter); ok {
+		return r.full, r.solidity
+	}
+	return c.transport, nil
+}
+
+// healthPool finds the HealthAwareTransport under the wrappers
+// newPoolTransport puts around it.
+func healthPool(t Transport) (*HealthAwareTransport, error) {
+	for {
+		switch x := t.(type) {
+		case *HealthAwareTransport:
+			return x, nil
+		case *OTelTransport:
+			t = x.transport
+		case *MiddlewareTransport:
+			t = x.transport
+		case *MetricsTransport:
+			t = x.transport
+		case *CachingTransport:
+			t = x.transport
+		case *CoalescingTransport:
+			t = x.transport
+		default:
+			return nil, fmt.Errorf("%w: nodes can only be managed with health checking enabled", ErrInvalidConfig)
+		}
+	}
+}
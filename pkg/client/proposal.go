@@ -0,0 +1,251 @@
+package client
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sxwebdev/gotron/pkg/tronutils"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+)
+
+// MaxProposalPage is the most proposals GetPaginatedProposalList returns in
+// one page.
+const MaxProposalPage = 1000
+
+// ProposalState is where a proposal stands.
+type ProposalState string
+
+const (
+	// ProposalPending is a proposal still open to approvals.
+	ProposalPending ProposalState = "pending"
+	// ProposalApproved is a proposal that expired with enough approvals; its
+	// parameters took effect at the maintenance that closed it.
+	ProposalApproved ProposalState = "approved"
+	// ProposalDisapproved is a proposal that expired without enough approvals.
+	ProposalDisapproved ProposalState = "disapproved"
+	// ProposalCanceled is a proposal its proposer deleted before it expired.
+	ProposalCanceled ProposalState = "canceled"
+)
+
+// proposalStates maps the chain's states onto ProposalState.
+var proposalStates = map[core.Proposal_State]ProposalState{
+	core.Proposal_PENDING:     ProposalPending,
+	core.Proposal_APPROVED:    ProposalApproved,
+	core.Proposal_DISAPPROVED: ProposalDisapproved,
+	core.Proposal_CANCELED:    ProposalCanceled,
+}
+
+// ProposalParameter is one change a proposal makes. Name and Unit come from
+// the registry and are empty for a key it does not know.
+type ProposalParameter struct {
+	Key   int64        `json:"key"`
+	Name  string       `json:"name,omitempty"`
+	Unit  ProposalUnit `json:"unit,omitempty"`
+	Value int64        `json:"value"`
+}
+
+// Proposal is a governance proposal to change network parameters.
+type Proposal struct {
+	ID int64 `json:"id"`
+	// Proposer is the base58 address of the witness that made the proposal.
+	Proposer string `json:"proposer"`
+	// Parameters are the changes, in key order.
+	Parameters     []ProposalParameter `json:"parameters"`
+	CreateTime     time.Time           `json:"create_time"`
+	ExpirationTime time.Time           `json:"expiration_time"`
+	// Approvals are the base58 addresses of the witnesses that approved it.
+	Approvals []string      `json:"approvals"`
+	State     ProposalState `json:"state"`
+}
+
+// proposalFromProto decodes a proposal as the chain returns it. A state this
+// build does not know is refused rather than guessed at.
+func proposalFromProto(p *core.Proposal) (*Proposal, error) {
+	state, ok := proposalStates[p.GetState()]
+	if !ok {
+		return nil, fmt.Errorf("proposal %d: unknown state %d", p.GetProposalId(), p.GetState())
+	}
+
+	out := &Proposal{
+		ID:             p.GetProposalId(),
+		Proposer:       tronutils.EncodeCheck(p.GetProposerAddress()),
+		Parameters:     make([]ProposalParameter, 0, len(p.GetParameters())),
+		CreateTime:     time.UnixMilli(p.GetCreateTime()),
+		ExpirationTime: time.UnixMilli(p.GetExpirationTime()),
+		Approvals:      make([]string, 0, len(p.GetApprovals())),
+		State:          state,
+	}
+
+	for key, value := range p.GetParameters() {
+		param := ProposalParameter{Key: key, Value: value}
+		if known, ok := ProposalParamByKey(key); ok {
+			param.Name, param.Unit = known.Name, known.Unit
+		}
+		out.Parameters = append(out.Parameters, param)
+	}
+	slices.SortFunc(out.Parameters, func(a, b ProposalParameter) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+
+	for _, approval := range p.GetApprovals() {
+		out.Approvals = append(out.Approvals, tronutils.EncodeCheck(approval))
+	}
+
+	return out, nil
+}
+
+// proposalsFromProto decodes a list of proposals.
+func proposalsFromProto(list *api.ProposalList) ([]Proposal, error) {
+	out := make([]Proposal, 0, len(list.GetProposals()))
+	for _, p := range list.GetProposals() {
+		proposal, err := proposalFromProto(p)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *proposal)
+	}
+
+	return out, nil
+}
+
+// CreateProposal builds a proposal to set each parameter key to its value.
+// Only a witness may propose. Every key must be in the registry and every
+// value in range; see ProposalParams.
+func (c *Client) CreateProposal(ctx context.Context, owner string, parameters map[int64]int64) (*api.TransactionExtention, error) {
+	addr, err := decodeOwner(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parameters) == 0 {
+		return nil, fmt.Errorf("%w: a proposal needs at least one parameter", ErrInvalidParams)
+	}
+
+	for key, value := range parameters {
+		if err := validateProposalParam(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := c.transport.ProposalCreate(ctx, &core.ProposalCreateContract{
+		OwnerAddress: addr,
+		Parameters:   parameters,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// ApproveProposal builds a witness's approval of proposal id, or the
+// withdrawal of an earlier one when approve is false.
+func (c *Client) ApproveProposal(ctx context.Context, owner string, id int64, approve bool) (*api.TransactionExtention, error) {
+	addr, err := decodeOwner(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: proposal id must be positive", ErrInvalidParams)
+	}
+
+	tx, err := c.transport.ProposalApprove(ctx, &core.ProposalApproveContract{
+		OwnerAddress:  addr,
+		ProposalId:    id,
+		IsAddApproval: approve,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// DeleteProposal builds the cancellation of a pending proposal by its
+// proposer.
+func (c *Client) DeleteProposal(ctx context.Context, owner string, id int64) (*api.TransactionExtention, error) {
+	addr, err := decodeOwner(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: proposal id must be positive", ErrInvalidParams)
+	}
+
+	tx, err := c.transport.ProposalDelete(ctx, &core.ProposalDeleteContract{
+		OwnerAddress: addr,
+		ProposalId:   id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// ListProposals returns every proposal ever made. There are a few hundred on
+// mainnet; GetPaginatedProposalList reads them a page at a time.
+func (c *Client) ListProposals(ctx context.Context) ([]Proposal, error) {
+	list, err := c.transport.ListProposals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return proposalsFromProto(list)
+}
+
+// GetPaginatedProposalList returns up to limit proposals starting at offset.
+// limit is 1 to MaxProposalPage.
+func (c *Client) GetPaginatedProposalList(ctx context.Context, offset, limit int64) ([]Proposal, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: offset cannot be negative", ErrInvalidParams)
+	}
+	if limit < 1 || limit > MaxProposalPage {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidParams, MaxProposalPage)
+	}
+
+	list, err := c.transport.GetPaginatedProposalList(ctx, GetPaginatedMessage(offset, limit))
+	if err != nil {
+		return nil, err
+	}
+
+	return proposalsFromProto(list)
+}
+
+// GetProposalById returns proposal id, or ErrProposalNotFound when there is
+// none.
+func (c *Client) GetProposalById(ctx context.Context, id int64) (*Proposal, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: proposal id must be positive", ErrInvalidParams)
+	}
+
+	p, err := c.transport.GetProposalById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// A node answers an id it does not know with an empty proposal.
+	if p.GetProposalId() == 0 {
+		return nil, fmt.Errorf("%w: %d", ErrProposalNotFound, id)
+	}
+
+	return proposalFromProto(p)
+}
@@ -0,0 +1,179 @@
+package client
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// ProposalUnit is what the value of a network parameter counts.
+type ProposalUnit string
+
+const (
+	// ProposalUnitSUN is an amount of SUN.
+	ProposalUnitSUN ProposalUnit = "sun"
+	// ProposalUnitMillis is a duration in milliseconds.
+	ProposalUnitMillis ProposalUnit = "ms"
+	// ProposalUnitDays is a duration in days.
+	ProposalUnitDays ProposalUnit = "days"
+	// ProposalUnitBlocks is a duration in blocks of 3 seconds.
+	ProposalUnitBlocks ProposalUnit = "blocks"
+	// ProposalUnitBytes is a size or an amount of bandwidth in bytes.
+	ProposalUnitBytes ProposalUnit = "bytes"
+	// ProposalUnitFlag is a switch: 1 turns a feature on, 0 off where the
+	// parameter allows it.
+	ProposalUnitFlag ProposalUnit = "flag"
+	// ProposalUnitNumber is a plain number: a rate, a ratio or a factor.
+	ProposalUnitNumber ProposalUnit = "number"
+)
+
+// ProposalParam describes one network parameter a proposal can change.
+type ProposalParam struct {
+	// Key is the parameter's number in ProposalCreateContract.
+	Key int64
+	// Name is the parameter's name among GetChainParameters' answer, e.g.
+	// "getEnergyFee".
+	Name string
+	// Unit is what the value counts.
+	Unit ProposalUnit
+	// Min and Max bound the value, both included. A feature that can only be
+	// switched on has both at 1.
+	Min, Max int64
+}
+
+// Validate reports whether value is in the range java-tron's ProposalUtil
+// accepts for p. The node may refuse a value in range for reasons of state -
+// a feature already on, a fork not yet passed - which are not checked here.
+func (p ProposalParam) Validate(value int64) error {
+	if value >= p.Min && value <= p.Max {
+		return nil
+	}
+
+	if p.Min == p.Max {
+		return fmt.Errorf("%w: proposal parameter %d (%s) can only be %d", ErrInvalidParams, p.Key, p.Name, p.Min)
+	}
+
+	return fmt.Errorf("%w: proposal parameter %d (%s) must be between %d and %d %s, got %d",
+		ErrInvalidParams, p.Key, p.Name, p.Min, p.Max, p.Unit, value)
+}
+
+// maxProposalValue is ProposalUtil's LONG_VALUE, the bound of most amounts.
+const maxProposalValue = 100_000_000_000_000_000
+
+// proposalParams is the registry, in key order. Keys java-tron has retired
+// (27, 28, 34, 36-38 and the like) are left out, so a proposal naming one is
+// refused before it reaches a node.
+var proposalParams = []ProposalParam{
+	{0, "getMaintenanceTimeInterval", ProposalUnitMillis, 81_000, 86_400_000},
+	{1, "getAccountUpgradeCost", ProposalUnitSUN, 0, maxProposalValue},
+	{2, "getCreateAccountFee", ProposalUnitSUN, 0, maxProposalValue},
+	{3, "getTransactionFee", ProposalUnitSUN, 0, maxProposalValue},
+	{4, "getAssetIssueFee", ProposalUnitSUN, 0, maxProposalValue},
+	{5, "getWitnessPayPerBlock", ProposalUnitSUN, 0, maxProposalValue},
+	{6, "getWitnessStandbyAllowance", ProposalUnitSUN, 0, maxProposalValue},
+	{7, "getCreateNewAccountFeeInSystemContract", ProposalUnitSUN, 0, maxProposalValue},
+	{8, "getCreateNewAccountBandwidthRate", ProposalUnitNumber, 0, maxProposalValue},
+	{9, "getAllowCreationOfContracts", ProposalUnitFlag, 1, 1},
+	{10, "getRemoveThePowerOfTheGr", ProposalUnitFlag, 1, 1},
+	{11, "getEnergyFee", ProposalUnitSUN, 0, maxProposalValue},
+	{12, "getExchangeCreateFee", ProposalUnitSUN, 0, maxProposalValue},
+	// The node holds this to 100 ms until getAllowHigherLimitForMaxCpuTimeOfOneTx.
+	{13, "getMaxCpuTimeOfOneTx", ProposalUnitMillis, 10, 400},
+	{14, "getAllowUpdateAccountName", ProposalUnitFlag, 1, 1},
+	{15, "getAllowSameTokenName", ProposalUnitFlag, 1, 1},
+	{16, "getAllowDelegateResource", ProposalUnitFlag, 1, 1},
+	{17, "getTotalEnergyLimit", ProposalUnitNumber, 0, maxProposalValue},
+	{18, "getAllowTvmTransferTrc10", ProposalUnitFlag, 1, 1},
+	{19, "getTotalEnergyCurrentLimit", ProposalUnitNumber, 0, maxProposalValue},
+	{20, "getAllowMultiSign", ProposalUnitFlag, 1, 1},
+	{21, "getAllowAdaptiveEnergy", ProposalUnitFlag, 1, 1},
+	{22, "getUpdateAccountPermissionFee", ProposalUnitSUN, 0, 100_000_000_000},
+	{23, "getMultiSignFee", ProposalUnitSUN, 0, 100_000_000_000},
+	{24, "getAllowProtoFilterNum", ProposalUnitFlag, 0, 1},
+	{25, "getAllowAccountStateRoot", ProposalUnitFlag, 0, 1},
+	{26, "getAllowTvmConstantinople", ProposalUnitFlag, 1, 1},
+	{29, "getAdaptiveResourceLimitMultiplier", ProposalUnitNumber, 1, 10_000},
+	{30, "getChangeDelegation", ProposalUnitFlag, 0, 1},
+	{31, "getWitness127PayPerBlock", ProposalUnitSUN, 0, maxProposalValue},
+	{32, "getAllowTvmSolidity059", ProposalUnitFlag, 1, 1},
+	{33, "getAdaptiveResourceLimitTargetRatio", ProposalUnitNumber, 1, 1_000},
+	{35, "getForbidTransferToContract", ProposalUnitFlag, 1, 1},
+	{39, "getAllowShieldedTRC20Transaction", ProposalUnitFlag, 0, 1},
+	{40, "getAllowPBFT", ProposalUnitFlag, 1, 1},
+	{41, "getAllowTvmIstanbul", ProposalUnitFlag, 1, 1},
+	{44, "getAllowMarketTransaction", ProposalUnitFlag, 1, 1},
+	{45, "getMarketSellFee", ProposalUnitSUN, 0, 10_000_000_000},
+	{46, "getMarketCancelFee", ProposalUnitSUN, 0, 10_000_000_000},
+	{47, "getMaxFeeLimit", ProposalUnitSUN, 0, maxProposalValue},
+	{48, "getAllowTransactionFeePool", ProposalUnitFlag, 1, 1},
+	{49, "getAllowBlackHoleOptimization", ProposalUnitFlag, 1, 1},
+	{51, "getAllowNewResourceModel", ProposalUnitFlag, 1, 1},
+	{52, "getAllowTvmFreeze", ProposalUnitFlag, 1, 1},
+	{53, "getAllowAccountAssetOptimization", ProposalUnitFlag, 1, 1},
+	{59, "getAllowTvmVote", ProposalUnitFlag, 1, 1},
+	{60, "getAllowTvmCompatibleEvm", ProposalUnitFlag, 1, 1},
+	{61, "getFreeNetLimit", ProposalUnitBytes, 0, 100_000},
+	{62, "getTotalNetLimit", ProposalUnitBytes, 0, 1_000_000_000_000},
+	{63, "getAllowTvmLondon", ProposalUnitFlag, 1, 1},
+	{65, "getAllowHigherLimitForMaxCpuTimeOfOneTx", ProposalUnitFlag, 1, 1},
+	{66, "getAllowAssetOptimization", ProposalUnitFlag, 1, 1},
+	{67, "getAllowNewReward", ProposalUnitFlag, 1, 1},
+	{68, "getMemoFee", ProposalUnitSUN, 0, 1_000_000_000},
+	{69, "getAllowDelegateOptimization", ProposalUnitFlag, 1, 1},
+	{70, "getUnfreezeDelayDays", ProposalUnitDays, 1, 365},
+	{71, "getAllowOptimizedReturnValueOfChainId", ProposalUnitFlag, 1, 1},
+	{72, "getAllowDynamicEnergy", ProposalUnitFlag, 0, 1},
+	{73, "getDynamicEnergyThreshold", ProposalUnitNumber, 0, maxProposalValue},
+	{74, "getDynamicEnergyIncreaseFactor", ProposalUnitNumber, 0, 10_000},
+	{75, "getDynamicEnergyMaxFactor", ProposalUnitNumber, 0, 100_000},
+	{76, "getAllowTvmShangHai", ProposalUnitFlag, 1, 1},
+	{77, "getAllowCancelAllUnfreezeV2", ProposalUnitFlag, 1, 1},
+	// The node also wants more than the current period.
+	{78, "getMaxDelegateLockPeriod", ProposalUnitBlocks, 1, 10_512_000},
+	{79, "getAllowOldRewardOpt", ProposalUnitFlag, 1, 1},
+	{81, "getAllowEnergyAdjustment", ProposalUnitFlag, 1, 1},
+	{82, "getMaxCreateAccountTxSize", ProposalUnitBytes, 500, 10_000},
+	{83, "getAllowTvmCancun", ProposalUnitFlag, 1, 1},
+	{87, "getAllowStrictMath", ProposalUnitFlag, 1, 1},
+	{88, "getConsensusLogicOptimization", ProposalUnitFlag, 1, 1},
+	{89, "getAllowTvmBlob", ProposalUnitFlag, 1, 1},
+}
+
+// ProposalParams returns every parameter a proposal can change, in key order.
+func ProposalParams() []ProposalParam {
+	return slices.Clone(proposalParams)
+}
+
+// ProposalParamByKey looks up a parameter by its key.
+func ProposalParamByKey(key int64) (ProposalParam, bool) {
+	i, ok := slices.BinarySearchFunc(proposalParams, key, func(p ProposalParam, key int64) int {
+		return cmp.Compare(p.Key, key)
+	})
+	if !ok {
+		return ProposalParam{}, false
+	}
+
+	return proposalParams[i], true
+}
+
+// ProposalParamByName looks up a parameter by its chain parameter name, e.g.
+// "getEnergyFee".
+func ProposalParamByName(name string) (ProposalParam, bool) {
+	i := slices.IndexFunc(proposalParams, func(p ProposalParam) bool { return p.Name == name })
+	if i < 0 {
+		return ProposalParam{}, false
+	}
+
+	return proposalParams[i], true
+}
+
+// validateProposalParam checks that key is a known parameter and value is in
+// its range.
+func validateProposalParam(key, value int64) error {
+	p, ok := ProposalParamByKey(key)
+	if !ok {
+		return fmt.Errorf("%w: unknown proposal parameter %d", ErrInvalidParams, key)
+	}
+
+	return p.Validate(value)
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProposalParamsRegistry(t *testing.T) {
+	t.Parallel()
+
+	params := ProposalParams()
+	names := make(map[string]bool, len(params))
+	for i, p := range params {
+		if i > 0 {
+			require.Greater(t, p.Key, params[i-1].Key, "the registry is searched by key, so it must stay sorted")
+		}
+		require.False(t, names[p.Name], "%s is listed twice", p.Name)
+		names[p.Name] = true
+		require.LessOrEqual(t, p.Min, p.Max, p.Name)
+
+		byKey, ok := ProposalParamByKey(p.Key)
+		require.True(t, ok)
+		require.Equal(t, p, byKey)
+
+		byName, ok := ProposalParamByName(p.Name)
+		require.True(t, ok)
+		require.Equal(t, p, byName)
+	}
+
+	_, ok := ProposalParamByKey(27)
+	require.False(t, ok, "a retired key is not offered")
+}
+
+func TestProposalParamValidate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		key   int64
+		value int64
+		ok    bool
+	}{
+		{"energy fee", 11, 100, true},
+		{"negative fee", 11, -1, false},
+		{"maintenance below a round of blocks", 0, 80_999, false},
+		{"maintenance of six hours", 0, 21_600_000, true},
+		{"switch on", 30, 1, true},
+		{"switch off", 30, 0, true},
+		{"enable-only switched off", 15, 0, false},
+		{"switch set to two", 30, 2, false},
+		{"unfreeze delay of a day", 70, 1, true},
+		{"unfreeze delay of no days", 70, 0, false},
+		{"free net limit too big", 61, 100_001, false},
+		{"account tx size below the least", 82, 499, false},
+		{"unknown key", 1000, 1, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateProposalParam(tc.key, tc.value)
+			if tc.ok {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrInvalidParams)
+		})
+	}
+}
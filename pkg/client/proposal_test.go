@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+)
+
+func TestListProposalsDecodes(t *testing.T) {
+	created := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	c := newTestClient(&fakeTransport{
+		listProposals: func(context.Context) (*api.ProposalList, error) {
+			return &api.ProposalList{Proposals: []*core.Proposal{
+				{
+					ProposalId:      92,
+					ProposerAddress: mustDecode(t, testAddr),
+					Parameters:      map[int64]int64{70: 14, 11: 210, 1000: 5},
+					CreateTime:      created.UnixMilli(),
+					ExpirationTime:  created.Add(72 * time.Hour).UnixMilli(),
+					Approvals:       [][]byte{mustDecode(t, testAddr), mustDecode(t, testAddr2)},
+					State:           core.Proposal_APPROVED,
+				},
+				{ProposalId: 93, ProposerAddress: mustDecode(t, testAddr2), Parameters: map[int64]int64{30: 1}},
+			}}, nil
+		},
+	})
+
+	proposals, err := c.ListProposals(t.Context())
+	require.NoError(t, err)
+	require.Len(t, proposals, 2)
+
+	p := proposals[0]
+	require.Equal(t, int64(92), p.ID)
+	require.Equal(t, testAddr, p.Proposer)
+	require.Equal(t, ProposalApproved, p.State)
+	require.Equal(t, []string{testAddr, testAddr2}, p.Approvals)
+	require.True(t, created.Equal(p.CreateTime))
+	require.True(t, created.Add(72*time.Hour).Equal(p.ExpirationTime))
+	require.Equal(t, []ProposalParameter{
+		{Key: 11, Name: "getEnergyFee", Unit: ProposalUnitSUN, Value: 210},
+		{Key: 70, Name: "getUnfreezeDelayDays", Unit: ProposalUnitDays, Value: 14},
+		{Key: 1000, Value: 5},
+	}, p.Parameters, "in key order, a key the registry lacks kept without a name")
+
+	require.Equal(t, ProposalPending, proposals[1].State, "the zero state is pending")
+	require.Empty(t, proposals[1].Approvals)
+
+	_, err = newTestClient(&fakeTransport{
+		listProposals: func(context.Context) (*api.ProposalList, error) {
+			return &api.ProposalList{Proposals: []*core.Proposal{{ProposalId: 1, State: 9}}}, nil
+		},
+	}).ListProposals(t.Context())
+	require.ErrorContains(t, err, "unknown state", "a state this build does not know is not read as pending")
+}
+
+func TestGetProposalById(t *testing.T) {
+	c := newTestClient(&fakeTransport{
+		getProposalById: func(_ context.Context, id int64) (*core.Proposal, error) {
+			if id != 7 {
+				return &core.Proposal{}, nil
+			}
+			return &core.Proposal{ProposalId: 7, State: core.Proposal_CANCELED}, nil
+		},
+	})
+
+	p, err := c.GetProposalById(t.Context(), 7)
+	require.NoError(t, err)
+	require.Equal(t, ProposalCanceled, p.State)
+
+	_, err = c.GetProposalById(t.Context(), 8)
+	require.ErrorIs(t, err, ErrProposalNotFound)
+
+	_, err = c.GetProposalById(t.Context(), 0)
+	require.ErrorIs(t, err, ErrInvalidParams)
+}
+
+func TestCreateProposalValidates(t *testing.T) {
+	var sent *core.ProposalCreateContract
+	c := newTestClient(&fakeTransport{
+		proposalCreate: func(_ context.Context, ct *core.ProposalCreateContract) (*api.TransactionExtention, error) {
+			sent = ct
+			return okTx(), nil
+		},
+	})
+
+	for name, params := range map[string]map[int64]int64{
+		"no parameters": nil,
+		"unknown key":   {1000: 1},
+		"out of range":  {11: 210, 70: 0},
+	} {
+		_, err := c.CreateProposal(t.Context(), testAddr, params)
+		require.ErrorIs(t, err, ErrInvalidParams, name)
+		require.Nil(t, sent, "%s: an invalid proposal is not sent", name)
+	}
+
+	_, err := c.CreateProposal(t.Context(), "", map[int64]int64{11: 210})
+	require.ErrorIs(t, err, ErrEmptyAddress)
+
+	_, err = c.CreateProposal(t.Context(), testAddr, map[int64]int64{11: 210, 70: 14})
+	require.NoError(t, err)
+	require.Equal(t, mustDecode(t, testAddr), sent.GetOwnerAddress())
+	require.Equal(t, map[int64]int64{11: 210, 70: 14}, sent.GetParameters())
+}
+
+func TestApproveProposalSendsVote(t *testing.T) {
+	var sent *core.ProposalApproveContract
+	c := newTestClient(&fakeTransport{
+		proposalApprove: func(_ context.Context, ct *core.ProposalApproveContract) (*api.TransactionExtention, error) {
+			sent = ct
+			return okTx(), nil
+		},
+	})
+
+	_, err := c.ApproveProposal(t.Context(), testAddr, 92, false)
+	require.NoError(t, err)
+	require.Equal(t, int64(92), sent.GetProposalId())
+	require.False(t, sent.GetIsAddApproval(), "false withdraws an approval")
+
+	_, err = c.ApproveProposal(t.Context(), testAddr, -1, true)
+	require.ErrorIs(t, err, ErrInvalidParams)
+}
+
+func TestGetPaginatedProposalListLimits(t *testing.T) {
+	var got *api.PaginatedMessage
+	c := newTestClient(&fakeTransport{
+		getPaginatedProposalList: func(_ context.Context, m *api.PaginatedMessage) (*api.ProposalList, error) {
+			got = m
+			return &api.ProposalList{}, nil
+		},
+	})
+
+	_, err := c.GetPaginatedProposalList(t.Context(), 20, 10)
+	require.NoError(t, err)
+	require.Equal(t, int64(20), got.GetOffset())
+	require.Equal(t, int64(10), got.GetLimit())
+
+	_, err = c.GetPaginatedProposalList(t.Context(), 0, MaxProposalPage+1)
+	require.ErrorIs(t, err, ErrInvalidParams)
+	_, err = c.GetPaginatedProposalList(t.Context(), -1, 10)
+	require.ErrorIs(t, err, ErrInvalidParams)
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"fmt"
+	"sync"
+)
+
+// TransportFactory creates the Transport of one node from its NodeConfig.
+type TransportFactory func(NodeConfig) (Transport, error)
+
+var (
+	protocolsMu sync.RWMutex
+	protocols   = map[Protocol]TransportFactory{
+		ProtocolGRPC:    func(cfg NodeConfig) (Transport, error) { return NewGRPCTransport(cfg) },
+		ProtocolHTTP:    func(cfg NodeConfig) (Transport, error) { return NewHTTPTransport(cfg) },
+		ProtocolJSONRPC: func(cfg NodeConfig) (Transport, error) { return NewJSONRPCTransport(cfg) },
+	}
+)
+
+// RegisterProtocol makes factory create the transports of the nodes whose
+// NodeConfig.Protocol is protocol, so that Config.Validate and New accept
+// them. Those transports are wrapped like the built-in ones: health checking,
+// tiers, metrics, tracing and the other Config layers all apply. The
+// NodeConfig fields the built-in transports honour - Headers, RateLimit,
+// APIKeys, Solidity and so on - are up to factory.
+//
+// Registering a protocol again replaces its factory, that of a built-in
+// protocol included. RegisterProtocol panics when protocol is empty or
+// factory is nil.
+func RegisterProtocol(protocol Protocol, factory TransportFactory) {
+	if protocol == "" || factory == nil {
+		panic("gotron: RegisterProtocol needs a protocol and a factory")
+	}
+	protocolsMu.Lock()
+	defer protocolsMu.Unlock()
+	protocols[protocol] = factory
+}
+
+// protocolFactory returns the factory registered for protocol.
+func protocolFactory(protocol Protocol) (TransportFactory, bool) {
+	protocolsMu.RLock()
+	defer protocolsMu.RUnlock()
+	factory, ok := protocols[protocol]
+	return factory, ok
+}
+
+// createTransportFromNode creates a Transport from NodeConfig
+func createTransportFromNode(nodeCfg NodeConfig) (Transport, error) {
+	factory, ok := protocolFactory(nodeCfg.GetProtocol())
+	if !ok {
+		return nil, fmt.Errorf("unsupported protocol: %s", nodeCfg.Protocol)
+	}
+	return factory(nodeCfg)
+}
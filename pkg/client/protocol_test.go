@@ -0,0 +1,95 @@
+package client
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// registerFake registers protocol with a factory that creates a
+// controllableTransport per node, returned by address, until the test ends.
+func registerFake(t *testing.T, protocol Protocol) map[string]*controllableTransport {
+	t.Helper()
+	created := make(map[string]*controllableTransport)
+	RegisterProtocol(protocol, func(cfg NodeConfig) (Transport, error) {
+		ct := newCT(cfg.Address)
+		created[cfg.Address] = ct
+		return ct, nil
+	})
+	t.Cleanup(func() {
+		protocolsMu.Lock()
+		delete(protocols, protocol)
+		protocolsMu.Unlock()
+	})
+	return created
+}
+
+func TestRegisterProtocol(t *testing.T) {
+	cfg := Config{Nodes: []NodeConfig{
+		{Protocol: "fake", Address: "primary"},
+		{Protocol: "fake", Address: "fallback", Tier: 1},
+	}}
+	require.ErrorIs(t, cfg.Validate(), ErrInvalidConfig, "an unregistered protocol is invalid")
+
+	created := registerFake(t, "fake")
+	require.NoError(t, cfg.Validate())
+
+	cfg.Health = HealthConfig{HealthyInterval: time.Hour}
+	c, err := New(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	require.Len(t, created, 2)
+
+	ht, ok := c.transport.(*solidifiedRouter).full.(*HealthAwareTransport)
+	require.True(t, ok, "custom transports are health checked")
+	assert.Len(t, ht.tiers, 2)
+
+	created["fallback"].setNextErr(errors.New("fallback used"))
+	_, err = c.GetLastBlock(t.Context())
+	require.NoError(t, err, "the primary tier serves the call")
+
+	assert.Panics(t, func() { RegisterProtocol("", func(NodeConfig) (Transport, error) { return nil, nil }) })
+	assert.Panics(t, func() { RegisterProtocol("other", nil) })
+}
+
+func TestNewWithTransport(t *testing.T) {
+	ct := newCT("custom")
+	rec := &cacheRecorder{}
+	c, err := NewWithTransport(answeringTransport{ct}, Config{
+		Metrics: rec,
+		Cache:   CacheConfig{Enabled: true},
+		Health:  HealthConfig{HealthyInterval: time.Hour},
+	})
+	require.NoError(t, err)
+
+	cache, ok := c.transport.(*solidifiedRouter).full.(*CachingTransport)
+	require.True(t, ok)
+	metrics, ok := cache.transport.(*MetricsTransport)
+	require.True(t, ok)
+	ht, ok := metrics.transport.(*HealthAwareTransport)
+	require.True(t, ok)
+	assert.Equal(t, "custom", ht.nodes[0].address)
+
+	for range 2 {
+		param, err := c.ChainParam(t.Context(), "getEnergyFee")
+		require.NoError(t, err)
+		assert.Equal(t, int64(100), param.GetValue())
+	}
+	assert.Equal(t, 1, rec.hits)
+
+	_, err = c.Confirmed()
+	require.ErrorIs(t, err, ErrNoSolidityNodes)
+
+	require.NoError(t, c.Close())
+	assert.True(t, ct.closed.Load(), "the Client closes the transport")
+
+	_, err = NewWithTransport(nil, Config{})
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	_, err = NewWithTransport(ct, Config{Nodes: []NodeConfig{{Address: "x"}}})
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	_, err = NewWithTransport(ct, Config{Cache: CacheConfig{MaxEntries: -1}})
+	require.ErrorIs(t, err, ErrInvalidConfig)
+}
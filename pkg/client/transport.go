@@ -63,10 +63,39 @@ type Transport interface {
 	ListWitnesses(ctx context.Context) (*api.WitnessList, error)
 	GetRewardInfo(ctx context.Context, address []byte) (*api.NumberMessage, error)
 	GetBrokerageInfo(ctx context.Context, address []byte) (*api.NumberMessage, error)
+	CreateWitness(ctx context.Context, contract *core.WitnessCreateContract) (*api.TransactionExtention, error)
+	UpdateWitness(ctx context.Context, contract *core.WitnessUpdateContract) (*api.TransactionExtention, error)
+	UpdateBrokerage(ctx context.Context, contract *core.UpdateBrokerageContract) (*api.TransactionExtention, error)
+	GetPaginatedNowWitnessList(ctx context.Context, msg *api.PaginatedMessage) (*api.WitnessList, error)
 
 	// Asset operations
 	GetAssetIssueById(ctx context.Context, id []byte) (*core.AssetIssueContract, error)
 	GetAssetIssueListByName(ctx context.Context, name []byte) (*api.AssetIssueList, error)
+	GetAssetIssueByAccount(ctx context.Context, address []byte) (*api.AssetIssueList, error)
+	GetAssetIssueList(ctx context.Context) (*api.AssetIssueList, error)
+	GetPaginatedAssetIssueList(ctx context.Context, msg *api.PaginatedMessage) (*api.AssetIssueList, error)
+	TransferAsset(ctx context.Context, contract *core.TransferAssetContract) (*api.TransactionExtention, error)
+	CreateAssetIssue(ctx context.Context, contract *core.AssetIssueContract) (*api.TransactionExtention, error)
+	ParticipateAssetIssue(ctx context.Context, contract *core.ParticipateAssetIssueContract) (*api.TransactionExtention, error)
+	UpdateAsset(ctx context.Context, contract *core.UpdateAssetContract) (*api.TransactionExtention, error)
+	UnfreezeAsset(ctx context.Context, contract *core.UnfreezeAssetContract) (*api.TransactionExtention, error)
+
+	// Proposal operations
+	ProposalCreate(ctx context.Context, contract *core.ProposalCreateContract) (*api.TransactionExtention, error)
+	ProposalApprove(ctx context.Context, contract *core.ProposalApproveContract) (*api.TransactionExtention, error)
+	ProposalDelete(ctx context.Context, contract *core.ProposalDeleteContract) (*api.TransactionExtention, error)
+	ListProposals(ctx context.Context) (*api.ProposalList, error)
+	GetPaginatedProposalList(ctx context.Context, msg *api.PaginatedMessage) (*api.ProposalList, error)
+	GetProposalById(ctx context.Context, id int64) (*core.Proposal, error)
+
+	// Exchange operations
+	ExchangeCreate(ctx context.Context, contract *core.ExchangeCreateContract) (*api.TransactionExtention, error)
+	ExchangeInject(ctx context.Context, contract *core.ExchangeInjectContract) (*api.TransactionExtention, error)
+	ExchangeWithdraw(ctx context.Context, contract *core.ExchangeWithdrawContract) (*api.TransactionExtention, error)
+	ExchangeTransaction(ctx context.Context, contract *core.ExchangeTransactionContract) (*api.TransactionExtention, error)
+	ListExchanges(ctx context.Context) (*api.ExchangeList, error)
+	GetPaginatedExchangeList(ctx context.Context, msg *api.PaginatedMessage) (*api.ExchangeList, error)
+	GetExchangeById(ctx context.Context, id int64) (*core.Exchange, error)
 
 	// Network operations
 	ListNodes(ctx context.Context) (*api.NodeList, error)
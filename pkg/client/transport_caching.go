@@ -0,0 +1,271 @@
+package client
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/protobuf/proto"
+)
+
+// defaultCacheMaxEntries is the default of CacheConfig.MaxEntries.
+const defaultCacheMaxEntries = 10000
+
+// defaultCacheTTLs is the policy CacheConfig.TTL is merged over.
+var defaultCacheTTLs = map[string]time.Duration{
+	"GetChainParameters": 10 * time.Minute,
+	// The code never changes, but UpdateSetting and UpdateEnergyLimit change
+	// the energy split that estimates price calls with.
+	"GetContract":       10 * time.Minute,
+	"GetAssetIssueById": CacheForever,
+	"GetBlockById":      CacheForever,
+	// Only the token metadata reads, see cacheableRequest.
+	"TriggerConstantContract": CacheForever,
+}
+
+// solidifiedCacheTTLs extends defaultCacheTTLs in the solidity pool, whose
+// blocks and transactions can no longer be reverted. On full nodes a recent
+// block may still be replaced by a fork, so reads by number are not cached
+// there unless CacheConfig.TTL asks for it.
+var solidifiedCacheTTLs = map[string]time.Duration{
+	"GetBlockByNum":                CacheForever,
+	"GetTransactionById":           CacheForever,
+	"GetTransactionInfoById":       CacheForever,
+	"GetTransactionInfoByBlockNum": CacheForever,
+}
+
+// tokenMetadataSelectors are the TRC20 calls whose answer is cached under
+// TriggerConstantContract: name(), symbol() and decimals(), as sent by
+// TRC20GetName, TRC20GetSymbol and TRC20GetDecimals.
+var tokenMetadataSelectors = [][]byte{
+	{0x06, 0xfd, 0xde, 0x03}, // trc20NameSignature
+	{0x95, 0xd8, 0x9b, 0x41}, // trc20SymbolSignature
+	{0x31, 0x3c, 0xe5, 0x67}, // trc20DecimalsSignature
+}
+
+// CachingTransport wraps a Transport and keeps the answers of reads whose
+// result rarely or never changes, for as long as CacheConfig.TTL says. The
+// cache holds at most CacheConfig.MaxEntries answers and evicts the least
+// recently used one first; Invalidate drops answers on demand.
+//
+// Errors and empty answers - which is how a node reports a block, contract or
+// asset it does not know yet - are never cached, and neither are reverted
+// constant calls. Each caller gets its own copy of a cached answer. The cache
+// does not look at CallOptions: what it keeps is the same on every node.
+// Lookups are counted through the MetricsCollector when it implements
+// CacheRecorder.
+type CachingTransport struct {
+	methodSet
+	transport  Transport
+	recorder   CacheRecorder
+	blockchain string
+	ttls       map[string]time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // of *cacheEntry, most recently used first
+}
+
+var _ Transport = (*CachingTransport)(nil)
+
+// cacheEntry is one cached answer.
+type cacheEntry struct {
+	key     string
+	method  string
+	res     proto.Message
+	expires time.Time // zero for an answer kept forever
+}
+
+// NewCachingTransport creates a transport that caches slow-changing reads
+// according to cfg; cfg.Enabled is not consulted. metrics may be nil; lookups
+// are recorded only when it implements CacheRecorder.
+func NewCachingTransport(transport Transport, cfg CacheConfig, metrics MetricsCollector, blockchain string) *CachingTransport {
+	return newCachingTransport(transport, cfg, metrics, blockchain, false)
+}
+
+// newCachingTransport is NewCachingTransport for a pool that is solidified or
+// not.
+func newCachingTransport(transport Transport, cfg CacheConfig, metrics MetricsCollector, blockchain string, solidified bool) *CachingTransport {
+	if blockchain == "" {
+		blockchain = "tron"
+	}
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = defaultCacheMaxEntries
+	}
+	ttls := maps.Clone(defaultCacheTTLs)
+	if solidified {
+		maps.Copy(ttls, solidifiedCacheTTLs)
+	}
+	maps.Copy(ttls, cfg.TTL)
+
+	recorder, _ := metrics.(CacheRecorder)
+	t := &CachingTransport{
+		transport:  transport,
+		recorder:   recorder,
+		blockchain: blockchain,
+		ttls:       ttls,
+		maxEntries: cfg.MaxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+	t.handle = t.call
+	return t
+}
+
+// Close closes the underlying transport.
+func (t *CachingTransport) Close() error {
+	return t.transport.Close()
+}
+
+// Invalidate drops the cached answers of the given Transport methods, or
+// every cached answer when no method is given.
+func (t *CachingTransport) Invalidate(methods ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(methods) == 0 {
+		clear(t.entries)
+		t.lru.Init()
+		return
+	}
+	for e := t.lru.Front(); e != nil; {
+		next := e.Next()
+		if entry := e.Value.(*cacheEntry); slices.Contains(methods, entry.method) {
+			t.remove(e)
+		}
+		e = next
+	}
+}
+
+// call answers a call from the cache, or passes it on and caches the
+// answer.
+func (t *CachingTransport) call(ctx context.Context, method string, request proto.Message) (proto.Message, error) {
+	ttl := t.ttls[method]
+	if ttl == 0 || transportMethods[method] != methodRead || !cacheableRequest(method, request) {
+		return dispatch(ctx, t.transport, method, request)
+	}
+	key, ok := requestKey(method, request)
+	if !ok {
+		return dispatch(ctx, t.transport, method, request)
+	}
+
+	if res, ok := t.get(key); ok {
+		t.record(method, true)
+		return proto.Clone(res), nil
+	}
+	t.record(method, false)
+
+	res, err := dispatch(ctx, t.transport, method, request)
+	if err != nil || !cacheableResponse(res) {
+		return res, err
+	}
+	t.put(key, method, proto.Clone(res), ttl)
+	return res, nil
+}
+
+// get returns the answer cached under key, unless it has expired.
+func (t *CachingTransport) get(key string) (proto.Message, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e, ok := t.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := e.Value.(*cacheEntry)
+	if !entry.expires.IsZero() && !time.Now().Before(entry.expires) {
+		t.remove(e)
+		return nil, false
+	}
+	t.lru.MoveToFront(e)
+	return entry.res, true
+}
+
+// put caches res under key for ttl, evicting the least recently used answers
+// beyond maxEntries.
+func (t *CachingTransport) put(key, method string, res proto.Message, ttl time.Duration) {
+	entry := &cacheEntry{key: key, method: method, res: res}
+	if ttl != CacheForever {
+		entry.expires = time.Now().Add(ttl)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e, ok := t.entries[key]; ok {
+		e.Value = entry
+		t.lru.MoveToFront(e)
+		return
+	}
+	t.entries[key] = t.lru.PushFront(entry)
+	for t.lru.Len() > t.maxEntries {
+		t.remove(t.lru.Back())
+	}
+}
+
+// remove drops e from the cache. Called with mu held.
+func (t *CachingTransport) remove(e *list.Element) {
+	delete(t.entries, e.Value.(*cacheEntry).key)
+	t.lru.Remove(e)
+}
+
+// record reports a lookup to the CacheRecorder, if there is one.
+func (t *CachingTransport) record(method string, hit bool) {
+	if t.recorder != nil {
+		t.recorder.RecordCacheLookup(t.blockchain, method, hit)
+	}
+}
+
+// cacheableRequest reports whether the answer to a request may be cached at
+// all. Of the constant contract calls only the token metadata reads qualify:
+// anything else, a balance included, changes with every block.
+func cacheableRequest(method string, request proto.Message) bool {
+	if method != "TriggerConstantContract" {
+		return true
+	}
+	call, ok := request.(*core.TriggerSmartContract)
+	if !ok || call.GetCallValue() != 0 || call.GetCallTokenValue() != 0 {
+		return false
+	}
+	for _, selector := range tokenMetadataSelectors {
+		if bytes.Equal(call.GetData(), selector) {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheableResponse reports whether res is a real answer: not empty, which
+// is how nodes report an unknown block, contract or asset, and not a failed
+// or reverted constant call.
+func cacheableResponse(res proto.Message) bool {
+	if !res.ProtoReflect().IsValid() || proto.Size(res) == 0 {
+		return false
+	}
+	if ext, ok := res.(*api.TransactionExtention); ok {
+		result := ext.GetResult()
+		return result.GetCode() == api.Return_SUCCESS && len(result.GetMessage()) == 0 && len(ext.GetConstantResult()) > 0
+	}
+	return true
+}
+
+// invalidateCaches calls Invalidate on every CachingTransport of the stack t.
+func invalidateCaches(t Transport, methods []string) {
+	switch t := t.(type) {
+	case *solidifiedRouter:
+		invalidateCaches(t.full, methods)
+		if t.solidity != nil {
+			invalidateCaches(t.solidity, methods)
+		}
+	case *OTelTransport:
+		invalidateCaches(t.transport, methods)
+	case *MiddlewareTransport:
+		invalidateCaches(t.transport, methods)
+	case *CachingTransport:
+		t.Invalidate(methods...)
+	}
+}
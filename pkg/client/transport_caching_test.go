@@ -0,0 +1,275 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/grpc/codes"
+)
+
+// answeringTransport is a controllableTransport whose cacheable reads answer
+// with something, rather than the empty message a node sends for "not found".
+type answeringTransport struct {
+	*controllableTransport
+}
+
+func (a answeringTransport) GetContract(ctx context.Context, address []byte) (*core.SmartContract, error) {
+	return &core.SmartContract{ContractAddress: address, Name: "Token"}, a.live(ctx)
+}
+
+func (a answeringTransport) GetChainParameters(ctx context.Context) (*core.ChainParameters, error) {
+	return &core.ChainParameters{ChainParameter: []*core.ChainParameters_ChainParameter{
+		{Key: "getEnergyFee", Value: 100},
+	}}, a.live(ctx)
+}
+
+func (a answeringTransport) GetBlockByNum(ctx context.Context, num int64) (*api.BlockExtention, error) {
+	return &api.BlockExtention{BlockHeader: &core.BlockHeader{RawData: &core.BlockHeaderRaw{Number: num}}}, a.live(ctx)
+}
+
+func (a answeringTransport) GetAccount(ctx context.Context, account *core.Account) (*core.Account, error) {
+	return &core.Account{Address: account.GetAddress(), Balance: 1}, a.live(ctx)
+}
+
+func (a answeringTransport) TriggerConstantContract(ctx context.Context, contract *core.TriggerSmartContract) (*api.TransactionExtention, error) {
+	return &api.TransactionExtention{
+		Result:         &api.Return{Result: true},
+		ConstantResult: [][]byte{{6}},
+	}, a.live(ctx)
+}
+
+// cacheRecorder counts RecordCacheLookup calls by result.
+type cacheRecorder struct {
+	mockMetricsCollector
+	hits, misses int
+}
+
+func (r *cacheRecorder) RecordCacheLookup(_, _ string, hit bool) {
+	if hit {
+		r.hits++
+	} else {
+		r.misses++
+	}
+}
+
+func newCaching(cfg CacheConfig, solidified bool) (*CachingTransport, *controllableTransport, *cacheRecorder) {
+	ct := newCT("a")
+	rec := &cacheRecorder{}
+	return newCachingTransport(answeringTransport{ct}, cfg, rec, "tron", solidified), ct, rec
+}
+
+// metadataCall is the request TRC20GetDecimals sends.
+func metadataCall(contract byte) *core.TriggerSmartContract {
+	return &core.TriggerSmartContract{
+		OwnerAddress:    make([]byte, 21),
+		ContractAddress: []byte{0x41, contract},
+		Data:            []byte{0x31, 0x3c, 0xe5, 0x67},
+	}
+}
+
+func TestCache_ImmutableReadsAreKeptForever(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		c, ct, rec := newCaching(CacheConfig{}, false)
+
+		for range 3 {
+			_, err := c.TriggerConstantContract(bgctx(), metadataCall(1))
+			require.NoError(t, err)
+			time.Sleep(24 * time.Hour)
+		}
+
+		assert.Equal(t, int64(1), ct.liveCallCount.Load())
+		assert.Equal(t, 2, rec.hits)
+		assert.Equal(t, 1, rec.misses)
+	})
+}
+
+func TestCache_TTLExpires(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		c, ct, _ := newCaching(CacheConfig{}, false)
+
+		_, err := c.GetChainParameters(bgctx())
+		require.NoError(t, err)
+		time.Sleep(10*time.Minute - time.Second)
+		_, err = c.GetChainParameters(bgctx())
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), ct.liveCallCount.Load())
+
+		time.Sleep(time.Second)
+		_, err = c.GetChainParameters(bgctx())
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), ct.liveCallCount.Load())
+	})
+}
+
+// A contract's consume_user_resource_percent and origin_energy_limit change
+// with UpdateSetting and UpdateEnergyLimit, so its answer is not kept for ever.
+func TestCache_ContractExpires(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		c, ct, _ := newCaching(CacheConfig{}, false)
+
+		sc, err := c.GetContract(bgctx(), []byte{1})
+		require.NoError(t, err)
+		assert.Equal(t, "Token", sc.GetName())
+		time.Sleep(10*time.Minute - time.Second)
+		_, err = c.GetContract(bgctx(), []byte{1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), ct.liveCallCount.Load())
+
+		time.Sleep(time.Second)
+		_, err = c.GetContract(bgctx(), []byte{1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), ct.liveCallCount.Load())
+	})
+}
+
+func TestCache_PolicyDecidesWhatIsCached(t *testing.T) {
+	full, ct, _ := newCaching(CacheConfig{}, false)
+	for range 2 {
+		_, _ = full.GetAccount(bgctx(), &core.Account{Address: []byte{1}})
+		_, _ = full.GetBlockByNum(bgctx(), 5)
+		_, _ = full.CreateTransaction(bgctx(), &core.TransferContract{Amount: 1})
+	}
+	assert.Equal(t, int64(6), ct.liveCallCount.Load(), "balances, reversible blocks and writes go through")
+
+	solidity, ct, _ := newCaching(CacheConfig{}, true)
+	for range 2 {
+		b, err := solidity.GetBlockByNum(bgctx(), 5)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), b.GetBlockHeader().GetRawData().GetNumber())
+	}
+	assert.Equal(t, int64(1), ct.liveCallCount.Load(), "solidified blocks are kept")
+
+	overridden, ct, _ := newCaching(CacheConfig{TTL: map[string]time.Duration{
+		"GetContract":   0,
+		"GetBlockByNum": time.Minute,
+	}}, false)
+	for range 2 {
+		_, _ = overridden.GetContract(bgctx(), []byte{1})
+		_, _ = overridden.GetBlockByNum(bgctx(), 5)
+	}
+	assert.Equal(t, int64(3), ct.liveCallCount.Load())
+}
+
+func TestCache_OnlyTokenMetadataCallsAreCached(t *testing.T) {
+	c, ct, _ := newCaching(CacheConfig{}, false)
+
+	balanceOf := metadataCall(1)
+	balanceOf.Data = []byte{0x70, 0xa0, 0x82, 0x31}
+	paid := metadataCall(1)
+	paid.CallValue = 1
+	for range 2 {
+		_, _ = c.TriggerConstantContract(bgctx(), balanceOf)
+		_, _ = c.TriggerConstantContract(bgctx(), paid)
+	}
+	assert.Equal(t, int64(4), ct.liveCallCount.Load())
+}
+
+func TestCache_FailuresAndEmptyAnswersAreNotCached(t *testing.T) {
+	c, ct, _ := newCaching(CacheConfig{}, false)
+
+	// controllableTransport answers GetAssetIssueById with an empty asset,
+	// which is how a node reports one it does not know.
+	for range 2 {
+		_, err := c.GetAssetIssueById(bgctx(), []byte("1000001"))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int64(2), ct.liveCallCount.Load())
+
+	ct.setNextErr(grpcErr(codes.Unavailable))
+	_, err := c.GetContract(bgctx(), []byte{1})
+	require.Error(t, err)
+	ct.setNextErr(nil)
+	_, err = c.GetContract(bgctx(), []byte{1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), ct.liveCallCount.Load())
+
+	assert.False(t, cacheableResponse(&api.TransactionExtention{
+		Result:         &api.Return{Result: true, Message: []byte("REVERT opcode executed")},
+		ConstantResult: [][]byte{{}},
+	}))
+	assert.False(t, cacheableResponse(&api.TransactionExtention{Result: &api.Return{Result: true}}))
+}
+
+func TestCache_CallersGetCopies(t *testing.T) {
+	c, _, _ := newCaching(CacheConfig{}, false)
+
+	first, err := c.GetContract(bgctx(), []byte{1})
+	require.NoError(t, err)
+	first.Name = "changed"
+	second, err := c.GetContract(bgctx(), []byte{1})
+	require.NoError(t, err)
+	second.Name = "changed too"
+	third, err := c.GetContract(bgctx(), []byte{1})
+	require.NoError(t, err)
+	assert.Equal(t, "Token", third.GetName())
+}
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c, ct, _ := newCaching(CacheConfig{MaxEntries: 2}, false)
+
+	_, _ = c.GetContract(bgctx(), []byte{1})
+	_, _ = c.GetContract(bgctx(), []byte{2})
+	_, _ = c.GetContract(bgctx(), []byte{1})
+	_, _ = c.GetContract(bgctx(), []byte{3}) // evicts 2
+	assert.Equal(t, 2, c.lru.Len())
+	assert.Equal(t, int64(3), ct.liveCallCount.Load())
+
+	_, _ = c.GetContract(bgctx(), []byte{1})
+	assert.Equal(t, int64(3), ct.liveCallCount.Load())
+	_, _ = c.GetContract(bgctx(), []byte{2})
+	assert.Equal(t, int64(4), ct.liveCallCount.Load())
+}
+
+func TestCache_Invalidate(t *testing.T) {
+	c, ct, _ := newCaching(CacheConfig{}, false)
+	fill := func() {
+		_, _ = c.GetContract(bgctx(), []byte{1})
+		_, _ = c.GetChainParameters(bgctx())
+	}
+
+	fill()
+	c.Invalidate("GetChainParameters")
+	fill()
+	assert.Equal(t, int64(3), ct.liveCallCount.Load())
+
+	c.Invalidate()
+	assert.Zero(t, c.lru.Len())
+	fill()
+	assert.Equal(t, int64(5), ct.liveCallCount.Load())
+}
+
+func TestClientCache(t *testing.T) {
+	c, err := New(Config{
+		Nodes: []NodeConfig{
+			{Protocol: ProtocolHTTP, Address: "http://full.invalid"},
+			{Protocol: ProtocolHTTP, Address: "http://solidity.invalid", Solidity: true},
+		},
+		Health:   HealthConfig{Disabled: true},
+		Coalesce: true,
+		Cache:    CacheConfig{Enabled: true},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	router, ok := c.transport.(*solidifiedRouter)
+	require.True(t, ok)
+	full, ok := router.full.(*CachingTransport)
+	require.True(t, ok)
+	assert.IsType(t, &CoalescingTransport{}, full.transport)
+	assert.Zero(t, full.ttls["GetBlockByNum"])
+	solidity, ok := router.solidity.(*CachingTransport)
+	require.True(t, ok)
+	assert.Equal(t, CacheForever, solidity.ttls["GetBlockByNum"])
+
+	full.put("k", "GetContract", &core.SmartContract{}, CacheForever)
+	solidity.put("k", "GetContract", &core.SmartContract{}, CacheForever)
+	c.InvalidateCache()
+	assert.Zero(t, full.lru.Len())
+	assert.Zero(t, solidity.lru.Len())
+}
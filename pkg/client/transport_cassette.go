@@ -0,0 +1,392 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Cassette is a recording of Transport calls: for each call, the method, the
+// request and the answer or the error. RecordingTransport fills one from a
+// live node and ReplayTransport serves it back, so that tests get realistic
+// answers without a network.
+//
+// A cassette is saved as indented JSON, with each request and answer in its
+// protojson form. The encoding is stable: saving the same calls twice gives
+// the same bytes, so checked-in cassettes only change where the chain did.
+type Cassette struct {
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// Interaction is one recorded Transport call. Request and Response are the
+// messages a Middleware sees for Method.
+type Interaction struct {
+	Method   string
+	Request  proto.Message
+	Response proto.Message
+	// Err is the message of the error the call failed with.
+	Err string
+	// Code is the gRPC status code the failure carried, codes.OK when it
+	// carried none.
+	Code codes.Code
+	// HTTP is the *HTTPStatusError the failure carried, nil when it carried
+	// none.
+	HTTP *HTTPStatusError
+	// Transport is the *TransportError the failure came in, with its Err
+	// left nil; nil when it came in none.
+	Transport *TransportError
+}
+
+// recordErr fills in the failure err, keeping what its type says next to
+// its message.
+func (i *Interaction) recordErr(err error) {
+	i.Err = err.Error()
+	if st, ok := status.FromError(err); ok {
+		i.Code = st.Code()
+	}
+	if he, ok := errors.AsType[*HTTPStatusError](err); ok {
+		copied := *he
+		i.HTTP = &copied
+	}
+	if te, ok := errors.AsType[*TransportError](err); ok {
+		i.Transport = &TransportError{Host: te.Host, Protocol: te.Protocol, Method: te.Method}
+	}
+}
+
+// replayErr rebuilds the failure the interaction recorded, so that a replay
+// is classified, retried and inspected with errors.AsType as the original
+// was. Its message is the recorded one.
+func (i Interaction) replayErr() error {
+	text := i.Err
+	if i.Transport != nil {
+		text = strings.TrimPrefix(text, fmt.Sprintf("%s %s (%s): ", i.Transport.Protocol, i.Transport.Method, i.Transport.Host))
+	}
+	var err error
+	switch {
+	case i.HTTP != nil:
+		copied := *i.HTTP
+		err = &copied
+	case i.Code != codes.OK:
+		err = status.Error(i.Code, strings.TrimPrefix(text, fmt.Sprintf("rpc error: code = %s desc = ", i.Code)))
+	default:
+		err = errors.New(text)
+	}
+	if i.Transport != nil {
+		wrapped := *i.Transport
+		wrapped.Err = err
+		err = &wrapped
+	}
+	if err.Error() != i.Err {
+		err = &replayedError{msg: i.Err, err: err}
+	}
+	return err
+}
+
+// replayedError is a replayed failure whose recorded message says more than
+// the error rebuilt from it, such as a wrapping the type of which was not
+// kept.
+type replayedError struct {
+	msg string
+	err error
+}
+
+func (e *replayedError) Error() string { return e.msg }
+
+func (e *replayedError) Unwrap() error { return e.err }
+
+// cassetteFile is the saved form of a Cassette.
+type cassetteFile struct {
+	Interactions []cassetteInteraction `json:"interactions"`
+}
+
+type cassetteInteraction struct {
+	Method   string          `json:"method"`
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	// Code, HTTP and Transport keep the type of the error; see Interaction.
+	Code      codes.Code          `json:"code,omitempty"`
+	HTTP      *cassetteHTTPStatus `json:"http_status,omitempty"`
+	Transport *cassetteTransport  `json:"transport,omitempty"`
+}
+
+type cassetteHTTPStatus struct {
+	Code       int           `json:"code"`
+	Body       string        `json:"body,omitempty"`
+	RetryAfter time.Duration `json:"retry_after,omitempty"`
+}
+
+type cassetteTransport struct {
+	Host     string `json:"host"`
+	Protocol string `json:"protocol"`
+	Method   string `json:"method"`
+}
+
+// LoadCassette reads a cassette saved with Save.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Cassette{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("cassette %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Save writes the cassette to path, replacing the file.
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Interactions returns the recorded calls in the order they were made.
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Interaction(nil), c.interactions...)
+}
+
+func (c *Cassette) add(i Interaction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interactions = append(c.interactions, i)
+}
+
+// MarshalJSON encodes the cassette in the form Save writes.
+func (c *Cassette) MarshalJSON() ([]byte, error) {
+	file := cassetteFile{Interactions: []cassetteInteraction{}}
+	for _, i := range c.Interactions() {
+		out := cassetteInteraction{Method: i.Method, Error: i.Err, Code: i.Code}
+		if i.HTTP != nil {
+			out.HTTP = &cassetteHTTPStatus{Code: i.HTTP.Code, Body: i.HTTP.Body, RetryAfter: i.HTTP.RetryAfter}
+		}
+		if i.Transport != nil {
+			out.Transport = &cassetteTransport{Host: i.Transport.Host, Protocol: i.Transport.Protocol, Method: i.Transport.Method}
+		}
+		var err error
+		if out.Request, err = protojson.Marshal(i.Request); err != nil {
+			return nil, fmt.Errorf("%s request: %w", i.Method, err)
+		}
+		if i.Response != nil && i.Response.ProtoReflect().IsValid() {
+			if out.Response, err = protojson.Marshal(i.Response); err != nil {
+				return nil, fmt.Errorf("%s response: %w", i.Method, err)
+			}
+		}
+		file.Interactions = append(file.Interactions, out)
+	}
+	// Re-encoding compacts the raw messages, which drops the whitespace
+	// protojson deliberately varies between runs.
+	return json.Marshal(file)
+}
+
+// UnmarshalJSON decodes a cassette in the form Save writes.
+func (c *Cassette) UnmarshalJSON(data []byte) error {
+	var file cassetteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+	interactions := make([]Interaction, 0, len(file.Interactions))
+	for n, in := range file.Interactions {
+		request, response, ok := transportMessages(in.Method)
+		if !ok {
+			return fmt.Errorf("interaction %d: unknown Transport method %q", n, in.Method)
+		}
+		if err := protojson.Unmarshal(in.Request, request); err != nil {
+			return fmt.Errorf("interaction %d: %s request: %w", n, in.Method, err)
+		}
+		if in.Response == nil {
+			response = nil
+		} else if err := protojson.Unmarshal(in.Response, response); err != nil {
+			return fmt.Errorf("interaction %d: %s response: %w", n, in.Method, err)
+		}
+		i := Interaction{
+			Method:   in.Method,
+			Request:  request,
+			Response: response,
+			Err:      in.Error,
+			Code:     in.Code,
+		}
+		if in.HTTP != nil {
+			i.HTTP = &HTTPStatusError{Code: in.HTTP.Code, Body: in.HTTP.Body, RetryAfter: in.HTTP.RetryAfter}
+		}
+		if in.Transport != nil {
+			i.Transport = &TransportError{Host: in.Transport.Host, Protocol: in.Transport.Protocol, Method: in.Transport.Method}
+		}
+		interactions = append(interactions, i)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interactions = interactions
+	return nil
+}
+
+// transportMessages returns new, empty messages of the request and the
+// answer of method, in the form methodSet hands them to a handler.
+func transportMessages(method string) (request, response proto.Message, ok bool) {
+	if _, ok := transportMethods[method]; !ok {
+		return nil, nil, false
+	}
+	m, ok := reflect.TypeFor[Transport]().MethodByName(method)
+	if !ok {
+		return nil, nil, false
+	}
+	// The first argument is always the context.
+	switch args := m.Type; {
+	case args.NumIn() == 1:
+		request = &api.EmptyMessage{}
+	case args.NumIn() == 3:
+		request = &api.BlockLimit{}
+	case args.In(1) == reflect.TypeFor[int64]():
+		request = &api.NumberMessage{}
+	case args.In(1) == reflect.TypeFor[[]byte]():
+		request = &api.BytesMessage{}
+	default:
+		request = reflect.New(args.In(1).Elem()).Interface().(proto.Message)
+	}
+	response = reflect.New(m.Type.Out(0).Elem()).Interface().(proto.Message)
+	return request, response, true
+}
+
+// RecordingTransport wraps a Transport and records every call it passes on
+// into a Cassette. Calls that end because the caller's context did are not
+// recorded: they say nothing about the node.
+//
+// Record with a plain GRPCTransport or HTTPTransport and hand it to
+// NewWithTransport with health checking disabled, so that background probes
+// do not end up in the cassette.
+type RecordingTransport struct {
+	methodSet
+	transport Transport
+	cassette  *Cassette
+}
+
+var _ Transport = (*RecordingTransport)(nil)
+
+// NewRecordingTransport creates a transport that records the calls it passes
+// on to transport into cassette.
+func NewRecordingTransport(transport Transport, cassette *Cassette) *RecordingTransport {
+	t := &RecordingTransport{
+		transport: transport,
+		cassette:  cassette,
+	}
+	t.handle = t.call
+	return t
+}
+
+// Close closes the underlying transport.
+func (t *RecordingTransport) Close() error {
+	return t.transport.Close()
+}
+
+// call passes a call on and records it.
+func (t *RecordingTransport) call(ctx context.Context, method string, request proto.Message) (proto.Message, error) {
+	res, err := dispatch(ctx, t.transport, method, request)
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return res, err
+	}
+	i := Interaction{Method: method, Request: proto.Clone(request)}
+	if err != nil {
+		i.recordErr(err)
+	} else if res != nil && res.ProtoReflect().IsValid() {
+		i.Response = proto.Clone(res)
+	}
+	t.cassette.add(i)
+	return res, err
+}
+
+// ReplayTransport answers calls from a Cassette, without a network. A call
+// is matched to a recorded one by method and the deterministic encoding of
+// its request; the timestamp GetCanWithdrawUnfreezeAmount stamps each
+// request with is left out, so that a cassette replays at any later time. A
+// call that matches nothing fails with ErrNotRecorded, naming the request,
+// and so does one recorded without an answer. A recorded failure replays
+// with the type it was recorded with: a gRPC status, an *HTTPStatusError
+// and the *TransportError around them.
+//
+// Identical calls recorded several times are answered in the order they
+// were recorded, and the last answer is repeated once they run out.
+type ReplayTransport struct {
+	methodSet
+
+	mu      sync.Mutex
+	answers map[string][]Interaction
+	served  map[string]int
+}
+
+var _ Transport = (*ReplayTransport)(nil)
+
+// NewReplayTransport creates a transport that answers from cassette.
+func NewReplayTransport(cassette *Cassette) *ReplayTransport {
+	t := &ReplayTransport{
+		answers: make(map[string][]Interaction),
+		served:  make(map[string]int),
+	}
+	for _, i := range cassette.Interactions() {
+		if key, ok := replayKey(i.Method, i.Request); ok {
+			t.answers[key] = append(t.answers[key], i)
+		}
+	}
+	t.handle = t.call
+	return t
+}
+
+// Close does nothing: a replay holds no resources.
+func (t *ReplayTransport) Close() error {
+	return nil
+}
+
+// call answers a call with its next recorded answer.
+func (t *ReplayTransport) call(ctx context.Context, method string, request proto.Message) (proto.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	key, ok := replayKey(method, request)
+	t.mu.Lock()
+	recorded := t.answers[key]
+	n := t.served[key]
+	t.served[key]++
+	t.mu.Unlock()
+	if !ok || len(recorded) == 0 {
+		text, _ := protojson.Marshal(request)
+		return nil, fmt.Errorf("%w: %s %s", ErrNotRecorded, method, text)
+	}
+
+	i := recorded[min(n, len(recorded)-1)]
+	if i.Err != "" {
+		return nil, i.replayErr()
+	}
+	if i.Response == nil {
+		text, _ := protojson.Marshal(request)
+		return nil, fmt.Errorf("%w: %s %s was recorded without an answer", ErrNotRecorded, method, text)
+	}
+	return proto.Clone(i.Response), nil
+}
+
+// replayKey identifies a request in a cassette: requestKey, without the
+// current time GetCanWithdrawUnfreezeAmount requests carry.
+func replayKey(method string, request proto.Message) (string, bool) {
+	if r, ok := request.(*api.CanWithdrawUnfreezeAmountRequestMessage); ok {
+		r = proto.Clone(r).(*api.CanWithdrawUnfreezeAmountRequestMessage)
+		r.Timestamp = 0
+		request = r
+	}
+	return requestKey(method, request)
+}
@@ -0,0 +1,250 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// cassetteNode answers the calls the cassette tests make, with a new head
+// block on every GetNowBlock.
+func cassetteNode() *handlerTransport {
+	var head int64
+	return newHandlerTransport(func(_ context.Context, method string, request proto.Message) (proto.Message, error) {
+		switch method {
+		case "GetNowBlock":
+			head++
+			return &api.BlockExtention{BlockHeader: &core.BlockHeader{RawData: &core.BlockHeaderRaw{Number: head}}}, nil
+		case "GetAccount":
+			return &core.Account{Address: request.(*core.Account).GetAddress(), Balance: 42}, nil
+		case "GetBlockByLimitNext":
+			limit := request.(*api.BlockLimit)
+			return &api.BlockListExtention{Block: []*api.BlockExtention{
+				{Blockid: []byte{byte(limit.GetStartNum())}},
+				{Blockid: []byte{byte(limit.GetEndNum())}},
+			}}, nil
+		case "GetCanWithdrawUnfreezeAmount":
+			return &api.CanWithdrawUnfreezeAmountResponseMessage{Amount: 7}, nil
+		}
+		return nil, errors.New("node says no")
+	})
+}
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	cassette := &Cassette{}
+	rec := NewRecordingTransport(cassetteNode(), cassette)
+	account := &core.Account{Address: []byte{0x41, 1}}
+
+	first, err := rec.GetNowBlock(t.Context())
+	require.NoError(t, err)
+	second, err := rec.GetNowBlock(t.Context())
+	require.NoError(t, err)
+	balance, err := rec.GetAccount(t.Context(), account)
+	require.NoError(t, err)
+	blocks, err := rec.GetBlockByLimitNext(t.Context(), 3, 5)
+	require.NoError(t, err)
+	_, err = rec.GetContract(t.Context(), []byte{0x41, 2})
+	require.EqualError(t, err, "node says no")
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	require.NoError(t, cassette.Save(path))
+	loaded, err := LoadCassette(path)
+	require.NoError(t, err)
+	require.Len(t, loaded.Interactions(), 5)
+	replay := NewReplayTransport(loaded)
+
+	got, err := replay.GetNowBlock(t.Context())
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(first, got), "the first recorded head")
+	got, err = replay.GetNowBlock(t.Context())
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(second, got), "then the second")
+	got, err = replay.GetNowBlock(t.Context())
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(second, got), "the last answer repeats")
+
+	gotAccount, err := replay.GetAccount(t.Context(), account)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(balance, gotAccount))
+	gotBlocks, err := replay.GetBlockByLimitNext(t.Context(), 3, 5)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(blocks, gotBlocks))
+	_, err = replay.GetContract(t.Context(), []byte{0x41, 2})
+	require.EqualError(t, err, "node says no", "a recorded failure replays")
+}
+
+func TestCassetteReplayUnmatched(t *testing.T) {
+	cassette := &Cassette{}
+	rec := NewRecordingTransport(cassetteNode(), cassette)
+	_, err := rec.GetAccount(t.Context(), &core.Account{Address: []byte{0x41, 1}})
+	require.NoError(t, err)
+	replay := NewReplayTransport(cassette)
+
+	_, err = replay.GetAccount(t.Context(), &core.Account{Address: []byte{0x41, 2}})
+	require.ErrorIs(t, err, ErrNotRecorded)
+	assert.Contains(t, err.Error(), "GetAccount")
+	assert.Contains(t, err.Error(), "QQI=", "the request is named")
+
+	_, err = replay.GetNowBlock(t.Context())
+	require.ErrorIs(t, err, ErrNotRecorded)
+}
+
+func TestCassetteReplayKeepsErrorTypes(t *testing.T) {
+	grpcErr := &TransportError{Host: "node:50051", Protocol: "grpc", Method: "/protocol.Wallet/GetNowBlock", Err: status.Error(codes.Unavailable, "node is down")}
+	httpErr := &TransportError{Host: "https://node", Protocol: "http", Method: "/wallet/getaccount", Err: &HTTPStatusError{Code: 429, Body: "slow down", RetryAfter: 2 * time.Second}}
+	wrapped := fmt.Errorf("decode: %w", status.Error(codes.InvalidArgument, "bad address"))
+	inner := newHandlerTransport(func(_ context.Context, method string, _ proto.Message) (proto.Message, error) {
+		switch method {
+		case "GetNowBlock":
+			return nil, grpcErr
+		case "GetAccount":
+			return nil, httpErr
+		}
+		return nil, wrapped
+	})
+	cassette := &Cassette{}
+	rec := NewRecordingTransport(inner, cassette)
+	_, err := rec.GetNowBlock(t.Context())
+	require.Error(t, err)
+	_, err = rec.GetAccount(t.Context(), &core.Account{Address: []byte{0x41, 1}})
+	require.Error(t, err)
+	_, err = rec.GetContract(t.Context(), []byte{0x41, 2})
+	require.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	require.NoError(t, cassette.Save(path))
+	loaded, err := LoadCassette(path)
+	require.NoError(t, err)
+	replay := NewReplayTransport(loaded)
+
+	_, err = replay.GetNowBlock(t.Context())
+	require.EqualError(t, err, grpcErr.Error())
+	te, ok := errors.AsType[*TransportError](err)
+	require.True(t, ok)
+	assert.Equal(t, "node:50051", te.Host)
+	assert.Equal(t, codes.Unavailable, status.Code(te.Err))
+	assert.True(t, isNetworkError(err), "a replayed outage counts against the node")
+
+	_, err = replay.GetAccount(t.Context(), &core.Account{Address: []byte{0x41, 1}})
+	require.EqualError(t, err, httpErr.Error())
+	he, ok := errors.AsType[*HTTPStatusError](err)
+	require.True(t, ok)
+	assert.Equal(t, HTTPStatusError{Code: 429, Body: "slow down", RetryAfter: 2 * time.Second}, *he)
+	assert.True(t, isRateLimited(err))
+
+	_, err = replay.GetContract(t.Context(), []byte{0x41, 2})
+	require.EqualError(t, err, wrapped.Error(), "the recorded message is kept")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestCassetteReplayWithoutAnswer(t *testing.T) {
+	cassette := &Cassette{}
+	cassette.add(Interaction{Method: "GetNowBlock", Request: &api.EmptyMessage{}})
+	replay := NewReplayTransport(cassette)
+
+	_, err := replay.GetNowBlock(t.Context())
+	require.ErrorIs(t, err, ErrNotRecorded)
+	assert.Contains(t, err.Error(), "without an answer")
+}
+
+func TestCassetteReplayIgnoresTimestamp(t *testing.T) {
+	cassette := &Cassette{}
+	rec := NewRecordingTransport(cassetteNode(), cassette)
+	_, err := rec.GetCanWithdrawUnfreezeAmount(t.Context(), &api.CanWithdrawUnfreezeAmountRequestMessage{OwnerAddress: []byte{0x41, 1}, Timestamp: 1000})
+	require.NoError(t, err)
+	replay := NewReplayTransport(cassette)
+
+	res, err := replay.GetCanWithdrawUnfreezeAmount(t.Context(), &api.CanWithdrawUnfreezeAmountRequestMessage{OwnerAddress: []byte{0x41, 1}, Timestamp: 9000})
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), res.GetAmount())
+}
+
+func TestCassetteSkipsCancelledCalls(t *testing.T) {
+	cassette := &Cassette{}
+	inner := newHandlerTransport(func(ctx context.Context, _ string, _ proto.Message) (proto.Message, error) {
+		return nil, ctx.Err()
+	})
+	rec := NewRecordingTransport(inner, cassette)
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err := rec.GetNowBlock(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, cassette.Interactions())
+}
+
+func TestCassetteEncodingIsStable(t *testing.T) {
+	cassette := &Cassette{}
+	rec := NewRecordingTransport(cassetteNode(), cassette)
+	for range 3 {
+		_, err := rec.GetNowBlock(t.Context())
+		require.NoError(t, err)
+		_, err = rec.GetAccount(t.Context(), &core.Account{Address: []byte{0x41, 1}})
+		require.NoError(t, err)
+	}
+
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json")
+	require.NoError(t, cassette.Save(a))
+	loaded, err := LoadCassette(a)
+	require.NoError(t, err)
+	require.NoError(t, loaded.Save(b))
+
+	first, err := os.ReadFile(a)
+	require.NoError(t, err)
+	second, err := os.ReadFile(b)
+	require.NoError(t, err)
+	assert.Equal(t, string(first), string(second))
+	assert.Contains(t, string(first), "\n      \"method\": \"GetAccount\",\n")
+}
+
+func TestLoadCassetteUnknownMethod(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"interactions":[{"method":"Mine","request":{}}]}`), 0o644))
+	_, err := LoadCassette(path)
+	require.ErrorContains(t, err, `unknown Transport method "Mine"`)
+}
+
+// TestTransportMessagesCoverEveryMethod checks that transportMessages gives,
+// for every Transport method, the request methodSet passes and an answer it
+// accepts.
+func TestTransportMessagesCoverEveryMethod(t *testing.T) {
+	var mismatched []string
+	tr := newHandlerTransport(func(_ context.Context, method string, request proto.Message) (proto.Message, error) {
+		want, response, ok := transportMessages(method)
+		require.True(t, ok, method)
+		if reflect.TypeOf(request) != reflect.TypeOf(want) {
+			mismatched = append(mismatched, method)
+		}
+		return response, nil
+	})
+
+	typ := reflect.TypeFor[Transport]()
+	v := reflect.ValueOf(tr)
+	for i := range typ.NumMethod() {
+		m := typ.Method(i)
+		if m.Name == "Close" {
+			continue
+		}
+		args := []reflect.Value{reflect.ValueOf(t.Context())}
+		for j := 1; j < m.Type.NumIn(); j++ {
+			args = append(args, reflect.Zero(m.Type.In(j)))
+		}
+		out := v.MethodByName(m.Name).Call(args)
+		assert.Nil(t, out[1].Interface(), m.Name)
+	}
+	assert.Empty(t, mismatched)
+}
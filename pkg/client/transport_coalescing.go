@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// CoalescingTransport wraps a Transport and merges identical read requests
+// that are in flight at the same time into a single upstream call, whose
+// answer every caller receives. Two requests are identical when they call the
+// same method with the same arguments - proto requests compared by their
+// deterministic encoding - and carry the same CallOptions. Methods that build
+// or broadcast a transaction always go through on their own.
+//
+// Every caller gets its own copy of the answer, the one that started the
+// call included, so callers may modify what they receive. A caller whose
+// context ends stops waiting without affecting the others; the upstream call
+// is cancelled only once every caller has gone. Merged calls are counted
+// through the MetricsCollector when it implements CoalesceRecorder.
+type CoalescingTransport struct {
+	methodSet
+	transport  Transport
+	recorder   CoalesceRecorder
+	blockchain string
+
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+var _ Transport = (*CoalescingTransport)(nil)
+
+// flight is one upstream call shared by every caller of the same request.
+type flight struct {
+	done   chan struct{}
+	res    proto.Message
+	err    error
+	cancel context.CancelFunc
+
+	// waiters is the number of callers still waiting, guarded by
+	// CoalescingTransport.mu.
+	waiters int
+}
+
+// NewCoalescingTransport creates a transport that merges identical concurrent
+// reads. metrics may be nil; merged calls are recorded only when it implements
+// CoalesceRecorder.
+func NewCoalescingTransport(transport Transport, metrics MetricsCollector, blockchain string) *CoalescingTransport {
+	if blockchain == "" {
+		blockchain = "tron"
+	}
+	recorder, _ := metrics.(CoalesceRecorder)
+	t := &CoalescingTransport{
+		transport:  transport,
+		recorder:   recorder,
+		blockchain: blockchain,
+		flights:    make(map[string]*flight),
+	}
+	t.handle = t.call
+	return t
+}
+
+// Close closes the underlying transport.
+func (t *CoalescingTransport) Close() error {
+	return t.transport.Close()
+}
+
+// call passes a call on, or joins the flight of an identical call already
+// in progress.
+func (t *CoalescingTransport) call(ctx context.Context, method string, request proto.Message) (proto.Message, error) {
+	if transportMethods[method] != methodRead {
+		return dispatch(ctx, t.transport, method, request)
+	}
+	key, ok := flightKey(ctx, method, request)
+	if !ok {
+		return dispatch(ctx, t.transport, method, request)
+	}
+
+	t.mu.Lock()
+	f, shared := t.flights[key]
+	if shared {
+		f.waiters++
+	} else {
+		// The shared call keeps the values of ctx, CallOptions included,
+		// but outlives the cancellation of any single caller.
+		fctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight{done: make(chan struct{}), cancel: cancel, waiters: 1}
+		t.flights[key] = f
+		go func() {
+			defer cancel()
+			res, err := dispatch(fctx, t.transport, method, request)
+			t.mu.Lock()
+			t.forget(key, f)
+			t.mu.Unlock()
+			f.res, f.err = res, err
+			close(f.done)
+		}()
+	}
+	t.mu.Unlock()
+
+	if shared && t.recorder != nil {
+		t.recorder.RecordCoalesced(t.blockchain, method)
+	}
+
+	select {
+	case <-f.done:
+		if f.err != nil {
+			return nil, f.err
+		}
+		// Nobody holds f.res itself, so the copies are made from a message
+		// no caller can be changing.
+		res := f.res
+		if res.ProtoReflect().IsValid() {
+			res = proto.Clone(res)
+		}
+		return res, nil
+	case <-ctx.Done():
+		t.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// Nobody wants the answer any more; a later identical call
+			// must start afresh rather than join a cancelled flight.
+			t.forget(key, f)
+			f.cancel()
+		}
+		t.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// forget removes f from the flights, so that later calls start a new one,
+// unless a newer flight has already taken its key. Called with mu held.
+func (t *CoalescingTransport) forget(key string, f *flight) {
+	if t.flights[key] == f {
+		delete(t.flights, key)
+	}
+}
+
+// flightKey identifies a request by CallOptions, method and arguments. It
+// reports false for a request it cannot encode, and the call then goes
+// through on its own.
+func flightKey(ctx context.Context, method string, request proto.Message) (string, bool) {
+	key, ok := requestKey(method, request)
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf("%+v ", callOptionsFrom(ctx)) + key, true
+}
+
+// requestKey identifies a request by method and the deterministic encoding
+// of its arguments. It reports false when they cannot be encoded.
+func requestKey(method string, request proto.Message) (string, bool) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(request)
+	if err != nil {
+		return "", false
+	}
+	return method + " " + string(data), true
+}
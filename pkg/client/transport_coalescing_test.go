@@ -0,0 +1,251 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/grpc/codes"
+)
+
+// coalesceRecorder counts RecordCoalesced calls, which arrive from many
+// goroutines at once.
+type coalesceRecorder struct {
+	mockMetricsCollector
+	merged atomic.Int64
+}
+
+func (r *coalesceRecorder) RecordCoalesced(_, _ string) {
+	r.merged.Add(1)
+}
+
+func newCoalescing(ct *controllableTransport) (*CoalescingTransport, *coalesceRecorder) {
+	rec := &coalesceRecorder{}
+	return NewCoalescingTransport(ct, rec, "tron"), rec
+}
+
+// concurrently runs n copies of call at once and waits for them all.
+func concurrently[T any](n int, call func() (T, error)) ([]T, []error) {
+	results := make([]T, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Go(func() { results[i], errs[i] = call() })
+	}
+	wg.Wait()
+	return results, errs
+}
+
+func TestCoalesce_MergesIdenticalReads(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ct := newCT("a")
+		ct.setDelay(time.Second)
+		ct.setHead(100, time.Now())
+		c, rec := newCoalescing(ct)
+
+		blocks, errs := concurrently(10, func() (*api.BlockExtention, error) {
+			return c.GetNowBlock(bgctx())
+		})
+
+		assert.Equal(t, int64(1), ct.liveCallCount.Load())
+		assert.Equal(t, int64(9), rec.merged.Load())
+		for i, b := range blocks {
+			require.NoError(t, errs[i])
+			assert.Equal(t, int64(100), b.GetBlockHeader().GetRawData().GetNumber())
+		}
+		distinct := map[*api.BlockExtention]bool{}
+		for _, b := range blocks {
+			distinct[b] = true
+		}
+		assert.Len(t, distinct, 10, "every caller gets its own copy")
+	})
+}
+
+// The caller that started the call changes its answer while the others are
+// still being served; run with -race.
+func TestCoalesce_StarterMutatesWhileJoinersAreServed(t *testing.T) {
+	const joiners = 8
+	upstream := &api.BlockExtention{BlockHeader: &core.BlockHeader{RawData: &core.BlockHeaderRaw{Number: 100}}}
+	started, release := make(chan struct{}), make(chan struct{})
+	c := NewCoalescingTransport(&fakeTransport{
+		getNowBlock: func(context.Context) (*api.BlockExtention, error) {
+			close(started)
+			<-release
+			return upstream, nil
+		},
+	}, nil, "tron")
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		b, err := c.GetNowBlock(bgctx())
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.NotSame(t, upstream, b)
+		for i := range 1000 {
+			b.BlockHeader.RawData.Number = int64(i)
+		}
+	})
+	<-started
+
+	blocks := make([]*api.BlockExtention, joiners)
+	for i := range joiners {
+		wg.Go(func() {
+			var err error
+			blocks[i], err = c.GetNowBlock(bgctx())
+			assert.NoError(t, err)
+		})
+	}
+	require.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for _, f := range c.flights {
+			return f.waiters == joiners+1
+		}
+		return false
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for _, b := range blocks {
+		assert.NotSame(t, upstream, b)
+		assert.Equal(t, int64(100), b.GetBlockHeader().GetRawData().GetNumber())
+	}
+}
+
+func TestCoalesce_DistinctRequestsAreNotMerged(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ct := newCT("a")
+		ct.setDelay(time.Second)
+		c, rec := newCoalescing(ct)
+
+		var wg sync.WaitGroup
+		wg.Go(func() { _, _ = c.GetBlockByNum(bgctx(), 1) })
+		wg.Go(func() { _, _ = c.GetBlockByNum(bgctx(), 2) })
+		wg.Go(func() { _, _ = c.GetAccount(bgctx(), &core.Account{Address: []byte{1}}) })
+		wg.Go(func() { _, _ = c.GetAccount(bgctx(), &core.Account{Address: []byte{2}}) })
+		wg.Go(func() { _, _ = c.GetContract(bgctx(), []byte{1}) })
+		wg.Go(func() { _, _ = c.GetContract(WithCallOptions(bgctx(), CallTier(1)), []byte{1}) })
+		wg.Wait()
+
+		assert.Equal(t, int64(6), ct.liveCallCount.Load())
+		assert.Zero(t, rec.merged.Load())
+	})
+}
+
+func TestCoalesce_WritesAndBroadcastsAreNotMerged(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ct := newCT("a")
+		ct.setDelay(time.Second)
+		c, _ := newCoalescing(ct)
+
+		concurrently(3, func() (*api.TransactionExtention, error) {
+			return c.CreateTransaction(bgctx(), &core.TransferContract{Amount: 1})
+		})
+		concurrently(3, func() (*api.Return, error) {
+			return c.BroadcastTransaction(bgctx(), &core.Transaction{})
+		})
+
+		assert.Equal(t, int64(6), ct.liveCallCount.Load())
+	})
+}
+
+func TestCoalesce_SequentialCallsAreNotMerged(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ct := newCT("a")
+		c, _ := newCoalescing(ct)
+
+		for range 3 {
+			_, err := c.GetChainParameters(bgctx())
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int64(3), ct.liveCallCount.Load())
+	})
+}
+
+func TestCoalesce_ErrorIsShared(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ct := newCT("a")
+		ct.setDelay(time.Second)
+		ct.setNextErr(grpcErr(codes.Unavailable))
+		c, _ := newCoalescing(ct)
+
+		_, errs := concurrently(4, func() (*core.ChainParameters, error) {
+			return c.GetChainParameters(bgctx())
+		})
+
+		assert.Equal(t, int64(1), ct.liveCallCount.Load())
+		for _, err := range errs {
+			require.Error(t, err)
+		}
+	})
+}
+
+func TestCoalesce_CallerLeavingDoesNotCancelOthers(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ct := newCT("a")
+		ct.setDelay(time.Second)
+		c, _ := newCoalescing(ct)
+
+		var wg sync.WaitGroup
+		var leaderErr, followerErr error
+		wg.Go(func() {
+			ctx, cancel := context.WithTimeout(bgctx(), 100*time.Millisecond)
+			defer cancel()
+			_, leaderErr = c.GetNowBlock(ctx)
+		})
+		synctest.Wait()
+		wg.Go(func() { _, followerErr = c.GetNowBlock(bgctx()) })
+		wg.Wait()
+
+		require.ErrorIs(t, leaderErr, context.DeadlineExceeded)
+		require.NoError(t, followerErr)
+		assert.Equal(t, int64(1), ct.liveCallCount.Load())
+	})
+}
+
+func TestCoalesce_LastCallerLeavingCancelsUpstream(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ct := newCT("a")
+		ct.setDelay(time.Hour)
+		c, _ := newCoalescing(ct)
+
+		ctx, cancel := context.WithTimeout(bgctx(), time.Second)
+		defer cancel()
+		_, err := c.GetNowBlock(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		synctest.Wait()
+		c.mu.Lock()
+		assert.Empty(t, c.flights, "the abandoned flight is gone")
+		c.mu.Unlock()
+
+		ct.setDelay(0)
+		_, err = c.GetNowBlock(bgctx())
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), ct.liveCallCount.Load(), "a later call starts afresh")
+	})
+}
+
+func TestClientCoalesce(t *testing.T) {
+	c, err := New(Config{
+		Nodes:    []NodeConfig{{Protocol: ProtocolHTTP, Address: "http://full.invalid"}},
+		Health:   HealthConfig{Disabled: true},
+		Coalesce: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	router, ok := c.transport.(*solidifiedRouter)
+	require.True(t, ok)
+	coalescing, ok := router.full.(*CoalescingTransport)
+	require.True(t, ok)
+	assert.IsType(t, &RoundRobinTransport{}, coalescing.transport)
+}
@@ -0,0 +1,866 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/protobuf/proto"
+)
+
+// handler performs the Transport call named method. request carries its
+// arguments: the proto message of a method that takes one, and otherwise
+// an *api.EmptyMessage for no argument, an *api.NumberMessage for an int64,
+// an *api.BytesMessage for a []byte, or an *api.BlockLimit for the start
+// and end of GetBlockByLimitNext. The answer is the message the method
+// returns.
+type handler func(ctx context.Context, method string, request proto.Message) (proto.Message, error)
+
+// methodSet implements every Transport method but Close by handing the call
+// to handle. A wrapping transport embeds it and sets handle to the one
+// function that does its work, which passes the call on with dispatch.
+type methodSet struct {
+	handle handler
+}
+
+// answer converts the answer of a handler back to the type T method returns.
+func answer[T proto.Message](method string, res proto.Message, err error) (T, error) {
+	out, ok := res.(T)
+	if !ok && res != nil && err == nil {
+		err = fmt.Errorf("%w: %s answered with %T", ErrMessageType, method, res)
+	}
+	return out, err
+}
+
+// argument converts the request of a handler to the type T method takes. A
+// nil request stands for the zero value.
+func argument[T proto.Message](method string, request proto.Message) (T, error) {
+	r, ok := request.(T)
+	if !ok && request != nil {
+		return r, fmt.Errorf("%w: %s called with %T", ErrMessageType, method, request)
+	}
+	return r, nil
+}
+
+// Account operations
+
+func (m methodSet) GetAccount(ctx context.Context, account *core.Account) (*core.Account, error) {
+	res, err := m.handle(ctx, "GetAccount", account)
+	return answer[*core.Account]("GetAccount", res, err)
+}
+
+func (m methodSet) GetAccountResource(ctx context.Context, account *core.Account) (*api.AccountResourceMessage, error) {
+	res, err := m.handle(ctx, "GetAccountResource", account)
+	return answer[*api.AccountResourceMessage]("GetAccountResource", res, err)
+}
+
+func (m methodSet) CreateAccount(ctx context.Context, contract *core.AccountCreateContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "CreateAccount", contract)
+	return answer[*api.TransactionExtention]("CreateAccount", res, err)
+}
+
+func (m methodSet) AccountPermissionUpdate(ctx context.Context, contract *core.AccountPermissionUpdateContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "AccountPermissionUpdate", contract)
+	return answer[*api.TransactionExtention]("AccountPermissionUpdate", res, err)
+}
+
+// Block operations
+
+func (m methodSet) GetNowBlock(ctx context.Context) (*api.BlockExtention, error) {
+	res, err := m.handle(ctx, "GetNowBlock", &api.EmptyMessage{})
+	return answer[*api.BlockExtention]("GetNowBlock", res, err)
+}
+
+func (m methodSet) GetBlockByNum(ctx context.Context, num int64) (*api.BlockExtention, error) {
+	res, err := m.handle(ctx, "GetBlockByNum", &api.NumberMessage{Num: num})
+	return answer[*api.BlockExtention]("GetBlockByNum", res, err)
+}
+
+func (m methodSet) GetBlockById(ctx context.Context, id []byte) (*core.Block, error) {
+	res, err := m.handle(ctx, "GetBlockById", &api.BytesMessage{Value: id})
+	return answer[*core.Block]("GetBlockById", res, err)
+}
+
+func (m methodSet) GetBlockByLimitNext(ctx context.Context, start, end int64) (*api.BlockListExtention, error) {
+	res, err := m.handle(ctx, "GetBlockByLimitNext", &api.BlockLimit{StartNum: start, EndNum: end})
+	return answer[*api.BlockListExtention]("GetBlockByLimitNext", res, err)
+}
+
+func (m methodSet) GetBlockByLatestNum(ctx context.Context, num int64) (*api.BlockListExtention, error) {
+	res, err := m.handle(ctx, "GetBlockByLatestNum", &api.NumberMessage{Num: num})
+	return answer[*api.BlockListExtention]("GetBlockByLatestNum", res, err)
+}
+
+func (m methodSet) GetTransactionInfoByBlockNum(ctx context.Context, num int64) (*api.TransactionInfoList, error) {
+	res, err := m.handle(ctx, "GetTransactionInfoByBlockNum", &api.NumberMessage{Num: num})
+	return answer[*api.TransactionInfoList]("GetTransactionInfoByBlockNum", res, err)
+}
+
+// Transaction operations
+
+func (m methodSet) GetTransactionById(ctx context.Context, id []byte) (*core.Transaction, error) {
+	res, err := m.handle(ctx, "GetTransactionById", &api.BytesMessage{Value: id})
+	return answer[*core.Transaction]("GetTransactionById", res, err)
+}
+
+func (m methodSet) GetTransactionInfoById(ctx context.Context, id []byte) (*core.TransactionInfo, error) {
+	res, err := m.handle(ctx, "GetTransactionInfoById", &api.BytesMessage{Value: id})
+	return answer[*core.TransactionInfo]("GetTransactionInfoById", res, err)
+}
+
+func (m methodSet) BroadcastTransaction(ctx context.Context, tx *core.Transaction) (*api.Return, error) {
+	res, err := m.handle(ctx, "BroadcastTransaction", tx)
+	return answer[*api.Return]("BroadcastTransaction", res, err)
+}
+
+func (m methodSet) CreateTransaction(ctx context.Context, contract *core.TransferContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "CreateTransaction", contract)
+	return answer[*api.TransactionExtention]("CreateTransaction", res, err)
+}
+
+// Contract operations
+
+func (m methodSet) TriggerContract(ctx context.Context, contract *core.TriggerSmartContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "TriggerContract", contract)
+	return answer[*api.TransactionExtention]("TriggerContract", res, err)
+}
+
+func (m methodSet) TriggerConstantContract(ctx context.Context, contract *core.TriggerSmartContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "TriggerConstantContract", contract)
+	return answer[*api.TransactionExtention]("TriggerConstantContract", res, err)
+}
+
+func (m methodSet) EstimateEnergy(ctx context.Context, contract *core.TriggerSmartContract) (*api.EstimateEnergyMessage, error) {
+	res, err := m.handle(ctx, "EstimateEnergy", contract)
+	return answer[*api.EstimateEnergyMessage]("EstimateEnergy", res, err)
+}
+
+func (m methodSet) DeployContract(ctx context.Context, contract *core.CreateSmartContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "DeployContract", contract)
+	return answer[*api.TransactionExtention]("DeployContract", res, err)
+}
+
+func (m methodSet) GetContract(ctx context.Context, address []byte) (*core.SmartContract, error) {
+	res, err := m.handle(ctx, "GetContract", &api.BytesMessage{Value: address})
+	return answer[*core.SmartContract]("GetContract", res, err)
+}
+
+func (m methodSet) UpdateSetting(ctx context.Context, contract *core.UpdateSettingContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "UpdateSetting", contract)
+	return answer[*api.TransactionExtention]("UpdateSetting", res, err)
+}
+
+func (m methodSet) UpdateEnergyLimit(ctx context.Context, contract *core.UpdateEnergyLimitContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "UpdateEnergyLimit", contract)
+	return answer[*api.TransactionExtention]("UpdateEnergyLimit", res, err)
+}
+
+// Resource operations
+
+func (m methodSet) GetAccountResourceMessage(ctx context.Context, account *core.Account) (*api.AccountResourceMessage, error) {
+	res, err := m.handle(ctx, "GetAccountResourceMessage", account)
+	return answer[*api.AccountResourceMessage]("GetAccountResourceMessage", res, err)
+}
+
+func (m methodSet) GetDelegatedResource(ctx context.Context, msg *api.DelegatedResourceMessage) (*api.DelegatedResourceList, error) {
+	res, err := m.handle(ctx, "GetDelegatedResource", msg)
+	return answer[*api.DelegatedResourceList]("GetDelegatedResource", res, err)
+}
+
+func (m methodSet) GetDelegatedResourceV2(ctx context.Context, msg *api.DelegatedResourceMessage) (*api.DelegatedResourceList, error) {
+	res, err := m.handle(ctx, "GetDelegatedResourceV2", msg)
+	return answer[*api.DelegatedResourceList]("GetDelegatedResourceV2", res, err)
+}
+
+func (m methodSet) GetDelegatedResourceAccountIndex(ctx context.Context, address []byte) (*core.DelegatedResourceAccountIndex, error) {
+	res, err := m.handle(ctx, "GetDelegatedResourceAccountIndex", &api.BytesMessage{Value: address})
+	return answer[*core.DelegatedResourceAccountIndex]("GetDelegatedResourceAccountIndex", res, err)
+}
+
+func (m methodSet) GetDelegatedResourceAccountIndexV2(ctx context.Context, address []byte) (*core.DelegatedResourceAccountIndex, error) {
+	res, err := m.handle(ctx, "GetDelegatedResourceAccountIndexV2", &api.BytesMessage{Value: address})
+	return answer[*core.DelegatedResourceAccountIndex]("GetDelegatedResourceAccountIndexV2", res, err)
+}
+
+func (m methodSet) GetCanDelegatedMaxSize(ctx context.Context, msg *api.CanDelegatedMaxSizeRequestMessage) (*api.CanDelegatedMaxSizeResponseMessage, error) {
+	res, err := m.handle(ctx, "GetCanDelegatedMaxSize", msg)
+	return answer[*api.CanDelegatedMaxSizeResponseMessage]("GetCanDelegatedMaxSize", res, err)
+}
+
+func (m methodSet) DelegateResource(ctx context.Context, contract *core.DelegateResourceContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "DelegateResource", contract)
+	return answer[*api.TransactionExtention]("DelegateResource", res, err)
+}
+
+func (m methodSet) UnDelegateResource(ctx context.Context, contract *core.UnDelegateResourceContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "UnDelegateResource", contract)
+	return answer[*api.TransactionExtention]("UnDelegateResource", res, err)
+}
+
+// Staking operations (Stake 2.0)
+
+func (m methodSet) FreezeBalanceV2(ctx context.Context, contract *core.FreezeBalanceV2Contract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "FreezeBalanceV2", contract)
+	return answer[*api.TransactionExtention]("FreezeBalanceV2", res, err)
+}
+
+func (m methodSet) UnfreezeBalanceV2(ctx context.Context, contract *core.UnfreezeBalanceV2Contract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "UnfreezeBalanceV2", contract)
+	return answer[*api.TransactionExtention]("UnfreezeBalanceV2", res, err)
+}
+
+func (m methodSet) WithdrawExpireUnfreeze(ctx context.Context, contract *core.WithdrawExpireUnfreezeContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "WithdrawExpireUnfreeze", contract)
+	return answer[*api.TransactionExtention]("WithdrawExpireUnfreeze", res, err)
+}
+
+func (m methodSet) CancelAllUnfreezeV2(ctx context.Context, contract *core.CancelAllUnfreezeV2Contract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "CancelAllUnfreezeV2", contract)
+	return answer[*api.TransactionExtention]("CancelAllUnfreezeV2", res, err)
+}
+
+func (m methodSet) GetAvailableUnfreezeCount(ctx context.Context, msg *api.GetAvailableUnfreezeCountRequestMessage) (*api.GetAvailableUnfreezeCountResponseMessage, error) {
+	res, err := m.handle(ctx, "GetAvailableUnfreezeCount", msg)
+	return answer[*api.GetAvailableUnfreezeCountResponseMessage]("GetAvailableUnfreezeCount", res, err)
+}
+
+func (m methodSet) GetCanWithdrawUnfreezeAmount(ctx context.Context, msg *api.CanWithdrawUnfreezeAmountRequestMessage) (*api.CanWithdrawUnfreezeAmountResponseMessage, error) {
+	res, err := m.handle(ctx, "GetCanWithdrawUnfreezeAmount", msg)
+	return answer[*api.CanWithdrawUnfreezeAmountResponseMessage]("GetCanWithdrawUnfreezeAmount", res, err)
+}
+
+// Witness operations
+
+func (m methodSet) VoteWitnessAccount(ctx context.Context, contract *core.VoteWitnessContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "VoteWitnessAccount", contract)
+	return answer[*api.TransactionExtention]("VoteWitnessAccount", res, err)
+}
+
+func (m methodSet) WithdrawBalance(ctx context.Context, contract *core.WithdrawBalanceContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "WithdrawBalance", contract)
+	return answer[*api.TransactionExtention]("WithdrawBalance", res, err)
+}
+
+func (m methodSet) ListWitnesses(ctx context.Context) (*api.WitnessList, error) {
+	res, err := m.handle(ctx, "ListWitnesses", &api.EmptyMessage{})
+	return answer[*api.WitnessList]("ListWitnesses", res, err)
+}
+
+func (m methodSet) GetRewardInfo(ctx context.Context, address []byte) (*api.NumberMessage, error) {
+	res, err := m.handle(ctx, "GetRewardInfo", &api.BytesMessage{Value: address})
+	return answer[*api.NumberMessage]("GetRewardInfo", res, err)
+}
+
+func (m methodSet) GetBrokerageInfo(ctx context.Context, address []byte) (*api.NumberMessage, error) {
+	res, err := m.handle(ctx, "GetBrokerageInfo", &api.BytesMessage{Value: address})
+	return answer[*api.NumberMessage]("GetBrokerageInfo", res, err)
+}
+
+func (m methodSet) CreateWitness(ctx context.Context, contract *core.WitnessCreateContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "CreateWitness", contract)
+	return answer[*api.TransactionExtention]("CreateWitness", res, err)
+}
+
+func (m methodSet) UpdateWitness(ctx context.Context, contract *core.WitnessUpdateContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "UpdateWitness", contract)
+	return answer[*api.TransactionExtention]("UpdateWitness", res, err)
+}
+
+func (m methodSet) UpdateBrokerage(ctx context.Context, contract *core.UpdateBrokerageContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "UpdateBrokerage", contract)
+	return answer[*api.TransactionExtention]("UpdateBrokerage", res, err)
+}
+
+func (m methodSet) GetPaginatedNowWitnessList(ctx context.Context, msg *api.PaginatedMessage) (*api.WitnessList, error) {
+	res, err := m.handle(ctx, "GetPaginatedNowWitnessList", msg)
+	return answer[*api.WitnessList]("GetPaginatedNowWitnessList", res, err)
+}
+
+// Asset operations
+
+func (m methodSet) GetAssetIssueById(ctx context.Context, id []byte) (*core.AssetIssueContract, error) {
+	res, err := m.handle(ctx, "GetAssetIssueById", &api.BytesMessage{Value: id})
+	return answer[*core.AssetIssueContract]("GetAssetIssueById", res, err)
+}
+
+func (m methodSet) GetAssetIssueListByName(ctx context.Context, name []byte) (*api.AssetIssueList, error) {
+	res, err := m.handle(ctx, "GetAssetIssueListByName", &api.BytesMessage{Value: name})
+	return answer[*api.AssetIssueList]("GetAssetIssueListByName", res, err)
+}
+
+func (m methodSet) GetAssetIssueByAccount(ctx context.Context, address []byte) (*api.AssetIssueList, error) {
+	res, err := m.handle(ctx, "GetAssetIssueByAccount", &api.BytesMessage{Value: address})
+	return answer[*api.AssetIssueList]("GetAssetIssueByAccount", res, err)
+}
+
+func (m methodSet) GetAssetIssueList(ctx context.Context) (*api.AssetIssueList, error) {
+	res, err := m.handle(ctx, "GetAssetIssueList", &api.EmptyMessage{})
+	return answer[*api.AssetIssueList]("GetAssetIssueList", res, err)
+}
+
+func (m methodSet) GetPaginatedAssetIssueList(ctx context.Context, msg *api.PaginatedMessage) (*api.AssetIssueList, error) {
+	res, err := m.handle(ctx, "GetPaginatedAssetIssueList", msg)
+	return answer[*api.AssetIssueList]("GetPaginatedAssetIssueList", res, err)
+}
+
+func (m methodSet) TransferAsset(ctx context.Context, contract *core.TransferAssetContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "TransferAsset", contract)
+	return answer[*api.TransactionExtention]("TransferAsset", res, err)
+}
+
+func (m methodSet) CreateAssetIssue(ctx context.Context, contract *core.AssetIssueContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "CreateAssetIssue", contract)
+	return answer[*api.TransactionExtention]("CreateAssetIssue", res, err)
+}
+
+func (m methodSet) ParticipateAssetIssue(ctx context.Context, contract *core.ParticipateAssetIssueContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "ParticipateAssetIssue", contract)
+	return answer[*api.TransactionExtention]("ParticipateAssetIssue", res, err)
+}
+
+func (m methodSet) UpdateAsset(ctx context.Context, contract *core.UpdateAssetContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "UpdateAsset", contract)
+	return answer[*api.TransactionExtention]("UpdateAsset", res, err)
+}
+
+func (m methodSet) UnfreezeAsset(ctx context.Context, contract *core.UnfreezeAssetContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "UnfreezeAsset", contract)
+	return answer[*api.TransactionExtention]("UnfreezeAsset", res, err)
+}
+
+// Proposal operations
+
+func (m methodSet) ProposalCreate(ctx context.Context, contract *core.ProposalCreateContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "ProposalCreate", contract)
+	return answer[*api.TransactionExtention]("ProposalCreate", res, err)
+}
+
+func (m methodSet) ProposalApprove(ctx context.Context, contract *core.ProposalApproveContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "ProposalApprove", contract)
+	return answer[*api.TransactionExtention]("ProposalApprove", res, err)
+}
+
+func (m methodSet) ProposalDelete(ctx context.Context, contract *core.ProposalDeleteContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "ProposalDelete", contract)
+	return answer[*api.TransactionExtention]("ProposalDelete", res, err)
+}
+
+func (m methodSet) ListProposals(ctx context.Context) (*api.ProposalList, error) {
+	res, err := m.handle(ctx, "ListProposals", &api.EmptyMessage{})
+	return answer[*api.ProposalList]("ListProposals", res, err)
+}
+
+func (m methodSet) GetPaginatedProposalList(ctx context.Context, msg *api.PaginatedMessage) (*api.ProposalList, error) {
+	res, err := m.handle(ctx, "GetPaginatedProposalList", msg)
+	return answer[*api.ProposalList]("GetPaginatedProposalList", res, err)
+}
+
+func (m methodSet) GetProposalById(ctx context.Context, id int64) (*core.Proposal, error) {
+	res, err := m.handle(ctx, "GetProposalById", &api.NumberMessage{Num: id})
+	return answer[*core.Proposal]("GetProposalById", res, err)
+}
+
+// Exchange operations
+
+func (m methodSet) ExchangeCreate(ctx context.Context, contract *core.ExchangeCreateContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "ExchangeCreate", contract)
+	return answer[*api.TransactionExtention]("ExchangeCreate", res, err)
+}
+
+func (m methodSet) ExchangeInject(ctx context.Context, contract *core.ExchangeInjectContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "ExchangeInject", contract)
+	return answer[*api.TransactionExtention]("ExchangeInject", res, err)
+}
+
+func (m methodSet) ExchangeWithdraw(ctx context.Context, contract *core.ExchangeWithdrawContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "ExchangeWithdraw", contract)
+	return answer[*api.TransactionExtention]("ExchangeWithdraw", res, err)
+}
+
+func (m methodSet) ExchangeTransaction(ctx context.Context, contract *core.ExchangeTransactionContract) (*api.TransactionExtention, error) {
+	res, err := m.handle(ctx, "ExchangeTransaction", contract)
+	return answer[*api.TransactionExtention]("ExchangeTransaction", res, err)
+}
+
+func (m methodSet) ListExchanges(ctx context.Context) (*api.ExchangeList, error) {
+	res, err := m.handle(ctx, "ListExchanges", &api.EmptyMessage{})
+	return answer[*api.ExchangeList]("ListExchanges", res, err)
+}
+
+func (m methodSet) GetPaginatedExchangeList(ctx context.Context, msg *api.PaginatedMessage) (*api.ExchangeList, error) {
+	res, err := m.handle(ctx, "GetPaginatedExchangeList", msg)
+	return answer[*api.ExchangeList]("GetPaginatedExchangeList", res, err)
+}
+
+func (m methodSet) GetExchangeById(ctx context.Context, id int64) (*core.Exchange, error) {
+	res, err := m.handle(ctx, "GetExchangeById", &api.NumberMessage{Num: id})
+	return answer[*core.Exchange]("GetExchangeById", res, err)
+}
+
+// Network operations
+
+func (m methodSet) ListNodes(ctx context.Context) (*api.NodeList, error) {
+	res, err := m.handle(ctx, "ListNodes", &api.EmptyMessage{})
+	return answer[*api.NodeList]("ListNodes", res, err)
+}
+
+func (m methodSet) GetNodeInfo(ctx context.Context) (*core.NodeInfo, error) {
+	res, err := m.handle(ctx, "GetNodeInfo", &api.EmptyMessage{})
+	return answer[*core.NodeInfo]("GetNodeInfo", res, err)
+}
+
+func (m methodSet) GetChainParameters(ctx context.Context) (*core.ChainParameters, error) {
+	res, err := m.handle(ctx, "GetChainParameters", &api.EmptyMessage{})
+	return answer[*core.ChainParameters]("GetChainParameters", res, err)
+}
+
+func (m methodSet) GetNextMaintenanceTime(ctx context.Context) (*api.NumberMessage, error) {
+	res, err := m.handle(ctx, "GetNextMaintenanceTime", &api.EmptyMessage{})
+	return answer[*api.NumberMessage]("GetNextMaintenanceTime", res, err)
+}
+
+func (m methodSet) TotalTransaction(ctx context.Context) (*api.NumberMessage, error) {
+	res, err := m.handle(ctx, "TotalTransaction", &api.EmptyMessage{})
+	return answer[*api.NumberMessage]("TotalTransaction", res, err)
+}
+
+// dispatch makes the call of method on t with the arguments request
+// carries; it is the inverse of methodSet.
+func dispatch(ctx context.Context, t Transport, method string, request proto.Message) (proto.Message, error) {
+	switch method {
+	case "GetAccount":
+		r, err := argument[*core.Account](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetAccount(ctx, r)
+	case "GetAccountResource":
+		r, err := argument[*core.Account](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetAccountResource(ctx, r)
+	case "CreateAccount":
+		r, err := argument[*core.AccountCreateContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.CreateAccount(ctx, r)
+	case "AccountPermissionUpdate":
+		r, err := argument[*core.AccountPermissionUpdateContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.AccountPermissionUpdate(ctx, r)
+	case "GetNowBlock":
+		_, err := argument[*api.EmptyMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetNowBlock(ctx)
+	case "GetBlockByNum":
+		r, err := argument[*api.NumberMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetBlockByNum(ctx, r.GetNum())
+	case "GetBlockById":
+		r, err := argument[*api.BytesMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetBlockById(ctx, r.GetValue())
+	case "GetBlockByLimitNext":
+		r, err := argument[*api.BlockLimit](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetBlockByLimitNext(ctx, r.GetStartNum(), r.GetEndNum())
+	case "GetBlockByLatestNum":
+		r, err := argument[*api.NumberMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetBlockByLatestNum(ctx, r.GetNum())
+	case "GetTransactionInfoByBlockNum":
+		r, err := argument[*api.NumberMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetTransactionInfoByBlockNum(ctx, r.GetNum())
+	case "GetTransactionById":
+		r, err := argument[*api.BytesMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetTransactionById(ctx, r.GetValue())
+	case "GetTransactionInfoById":
+		r, err := argument[*api.BytesMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetTransactionInfoById(ctx, r.GetValue())
+	case "BroadcastTransaction":
+		r, err := argument[*core.Transaction](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.BroadcastTransaction(ctx, r)
+	case "CreateTransaction":
+		r, err := argument[*core.TransferContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.CreateTransaction(ctx, r)
+	case "TriggerContract":
+		r, err := argument[*core.TriggerSmartContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.TriggerContract(ctx, r)
+	case "TriggerConstantContract":
+		r, err := argument[*core.TriggerSmartContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.TriggerConstantContract(ctx, r)
+	case "EstimateEnergy":
+		r, err := argument[*core.TriggerSmartContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.EstimateEnergy(ctx, r)
+	case "DeployContract":
+		r, err := argument[*core.CreateSmartContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.DeployContract(ctx, r)
+	case "GetContract":
+		r, err := argument[*api.BytesMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetContract(ctx, r.GetValue())
+	case "UpdateSetting":
+		r, err := argument[*core.UpdateSettingContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.UpdateSetting(ctx, r)
+	case "UpdateEnergyLimit":
+		r, err := argument[*core.UpdateEnergyLimitContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.UpdateEnergyLimit(ctx, r)
+	case "GetAccountResourceMessage":
+		r, err := argument[*core.Account](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetAccountResourceMessage(ctx, r)
+	case "GetDelegatedResource":
+		r, err := argument[*api.DelegatedResourceMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetDelegatedResource(ctx, r)
+	case "GetDelegatedResourceV2":
+		r, err := argument[*api.DelegatedResourceMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetDelegatedResourceV2(ctx, r)
+	case "GetDelegatedResourceAccountIndex":
+		r, err := argument[*api.BytesMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetDelegatedResourceAccountIndex(ctx, r.GetValue())
+	case "GetDelegatedResourceAccountIndexV2":
+		r, err := argument[*api.BytesMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetDelegatedResourceAccountIndexV2(ctx, r.GetValue())
+	case "GetCanDelegatedMaxSize":
+		r, err := argument[*api.CanDelegatedMaxSizeRequestMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetCanDelegatedMaxSize(ctx, r)
+	case "DelegateResource":
+		r, err := argument[*core.DelegateResourceContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.DelegateResource(ctx, r)
+	case "UnDelegateResource":
+		r, err := argument[*core.UnDelegateResourceContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.UnDelegateResource(ctx, r)
+	case "FreezeBalanceV2":
+		r, err := argument[*core.FreezeBalanceV2Contract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.FreezeBalanceV2(ctx, r)
+	case "UnfreezeBalanceV2":
+		r, err := argument[*core.UnfreezeBalanceV2Contract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.UnfreezeBalanceV2(ctx, r)
+	case "WithdrawExpireUnfreeze":
+		r, err := argument[*core.WithdrawExpireUnfreezeContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.WithdrawExpireUnfreeze(ctx, r)
+	case "CancelAllUnfreezeV2":
+		r, err := argument[*core.CancelAllUnfreezeV2Contract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.CancelAllUnfreezeV2(ctx, r)
+	case "GetAvailableUnfreezeCount":
+		r, err := argument[*api.GetAvailableUnfreezeCountRequestMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetAvailableUnfreezeCount(ctx, r)
+	case "GetCanWithdrawUnfreezeAmount":
+		r, err := argument[*api.CanWithdrawUnfreezeAmountRequestMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetCanWithdrawUnfreezeAmount(ctx, r)
+	case "VoteWitnessAccount":
+		r, err := argument[*core.VoteWitnessContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.VoteWitnessAccount(ctx, r)
+	case "WithdrawBalance":
+		r, err := argument[*core.WithdrawBalanceContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.WithdrawBalance(ctx, r)
+	case "ListWitnesses":
+		_, err := argument[*api.EmptyMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.ListWitnesses(ctx)
+	case "GetRewardInfo":
+		r, err := argument[*api.BytesMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetRewardInfo(ctx, r.GetValue())
+	case "GetBrokerageInfo":
+		r, err := argument[*api.BytesMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetBrokerageInfo(ctx, r.GetValue())
+	case "CreateWitness":
+		r, err := argument[*core.WitnessCreateContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.CreateWitness(ctx, r)
+	case "UpdateWitness":
+		r, err := argument[*core.WitnessUpdateContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.UpdateWitness(ctx, r)
+	case "UpdateBrokerage":
+		r, err := argument[*core.UpdateBrokerageContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.UpdateBrokerage(ctx, r)
+	case "GetPaginatedNowWitnessList":
+		r, err := argument[*api.PaginatedMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetPaginatedNowWitnessList(ctx, r)
+	case "GetAssetIssueById":
+		r, err := argument[*api.BytesMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetAssetIssueById(ctx, r.GetValue())
+	case "GetAssetIssueListByName":
+		r, err := argument[*api.BytesMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetAssetIssueListByName(ctx, r.GetValue())
+	case "GetAssetIssueByAccount":
+		r, err := argument[*api.BytesMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetAssetIssueByAccount(ctx, r.GetValue())
+	case "GetAssetIssueList":
+		_, err := argument[*api.EmptyMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetAssetIssueList(ctx)
+	case "GetPaginatedAssetIssueList":
+		r, err := argument[*api.PaginatedMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetPaginatedAssetIssueList(ctx, r)
+	case "TransferAsset":
+		r, err := argument[*core.TransferAssetContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.TransferAsset(ctx, r)
+	case "CreateAssetIssue":
+		r, err := argument[*core.AssetIssueContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.CreateAssetIssue(ctx, r)
+	case "ParticipateAssetIssue":
+		r, err := argument[*core.ParticipateAssetIssueContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.ParticipateAssetIssue(ctx, r)
+	case "UpdateAsset":
+		r, err := argument[*core.UpdateAssetContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.UpdateAsset(ctx, r)
+	case "UnfreezeAsset":
+		r, err := argument[*core.UnfreezeAssetContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.UnfreezeAsset(ctx, r)
+	case "ProposalCreate":
+		r, err := argument[*core.ProposalCreateContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.ProposalCreate(ctx, r)
+	case "ProposalApprove":
+		r, err := argument[*core.ProposalApproveContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.ProposalApprove(ctx, r)
+	case "ProposalDelete":
+		r, err := argument[*core.ProposalDeleteContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.ProposalDelete(ctx, r)
+	case "ListProposals":
+		_, err := argument[*api.EmptyMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.ListProposals(ctx)
+	case "GetPaginatedProposalList":
+		r, err := argument[*api.PaginatedMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetPaginatedProposalList(ctx, r)
+	case "GetProposalById":
+		r, err := argument[*api.NumberMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetProposalById(ctx, r.GetNum())
+	case "ExchangeCreate":
+		r, err := argument[*core.ExchangeCreateContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.ExchangeCreate(ctx, r)
+	case "ExchangeInject":
+		r, err := argument[*core.ExchangeInjectContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.ExchangeInject(ctx, r)
+	case "ExchangeWithdraw":
+		r, err := argument[*core.ExchangeWithdrawContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.ExchangeWithdraw(ctx, r)
+	case "ExchangeTransaction":
+		r, err := argument[*core.ExchangeTransactionContract](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.ExchangeTransaction(ctx, r)
+	case "ListExchanges":
+		_, err := argument[*api.EmptyMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.ListExchanges(ctx)
+	case "GetPaginatedExchangeList":
+		r, err := argument[*api.PaginatedMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetPaginatedExchangeList(ctx, r)
+	case "GetExchangeById":
+		r, err := argument[*api.NumberMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetExchangeById(ctx, r.GetNum())
+	case "ListNodes":
+		_, err := argument[*api.EmptyMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.ListNodes(ctx)
+	case "GetNodeInfo":
+		_, err := argument[*api.EmptyMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetNodeInfo(ctx)
+	case "GetChainParameters":
+		_, err := argument[*api.EmptyMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetChainParameters(ctx)
+	case "GetNextMaintenanceTime":
+		_, err := argument[*api.EmptyMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.GetNextMaintenanceTime(ctx)
+	case "TotalTransaction":
+		_, err := argument[*api.EmptyMessage](method, request)
+		if err != nil {
+			return nil, err
+		}
+		return t.TotalTransaction(ctx)
+	}
+	return nil, fmt.Errorf("%w: no Transport method %q", ErrMessageType, method)
+}
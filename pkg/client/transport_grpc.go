@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"crypto/tls"
+	"encoding/binary"
 	"fmt"
 
 	"github.com/sxwebdev/gotron/schema/pb/api"
@@ -20,6 +21,7 @@ var defaultMaxSizeOption = grpc.MaxCallRecvMsgSize(32 * 10e6)
 type GRPCTransport struct {
 	conn         *grpc.ClientConn
 	walletClient api.WalletClient
+	limiter      *nodeLimiter
 }
 
 // NewGRPCTransport creates a new gRPC transport
@@ -30,10 +32,14 @@ func NewGRPCTransport(cfg NodeConfig) (*GRPCTransport, error) {
 	)
 
 	if cfg.UseTLS {
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
-			InsecureSkipVerify: false,
-			MinVersion:         tls.VersionTLS13,
-		})))
+		tlsConfig := cfg.TLSConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{
+				InsecureSkipVerify: false,
+				MinVersion:         tls.VersionTLS13,
+			}
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 	} else {
 		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
@@ -41,14 +47,18 @@ func NewGRPCTransport(cfg NodeConfig) (*GRPCTransport, error) {
 	// Build interceptor chain
 	var interceptors []grpc.UnaryClientInterceptor
 
-	// Add interceptor to inject headers as gRPC metadata
-	if len(cfg.Headers) > 0 {
-		interceptors = append(interceptors, headersInterceptor(cfg.Headers))
-	}
+	// Add interceptor to inject headers, and those of the call, as gRPC
+	// metadata
+	interceptors = append(interceptors, headersInterceptor(cfg.Headers))
 
 	// Add interceptor to wrap errors with transport context
 	interceptors = append(interceptors, transportErrorInterceptor(cfg.Address))
 
+	// Add interceptor to pace calls and rotate API keys; it runs inside the
+	// error wrapper so that it sees the raw status of the node's answer
+	limiter := newNodeLimiter(cfg)
+	interceptors = append(interceptors, limiterInterceptor(limiter))
+
 	opts = append(opts, grpc.WithChainUnaryInterceptor(interceptors...))
 
 	conn, err := grpc.NewClient(cfg.Address, opts...)
@@ -56,17 +66,35 @@ func NewGRPCTransport(cfg NodeConfig) (*GRPCTransport, error) {
 		return nil, fmt.Errorf("failed to dial gRPC: %w", err)
 	}
 
+	var cc grpc.ClientConnInterface = conn
+	if cfg.Solidity {
+		cc = solidityConn{cc: conn, address: cfg.Address}
+	}
+
 	return &GRPCTransport{
 		conn:         conn,
-		walletClient: api.NewWalletClient(conn),
+		walletClient: api.NewWalletClient(cc),
+		limiter:      limiter,
 	}, nil
 }
 
-// headersInterceptor returns a gRPC unary interceptor that adds headers as metadata
+// headersInterceptor returns a gRPC unary interceptor that adds headers, and
+// the headers set for the call by withCallHeaders, as metadata. Metadata
+// already in the context is kept.
 func headersInterceptor(headers map[string]string) grpc.UnaryClientInterceptor {
 	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
-		md := metadata.New(headers)
-		ctx = metadata.NewOutgoingContext(ctx, md)
+		call := callHeadersFrom(ctx)
+		if len(headers) > 0 || len(call) > 0 {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			md = md.Copy()
+			for key, value := range headers {
+				md.Set(key, value)
+			}
+			for key, value := range call {
+				md.Set(key, value)
+			}
+			ctx = metadata.NewOutgoingContext(ctx, md)
+		}
 		return invoker(ctx, method, req, reply, cc, opts...)
 	}
 }
@@ -87,7 +115,8 @@ func transportErrorInterceptor(address string) grpc.UnaryClientInterceptor {
 	}
 }
 
-// WalletClient returns the underlying WalletClient for direct access
+// WalletClient returns the underlying WalletClient for direct access. For a
+// solidity node its calls go to the WalletSolidity service.
 func (t *GRPCTransport) WalletClient() api.WalletClient {
 	return t.walletClient
 }
@@ -289,6 +318,24 @@ func (t *GRPCTransport) GetBrokerageInfo(ctx context.Context, address []byte) (*
 	return t.walletClient.GetBrokerageInfo(ctx, req)
 }
 
+// CreateWitness uses the 2-suffixed RPC for the same reason as VoteWitnessAccount.
+func (t *GRPCTransport) CreateWitness(ctx context.Context, contract *core.WitnessCreateContract) (*api.TransactionExtention, error) {
+	return t.walletClient.CreateWitness2(ctx, contract)
+}
+
+// UpdateWitness uses the 2-suffixed RPC for the same reason as VoteWitnessAccount.
+func (t *GRPCTransport) UpdateWitness(ctx context.Context, contract *core.WitnessUpdateContract) (*api.TransactionExtention, error) {
+	return t.walletClient.UpdateWitness2(ctx, contract)
+}
+
+func (t *GRPCTransport) UpdateBrokerage(ctx context.Context, contract *core.UpdateBrokerageContract) (*api.TransactionExtention, error) {
+	return t.walletClient.UpdateBrokerage(ctx, contract)
+}
+
+func (t *GRPCTransport) GetPaginatedNowWitnessList(ctx context.Context, msg *api.PaginatedMessage) (*api.WitnessList, error) {
+	return t.walletClient.GetPaginatedNowWitnessList(ctx, msg)
+}
+
 // Asset operations
 
 func (t *GRPCTransport) GetAssetIssueById(ctx context.Context, id []byte) (*core.AssetIssueContract, error) {
@@ -301,6 +348,100 @@ func (t *GRPCTransport) GetAssetIssueListByName(ctx context.Context, name []byte
 	return t.walletClient.GetAssetIssueListByName(ctx, req)
 }
 
+func (t *GRPCTransport) GetAssetIssueByAccount(ctx context.Context, address []byte) (*api.AssetIssueList, error) {
+	return t.walletClient.GetAssetIssueByAccount(ctx, &core.Account{Address: address})
+}
+
+func (t *GRPCTransport) GetAssetIssueList(ctx context.Context) (*api.AssetIssueList, error) {
+	return t.walletClient.GetAssetIssueList(ctx, new(api.EmptyMessage))
+}
+
+func (t *GRPCTransport) GetPaginatedAssetIssueList(ctx context.Context, msg *api.PaginatedMessage) (*api.AssetIssueList, error) {
+	return t.walletClient.GetPaginatedAssetIssueList(ctx, msg)
+}
+
+// TransferAsset and the asset lifecycle calls below use the 2-suffixed RPCs
+// for the same reason as VoteWitnessAccount.
+func (t *GRPCTransport) TransferAsset(ctx context.Context, contract *core.TransferAssetContract) (*api.TransactionExtention, error) {
+	return t.walletClient.TransferAsset2(ctx, contract)
+}
+
+func (t *GRPCTransport) CreateAssetIssue(ctx context.Context, contract *core.AssetIssueContract) (*api.TransactionExtention, error) {
+	return t.walletClient.CreateAssetIssue2(ctx, contract)
+}
+
+func (t *GRPCTransport) ParticipateAssetIssue(ctx context.Context, contract *core.ParticipateAssetIssueContract) (*api.TransactionExtention, error) {
+	return t.walletClient.ParticipateAssetIssue2(ctx, contract)
+}
+
+func (t *GRPCTransport) UpdateAsset(ctx context.Context, contract *core.UpdateAssetContract) (*api.TransactionExtention, error) {
+	return t.walletClient.UpdateAsset2(ctx, contract)
+}
+
+func (t *GRPCTransport) UnfreezeAsset(ctx context.Context, contract *core.UnfreezeAssetContract) (*api.TransactionExtention, error) {
+	return t.walletClient.UnfreezeAsset2(ctx, contract)
+}
+
+// Proposal operations
+
+func (t *GRPCTransport) ProposalCreate(ctx context.Context, contract *core.ProposalCreateContract) (*api.TransactionExtention, error) {
+	return t.walletClient.ProposalCreate(ctx, contract)
+}
+
+func (t *GRPCTransport) ProposalApprove(ctx context.Context, contract *core.ProposalApproveContract) (*api.TransactionExtention, error) {
+	return t.walletClient.ProposalApprove(ctx, contract)
+}
+
+func (t *GRPCTransport) ProposalDelete(ctx context.Context, contract *core.ProposalDeleteContract) (*api.TransactionExtention, error) {
+	return t.walletClient.ProposalDelete(ctx, contract)
+}
+
+func (t *GRPCTransport) ListProposals(ctx context.Context) (*api.ProposalList, error) {
+	return t.walletClient.ListProposals(ctx, new(api.EmptyMessage))
+}
+
+func (t *GRPCTransport) GetPaginatedProposalList(ctx context.Context, msg *api.PaginatedMessage) (*api.ProposalList, error) {
+	return t.walletClient.GetPaginatedProposalList(ctx, msg)
+}
+
+// GetProposalById sends the id as java-tron's ByteArray.fromLong writes it:
+// eight bytes, big-endian.
+func (t *GRPCTransport) GetProposalById(ctx context.Context, id int64) (*core.Proposal, error) {
+	return t.walletClient.GetProposalById(ctx, &api.BytesMessage{Value: binary.BigEndian.AppendUint64(nil, uint64(id))})
+}
+
+// Exchange operations
+
+func (t *GRPCTransport) ExchangeCreate(ctx context.Context, contract *core.ExchangeCreateContract) (*api.TransactionExtention, error) {
+	return t.walletClient.ExchangeCreate(ctx, contract)
+}
+
+func (t *GRPCTransport) ExchangeInject(ctx context.Context, contract *core.ExchangeInjectContract) (*api.TransactionExtention, error) {
+	return t.walletClient.ExchangeInject(ctx, contract)
+}
+
+func (t *GRPCTransport) ExchangeWithdraw(ctx context.Context, contract *core.ExchangeWithdrawContract) (*api.TransactionExtention, error) {
+	return t.walletClient.ExchangeWithdraw(ctx, contract)
+}
+
+func (t *GRPCTransport) ExchangeTransaction(ctx context.Context, contract *core.ExchangeTransactionContract) (*api.TransactionExtention, error) {
+	return t.walletClient.ExchangeTransaction(ctx, contract)
+}
+
+func (t *GRPCTransport) ListExchanges(ctx context.Context) (*api.ExchangeList, error) {
+	return t.walletClient.ListExchanges(ctx, new(api.EmptyMessage))
+}
+
+func (t *GRPCTransport) GetPaginatedExchangeList(ctx context.Context, msg *api.PaginatedMessage) (*api.ExchangeList, error) {
+	return t.walletClient.GetPaginatedExchangeList(ctx, msg)
+}
+
+// GetExchangeById sends the id as eight big-endian bytes, as GetProposalById
+// does.
+func (t *GRPCTransport) GetExchangeById(ctx context.Context, id int64) (*core.Exchange, error) {
+	return t.walletClient.GetExchangeById(ctx, &api.BytesMessage{Value: binary.BigEndian.AppendUint64(nil, uint64(id))})
+}
+
 // Network operations
 
 func (t *GRPCTransport) ListNodes(ctx context.Context) (*api.NodeList, error) {
@@ -8,7 +8,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -24,26 +26,40 @@ type HTTPTransport struct {
 	baseURL    string
 	httpClient *http.Client
 	headers    map[string]string
+	solidity   bool
+	limiter    *nodeLimiter
 }
 
 // NewHTTPTransport creates a new HTTP transport
 func NewHTTPTransport(cfg NodeConfig) (*HTTPTransport, error) {
-	httpClient := cfg.HTTPClient
-	if httpClient == nil {
-		httpClient = &http.Client{
-			Timeout: 30 * time.Second,
-		}
-	}
-
 	baseURL := strings.TrimSuffix(cfg.Address, "/")
 
 	return &HTTPTransport{
 		baseURL:    baseURL,
-		httpClient: httpClient,
+		httpClient: newHTTPClient(cfg),
 		headers:    cfg.Headers,
+		solidity:   cfg.Solidity,
+		limiter:    newNodeLimiter(cfg),
 	}, nil
 }
 
+// newHTTPClient returns NodeConfig.HTTPClient, or a client with a 30s timeout
+// that applies NodeConfig.TLSConfig.
+func newHTTPClient(cfg NodeConfig) *http.Client {
+	if cfg.HTTPClient != nil {
+		return cfg.HTTPClient
+	}
+	httpClient := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+	if cfg.TLSConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = cfg.TLSConfig
+		httpClient.Transport = transport
+	}
+	return httpClient
+}
+
 // Close closes the HTTP transport (no-op for HTTP)
 func (t *HTTPTransport) Close() error {
 	return nil
@@ -314,57 +330,102 @@ func transformBlockListJSON(data any) any {
 
 // doRequestRaw performs an HTTP POST request and returns raw JSON response
 func (t *HTTPTransport) doRequestRaw(ctx context.Context, endpoint string, body any) ([]byte, error) {
-	var bodyReader io.Reader
+	if t.solidity {
+		if _, ok := solidityEndpoint(endpoint); !ok {
+			return nil, t.wrapErr(endpoint, ErrUnsupportedBySolidity)
+		}
+	}
 
+	jsonBody := []byte("{}")
 	if body != nil {
-		jsonBody, err := json.Marshal(body)
-		if err != nil {
+		var err error
+		if jsonBody, err = json.Marshal(body); err != nil {
 			return nil, t.wrapErr(endpoint, fmt.Errorf("marshal request body: %w", err))
 		}
-		bodyReader = bytes.NewReader(jsonBody)
-	} else {
-		bodyReader = bytes.NewReader([]byte("{}"))
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+endpoint, bodyReader)
+	respBody, err := postJSON(ctx, t.httpClient, t.baseURL+t.path(endpoint), t.headers, t.limiter, jsonBody)
 	if err != nil {
-		return nil, t.wrapErr(endpoint, fmt.Errorf("create request: %w", err))
+		return nil, t.wrapErr(endpoint, err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
+	return respBody, nil
+}
 
-	for key, value := range t.headers {
-		req.Header.Set(key, value)
-	}
+// postJSON posts body to url through limiter and returns the answer of a 200,
+// or an *HTTPStatusError. A 429 rests the API key the request carried and, while
+// another key is free, the request is sent again with it; the node is only
+// reported rate-limited once every key is.
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, limiter *nodeLimiter, body []byte) ([]byte, error) {
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("create request: %w", err)
+		}
 
-	resp, err := t.httpClient.Do(req)
-	if err != nil {
-		return nil, t.wrapErr(endpoint, fmt.Errorf("http request: %w", err))
-	}
-	defer func() { _ = resp.Body.Close() }()
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, t.wrapErr(endpoint, fmt.Errorf("read response: %w", err))
-	}
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		for key, value := range callHeadersFrom(ctx) {
+			req.Header.Set(key, value)
+		}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, t.wrapErr(endpoint, &HTTPStatusError{Code: resp.StatusCode, Body: string(respBody)})
-	}
+		apiKey, err := limiter.wait(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if apiKey != "" {
+			req.Header.Set(limiter.header, apiKey)
+		}
 
-	return respBody, nil
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("http request: %w", err)
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read response: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			statusErr := &HTTPStatusError{Code: resp.StatusCode, Body: string(respBody)}
+			if resp.StatusCode == http.StatusTooManyRequests {
+				statusErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+				if limiter.throttled(apiKey, statusErr.RetryAfter) {
+					continue
+				}
+			}
+			return nil, statusErr
+		}
+
+		return respBody, nil
+	}
 }
 
 func (t *HTTPTransport) wrapErr(method string, err error) error {
 	return &TransportError{
 		Host:     t.baseURL,
 		Protocol: "http",
-		Method:   method,
+		Method:   t.path(method),
 		Err:      err,
 	}
 }
 
+// path returns the path endpoint is served at: the endpoint itself on a full
+// node, its /walletsolidity counterpart on a solidity node.
+func (t *HTTPTransport) path(endpoint string) string {
+	if t.solidity {
+		if p, ok := solidityEndpoint(endpoint); ok {
+			return p
+		}
+	}
+	return endpoint
+}
+
 // apiError reports a refusal the node returned as HTTP 200 with an "Error"
 // field, which is how the /wallet endpoints answer a request they would not
 // process at all - a malformed address, a value out of range.
@@ -671,6 +732,13 @@ type httpAccount struct {
 	AssetV2               []httpAssetBalance   `json:"assetV2"`
 	FreeAssetNetUsageV2   []httpAssetBalance   `json:"free_asset_net_usageV2"`
 	AssetOptimized        bool                 `json:"asset_optimized"`
+	// The issued asset's name and id are text rather than hex under "visible".
+	AssetIssuedName string `json:"asset_issued_name"`
+	AssetIssuedID   string `json:"asset_issued_ID"`
+	FrozenSupply    []struct {
+		FrozenBalance int64 `json:"frozen_balance"`
+		ExpireTime    int64 `json:"expire_time"`
+	} `json:"frozen_supply"`
 }
 
 // httpAssetBalance is one entry of a TRC10 id -> amount map. Tron renders a
@@ -827,6 +895,19 @@ func (t *HTTPTransport) GetAccount(ctx context.Context, account *core.Account) (
 		FreeAssetNetUsageV2:   assetMap(httpAcc.FreeAssetNetUsageV2),
 	}
 
+	if httpAcc.AssetIssuedName != "" {
+		result.AssetIssuedName = []byte(httpAcc.AssetIssuedName)
+	}
+	if httpAcc.AssetIssuedID != "" {
+		result.AssetIssued_ID = []byte(httpAcc.AssetIssuedID)
+	}
+	for _, f := range httpAcc.FrozenSupply {
+		result.FrozenSupply = append(result.FrozenSupply, &core.Account_Frozen{
+			FrozenBalance: f.FrozenBalance,
+			ExpireTime:    f.ExpireTime,
+		})
+	}
+
 	// An unreadable address is refused rather than dropped: Client.GetAccount
 	// compares it against the one it asked for, so a nil here reports a funded
 	// account as ErrAccountNotFound.
@@ -1119,26 +1200,75 @@ func (t *HTTPTransport) GetTransactionInfoByBlockNum(ctx context.Context, num in
 
 // Transaction operations
 
+// GetTransactionById rebuilds the transaction from raw_data_hex, the way
+// parseTxResponse does. Plain protojson could not read the answer at all: the
+// contract parameter is a "type_url"/"value" Any, which it refuses, and every
+// bytes field is hex, which it would have read as base64.
 func (t *HTTPTransport) GetTransactionById(ctx context.Context, id []byte) (*core.Transaction, error) {
 	reqBody := map[string]any{
 		"value": hex.EncodeToString(id),
 	}
 
-	result := &core.Transaction{}
-	if err := t.doRequest(ctx, "/wallet/gettransactionbyid", reqBody, result); err != nil {
+	var resp struct {
+		RawDataHex string            `json:"raw_data_hex"`
+		Signature  []string          `json:"signature"`
+		Ret        []json.RawMessage `json:"ret"`
+	}
+	if err := t.fetchJSON(ctx, "/wallet/gettransactionbyid", reqBody, &resp); err != nil {
 		return nil, err
 	}
 
+	// A transaction the node does not have comes back as {}.
+	result := &core.Transaction{}
+	if resp.RawDataHex == "" {
+		return result, nil
+	}
+
+	rawBytes, err := hex.DecodeString(resp.RawDataHex)
+	if err != nil {
+		return nil, t.wrapErr("/wallet/gettransactionbyid", fmt.Errorf("decode raw_data_hex: %w", err))
+	}
+
+	result.RawData = &core.TransactionRaw{}
+	if err := proto.Unmarshal(rawBytes, result.RawData); err != nil {
+		return nil, t.wrapErr("/wallet/gettransactionbyid", fmt.Errorf("unmarshal raw_data_hex: %w", err))
+	}
+
+	for _, signature := range resp.Signature {
+		decoded, err := hex.DecodeString(signature)
+		if err != nil {
+			return nil, t.wrapErr("/wallet/gettransactionbyid", fmt.Errorf("decode signature: %w", err))
+		}
+
+		result.Signature = append(result.Signature, decoded)
+	}
+
+	// The results carry no bytes fields, so protojson reads them as they are.
+	opts := protojson.UnmarshalOptions{DiscardUnknown: true}
+	for _, item := range resp.Ret {
+		ret := &core.Transaction_Result{}
+		if err := opts.Unmarshal(item, ret); err != nil {
+			return nil, t.wrapErr("/wallet/gettransactionbyid", fmt.Errorf("unmarshal ret: %w", err))
+		}
+
+		result.Ret = append(result.Ret, ret)
+	}
+
 	return result, nil
 }
 
+// GetTransactionInfoById needs the hex-to-base64 transform for the same
+// reason GetContract does: the id, the log addresses and topics and the
+// contract result all arrive as hex. Read as base64 the id came back as 48
+// bytes of nonsense, so Client.GetTransactionInfoByHash, which compares it
+// against the hash it asked for, reported every transaction as not found.
 func (t *HTTPTransport) GetTransactionInfoById(ctx context.Context, id []byte) (*core.TransactionInfo, error) {
 	reqBody := map[string]any{
 		"value": hex.EncodeToString(id),
 	}
 
 	result := &core.TransactionInfo{}
-	if err := t.doRequest(ctx, "/wallet/gettransactioninfobyid", reqBody, result); err != nil {
+	if err := t.doRequestTransformed(ctx, "/wallet/gettransactioninfobyid", reqBody, result); err != nil {
 		return nil, err
 	}
 
@@ -1696,10 +1826,26 @@ type httpWitness struct {
 }
 
 func (t *HTTPTransport) ListWitnesses(ctx context.Context) (*api.WitnessList, error) {
+	return t.fetchWitnessList(ctx, "/wallet/listwitnesses", nil)
+}
+
+// GetPaginatedNowWitnessList is sent without "visible", so its reply carries
+// the same hex addresses as /wallet/listwitnesses.
+func (t *HTTPTransport) GetPaginatedNowWitnessList(ctx context.Context, msg *api.PaginatedMessage) (*api.WitnessList, error) {
+	reqBody := map[string]any{
+		"offset": msg.GetOffset(),
+		"limit":  msg.GetLimit(),
+	}
+
+	return t.fetchWitnessList(ctx, "/wallet/getpaginatednowwitnesslist", reqBody)
+}
+
+// fetchWitnessList reads a {"witnesses": [...]} reply of hex addresses.
+func (t *HTTPTransport) fetchWitnessList(ctx context.Context, endpoint string, reqBody any) (*api.WitnessList, error) {
 	var resp struct {
 		Witnesses []httpWitness `json:"witnesses"`
 	}
-	if err := t.fetchJSON(ctx, "/wallet/listwitnesses", nil, &resp); err != nil {
+	if err := t.fetchJSON(ctx, endpoint, reqBody, &resp); err != nil {
 		return nil, err
 	}
 
@@ -1761,6 +1907,40 @@ func (t *HTTPTransport) GetBrokerageInfo(ctx context.Context, address []byte) (*
 	return &api.NumberMessage{Num: resp.Brokerage}, nil
 }
 
+// CreateWitness applies for a witness. With "visible" set, java-tron reads url
+// as plain text, like CreateAssetIssue's.
+func (t *HTTPTransport) CreateWitness(ctx context.Context, contract *core.WitnessCreateContract) (*api.TransactionExtention, error) {
+	reqBody := map[string]any{
+		"owner_address": tronutils.EncodeCheck(contract.OwnerAddress),
+		"url":           string(contract.Url),
+		"visible":       true,
+	}
+
+	return t.doTxRequest(ctx, "/wallet/createwitness", reqBody)
+}
+
+func (t *HTTPTransport) UpdateWitness(ctx context.Context, contract *core.WitnessUpdateContract) (*api.TransactionExtention, error) {
+	reqBody := map[string]any{
+		"owner_address": tronutils.EncodeCheck(contract.OwnerAddress),
+		"update_url":    string(contract.UpdateUrl),
+		"visible":       true,
+	}
+
+	return t.doTxRequest(ctx, "/wallet/updatewitness", reqBody)
+}
+
+// UpdateBrokerage uses the camelCase /wallet/updateBrokerage endpoint, like
+// GetBrokerageInfo.
+func (t *HTTPTransport) UpdateBrokerage(ctx context.Context, contract *core.UpdateBrokerageContract) (*api.TransactionExtention, error) {
+	reqBody := map[string]any{
+		"owner_address": tronutils.EncodeCheck(contract.OwnerAddress),
+		"brokerage":     contract.Brokerage,
+		"visible":       true,
+	}
+
+	return t.doTxRequest(ctx, "/wallet/updateBrokerage", reqBody)
+}
+
 // Asset operations
 
 // httpAssetIssue is one TRC10 asset as /wallet/getassetissue* renders it.
@@ -1891,10 +2071,40 @@ func (t *HTTPTransport) GetAssetIssueListByName(ctx context.Context, name []byte
 		"value": hex.EncodeToString(name),
 	}
 
+	return t.fetchAssetIssueList(ctx, "/wallet/getassetissuelistbyname", reqBody)
+}
+
+// GetAssetIssueByAccount lists the assets an account has issued. The address
+// goes as hex without "visible", so the reply carries hex fields that
+// httpAssetIssue knows how to read.
+func (t *HTTPTransport) GetAssetIssueByAccount(ctx context.Context, address []byte) (*api.AssetIssueList, error) {
+	reqBody := map[string]any{
+		"address": hex.EncodeToString(address),
+	}
+
+	return t.fetchAssetIssueList(ctx, "/wallet/getassetissuebyaccount", reqBody)
+}
+
+func (t *HTTPTransport) GetAssetIssueList(ctx context.Context) (*api.AssetIssueList, error) {
+	return t.fetchAssetIssueList(ctx, "/wallet/getassetissuelist", nil)
+}
+
+func (t *HTTPTransport) GetPaginatedAssetIssueList(ctx context.Context, msg *api.PaginatedMessage) (*api.AssetIssueList, error) {
+	reqBody := map[string]any{
+		"offset": msg.GetOffset(),
+		"limit":  msg.GetLimit(),
+	}
+
+	return t.fetchAssetIssueList(ctx, "/wallet/getpaginatedassetissuelist", reqBody)
+}
+
+// fetchAssetIssueList reads an {"assetIssue": [...]} reply, which every asset
+// listing endpoint shares. An empty list comes back as an empty object.
+func (t *HTTPTransport) fetchAssetIssueList(ctx context.Context, endpoint string, reqBody any) (*api.AssetIssueList, error) {
 	var parsed struct {
 		AssetIssue []httpAssetIssue `json:"assetIssue"`
 	}
-	if err := t.fetchJSON(ctx, "/wallet/getassetissuelistbyname", reqBody, &parsed); err != nil {
+	if err := t.fetchJSON(ctx, endpoint, reqBody, &parsed); err != nil {
 		return nil, err
 	}
 
@@ -1902,7 +2112,7 @@ func (t *HTTPTransport) GetAssetIssueListByName(ctx context.Context, name []byte
 	for _, item := range parsed.AssetIssue {
 		asset, err := item.toProto()
 		if err != nil {
-			return nil, t.wrapErr("/wallet/getassetissuelistbyname", err)
+			return nil, t.wrapErr(endpoint, err)
 		}
 
 		result.AssetIssue = append(result.AssetIssue, asset)
@@ -1911,6 +2121,400 @@ func (t *HTTPTransport) GetAssetIssueListByName(ctx context.Context, name []byte
 	return result, nil
 }
 
+// TransferAsset sends a TRC10 token. With "visible" set, java-tron reads
+// asset_name as plain text rather than hex, so the id goes as it stands.
+func (t *HTTPTransport) TransferAsset(ctx context.Context, contract *core.TransferAssetContract) (*api.TransactionExtention, error) {
+	reqBody := map[string]any{
+		"owner_address": tronutils.EncodeCheck(contract.OwnerAddress),
+		"to_address":    tronutils.EncodeCheck(contract.ToAddress),
+		"asset_name":    string(contract.AssetName),
+		"amount":        contract.Amount,
+		"visible":       true,
+	}
+
+	return t.doTxRequest(ctx, "/wallet/transferasset", reqBody)
+}
+
+// CreateAssetIssue issues a TRC10 token. With "visible" set, java-tron reads
+// name, abbr, description and url as plain text, like TransferAsset's
+// asset_name.
+func (t *HTTPTransport) CreateAssetIssue(ctx context.Context, contract *core.AssetIssueContract) (*api.TransactionExtention, error) {
+	frozen := make([]map[string]any, len(contract.FrozenSupply))
+	for i, f := range contract.FrozenSupply {
+		frozen[i] = map[string]any{
+			"frozen_amount": f.FrozenAmount,
+			"frozen_days":   f.FrozenDays,
+		}
+	}
+
+	reqBody := map[string]any{
+		"owner_address":               tronutils.EncodeCheck(contract.OwnerAddress),
+		"name":                        string(contract.Name),
+		"abbr":                        string(contract.Abbr),
+		"total_supply":                contract.TotalSupply,
+		"frozen_supply":               frozen,
+		"trx_num":                     contract.TrxNum,
+		"num":                         contract.Num,
+		"precision":                   contract.Precision,
+		"start_time":                  contract.StartTime,
+		"end_time":                    contract.EndTime,
+		"description":                 string(contract.Description),
+		"url":                         string(contract.Url),
+		"free_asset_net_limit":        contract.FreeAssetNetLimit,
+		"public_free_asset_net_limit": contract.PublicFreeAssetNetLimit,
+		"visible":                     true,
+	}
+
+	return t.doTxRequest(ctx, "/wallet/createassetissue", reqBody)
+}
+
+func (t *HTTPTransport) ParticipateAssetIssue(ctx context.Context, contract *core.ParticipateAssetIssueContract) (*api.TransactionExtention, error) {
+	reqBody := map[string]any{
+		"owner_address": tronutils.EncodeCheck(contract.OwnerAddress),
+		"to_address":    tronutils.EncodeCheck(contract.ToAddress),
+		"asset_name":    string(contract.AssetName),
+		"amount":        contract.Amount,
+		"visible":       true,
+	}
+
+	return t.doTxRequest(ctx, "/wallet/participateassetissue", reqBody)
+}
+
+func (t *HTTPTransport) UpdateAsset(ctx context.Context, contract *core.UpdateAssetContract) (*api.TransactionExtention, error) {
+	reqBody := map[string]any{
+		"owner_address":    tronutils.EncodeCheck(contract.OwnerAddress),
+		"description":      string(contract.Description),
+		"url":              string(contract.Url),
+		"new_limit":        contract.NewLimit,
+		"new_public_limit": contract.NewPublicLimit,
+		"visible":          true,
+	}
+
+	return t.doTxRequest(ctx, "/wallet/updateasset", reqBody)
+}
+
+func (t *HTTPTransport) UnfreezeAsset(ctx context.Context, contract *core.UnfreezeAssetContract) (*api.TransactionExtention, error) {
+	reqBody := map[string]any{
+		"owner_address": tronutils.EncodeCheck(contract.OwnerAddress),
+		"visible":       true,
+	}
+
+	return t.doTxRequest(ctx, "/wallet/unfreezeasset", reqBody)
+}
+
+// Proposal operations
+
+// httpProposalParameter is one entry of a proposal's parameter map, which
+// java-tron renders as a list of key/value pairs rather than a JSON object.
+type httpProposalParameter struct {
+	Key   int64 `json:"key"`
+	Value int64 `json:"value"`
+}
+
+// httpProposal is one proposal as the proposal reads render it without
+// "visible": addresses are hex, and state is the enum's name, left out while
+// the proposal is still PENDING.
+type httpProposal struct {
+	ProposalID      int64                   `json:"proposal_id"`
+	ProposerAddress string                  `json:"proposer_address"`
+	Parameters      []httpProposalParameter `json:"parameters"`
+	ExpirationTime  int64                   `json:"expiration_time"`
+	CreateTime      int64                   `json:"create_time"`
+	Approvals       []string                `json:"approvals"`
+	State           string                  `json:"state"`
+}
+
+func (p httpProposal) toProto() (*core.Proposal, error) {
+	out := &core.Proposal{
+		ProposalId:     p.ProposalID,
+		ExpirationTime: p.ExpirationTime,
+		CreateTime:     p.CreateTime,
+	}
+
+	if p.ProposerAddress != "" {
+		proposer, err := hex.DecodeString(p.ProposerAddress)
+		if err != nil {
+			return nil, fmt.Errorf("%w: proposer_address %q: %w", ErrInvalidAddress, p.ProposerAddress, err)
+		}
+
+		out.ProposerAddress = proposer
+	}
+
+	if len(p.Parameters) > 0 {
+		out.Parameters = make(map[int64]int64, len(p.Parameters))
+		for _, param := range p.Parameters {
+			out.Parameters[param.Key] = param.Value
+		}
+	}
+
+	for _, approval := range p.Approvals {
+		voter, err := hex.DecodeString(approval)
+		if err != nil {
+			return nil, fmt.Errorf("%w: approvals %q: %w", ErrInvalidAddress, approval, err)
+		}
+
+		out.Approvals = append(out.Approvals, voter)
+	}
+
+	// A state this build does not know is refused rather than read as PENDING,
+	// which a caller would take for a proposal still open to votes.
+	if p.State != "" {
+		state, ok := core.Proposal_State_value[p.State]
+		if !ok {
+			return nil, fmt.Errorf("unknown proposal state %q", p.State)
+		}
+
+		out.State = core.Proposal_State(state)
+	}
+
+	return out, nil
+}
+
+// ProposalCreate opens a proposal. The parameters go as the key/value list
+// java-tron reads a map from, in key order so that the request is the same
+// every time.
+func (t *HTTPTransport) ProposalCreate(ctx context.Context, contract *core.ProposalCreateContract) (*api.TransactionExtention, error) {
+	params := make([]httpProposalParameter, 0, len(contract.Parameters))
+	for _, key := range slices.Sorted(maps.Keys(contract.Parameters)) {
+		params = append(params, httpProposalParameter{Key: key, Value: contract.Parameters[key]})
+	}
+
+	reqBody := map[string]any{
+		"owner_address": tronutils.EncodeCheck(contract.OwnerAddress),
+		"parameters":    params,
+		"visible":       true,
+	}
+
+	return t.doTxRequest(ctx, "/wallet/proposalcreate", reqBody)
+}
+
+func (t *HTTPTransport) ProposalApprove(ctx context.Context, contract *core.ProposalApproveContract) (*api.TransactionExtention, error) {
+	reqBody := map[string]any{
+		"owner_address":   tronutils.EncodeCheck(contract.OwnerAddress),
+		"proposal_id":     contract.ProposalId,
+		"is_add_approval": contract.IsAddApproval,
+		"visible":         true,
+	}
+
+	return t.doTxRequest(ctx, "/wallet/proposalapprove", reqBody)
+}
+
+func (t *HTTPTransport) ProposalDelete(ctx context.Context, contract *core.ProposalDeleteContract) (*api.TransactionExtention, error) {
+	reqBody := map[string]any{
+		"owner_address": tronutils.EncodeCheck(contract.OwnerAddress),
+		"proposal_id":   contract.ProposalId,
+		"visible":       true,
+	}
+
+	return t.doTxRequest(ctx, "/wallet/proposaldelete", reqBody)
+}
+
+func (t *HTTPTransport) ListProposals(ctx context.Context) (*api.ProposalList, error) {
+	return t.fetchProposalList(ctx, "/wallet/listproposals", nil)
+}
+
+func (t *HTTPTransport) GetPaginatedProposalList(ctx context.Context, msg *api.PaginatedMessage) (*api.ProposalList, error) {
+	reqBody := map[string]any{
+		"offset": msg.GetOffset(),
+		"limit":  msg.GetLimit(),
+	}
+
+	return t.fetchProposalList(ctx, "/wallet/getpaginatedproposallist", reqBody)
+}
+
+// GetProposalById looks up one proposal. An id that names none comes back as
+// an empty object, and so as an empty core.Proposal, as over gRPC.
+func (t *HTTPTransport) GetProposalById(ctx context.Context, id int64) (*core.Proposal, error) {
+	reqBody := map[string]any{
+		"id": id,
+	}
+
+	var parsed httpProposal
+	if err := t.fetchJSON(ctx, "/wallet/getproposalbyid", reqBody, &parsed); err != nil {
+		return nil, err
+	}
+
+	result, err := parsed.toProto()
+	if err != nil {
+		return nil, t.wrapErr("/wallet/getproposalbyid", err)
+	}
+
+	return result, nil
+}
+
+// fetchProposalList reads a {"proposals": [...]} reply. An empty list comes
+// back as an empty object.
+func (t *HTTPTransport) fetchProposalList(ctx context.Context, endpoint string, reqBody any) (*api.ProposalList, error) {
+	var parsed struct {
+		Proposals []httpProposal `json:"proposals"`
+	}
+	if err := t.fetchJSON(ctx, endpoint, reqBody, &parsed); err != nil {
+		return nil, err
+	}
+
+	result := &api.ProposalList{Proposals: make([]*core.Proposal, 0, len(parsed.Proposals))}
+	for _, item := range parsed.Proposals {
+		proposal, err := item.toProto()
+		if err != nil {
+			return nil, t.wrapErr(endpoint, err)
+		}
+
+		result.Proposals = append(result.Proposals, proposal)
+	}
+
+	return result, nil
+}
+
+// Exchange operations
+
+// httpExchange is one exchange as the exchange reads render it without
+// "visible": the creator address and both token ids are hex, so TRX's "_" is
+// "5f".
+type httpExchange struct {
+	ExchangeID         int64  `json:"exchange_id"`
+	CreatorAddress     string `json:"creator_address"`
+	CreateTime         int64  `json:"create_time"`
+	FirstTokenID       string `json:"first_token_id"`
+	FirstTokenBalance  int64  `json:"first_token_balance"`
+	SecondTokenID      string `json:"second_token_id"`
+	SecondTokenBalance int64  `json:"second_token_balance"`
+}
+
+func (e httpExchange) toProto() (*core.Exchange, error) {
+	creator, err := hex.DecodeString(e.CreatorAddress)
+	if err != nil {
+		return nil, fmt.Errorf("%w: creator_address %q: %w", ErrInvalidAddress, e.CreatorAddress, err)
+	}
+
+	first, err := hex.DecodeString(e.FirstTokenID)
+	if err != nil {
+		return nil, fmt.Errorf("first_token_id %q: %w", e.FirstTokenID, err)
+	}
+
+	second, err := hex.DecodeString(e.SecondTokenID)
+	if err != nil {
+		return nil, fmt.Errorf("second_token_id %q: %w", e.SecondTokenID, err)
+	}
+
+	return &core.Exchange{
+		ExchangeId:         e.ExchangeID,
+		CreatorAddress:     creator,
+		CreateTime:         e.CreateTime,
+		FirstTokenId:       first,
+		FirstTokenBalance:  e.FirstTokenBalance,
+		SecondTokenId:      second,
+		SecondTokenBalance: e.SecondTokenBalance,
+	}, nil
+}
+
+// ExchangeCreate opens an exchange. With "visible" set, java-tron reads the
+// token ids as text, as it does an asset name.
+func (t *HTTPTransport) ExchangeCreate(ctx context.Context, contract *core.ExchangeCreateContract) (*api.TransactionExtention, error) {
+	reqBody := map[string]any{
+		"owner_address":        tronutils.EncodeCheck(contract.OwnerAddress),
+		"first_token_id":       string(contract.FirstTokenId),
+		"first_token_balance":  contract.FirstTokenBalance,
+		"second_token_id":      string(contract.SecondTokenId),
+		"second_token_balance": contract.SecondTokenBalance,
+		"visible":              true,
+	}
+
+	return t.doTxRequest(ctx, "/wallet/exchangecreate", reqBody)
+}
+
+func (t *HTTPTransport) ExchangeInject(ctx context.Context, contract *core.ExchangeInjectContract) (*api.TransactionExtention, error) {
+	reqBody := map[string]any{
+		"owner_address": tronutils.EncodeCheck(contract.OwnerAddress),
+		"exchange_id":   contract.ExchangeId,
+		"token_id":      string(contract.TokenId),
+		"quant":         contract.Quant,
+		"visible":       true,
+	}
+
+	return t.doTxRequest(ctx, "/wallet/exchangeinject", reqBody)
+}
+
+func (t *HTTPTransport) ExchangeWithdraw(ctx context.Context, contract *core.ExchangeWithdrawContract) (*api.TransactionExtention, error) {
+	reqBody := map[string]any{
+		"owner_address": tronutils.EncodeCheck(contract.OwnerAddress),
+		"exchange_id":   contract.ExchangeId,
+		"token_id":      string(contract.TokenId),
+		"quant":         contract.Quant,
+		"visible":       true,
+	}
+
+	return t.doTxRequest(ctx, "/wallet/exchangewithdraw", reqBody)
+}
+
+func (t *HTTPTransport) ExchangeTransaction(ctx context.Context, contract *core.ExchangeTransactionContract) (*api.TransactionExtention, error) {
+	reqBody := map[string]any{
+		"owner_address": tronutils.EncodeCheck(contract.OwnerAddress),
+		"exchange_id":   contract.ExchangeId,
+		"token_id":      string(contract.TokenId),
+		"quant":         contract.Quant,
+		"expected":      contract.Expected,
+		"visible":       true,
+	}
+
+	return t.doTxRequest(ctx, "/wallet/exchangetransaction", reqBody)
+}
+
+func (t *HTTPTransport) ListExchanges(ctx context.Context) (*api.ExchangeList, error) {
+	return t.fetchExchangeList(ctx, "/wallet/listexchanges", nil)
+}
+
+func (t *HTTPTransport) GetPaginatedExchangeList(ctx context.Context, msg *api.PaginatedMessage) (*api.ExchangeList, error) {
+	reqBody := map[string]any{
+		"offset": msg.GetOffset(),
+		"limit":  msg.GetLimit(),
+	}
+
+	return t.fetchExchangeList(ctx, "/wallet/getpaginatedexchangelist", reqBody)
+}
+
+// GetExchangeById looks up one exchange. An id that names none comes back as
+// an empty object, and so as an empty core.Exchange, as over gRPC.
+func (t *HTTPTransport) GetExchangeById(ctx context.Context, id int64) (*core.Exchange, error) {
+	reqBody := map[string]any{
+		"id": id,
+	}
+
+	var parsed httpExchange
+	if err := t.fetchJSON(ctx, "/wallet/getexchangebyid", reqBody, &parsed); err != nil {
+		return nil, err
+	}
+
+	result, err := parsed.toProto()
+	if err != nil {
+		return nil, t.wrapErr("/wallet/getexchangebyid", err)
+	}
+
+	return result, nil
+}
+
+// fetchExchangeList reads an {"exchanges": [...]} reply. An empty list comes
+// back as an empty object.
+func (t *HTTPTransport) fetchExchangeList(ctx context.Context, endpoint string, reqBody any) (*api.ExchangeList, error) {
+	var parsed struct {
+		Exchanges []httpExchange `json:"exchanges"`
+	}
+	if err := t.fetchJSON(ctx, endpoint, reqBody, &parsed); err != nil {
+		return nil, err
+	}
+
+	result := &api.ExchangeList{Exchanges: make([]*core.Exchange, 0, len(parsed.Exchanges))}
+	for _, item := range parsed.Exchanges {
+		exchange, err := item.toProto()
+		if err != nil {
+			return nil, t.wrapErr(endpoint, err)
+		}
+
+		result.Exchanges = append(result.Exchanges, exchange)
+	}
+
+	return result, nil
+}
+
 // Network operations
 
 func (t *HTTPTransport) ListNodes(ctx context.Context) (*api.NodeList, error) {
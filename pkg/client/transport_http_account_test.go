@@ -126,6 +126,23 @@ func TestHTTPGetAccountKeepsAssetMaps(t *testing.T) {
 	require.Equal(t, map[string]int64{"1004977": 3}, acc.GetFreeAssetNetUsageV2())
 }
 
+// An issuer's asset name and id come back as text under "visible", not hex,
+// and CreateAssetIssue reads the id to refuse a second issue.
+func TestHTTPGetAccountKeepsIssuedAsset(t *testing.T) {
+	tr, _ := newStubTransport(t, http.StatusOK, `{"address":"`+testAddr+`","balance":1,`+
+		`"asset_issued_name":"Loyalty","asset_issued_ID":"1000001",`+
+		`"frozen_supply":[{"frozen_balance":100,"expire_time":1800000000000}]}`)
+
+	acc, err := tr.GetAccount(t.Context(), &core.Account{Address: mustDecode(t, testAddr)})
+	require.NoError(t, err)
+
+	require.Equal(t, "Loyalty", string(acc.GetAssetIssuedName()))
+	require.Equal(t, "1000001", string(acc.GetAssetIssued_ID()))
+	require.Len(t, acc.GetFrozenSupply(), 1)
+	require.Equal(t, int64(100), acc.GetFrozenSupply()[0].GetFrozenBalance())
+	require.Equal(t, int64(1_800_000_000_000), acc.GetFrozenSupply()[0].GetExpireTime())
+}
+
 // An account with none of these is the common case and must stay an answer
 // rather than an empty message full of zero-valued sub-messages.
 func TestHTTPGetAccountWithoutOptionalSections(t *testing.T) {
@@ -1,11 +1,16 @@
 package client
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"github.com/sxwebdev/gotron/pkg/tronutils"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/protobuf/proto"
 )
 
 // The node's own answer for asset 1002000, verbatim. Every bytes field in it is
@@ -134,3 +139,82 @@ func TestHTTPAssetIssueRejectsMalformedHex(t *testing.T) {
 	_, err := tr.GetAssetIssueById(t.Context(), []byte("1002000"))
 	require.ErrorContains(t, err, "decode name")
 }
+
+// With "visible" set, java-tron reads asset_name as text, so the id goes as it
+// stands. Hex-encoded, as GetAssetIssueListByName sends a name, it would name
+// a token whose id is the hex digits, which does not exist.
+func TestHTTPTransferAssetSendsIdAsText(t *testing.T) {
+	raw := &core.TransactionRaw{Contract: []*core.Transaction_Contract{{Type: core.Transaction_Contract_TransferAssetContract}}}
+	rawBytes, err := proto.Marshal(raw)
+	require.NoError(t, err)
+	digest := sha256.Sum256(rawBytes)
+	response := fmt.Sprintf(`{"raw_data_hex":%q,"txID":%q}`, hex.EncodeToString(rawBytes), hex.EncodeToString(digest[:]))
+	tr, lastReq := newStubTransportAtPath(t, "/wallet/transferasset", http.StatusOK, response)
+
+	tx, err := tr.TransferAsset(t.Context(), &core.TransferAssetContract{
+		OwnerAddress: mustDecode(t, testAddr),
+		ToAddress:    mustDecode(t, testAddr2),
+		AssetName:    []byte("1002000"),
+		Amount:       1_500_000,
+	})
+	require.NoError(t, err)
+	require.Equal(t, digest[:], tx.GetTxid())
+
+	require.Equal(t, map[string]any{
+		"owner_address": testAddr,
+		"to_address":    testAddr2,
+		"asset_name":    "1002000",
+		"amount":        float64(1_500_000),
+		"visible":       true,
+	}, *lastReq)
+}
+
+// An issue sends its text fields as text for the same reason, and the frozen
+// supply as a list of objects rather than the proto's repeated message.
+func TestHTTPCreateAssetIssueSendsTextFields(t *testing.T) {
+	raw := &core.TransactionRaw{Contract: []*core.Transaction_Contract{{Type: core.Transaction_Contract_AssetIssueContract}}}
+	rawBytes, err := proto.Marshal(raw)
+	require.NoError(t, err)
+	digest := sha256.Sum256(rawBytes)
+	response := fmt.Sprintf(`{"raw_data_hex":%q,"txID":%q}`, hex.EncodeToString(rawBytes), hex.EncodeToString(digest[:]))
+	tr, lastReq := newStubTransportAtPath(t, "/wallet/createassetissue", http.StatusOK, response)
+
+	_, err = tr.CreateAssetIssue(t.Context(), &core.AssetIssueContract{
+		OwnerAddress: mustDecode(t, testAddr),
+		Name:         []byte("Loyalty"),
+		Abbr:         []byte("LOY"),
+		TotalSupply:  1_000_000,
+		FrozenSupply: []*core.AssetIssueContract_FrozenSupply{{FrozenAmount: 100, FrozenDays: 30}},
+		TrxNum:       1,
+		Num:          10,
+		Precision:    2,
+		StartTime:    1_800_000_000_000,
+		EndTime:      1_800_086_400_000,
+		Description:  []byte("Partner points"),
+		Url:          []byte("https://example.com"),
+	})
+	require.NoError(t, err)
+
+	req := *lastReq
+	require.Equal(t, testAddr, req["owner_address"])
+	require.Equal(t, "Loyalty", req["name"])
+	require.Equal(t, "LOY", req["abbr"])
+	require.Equal(t, "Partner points", req["description"])
+	require.Equal(t, "https://example.com", req["url"])
+	require.Equal(t, []any{map[string]any{"frozen_amount": float64(100), "frozen_days": float64(30)}}, req["frozen_supply"])
+	require.Equal(t, float64(1_800_000_000_000), req["start_time"])
+	require.Equal(t, true, req["visible"])
+}
+
+// The issuer goes as hex without "visible", so the answer's bytes fields are
+// hex too and decode like any other asset listing.
+func TestHTTPAssetIssueByAccountSendsHexAddress(t *testing.T) {
+	tr, lastReq := newStubTransportAtPath(t, "/wallet/getassetissuebyaccount", http.StatusOK, `{"assetIssue":[`+liveAssetIssue+`]}`)
+
+	list, err := tr.GetAssetIssueByAccount(t.Context(), mustDecode(t, bttOwner))
+	require.NoError(t, err)
+	require.Len(t, list.GetAssetIssue(), 1)
+	require.Equal(t, "BitTorrent", string(list.GetAssetIssue()[0].GetName()))
+
+	require.Equal(t, map[string]any{"address": "4137fa1a56eb8c503624701d776d95f6dae1d9f0d6"}, *lastReq)
+}
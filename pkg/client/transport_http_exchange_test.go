@@ -0,0 +1,58 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/pkg/tronutils"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+)
+
+// With "visible" set, java-tron reads exchange token ids as text, so TRX goes
+// as "_" rather than "5f".
+func TestHTTPExchangeCreateSendsTokenIDsAsText(t *testing.T) {
+	tr, lastReq := newStubTransportAtPath(t, "/wallet/exchangecreate", http.StatusOK, `{"Error":"No enough balance for exchange create fee!"}`)
+
+	_, err := tr.ExchangeCreate(t.Context(), &core.ExchangeCreateContract{
+		OwnerAddress:       mustDecode(t, testAddr),
+		FirstTokenId:       []byte(ExchangeTRX),
+		FirstTokenBalance:  1_000_000,
+		SecondTokenId:      []byte("1000001"),
+		SecondTokenBalance: 100,
+	})
+	require.ErrorContains(t, err, "exchange create fee")
+
+	require.NotNil(t, *lastReq)
+	require.Equal(t, testAddr, (*lastReq)["owner_address"])
+	require.Equal(t, "_", (*lastReq)["first_token_id"])
+	require.Equal(t, "1000001", (*lastReq)["second_token_id"])
+	require.Equal(t, true, (*lastReq)["visible"])
+}
+
+// The reads go without "visible": the creator and the token ids come back hex.
+func TestHTTPListExchangesDecodes(t *testing.T) {
+	tr, _ := newStubTransportAtPath(t, "/wallet/listexchanges", http.StatusOK,
+		`{"exchanges":[{"exchange_id": 1,"creator_address": "4137fa1a56eb8c503624701d776d95f6dae1d9f0d6","create_time": 1539673398000,`+
+			`"first_token_id": "31303030303031","first_token_balance": 100,"second_token_id": "5f","second_token_balance": 1000000}]}`)
+
+	list, err := tr.ListExchanges(t.Context())
+	require.NoError(t, err)
+	require.Len(t, list.GetExchanges(), 1)
+
+	e := list.GetExchanges()[0]
+	require.Equal(t, int64(1), e.GetExchangeId())
+	require.Equal(t, bttOwner, tronutils.EncodeCheck(e.GetCreatorAddress()))
+	require.Equal(t, "1000001", string(e.GetFirstTokenId()))
+	require.Equal(t, ExchangeTRX, string(e.GetSecondTokenId()))
+	require.Equal(t, int64(1_000_000), e.GetSecondTokenBalance())
+}
+
+func TestHTTPGetExchangeByIdUnknown(t *testing.T) {
+	tr, lastReq := newStubTransportAtPath(t, "/wallet/getexchangebyid", http.StatusOK, `{}`)
+
+	e, err := tr.GetExchangeById(t.Context(), 9)
+	require.NoError(t, err)
+	require.Zero(t, e.GetExchangeId())
+	require.Equal(t, float64(9), (*lastReq)["id"])
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/pkg/tronutils"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+)
+
+// A proposal as the node renders it without "visible": hex addresses, the
+// parameter map as a list of pairs and the state by its enum name.
+const liveProposal = `{"proposal_id": 92,"proposer_address": "4137fa1a56eb8c503624701d776d95f6dae1d9f0d6",` +
+	`"parameters": [{"key": 70,"value": 14}],"expiration_time": 1709510400000,"create_time": 1709251200000,` +
+	`"approvals": ["4137fa1a56eb8c503624701d776d95f6dae1d9f0d6"],"state": "APPROVED"}`
+
+// java-tron reads a map from a list of key/value pairs; a JSON object is not
+// a map to it.
+func TestHTTPProposalCreateSendsParameterList(t *testing.T) {
+	tr, lastReq := newStubTransportAtPath(t, "/wallet/proposalcreate", http.StatusOK, `{"Error":"Witness[41] not exists"}`)
+
+	_, _ = tr.ProposalCreate(t.Context(), &core.ProposalCreateContract{
+		OwnerAddress: mustDecode(t, testAddr),
+		Parameters:   map[int64]int64{70: 14, 11: 210},
+	})
+
+	require.NotNil(t, *lastReq)
+	require.Equal(t, testAddr, (*lastReq)["owner_address"])
+	require.Equal(t, true, (*lastReq)["visible"])
+	require.Equal(t, []any{
+		map[string]any{"key": float64(11), "value": float64(210)},
+		map[string]any{"key": float64(70), "value": float64(14)},
+	}, (*lastReq)["parameters"], "in key order")
+}
+
+func TestHTTPListProposalsDecodes(t *testing.T) {
+	tr, _ := newStubTransportAtPath(t, "/wallet/listproposals", http.StatusOK, `{"proposals":[`+liveProposal+`,{"proposal_id": 93}]}`)
+
+	list, err := tr.ListProposals(t.Context())
+	require.NoError(t, err)
+	require.Len(t, list.GetProposals(), 2)
+
+	p := list.GetProposals()[0]
+	require.Equal(t, int64(92), p.GetProposalId())
+	require.Equal(t, bttOwner, tronutils.EncodeCheck(p.GetProposerAddress()))
+	require.Equal(t, map[int64]int64{70: 14}, p.GetParameters())
+	require.Len(t, p.GetApprovals(), 1)
+	require.Equal(t, "4137fa1a56eb8c503624701d776d95f6dae1d9f0d6", hex.EncodeToString(p.GetApprovals()[0]))
+	require.Equal(t, core.Proposal_APPROVED, p.GetState())
+
+	require.Equal(t, core.Proposal_PENDING, list.GetProposals()[1].GetState(), "the node leaves a pending state out")
+}
+
+func TestHTTPListProposalsRefusesUnknownState(t *testing.T) {
+	tr, _ := newStubTransport(t, http.StatusOK, `{"proposals":[{"proposal_id": 1,"state": "VETOED"}]}`)
+
+	_, err := tr.ListProposals(t.Context())
+	require.ErrorContains(t, err, "VETOED")
+}
+
+func TestHTTPGetProposalByIdSendsId(t *testing.T) {
+	tr, lastReq := newStubTransportAtPath(t, "/wallet/getproposalbyid", http.StatusOK, `{}`)
+
+	p, err := tr.GetProposalById(t.Context(), 92)
+	require.NoError(t, err)
+	require.Zero(t, p.GetProposalId(), "an unknown id is an empty object")
+
+	require.NotNil(t, *lastReq)
+	require.Equal(t, float64(92), (*lastReq)["id"])
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+)
+
+// liveFreezeResponse's transaction as /wallet/gettransactionbyid returns it
+// once signed and mined.
+const liveTransactionByID = `{"ret":[{"contractRet":"SUCCESS"}],"signature":["0a1b2c3d"],"txID":"233666eb2d145d3c577a308b362d3bc101cc8e0beb018b901ea819e099cb8cfc",` +
+	`"raw_data":{"contract":[{"parameter":{"value":{"owner_address":"41a614f803b6fd780986a42c78ec9c7f77e6ded13c","frozen_balance":1000000,"resource":"ENERGY"},"type_url":"type.googleapis.com/protocol.FreezeBalanceV2Contract"},"type":"FreezeBalanceV2Contract"}],"ref_block_bytes":"cc02","ref_block_hash":"c1171222b68f923e","expiration":1785238218000,"timestamp":1785238160928},` +
+	`"raw_data_hex":"0a02cc022208c1171222b68f923e4090caf5c3fa335a59083612550a34747970652e676f6f676c65617069732e636f6d2f70726f746f636f6c2e467265657a6542616c616e63655632436f6e7472616374121d0a1541a614f803b6fd780986a42c78ec9c7f77e6ded13c10c0843d180170a08cf2c3fa33"}`
+
+func TestHTTPGetTransactionByIdReadsRawDataHex(t *testing.T) {
+	tr, lastReq := newStubTransportAtPath(t, "/wallet/gettransactionbyid", http.StatusOK, liveTransactionByID)
+
+	id, err := hex.DecodeString("233666eb2d145d3c577a308b362d3bc101cc8e0beb018b901ea819e099cb8cfc")
+	require.NoError(t, err)
+	tx, err := tr.GetTransactionById(t.Context(), id)
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(id), (*lastReq)["value"])
+
+	raw := tx.GetRawData()
+	require.Equal(t, int64(1785238218000), raw.GetExpiration())
+	require.Equal(t, []byte{0xcc, 0x02}, raw.GetRefBlockBytes())
+	require.Len(t, raw.GetContract(), 1)
+	require.Equal(t, core.Transaction_Contract_FreezeBalanceV2Contract, raw.GetContract()[0].GetType())
+
+	freeze := &core.FreezeBalanceV2Contract{}
+	require.NoError(t, raw.GetContract()[0].GetParameter().UnmarshalTo(freeze))
+	require.Equal(t, int64(1_000_000), freeze.GetFrozenBalance())
+
+	require.Equal(t, [][]byte{{0x0a, 0x1b, 0x2c, 0x3d}}, tx.GetSignature())
+	require.Len(t, tx.GetRet(), 1)
+	require.Equal(t, core.Transaction_Result_SUCCESS, tx.GetRet()[0].GetContractRet())
+}
+
+func TestHTTPGetTransactionByIdUnknown(t *testing.T) {
+	tr, _ := newStubTransport(t, http.StatusOK, `{}`)
+
+	tx, err := tr.GetTransactionById(t.Context(), make([]byte, 32))
+	require.NoError(t, err)
+	require.Nil(t, tx.GetRawData())
+}
+
+func TestHTTPGetTransactionByIdBadRawDataHex(t *testing.T) {
+	tr, _ := newStubTransport(t, http.StatusOK, `{"raw_data_hex":"zz"}`)
+
+	_, err := tr.GetTransactionById(t.Context(), make([]byte, 32))
+	require.ErrorContains(t, err, "decode raw_data_hex")
+}
+
+// The id, the log address and topics and the contract result arrive hex; read
+// as base64 the id would not match the hash asked for.
+func TestHTTPGetTransactionInfoByIdDecodesHex(t *testing.T) {
+	const id = "233666eb2d145d3c577a308b362d3bc101cc8e0beb018b901ea819e099cb8cfc"
+	const topic = "ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	tr, _ := newStubTransportAtPath(t, "/wallet/gettransactioninfobyid", http.StatusOK,
+		`{"id":"`+id+`","fee":345000,"blockNumber":62000000,"blockTimeStamp":1716000000000,"contractResult":["0000000000000000000000000000000000000000000000000000000000000001"],`+
+			`"contract_address":"41a614f803b6fd780986a42c78ec9c7f77e6ded13c","receipt":{"energy_usage_total":13045,"result":"SUCCESS"},`+
+			`"log":[{"address":"a614f803b6fd780986a42c78ec9c7f77e6ded13c","topics":["`+topic+`"],"data":"00000000000000000000000000000000000000000000000000000000000f4240"}]}`)
+
+	want, err := hex.DecodeString(id)
+	require.NoError(t, err)
+	info, err := tr.GetTransactionInfoById(t.Context(), want)
+	require.NoError(t, err)
+
+	require.Equal(t, want, info.GetId())
+	require.Equal(t, int64(345_000), info.GetFee())
+	require.Equal(t, int64(13_045), info.GetReceipt().GetEnergyUsageTotal())
+	require.Len(t, info.GetContractResult(), 1)
+	require.Len(t, info.GetContractResult()[0], 32)
+	require.Len(t, info.GetLog(), 1)
+	require.Len(t, info.GetLog()[0].GetAddress(), 20)
+	require.Equal(t, topic, hex.EncodeToString(info.GetLog()[0].GetTopics()[0]))
+}
+
+func TestHTTPTransportTLSConfig(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	untrusted, err := NewHTTPTransport(NodeConfig{Protocol: ProtocolHTTP, Address: srv.URL})
+	require.NoError(t, err)
+	_, err = untrusted.GetTransactionById(t.Context(), make([]byte, 32))
+	require.Error(t, err, "the test server's certificate is not in the system roots")
+
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+	trusted, err := NewHTTPTransport(NodeConfig{
+		Protocol:  ProtocolHTTP,
+		Address:   srv.URL,
+		TLSConfig: &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12},
+	})
+	require.NoError(t, err)
+	_, err = trusted.GetTransactionById(t.Context(), make([]byte, 32))
+	require.NoError(t, err)
+}
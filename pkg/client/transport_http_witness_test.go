@@ -0,0 +1,53 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/pkg/tronutils"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+)
+
+// With "visible" set, java-tron reads a witness url as text rather than hex.
+func TestHTTPCreateWitnessSendsURLAsText(t *testing.T) {
+	tr, lastReq := newStubTransportAtPath(t, "/wallet/createwitness", http.StatusOK, `{"Error":"Witness[41] has existed"}`)
+
+	_, _ = tr.CreateWitness(t.Context(), &core.WitnessCreateContract{
+		OwnerAddress: mustDecode(t, testAddr),
+		Url:          []byte("https://sr.example"),
+	})
+
+	require.NotNil(t, *lastReq)
+	require.Equal(t, testAddr, (*lastReq)["owner_address"])
+	require.Equal(t, "https://sr.example", (*lastReq)["url"])
+	require.Equal(t, true, (*lastReq)["visible"])
+}
+
+// The lowercase /wallet/updatebrokerage is not served.
+func TestHTTPUpdateBrokerageUsesCamelCasePath(t *testing.T) {
+	tr, lastReq := newStubTransportAtPath(t, "/wallet/updateBrokerage", http.StatusOK, `{"Error":"Not existed witness:41"}`)
+
+	_, err := tr.UpdateBrokerage(t.Context(), &core.UpdateBrokerageContract{
+		OwnerAddress: mustDecode(t, testAddr),
+		Brokerage:    30,
+	})
+	require.ErrorContains(t, err, "Not existed witness")
+
+	require.NotNil(t, *lastReq)
+	require.Equal(t, float64(30), (*lastReq)["brokerage"])
+}
+
+func TestHTTPGetPaginatedNowWitnessListDecodes(t *testing.T) {
+	tr, lastReq := newStubTransportAtPath(t, "/wallet/getpaginatednowwitnesslist", http.StatusOK,
+		`{"witnesses":[{"address":"4137fa1a56eb8c503624701d776d95f6dae1d9f0d6","voteCount":1200,"url":"https://sr.example"}]}`)
+
+	list, err := tr.GetPaginatedNowWitnessList(t.Context(), &api.PaginatedMessage{Offset: 0, Limit: 27})
+	require.NoError(t, err)
+	require.Equal(t, float64(27), (*lastReq)["limit"])
+	require.Len(t, list.GetWitnesses(), 1)
+	require.Equal(t, bttOwner, tronutils.EncodeCheck(list.GetWitnesses()[0].GetAddress()))
+	require.Equal(t, int64(1200), list.GetWitnesses()[0].GetVoteCount())
+	require.Equal(t, "https://sr.example", list.GetWitnesses()[0].GetUrl())
+}
@@ -0,0 +1,492 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/sxwebdev/gotron/pkg/tronutils"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/protobuf/proto"
+)
+
+// JSONRPCTransport implements Transport over the Ethereum-compatible JSON-RPC
+// endpoint of a Tron node - /jsonrpc, which some providers offer alone. The
+// eth_* methods cover only part of Transport:
+//
+//   - GetNowBlock, GetBlockByNum, GetBlockByLimitNext and GetBlockByLatestNum
+//     call eth_getBlockByNumber, one request per block; GetBlockById calls
+//     eth_getBlockByHash. A block carries its header - number, timestamp,
+//     parent hash, transaction root, witness - and the ids of its
+//     transactions, but not the transactions themselves: GetBlockById, whose
+//     core.Block has no place for an id, returns the header alone.
+//   - GetTransactionInfoById calls eth_getTransactionReceipt: the id, block
+//     number, result, created contract, energy used and logs. The fee is not
+//     reported.
+//   - TriggerConstantContract calls eth_call. EnergyUsed is left zero unless
+//     the call carries CallEnergyUsed, which adds an eth_estimateGas; a
+//     failed estimate leaves it zero rather than failing the call.
+//     EstimateEnergy calls eth_estimateGas.
+//
+// Every other method fails with ErrUnsupportedByJSONRPC without sending
+// anything: GetTransactionById, as eth_getTransactionByHash does not say which
+// Tron contract a transaction carries, and GetTransactionInfoByBlockNum, as
+// eth_getLogs sees only the transactions that emitted an event, and nothing of
+// their fees. GetBlockLogs returns what eth_getLogs does see. A JSON-RPC node mixed with
+// gRPC or HTTP nodes in one tier fails the calls it cannot serve, so give it
+// a tier of its own, or the whole pool of a Client that only reads blocks and
+// contracts.
+//
+// Addresses are converted both ways: the 21-byte Tron addresses of a request
+// are sent as 0x-prefixed 20-byte hex, and those of an answer get their 0x41
+// prefix back. Log addresses stay 20 bytes, as gRPC reports them.
+type JSONRPCTransport struct {
+	methodSet
+
+	url        string
+	httpClient *http.Client
+	headers    map[string]string
+	limiter    *nodeLimiter
+	nextID     atomic.Uint64
+}
+
+// NewJSONRPCTransport creates a JSON-RPC transport. NodeConfig.Address is the
+// URL of the endpoint, path included: "https://api.trongrid.io/jsonrpc". For
+// a NodeConfig.Solidity node, use the JSON-RPC endpoint of a solidity node,
+// which answers with solidified state.
+func NewJSONRPCTransport(cfg NodeConfig) (*JSONRPCTransport, error) {
+	t := &JSONRPCTransport{
+		url:        cfg.Address,
+		httpClient: newHTTPClient(cfg),
+		headers:    cfg.Headers,
+		limiter:    newNodeLimiter(cfg),
+	}
+	t.handle = t.serve
+	return t, nil
+}
+
+// Close closes the JSON-RPC transport (no-op for HTTP)
+func (t *JSONRPCTransport) Close() error {
+	return nil
+}
+
+// jsonRPCServerError is the error code java-tron answers an eth_call or
+// eth_estimateGas with when the VM did not complete the call - a revert, an
+// invalid opcode, energy running out.
+const jsonRPCServerError = -32000
+
+// JSONRPCError is an error object a JSON-RPC node answered with, as the Err
+// of a TransportError.
+type JSONRPCError struct {
+	Code    int
+	Message string
+	// Data is the data member of the error when it is a string - for a call
+	// the VM reverted, the revert data in 0x-prefixed hex.
+	Data string
+}
+
+func (e *JSONRPCError) Error() string {
+	return fmt.Sprintf("json-rpc error %d: %s", e.Code, e.Message)
+}
+
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      uint64 `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int             `json:"code"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	} `json:"error"`
+}
+
+// serve performs the Transport call named method; see handler.
+func (t *JSONRPCTransport) serve(ctx context.Context, method string, request proto.Message) (proto.Message, error) {
+	switch method {
+	case "GetNowBlock":
+		return t.blockByNumber(ctx, "latest")
+	case "GetBlockByNum":
+		return t.blockByNumber(ctx, hexNumber(request.(*api.NumberMessage).GetNum()))
+	case "GetBlockById":
+		return t.blockByHash(ctx, request.(*api.BytesMessage).GetValue())
+	case "GetBlockByLimitNext":
+		limit := request.(*api.BlockLimit)
+		return t.blockRange(ctx, limit.GetStartNum(), limit.GetEndNum())
+	case "GetBlockByLatestNum":
+		return t.latestBlocks(ctx, request.(*api.NumberMessage).GetNum())
+	case "GetTransactionInfoById":
+		return t.transactionInfo(ctx, request.(*api.BytesMessage).GetValue())
+	case "TriggerConstantContract":
+		return t.constantCall(ctx, request.(*core.TriggerSmartContract))
+	case "EstimateEnergy":
+		return t.estimateEnergy(ctx, request.(*core.TriggerSmartContract))
+	}
+	return nil, t.wrapErr(method, ErrUnsupportedByJSONRPC)
+}
+
+// call sends one JSON-RPC request and decodes its result into result. An
+// error object in the answer is returned as a *JSONRPCError.
+func (t *JSONRPCTransport) call(ctx context.Context, method string, result any, params ...any) error {
+	if params == nil {
+		params = []any{}
+	}
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: t.nextID.Add(1), Method: method, Params: params})
+	if err != nil {
+		return t.wrapErr(method, fmt.Errorf("marshal request body: %w", err))
+	}
+
+	respBody, err := postJSON(ctx, t.httpClient, t.url, t.headers, t.limiter, body)
+	if err != nil {
+		return t.wrapErr(method, err)
+	}
+
+	var rpcResp rpcResponse
+	if err := json.Unmarshal(respBody, &rpcResp); err != nil {
+		return t.wrapErr(method, fmt.Errorf("unmarshal response: %w (body: %s)", err, string(respBody)))
+	}
+	if e := rpcResp.Error; e != nil {
+		rpcErr := &JSONRPCError{Code: e.Code, Message: e.Message}
+		// Data that is not a string is left out.
+		_ = json.Unmarshal(e.Data, &rpcErr.Data)
+		return t.wrapErr(method, rpcErr)
+	}
+	if result != nil && len(rpcResp.Result) > 0 {
+		if err := json.Unmarshal(rpcResp.Result, result); err != nil {
+			return t.wrapErr(method, fmt.Errorf("unmarshal result: %w (body: %s)", err, string(respBody)))
+		}
+	}
+
+	return nil
+}
+
+func (t *JSONRPCTransport) wrapErr(method string, err error) error {
+	return &TransportError{
+		Host:     t.url,
+		Protocol: "jsonrpc",
+		Method:   method,
+		Err:      err,
+	}
+}
+
+// Block operations
+
+// rpcBlock is a block as eth_getBlockByNumber returns it without full
+// transactions. java-tron reports the timestamp in seconds and the witness
+// as the 20-byte miner.
+type rpcBlock struct {
+	Number           hexInt     `json:"number"`
+	Hash             hexBytes   `json:"hash"`
+	ParentHash       hexBytes   `json:"parentHash"`
+	TransactionsRoot hexBytes   `json:"transactionsRoot"`
+	Miner            hexBytes   `json:"miner"`
+	Timestamp        hexInt     `json:"timestamp"`
+	Transactions     []hexBytes `json:"transactions"`
+}
+
+func (b *rpcBlock) header() *core.BlockHeader {
+	return &core.BlockHeader{
+		RawData: &core.BlockHeaderRaw{
+			Number:         int64(b.Number),
+			Timestamp:      int64(b.Timestamp) * 1000,
+			ParentHash:     b.ParentHash,
+			TxTrieRoot:     b.TransactionsRoot,
+			WitnessAddress: tronAddress(b.Miner),
+		},
+	}
+}
+
+func (b *rpcBlock) extention() *api.BlockExtention {
+	block := &api.BlockExtention{
+		Blockid:     b.Hash,
+		BlockHeader: b.header(),
+	}
+	for _, id := range b.Transactions {
+		block.Transactions = append(block.Transactions, &api.TransactionExtention{Txid: id})
+	}
+	return block
+}
+
+// blockByNumber returns the block tag names - a 0x-prefixed number or
+// "latest" - and an empty block when the node has none, like gRPC does.
+func (t *JSONRPCTransport) blockByNumber(ctx context.Context, tag string) (*api.BlockExtention, error) {
+	var block *rpcBlock
+	if err := t.call(ctx, "eth_getBlockByNumber", &block, tag, false); err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return &api.BlockExtention{}, nil
+	}
+	return block.extention(), nil
+}
+
+func (t *JSONRPCTransport) blockByHash(ctx context.Context, id []byte) (*core.Block, error) {
+	var block *rpcBlock
+	if err := t.call(ctx, "eth_getBlockByHash", &block, hexData(id), false); err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return &core.Block{}, nil
+	}
+	return &core.Block{BlockHeader: block.header()}, nil
+}
+
+// blockRange returns the blocks from start up to, not including, end. It
+// stops at the first block the node does not have yet.
+func (t *JSONRPCTransport) blockRange(ctx context.Context, start, end int64) (*api.BlockListExtention, error) {
+	list := &api.BlockListExtention{}
+	for num := start; num < end; num++ {
+		var block *rpcBlock
+		if err := t.call(ctx, "eth_getBlockByNumber", &block, hexNumber(num), false); err != nil {
+			return nil, err
+		}
+		if block == nil {
+			break
+		}
+		list.Block = append(list.Block, block.extention())
+	}
+	return list, nil
+}
+
+// latestBlocks returns the last num blocks, oldest first.
+func (t *JSONRPCTransport) latestBlocks(ctx context.Context, num int64) (*api.BlockListExtention, error) {
+	var head hexInt
+	if err := t.call(ctx, "eth_blockNumber", &head); err != nil {
+		return nil, err
+	}
+	start := max(int64(head)-num+1, 0)
+	return t.blockRange(ctx, start, int64(head)+1)
+}
+
+// Transaction operations
+
+type rpcLog struct {
+	TransactionHash hexBytes   `json:"transactionHash"`
+	BlockNumber     hexInt     `json:"blockNumber"`
+	Address         hexBytes   `json:"address"`
+	Topics          []hexBytes `json:"topics"`
+	Data            hexBytes   `json:"data"`
+}
+
+func (l *rpcLog) log() *core.TransactionInfo_Log {
+	log := &core.TransactionInfo_Log{Address: l.Address, Data: l.Data}
+	for _, topic := range l.Topics {
+		log.Topics = append(log.Topics, topic)
+	}
+	return log
+}
+
+type rpcReceipt struct {
+	TransactionHash hexBytes `json:"transactionHash"`
+	BlockNumber     hexInt   `json:"blockNumber"`
+	ContractAddress hexBytes `json:"contractAddress"`
+	GasUsed         hexInt   `json:"gasUsed"`
+	Status          hexInt   `json:"status"`
+	Logs            []rpcLog `json:"logs"`
+}
+
+// transactionInfo returns the info of the transaction id, and an empty one
+// when the node has no receipt for it, like gRPC does.
+func (t *JSONRPCTransport) transactionInfo(ctx context.Context, id []byte) (*core.TransactionInfo, error) {
+	var receipt *rpcReceipt
+	if err := t.call(ctx, "eth_getTransactionReceipt", &receipt, hexData(id)); err != nil {
+		return nil, err
+	}
+	if receipt == nil {
+		return &core.TransactionInfo{}, nil
+	}
+
+	info := &core.TransactionInfo{
+		Id:              receipt.TransactionHash,
+		BlockNumber:     int64(receipt.BlockNumber),
+		ContractAddress: tronAddress(receipt.ContractAddress),
+		Receipt:         &core.ResourceReceipt{EnergyUsageTotal: int64(receipt.GasUsed)},
+	}
+	if receipt.Status != 1 {
+		info.Result = core.TransactionInfo_FAILED
+	}
+	for _, l := range receipt.Logs {
+		info.Log = append(info.Log, l.log())
+	}
+	return info, nil
+}
+
+// GetBlockLogs returns the transactions of block num that emitted an event,
+// each with its id, block number and logs, in the order the block lists them
+// - enough to follow TRC20 transfers, not to account for fees. It is not
+// GetTransactionInfoByBlockNum, which a JSONRPCTransport does not serve: the
+// transactions that emitted nothing are missing, and so are the receipts.
+func (t *JSONRPCTransport) GetBlockLogs(ctx context.Context, num int64) (*api.TransactionInfoList, error) {
+	filter := map[string]any{
+		"fromBlock": hexNumber(num),
+		"toBlock":   hexNumber(num),
+	}
+	var logs []rpcLog
+	if err := t.call(ctx, "eth_getLogs", &logs, filter); err != nil {
+		return nil, err
+	}
+
+	list := &api.TransactionInfoList{}
+	byID := make(map[string]*core.TransactionInfo)
+	for _, l := range logs {
+		info, ok := byID[string(l.TransactionHash)]
+		if !ok {
+			info = &core.TransactionInfo{Id: l.TransactionHash, BlockNumber: int64(l.BlockNumber)}
+			byID[string(l.TransactionHash)] = info
+			list.TransactionInfo = append(list.TransactionInfo, info)
+		}
+		info.Log = append(info.Log, l.log())
+	}
+	return list, nil
+}
+
+// Contract operations
+
+// callArgs returns the eth_call and eth_estimateGas arguments of contract.
+// The contract address is left out for a deployment, as HTTPTransport does.
+func callArgs(contract *core.TriggerSmartContract) map[string]any {
+	args := map[string]any{
+		"from": ethAddress(contract.GetOwnerAddress()),
+		"data": hexData(contract.GetData()),
+	}
+	if len(contract.GetContractAddress()) > 0 {
+		args["to"] = ethAddress(contract.GetContractAddress())
+	}
+	if contract.GetCallValue() > 0 {
+		args["value"] = hexNumber(contract.GetCallValue())
+	}
+	return args
+}
+
+// constantCall answers a call the VM did not complete the way the node's
+// TriggerConstantContract does: result true, with the failure in the message
+// and the revert data as the constant result, which Client.TriggerConstantContract
+// reports as ErrContractCallFailed. EnergyUsed is estimated for a call that
+// carries CallEnergyUsed only, and is left zero when the estimate fails: the
+// read itself succeeded.
+func (t *JSONRPCTransport) constantCall(ctx context.Context, contract *core.TriggerSmartContract) (*api.TransactionExtention, error) {
+	args := callArgs(contract)
+
+	var out hexBytes
+	err := t.call(ctx, "eth_call", &out, args, "latest")
+	if rpcErr, ok := vmError(err); ok {
+		result := &api.TransactionExtention{
+			Result: &api.Return{Result: true, Message: []byte(rpcErr.Message)},
+		}
+		if data, err := hex.DecodeString(strings.TrimPrefix(rpcErr.Data, "0x")); err == nil && len(data) > 0 {
+			result.ConstantResult = [][]byte{data}
+		}
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api.TransactionExtention{
+		Result:         &api.Return{Result: true},
+		ConstantResult: [][]byte{out},
+	}
+	if callOptionsFrom(ctx).energyUsed {
+		var energy hexInt
+		if err := t.call(ctx, "eth_estimateGas", &energy, args); err == nil {
+			result.EnergyUsed = int64(energy)
+		}
+	}
+	return result, nil
+}
+
+func (t *JSONRPCTransport) estimateEnergy(ctx context.Context, contract *core.TriggerSmartContract) (*api.EstimateEnergyMessage, error) {
+	var energy hexInt
+	err := t.call(ctx, "eth_estimateGas", &energy, callArgs(contract))
+	if rpcErr, ok := vmError(err); ok {
+		return &api.EstimateEnergyMessage{
+			Result: &api.Return{Code: api.Return_CONTRACT_EXE_ERROR, Message: []byte(rpcErr.Message)},
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.EstimateEnergyMessage{
+		Result:         &api.Return{Result: true},
+		EnergyRequired: int64(energy),
+	}, nil
+}
+
+// vmError returns the error object of err when it reports a call the VM did
+// not complete.
+func vmError(err error) (*JSONRPCError, bool) {
+	rpcErr, ok := errors.AsType[*JSONRPCError](err)
+	return rpcErr, ok && rpcErr.Code == jsonRPCServerError
+}
+
+// Encoding
+
+// hexInt is a JSON-RPC quantity: a 0x-prefixed hex number.
+type hexInt int64
+
+func (n *hexInt) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil || s == nil {
+		return err
+	}
+	v, err := strconv.ParseInt(strings.TrimPrefix(*s, "0x"), 16, 64)
+	if err != nil {
+		return fmt.Errorf("invalid quantity %q: %w", *s, err)
+	}
+	*n = hexInt(v)
+	return nil
+}
+
+// hexBytes is JSON-RPC data: 0x-prefixed hex bytes.
+type hexBytes []byte
+
+func (b *hexBytes) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil || s == nil {
+		return err
+	}
+	v, err := tronutils.FromHex(*s)
+	if err != nil {
+		return fmt.Errorf("invalid data %q: %w", *s, err)
+	}
+	*b = v
+	return nil
+}
+
+func hexNumber(n int64) string {
+	return "0x" + strconv.FormatInt(n, 16)
+}
+
+func hexData(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+// ethAddress returns a Tron address the way JSON-RPC takes it: 20 bytes
+// without the 0x41 prefix.
+func ethAddress(addr []byte) string {
+	if len(addr) == tronutils.AddressLength && addr[0] == tronutils.TronBytePrefix {
+		addr = addr[1:]
+	}
+	return hexData(addr)
+}
+
+// tronAddress restores the 0x41 prefix of a 20-byte JSON-RPC address. Empty
+// stays empty.
+func tronAddress(addr []byte) []byte {
+	if len(addr) != tronutils.AddressLength-1 {
+		return addr
+	}
+	return append([]byte{tronutils.TronBytePrefix}, addr...)
+}
@@ -0,0 +1,290 @@
+package client
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/pkg/tronutils"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+)
+
+// jsonRPCStub is a JSON-RPC node answering each method with a canned result
+// or error object, recording the requests it receives.
+type jsonRPCStub struct {
+	mu       sync.Mutex
+	answers  map[string]string // method -> "result" or "error" member
+	requests []rpcRequest
+}
+
+func newJSONRPCStub(t *testing.T, answers map[string]string) (*JSONRPCTransport, *jsonRPCStub) {
+	t.Helper()
+	stub := &jsonRPCStub{answers: answers}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/jsonrpc", r.URL.Path)
+		raw, _ := io.ReadAll(r.Body)
+		var req rpcRequest
+		require.NoError(t, json.Unmarshal(raw, &req))
+		stub.mu.Lock()
+		stub.requests = append(stub.requests, req)
+		answer, ok := stub.answers[req.Method]
+		stub.mu.Unlock()
+		if !ok {
+			answer = `"error":{"code":-32601,"message":"method not found"}`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,%s}`, req.ID, answer)
+	}))
+	t.Cleanup(srv.Close)
+
+	tr, err := NewJSONRPCTransport(NodeConfig{Protocol: ProtocolJSONRPC, Address: srv.URL + "/jsonrpc"})
+	require.NoError(t, err)
+	return tr, stub
+}
+
+// calls returns the method and JSON params of every request received.
+func (s *jsonRPCStub) calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]string, len(s.requests))
+	for i, req := range s.requests {
+		params, _ := json.Marshal(req.Params)
+		out[i] = req.Method + " " + string(params)
+	}
+	return out
+}
+
+const (
+	jsonRPCBlockID = "0000000004c4b4018a7cc2a6e5e2f83bc6a1bd5eb7e5f1b07d6d3d7c5b7a6a9e"
+	jsonRPCTxID    = "7c2d4206c03a883dd9066d6c839d0deaef32dc5a0d9b15f6d06e506906c90332"
+)
+
+// A block as java-tron's eth_getBlockByNumber returns it: seconds, 20-byte
+// miner, ids only.
+const jsonRPCBlock = `"result":{"number":"0x4c4b401","hash":"0x` + jsonRPCBlockID + `",
+	"parentHash":"0x0000000004c4b400aa","transactionsRoot":"0x01ff",
+	"miner":"0xa614f803b6fd780986a42c78ec9c7f77e6ded13c","timestamp":"0x6523a1f0",
+	"transactions":["0x` + jsonRPCTxID + `"],"gasLimit":"0x0","uncles":[]}`
+
+func TestJSONRPCGetBlockByNum(t *testing.T) {
+	tr, stub := newJSONRPCStub(t, map[string]string{"eth_getBlockByNumber": jsonRPCBlock})
+
+	block, err := tr.GetBlockByNum(t.Context(), 80_000_001)
+	require.NoError(t, err)
+	assert.Equal(t, []string{`eth_getBlockByNumber ["0x4c4b401",false]`}, stub.calls())
+
+	raw := block.GetBlockHeader().GetRawData()
+	assert.Equal(t, int64(80_000_001), raw.GetNumber())
+	assert.Equal(t, int64(0x6523a1f0)*1000, raw.GetTimestamp(), "seconds become milliseconds")
+	assert.Equal(t, "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", tronutils.EncodeCheck(raw.GetWitnessAddress()))
+	assert.Equal(t, jsonRPCBlockID, hex.EncodeToString(block.GetBlockid()))
+	require.Len(t, block.GetTransactions(), 1)
+	assert.Equal(t, jsonRPCTxID, hex.EncodeToString(block.GetTransactions()[0].GetTxid()))
+
+	block, err = tr.GetNowBlock(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, int64(80_000_001), block.GetBlockHeader().GetRawData().GetNumber())
+	assert.Equal(t, `eth_getBlockByNumber ["latest",false]`, stub.calls()[1])
+}
+
+func TestJSONRPCBlockNotFound(t *testing.T) {
+	tr, stub := newJSONRPCStub(t, map[string]string{
+		"eth_blockNumber":      `"result":"0x4c4b401"`,
+		"eth_getBlockByNumber": `"result":null`,
+	})
+
+	block, err := tr.GetBlockByNum(t.Context(), 90_000_000)
+	require.NoError(t, err)
+	assert.Nil(t, block.GetBlockHeader(), "an unknown block is empty, as over gRPC")
+
+	list, err := tr.GetBlockByLatestNum(t.Context(), 3)
+	require.NoError(t, err)
+	assert.Empty(t, list.GetBlock())
+	assert.Equal(t, []string{
+		`eth_getBlockByNumber ["0x55d4a80",false]`,
+		`eth_blockNumber []`,
+		`eth_getBlockByNumber ["0x4c4b3ff",false]`,
+	}, stub.calls(), "the range stops at the first block the node does not have")
+}
+
+func TestJSONRPCGetTransactionInfoById(t *testing.T) {
+	tr, stub := newJSONRPCStub(t, map[string]string{
+		"eth_getTransactionReceipt": `"result":{"transactionHash":"0x` + jsonRPCTxID + `",
+			"blockNumber":"0x4c4b401","gasUsed":"0x3458","status":"0x1","contractAddress":null,
+			"logs":[{"address":"0xa614f803b6fd780986a42c78ec9c7f77e6ded13c",
+				"topics":["0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"],
+				"data":"0x00000000000000000000000000000000000000000000000000000000000f4240"}]}`,
+	})
+	id, err := hex.DecodeString(jsonRPCTxID)
+	require.NoError(t, err)
+
+	info, err := tr.GetTransactionInfoById(t.Context(), id)
+	require.NoError(t, err)
+	assert.Equal(t, []string{`eth_getTransactionReceipt ["0x` + jsonRPCTxID + `"]`}, stub.calls())
+	assert.Equal(t, id, info.GetId())
+	assert.Equal(t, int64(80_000_001), info.GetBlockNumber())
+	assert.Equal(t, core.TransactionInfo_SUCESS, info.GetResult())
+	assert.Equal(t, int64(0x3458), info.GetReceipt().GetEnergyUsageTotal())
+	assert.Empty(t, info.GetContractAddress())
+	require.Len(t, info.GetLog(), 1)
+	assert.Len(t, info.GetLog()[0].GetAddress(), 20, "log addresses stay 20 bytes, as over gRPC")
+	assert.Len(t, info.GetLog()[0].GetTopics(), 1)
+
+	stub.answers["eth_getTransactionReceipt"] = `"result":null`
+	info, err = tr.GetTransactionInfoById(t.Context(), id)
+	require.NoError(t, err)
+	assert.Empty(t, info.GetId(), "an unknown transaction has empty info, as over gRPC")
+}
+
+func TestJSONRPCGetBlockLogs(t *testing.T) {
+	log := func(tx, data string) string {
+		return `{"transactionHash":"0x` + tx + `","blockNumber":"0x4c4b401",
+			"address":"0xa614f803b6fd780986a42c78ec9c7f77e6ded13c","topics":[],"data":"0x` + data + `"}`
+	}
+	other := "aa" + jsonRPCTxID[2:]
+	tr, stub := newJSONRPCStub(t, map[string]string{
+		"eth_getLogs": `"result":[` + log(jsonRPCTxID, "01") + `,` + log(other, "02") + `,` + log(jsonRPCTxID, "03") + `]`,
+	})
+
+	_, err := tr.GetTransactionInfoByBlockNum(t.Context(), 80_000_001)
+	require.ErrorIs(t, err, ErrUnsupportedByJSONRPC, "the logs are not the block's transaction info")
+	assert.Empty(t, stub.calls())
+
+	list, err := tr.GetBlockLogs(t.Context(), 80_000_001)
+	require.NoError(t, err)
+	assert.Equal(t, []string{`eth_getLogs [{"fromBlock":"0x4c4b401","toBlock":"0x4c4b401"}]`}, stub.calls())
+	require.Len(t, list.GetTransactionInfo(), 2)
+	first := list.GetTransactionInfo()[0]
+	assert.Equal(t, jsonRPCTxID, hex.EncodeToString(first.GetId()))
+	assert.Equal(t, int64(80_000_001), first.GetBlockNumber())
+	require.Len(t, first.GetLog(), 2, "logs are grouped by transaction")
+	assert.Equal(t, []byte{3}, first.GetLog()[1].GetData())
+	assert.Equal(t, other, hex.EncodeToString(list.GetTransactionInfo()[1].GetId()))
+}
+
+func TestJSONRPCTriggerConstantContract(t *testing.T) {
+	tr, stub := newJSONRPCStub(t, map[string]string{
+		"eth_call":        `"result":"0x00000000000000000000000000000000000000000000000000000000000f4240"`,
+		"eth_estimateGas": `"result":"0x3458"`,
+	})
+	contract := &core.TriggerSmartContract{
+		OwnerAddress:    mustDecode(t, triggerOwnerAddr),
+		ContractAddress: mustDecode(t, triggerContractAddr),
+		Data:            []byte{0x70, 0xa0, 0x82, 0x31},
+		CallValue:       16,
+	}
+
+	res, err := tr.TriggerConstantContract(t.Context(), contract)
+	require.NoError(t, err)
+	args := `{"data":"0x70a08231","from":"0xa614f803b6fd780986a42c78ec9c7f77e6ded13c","to":"0xea51342dabbb928ae1e576bd39eff8aaf070a8c6","value":"0x10"}`
+	assert.Equal(t, []string{`eth_call [` + args + `,"latest"]`}, stub.calls(),
+		"base58 addresses are sent as 20-byte hex, and a read is not estimated")
+	assert.True(t, res.GetResult().GetResult())
+	require.Len(t, res.GetConstantResult(), 1)
+	assert.Len(t, res.GetConstantResult()[0], 32)
+	assert.Zero(t, res.GetEnergyUsed())
+
+	res, err = tr.TriggerConstantContract(WithCallOptions(t.Context(), CallEnergyUsed()), contract)
+	require.NoError(t, err)
+	assert.Equal(t, `eth_estimateGas [`+args+`]`, stub.calls()[2])
+	assert.Equal(t, int64(0x3458), res.GetEnergyUsed())
+
+	stub.mu.Lock()
+	stub.answers["eth_estimateGas"] = `"error":{"code":-32601,"message":"method not found"}`
+	stub.mu.Unlock()
+	res, err = tr.TriggerConstantContract(WithCallOptions(t.Context(), CallEnergyUsed()), contract)
+	require.NoError(t, err, "a failed estimate does not fail the read")
+	assert.Len(t, res.GetConstantResult(), 1)
+	assert.Zero(t, res.GetEnergyUsed())
+	stub.mu.Lock()
+	stub.answers["eth_estimateGas"] = `"result":"0x3458"`
+	stub.mu.Unlock()
+
+	energy, err := tr.EstimateEnergy(t.Context(), contract)
+	require.NoError(t, err)
+	assert.True(t, energy.GetResult().GetResult())
+	assert.Equal(t, int64(0x3458), energy.GetEnergyRequired())
+}
+
+func TestJSONRPCTriggerConstantContractReverted(t *testing.T) {
+	tr, stub := newJSONRPCStub(t, map[string]string{
+		"eth_call":        `"error":{"code":-32000,"message":"REVERT opcode executed","data":"0x08c379a0"}`,
+		"eth_estimateGas": `"error":{"code":-32000,"message":"REVERT opcode executed","data":"0x08c379a0"}`,
+	})
+	contract := &core.TriggerSmartContract{
+		OwnerAddress:    mustDecode(t, triggerOwnerAddr),
+		ContractAddress: mustDecode(t, triggerContractAddr),
+	}
+
+	res, err := tr.TriggerConstantContract(WithCallOptions(t.Context(), CallEnergyUsed()), contract)
+	require.NoError(t, err, "a revert is an answer, as over HTTP")
+	assert.Equal(t, "REVERT opcode executed", string(res.GetResult().GetMessage()))
+	assert.Equal(t, [][]byte{{0x08, 0xc3, 0x79, 0xa0}}, res.GetConstantResult())
+	assert.Len(t, stub.calls(), 1, "no estimate for a reverted call")
+
+	energy, err := tr.EstimateEnergy(t.Context(), contract)
+	require.NoError(t, err)
+	assert.Equal(t, api.Return_CONTRACT_EXE_ERROR, energy.GetResult().GetCode())
+
+	c := &Client{transport: tr}
+	_, err = c.TriggerConstantContract(t.Context(), contract)
+	require.ErrorIs(t, err, ErrContractCallFailed)
+}
+
+func TestJSONRPCErrors(t *testing.T) {
+	tr, stub := newJSONRPCStub(t, map[string]string{
+		"eth_getBlockByNumber": `"error":{"code":-32602,"message":"invalid block number"}`,
+	})
+
+	_, err := tr.GetBlockByNum(t.Context(), 1)
+	rpcErr, ok := errors.AsType[*JSONRPCError](err)
+	require.True(t, ok, "got %v", err)
+	assert.Equal(t, -32602, rpcErr.Code)
+	te, ok := errors.AsType[*TransportError](err)
+	require.True(t, ok)
+	assert.Equal(t, "jsonrpc", te.Protocol)
+	assert.Equal(t, "eth_getBlockByNumber", te.Method)
+	assert.False(t, isNetworkError(err), "an error object is an answer")
+
+	for _, call := range []func() error{
+		func() error { _, err := tr.GetAccount(t.Context(), &core.Account{}); return err },
+		func() error { _, err := tr.FreezeBalanceV2(t.Context(), &core.FreezeBalanceV2Contract{}); return err },
+		func() error {
+			_, err := tr.GetDelegatedResourceV2(t.Context(), &api.DelegatedResourceMessage{})
+			return err
+		},
+		func() error { _, err := tr.BroadcastTransaction(t.Context(), &core.Transaction{}); return err },
+		func() error { _, err := tr.GetTransactionById(t.Context(), []byte{1}); return err },
+	} {
+		require.ErrorIs(t, call(), ErrUnsupportedByJSONRPC)
+	}
+	assert.Len(t, stub.calls(), 1, "unsupported calls send nothing")
+}
+
+func TestJSONRPCProtocol(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := New(Config{
+		Nodes:  []NodeConfig{{Protocol: ProtocolJSONRPC, Address: srv.URL + "/jsonrpc"}},
+		Health: HealthConfig{Disabled: true},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	_, err = c.GetLastBlock(t.Context())
+	statusErr, ok := errors.AsType[*HTTPStatusError](err)
+	require.True(t, ok, "got %v", err)
+	assert.Equal(t, http.StatusTooManyRequests, statusErr.Code)
+}
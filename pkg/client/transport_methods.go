@@ -0,0 +1,126 @@
+package client
+
+// methodKind says what a Transport method does to the chain. The routing
+// layers use it to decide whether a call may be sent to a node more than once.
+type methodKind int
+
+const (
+	// methodRead queries node state and changes nothing.
+	methodRead methodKind = iota
+	// methodWrite asks the node to build an unsigned transaction. Nothing
+	// reaches the chain, but the answer embeds the node's reference block, so
+	// two nodes never return the same transaction.
+	methodWrite
+	// methodBroadcast submits a signed transaction. Sending the same signed
+	// bytes twice is harmless: the transaction id is the hash of its raw data,
+	// so a second copy is rejected as a duplicate and never executes twice.
+	methodBroadcast
+)
+
+// transportMethods classifies every Transport method except Close, keyed by
+// the method name used in metrics labels. TestTransportMethodsCoverInterface
+// fails when a method is added to Transport without an entry here.
+var transportMethods = map[string]methodKind{
+	// Account operations
+	"GetAccount":              methodRead,
+	"GetAccountResource":      methodRead,
+	"CreateAccount":           methodWrite,
+	"AccountPermissionUpdate": methodWrite,
+
+	// Block operations
+	"GetNowBlock":                  methodRead,
+	"GetBlockByNum":                methodRead,
+	"GetBlockById":                 methodRead,
+	"GetBlockByLimitNext":          methodRead,
+	"GetBlockByLatestNum":          methodRead,
+	"GetTransactionInfoByBlockNum": methodRead,
+
+	// Transaction operations
+	"GetTransactionById":     methodRead,
+	"GetTransactionInfoById": methodRead,
+	"BroadcastTransaction":   methodBroadcast,
+	"CreateTransaction":      methodWrite,
+
+	// Contract operations
+	"TriggerContract":         methodWrite,
+	"TriggerConstantContract": methodRead,
+	"EstimateEnergy":          methodRead,
+	"DeployContract":          methodWrite,
+	"GetContract":             methodRead,
+	"UpdateSetting":           methodWrite,
+	"UpdateEnergyLimit":       methodWrite,
+
+	// Resource operations
+	"GetAccountResourceMessage":          methodRead,
+	"GetDelegatedResource":               methodRead,
+	"GetDelegatedResourceV2":             methodRead,
+	"GetDelegatedResourceAccountIndex":   methodRead,
+	"GetDelegatedResourceAccountIndexV2": methodRead,
+	"GetCanDelegatedMaxSize":             methodRead,
+	"DelegateResource":                   methodWrite,
+	"UnDelegateResource":                 methodWrite,
+
+	// Staking operations (Stake 2.0)
+	"FreezeBalanceV2":              methodWrite,
+	"UnfreezeBalanceV2":            methodWrite,
+	"WithdrawExpireUnfreeze":       methodWrite,
+	"CancelAllUnfreezeV2":          methodWrite,
+	"GetAvailableUnfreezeCount":    methodRead,
+	"GetCanWithdrawUnfreezeAmount": methodRead,
+
+	// Witness operations
+	"VoteWitnessAccount":         methodWrite,
+	"WithdrawBalance":            methodWrite,
+	"ListWitnesses":              methodRead,
+	"GetRewardInfo":              methodRead,
+	"GetBrokerageInfo":           methodRead,
+	"CreateWitness":              methodWrite,
+	"UpdateWitness":              methodWrite,
+	"UpdateBrokerage":            methodWrite,
+	"GetPaginatedNowWitnessList": methodRead,
+
+	// Asset operations
+	"GetAssetIssueById":          methodRead,
+	"GetAssetIssueListByName":    methodRead,
+	"GetAssetIssueByAccount":     methodRead,
+	"GetAssetIssueList":          methodRead,
+	"GetPaginatedAssetIssueList": methodRead,
+	"TransferAsset":              methodWrite,
+	"CreateAssetIssue":           methodWrite,
+	"ParticipateAssetIssue":      methodWrite,
+	"UpdateAsset":                methodWrite,
+	"UnfreezeAsset":              methodWrite,
+
+	// Proposal operations
+	"ProposalCreate":           methodWrite,
+	"ProposalApprove":          methodWrite,
+	"ProposalDelete":           methodWrite,
+	"ListProposals":            methodRead,
+	"GetPaginatedProposalList": methodRead,
+	"GetProposalById":          methodRead,
+
+	// Exchange operations
+	"ExchangeCreate":           methodWrite,
+	"ExchangeInject":           methodWrite,
+	"ExchangeWithdraw":         methodWrite,
+	"ExchangeTransaction":      methodWrite,
+	"ListExchanges":            methodRead,
+	"GetPaginatedExchangeList": methodRead,
+	"GetExchangeById":          methodRead,
+
+	// Network operations
+	"ListNodes":              methodRead,
+	"GetNodeInfo":            methodRead,
+	"GetChainParameters":     methodRead,
+	"GetNextMaintenanceTime": methodRead,
+	"TotalTransaction":       methodRead,
+}
+
+// isIdempotent reports whether repeating method on another node is safe:
+// reads, and broadcasts of an already-signed transaction. Transaction builders
+// are left out: whether a rebuilt transaction is acceptable is the caller's
+// decision, not the transport's.
+func isIdempotent(method string) bool {
+	kind, ok := transportMethods[method]
+	return ok && (kind == methodRead || kind == methodBroadcast)
+}
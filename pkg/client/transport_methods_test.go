@@ -0,0 +1,38 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTransportMethodsCoverInterface keeps transportMethods in step with the
+// Transport interface: a method missing from the table would never be retried
+// and, worse, would be silently treated as a write by every routing layer.
+func TestTransportMethodsCoverInterface(t *testing.T) {
+	typ := reflect.TypeFor[Transport]()
+
+	names := make(map[string]bool, typ.NumMethod())
+	for i := range typ.NumMethod() {
+		name := typ.Method(i).Name
+		if name == "Close" {
+			continue
+		}
+		names[name] = true
+		assert.Contains(t, transportMethods, name, "Transport.%s has no entry in transportMethods", name)
+	}
+
+	for name := range transportMethods {
+		assert.True(t, names[name], "transportMethods lists %s, which is not a Transport method", name)
+	}
+}
+
+func TestIsIdempotent(t *testing.T) {
+	assert.True(t, isIdempotent("GetAccount"))
+	assert.True(t, isIdempotent("TriggerConstantContract"))
+	assert.True(t, isIdempotent("BroadcastTransaction"))
+	assert.False(t, isIdempotent("CreateTransaction"))
+	assert.False(t, isIdempotent("TriggerContract"))
+	assert.False(t, isIdempotent("NoSuchMethod"))
+}
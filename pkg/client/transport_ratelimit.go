@@ -0,0 +1,242 @@
+package client
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// defaultAPIKeyHeader is the header TronGrid reads its API key from.
+const defaultAPIKeyHeader = "TRON-PRO-API-KEY"
+
+// defaultRetryAfter is how long an API key rests after a rate-limited answer
+// that did not say, through Retry-After, how long to wait.
+const defaultRetryAfter = time.Second
+
+// nodeLimiter paces the requests sent to one node. It holds the token bucket
+// of NodeConfig.RateLimit and rotates through NodeConfig.APIKeys: the key in
+// use is kept until the node rate-limits it, then rests for the Retry-After
+// the node asked for while the next key takes over, and the rate-limited
+// request goes again at once with that key. A node without keys rests as a
+// whole.
+//
+// A nil *nodeLimiter lets every request through at once.
+type nodeLimiter struct {
+	rate   float64 // tokens per second; 0 means no limit
+	burst  float64
+	header string
+	keys   []string
+
+	mu      sync.Mutex
+	tokens  float64
+	last    time.Time   // when tokens was last refilled
+	current int         // index into keys of the key in use
+	resting []time.Time // per key, or a single entry without keys: rests until
+
+	// observe, when set, receives every wait the limiter imposes.
+	observe func(time.Duration)
+}
+
+func newNodeLimiter(cfg NodeConfig) *nodeLimiter {
+	l := &nodeLimiter{
+		rate:   cfg.RateLimit,
+		burst:  float64(cfg.RateBurst),
+		header: cfg.APIKeyHeader,
+		keys:   cfg.APIKeys,
+	}
+	if l.burst <= 0 {
+		l.burst = max(1, math.Ceil(l.rate))
+	}
+	if l.header == "" {
+		l.header = defaultAPIKeyHeader
+	}
+	l.tokens = l.burst
+	l.resting = make([]time.Time, max(1, len(l.keys)))
+	return l
+}
+
+// wait blocks until the next request may be sent and returns the API key it
+// must carry, "" when the node has none. It fails at once with ErrRateLimited
+// when the wait would outlast the deadline of ctx, so that the caller can try
+// another node instead.
+func (l *nodeLimiter) wait(ctx context.Context) (string, error) {
+	if l == nil {
+		return "", nil
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	key, rest := l.pickKey(now)
+	d := max(rest, l.reserve(now))
+	if deadline, ok := ctx.Deadline(); ok && d > 0 && now.Add(d).After(deadline) {
+		l.refund()
+		l.mu.Unlock()
+		return "", ErrRateLimited
+	}
+	l.mu.Unlock()
+
+	if d <= 0 {
+		return key, nil
+	}
+	if l.observe != nil {
+		l.observe(d)
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return key, nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.refund()
+		l.mu.Unlock()
+		return "", ctx.Err()
+	}
+}
+
+// pickKey returns the first key from the current one on that is not resting,
+// or, when all of them are, the one that is free again first together with
+// how long that takes. Called with mu held.
+func (l *nodeLimiter) pickKey(now time.Time) (string, time.Duration) {
+	best := l.current
+	for i := range l.resting {
+		idx := (l.current + i) % len(l.resting)
+		if !l.resting[idx].After(now) {
+			best = idx
+			break
+		}
+		if l.resting[idx].Before(l.resting[best]) {
+			best = idx
+		}
+	}
+	l.current = best
+
+	var key string
+	if len(l.keys) > 0 {
+		key = l.keys[best]
+	}
+	return key, max(0, l.resting[best].Sub(now))
+}
+
+// reserve takes a token and returns how long the request must wait for it.
+// Called with mu held.
+func (l *nodeLimiter) reserve(now time.Time) time.Duration {
+	if l.rate <= 0 {
+		return 0
+	}
+	if !l.last.IsZero() {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// refund returns the token of a request that was not sent. Called with mu
+// held.
+func (l *nodeLimiter) refund() {
+	if l.rate > 0 {
+		l.tokens = min(l.burst, l.tokens+1)
+	}
+}
+
+// throttled makes key rest for retryAfter - defaultRetryAfter when the node
+// did not say - after the node rate-limited a request that carried it. It
+// reports whether another key is free right now, in which case the request
+// should be sent again at once: the node is not at fault, only the key.
+func (l *nodeLimiter) throttled(key string, retryAfter time.Duration) bool {
+	if l == nil {
+		return false
+	}
+	if retryAfter <= 0 {
+		retryAfter = defaultRetryAfter
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	idx := 0
+	if len(l.keys) > 0 {
+		if idx = slices.Index(l.keys, key); idx < 0 {
+			return false
+		}
+	}
+	now := time.Now()
+	l.resting[idx] = now.Add(retryAfter)
+	return slices.ContainsFunc(l.resting, func(until time.Time) bool { return !until.After(now) })
+}
+
+// parseRetryAfter reads a Retry-After header, which is either a number of
+// seconds or an HTTP date. It returns 0 when the header is absent or invalid.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(0, secs)) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(0, time.Until(at))
+	}
+	return 0
+}
+
+// limiterInterceptor returns a gRPC unary interceptor that paces calls
+// through l, sends the API key it picks as metadata, and rests that key when
+// the node answers ResourceExhausted, calling again with the next key while
+// one is free.
+func limiterInterceptor(l *nodeLimiter) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		for {
+			key, err := l.wait(ctx)
+			if err != nil {
+				return err
+			}
+			callCtx := ctx
+			if key != "" {
+				md, _ := metadata.FromOutgoingContext(ctx)
+				md = md.Copy()
+				md.Set(l.header, key)
+				callCtx = metadata.NewOutgoingContext(ctx, md)
+			}
+
+			err = invoker(callCtx, method, req, reply, cc, opts...)
+			if status.Code(err) == codes.ResourceExhausted && l.throttled(key, 0) {
+				continue
+			}
+			return err
+		}
+	}
+}
+
+// observeRateLimitWaits reports the waits imposed by the limiter of a node
+// transport to rec.
+func observeRateLimitWaits(t Transport, rec RateLimitRecorder, blockchain, node string) {
+	var l *nodeLimiter
+	switch t := t.(type) {
+	case *GRPCTransport:
+		l = t.limiter
+	case *HTTPTransport:
+		l = t.limiter
+	case *JSONRPCTransport:
+		l = t.limiter
+	}
+	if l == nil {
+		return
+	}
+	l.observe = func(d time.Duration) {
+		rec.RecordRateLimitWait(blockchain, node, d)
+	}
+}
@@ -0,0 +1,287 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestNodeLimiter_TokenBucket(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		l := newNodeLimiter(NodeConfig{RateLimit: 10, RateBurst: 2})
+		var waits []time.Duration
+		l.observe = func(d time.Duration) { waits = append(waits, d) }
+
+		start := time.Now()
+		for range 4 {
+			_, err := l.wait(t.Context())
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 200*time.Millisecond, time.Since(start), "the burst goes at once, then 10/s")
+		assert.Equal(t, []time.Duration{100 * time.Millisecond, 100 * time.Millisecond}, waits)
+
+		time.Sleep(time.Second)
+		start = time.Now()
+		for range 2 {
+			_, err := l.wait(t.Context())
+			require.NoError(t, err)
+		}
+		assert.Zero(t, time.Since(start), "an idle limiter refills up to the burst")
+	})
+}
+
+func TestNodeLimiter_DeadlineFailsFast(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		l := newNodeLimiter(NodeConfig{RateLimit: 1})
+		_, err := l.wait(t.Context())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err = l.wait(ctx)
+		require.ErrorIs(t, err, ErrRateLimited)
+		assert.Zero(t, time.Since(start))
+
+		// The refused call gave its token back.
+		_, err = l.wait(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, time.Second, time.Since(start))
+	})
+}
+
+func TestNodeLimiter_RotatesRateLimitedKeys(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		l := newNodeLimiter(NodeConfig{APIKeys: []string{"a", "b"}})
+
+		key, err := l.wait(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, "a", key)
+		key, _ = l.wait(t.Context())
+		assert.Equal(t, "a", key, "a key is kept until it is rate-limited")
+
+		assert.True(t, l.throttled("a", 5*time.Second), "b is free")
+		key, _ = l.wait(t.Context())
+		assert.Equal(t, "b", key)
+
+		assert.False(t, l.throttled("b", 10*time.Second), "both keys rest")
+		start := time.Now()
+		key, _ = l.wait(t.Context())
+		assert.Equal(t, "a", key, "the key that rests the shortest comes back first")
+		assert.Equal(t, 5*time.Second, time.Since(start))
+
+		assert.False(t, l.throttled("unknown", time.Hour))
+		key, _ = l.wait(t.Context())
+		assert.Equal(t, "a", key)
+	})
+}
+
+func TestNodeLimiter_NodeWithoutKeysRests(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		l := newNodeLimiter(NodeConfig{})
+		assert.False(t, l.throttled("", 0))
+
+		start := time.Now()
+		key, err := l.wait(t.Context())
+		require.NoError(t, err)
+		assert.Empty(t, key)
+		assert.Equal(t, defaultRetryAfter, time.Since(start))
+	})
+}
+
+func TestNodeLimiter_Nil(t *testing.T) {
+	var l *nodeLimiter
+	key, err := l.wait(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, key)
+	assert.False(t, l.throttled("", time.Second))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		assert.Zero(t, parseRetryAfter(""))
+		assert.Zero(t, parseRetryAfter("soon"))
+		assert.Zero(t, parseRetryAfter("-3"))
+		assert.Equal(t, 3*time.Second, parseRetryAfter("3"))
+		at := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+		assert.Equal(t, time.Minute, parseRetryAfter(at).Round(time.Second))
+	})
+}
+
+// rateLimitedServer answers 429 to the API keys in limited and records the key
+// of every request.
+func rateLimitedServer(t *testing.T, limited ...string) (*httptest.Server, func() []string) {
+	var (
+		mu   sync.Mutex
+		keys []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("TRON-PRO-API-KEY")
+		mu.Lock()
+		keys = append(keys, key)
+		mu.Unlock()
+		if slices.Contains(limited, key) {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(keys)
+	}
+}
+
+// One node with two keys: a 429 on the first is answered by the second,
+// within the same call.
+func TestHTTPRateLimitRotatesKey(t *testing.T) {
+	srv, keys := rateLimitedServer(t, "a")
+	tr, err := NewHTTPTransport(NodeConfig{
+		Protocol: ProtocolHTTP,
+		Address:  srv.URL,
+		Headers:  map[string]string{"TRON-PRO-API-KEY": "static"},
+		APIKeys:  []string{"a", "b"},
+	})
+	require.NoError(t, err)
+
+	_, err = tr.GetNowBlock(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, keys())
+
+	_, err = tr.GetNowBlock(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "b"}, keys(), "a rests")
+}
+
+func TestHTTPRateLimitEveryKeyLimited(t *testing.T) {
+	srv, keys := rateLimitedServer(t, "a", "b")
+	tr, err := NewHTTPTransport(NodeConfig{Protocol: ProtocolHTTP, Address: srv.URL, APIKeys: []string{"a", "b"}})
+	require.NoError(t, err)
+
+	_, err = tr.GetNowBlock(t.Context())
+	statusErr, ok := errors.AsType[*HTTPStatusError](err)
+	require.True(t, ok)
+	assert.Equal(t, 30*time.Second, statusErr.RetryAfter)
+	assert.True(t, isRateLimited(err))
+	assert.False(t, isNetworkError(err))
+	assert.Equal(t, []string{"a", "b"}, keys(), "each key tried once")
+}
+
+// The key rotation happens inside the node's transport, so a client of a
+// single node gets its answer and spends nothing of the retry budget.
+func TestHealthAware_KeyRotationOnSingleNode(t *testing.T) {
+	srv, keys := rateLimitedServer(t, "a")
+	metrics := &mockMetricsCollector{}
+	c, err := New(Config{
+		Nodes:   []NodeConfig{{Protocol: ProtocolHTTP, Address: srv.URL, APIKeys: []string{"a", "b"}}},
+		Metrics: metrics,
+		Health: HealthConfig{
+			HealthyInterval: time.Hour, UnhealthyInterval: time.Hour, InactiveTierInterval: time.Hour,
+			Probe: func(context.Context, Transport) error { return nil },
+			Retry: RetryPolicy{MaxAttempts: 3},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	_, err = c.GetLastBlock(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, keys())
+	assert.Empty(t, metrics.retries)
+}
+
+func TestGRPCLimiterInterceptor(t *testing.T) {
+	l := newNodeLimiter(NodeConfig{APIKeys: []string{"a", "b"}})
+	interceptor := limiterInterceptor(l)
+
+	var sent []metadata.MD
+	invoker := func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		sent = append(sent, md)
+		if md.Get("tron-pro-api-key")[0] == "a" {
+			return status.Error(codes.ResourceExhausted, "rate limited")
+		}
+		return nil
+	}
+
+	ctx := metadata.NewOutgoingContext(t.Context(), metadata.Pairs("x-custom", "1"))
+	require.NoError(t, interceptor(ctx, "/protocol.Wallet/GetNowBlock2", nil, nil, nil, invoker), "b answers for a")
+
+	require.Len(t, sent, 2)
+	assert.Equal(t, []string{"a"}, sent[0].Get("tron-pro-api-key"))
+	assert.Equal(t, []string{"b"}, sent[1].Get("tron-pro-api-key"))
+	assert.Equal(t, []string{"1"}, sent[1].Get("x-custom"), "other metadata is kept")
+
+	// Once b is limited too, the call fails and the caller may try another node.
+	sent = nil
+	limitedB := func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		sent = append(sent, md)
+		return status.Error(codes.ResourceExhausted, "rate limited")
+	}
+	err := interceptor(ctx, "/protocol.Wallet/GetNowBlock2", nil, nil, nil, limitedB)
+	require.True(t, isRateLimited(err))
+	require.Len(t, sent, 1, "a still rests")
+}
+
+func TestHealthAware_RateLimitIsRetriedWithoutHurtingHealth(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		h := newHarness(t, []int{0, 0}, quietProbes(HealthConfig{
+			FailureThreshold: 1,
+			Retry:            RetryPolicy{MaxAttempts: 2},
+		}))
+		h.nodes[0].setNextErr(&TransportError{Protocol: "http", Err: &HTTPStatusError{Code: http.StatusTooManyRequests}})
+
+		_, err := h.transport.GetAccount(bgctx(), &core.Account{})
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(1), h.nodes[1].liveCallCount.Load())
+		assert.True(t, h.nodeHealthy(0))
+		assert.Len(t, h.metrics.retries, 1)
+	})
+}
+
+type waitRecorder struct {
+	blockchain, node string
+	waits            []time.Duration
+}
+
+func (r *waitRecorder) RecordRateLimitWait(blockchain, node string, d time.Duration) {
+	r.blockchain, r.node = blockchain, node
+	r.waits = append(r.waits, d)
+}
+
+func TestObserveRateLimitWaits(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		node := NodeConfig{Protocol: ProtocolHTTP, Address: "http://node.invalid", RateLimit: 2}
+		tr, err := NewHTTPTransport(node)
+		require.NoError(t, err)
+		rec := &waitRecorder{}
+		observeRateLimitWaits(tr, rec, "tron", node.Address)
+
+		for range 3 {
+			_, err := tr.limiter.wait(t.Context())
+			require.NoError(t, err)
+		}
+		assert.Equal(t, "tron", rec.blockchain)
+		assert.Equal(t, node.Address, rec.node)
+		assert.Equal(t, []time.Duration{500 * time.Millisecond}, rec.waits)
+	})
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// solidifiedRouter is the transport of a Client: it sends calls made with
+// CallSolidified to the solidity pool and everything else to the full nodes.
+type solidifiedRouter struct {
+	methodSet
+	full Transport
+	// solidity is nil when no solidity node is configured.
+	solidity Transport
+}
+
+var _ Transport = (*solidifiedRouter)(nil)
+
+// pick returns the pool a call with ctx goes to.
+func (r *solidifiedRouter) pick(ctx context.Context) (Transport, error) {
+	if !callOptionsFrom(ctx).solidified {
+		return r.full, nil
+	}
+	if r.solidity == nil {
+		return nil, ErrNoSolidityNodes
+	}
+	return r.solidity, nil
+}
+
+// newSolidifiedRouter creates the router over the full pool and the
+// solidity pool, which may be nil.
+func newSolidifiedRouter(full, solidity Transport) *solidifiedRouter {
+	r := &solidifiedRouter{full: full, solidity: solidity}
+	r.handle = r.call
+	return r
+}
+
+// call sends a call to the pool picked for ctx.
+func (r *solidifiedRouter) call(ctx context.Context, method string, request proto.Message) (proto.Message, error) {
+	t, err := r.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dispatch(ctx, t, method, request)
+}
+
+// Close closes both pools.
+func (r *solidifiedRouter) Close() error {
+	errs := []error{r.full.Close()}
+	if r.solidity != nil {
+		errs = append(errs, r.solidity.Close())
+	}
+	return errors.Join(errs...)
+}
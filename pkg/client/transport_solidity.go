@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"google.golang.org/grpc"
+)
+
+// solidityGRPCMethods maps the Wallet methods GRPCTransport calls to their
+// WalletSolidity counterparts. A solidity node serves only reads, and only
+// some of them: everything missing here fails with ErrUnsupportedBySolidity.
+var solidityGRPCMethods = map[string]string{
+	api.Wallet_GetAccount_FullMethodName:                         api.WalletSolidity_GetAccount_FullMethodName,
+	api.Wallet_GetNowBlock2_FullMethodName:                       api.WalletSolidity_GetNowBlock2_FullMethodName,
+	api.Wallet_GetBlockByNum2_FullMethodName:                     api.WalletSolidity_GetBlockByNum2_FullMethodName,
+	api.Wallet_GetTransactionById_FullMethodName:                 api.WalletSolidity_GetTransactionById_FullMethodName,
+	api.Wallet_GetTransactionInfoById_FullMethodName:             api.WalletSolidity_GetTransactionInfoById_FullMethodName,
+	api.Wallet_GetTransactionInfoByBlockNum_FullMethodName:       api.WalletSolidity_GetTransactionInfoByBlockNum_FullMethodName,
+	api.Wallet_TriggerConstantContract_FullMethodName:            api.WalletSolidity_TriggerConstantContract_FullMethodName,
+	api.Wallet_EstimateEnergy_FullMethodName:                     api.WalletSolidity_EstimateEnergy_FullMethodName,
+	api.Wallet_GetDelegatedResource_FullMethodName:               api.WalletSolidity_GetDelegatedResource_FullMethodName,
+	api.Wallet_GetDelegatedResourceV2_FullMethodName:             api.WalletSolidity_GetDelegatedResourceV2_FullMethodName,
+	api.Wallet_GetDelegatedResourceAccountIndex_FullMethodName:   api.WalletSolidity_GetDelegatedResourceAccountIndex_FullMethodName,
+	api.Wallet_GetDelegatedResourceAccountIndexV2_FullMethodName: api.WalletSolidity_GetDelegatedResourceAccountIndexV2_FullMethodName,
+	api.Wallet_GetCanDelegatedMaxSize_FullMethodName:             api.WalletSolidity_GetCanDelegatedMaxSize_FullMethodName,
+	api.Wallet_GetAvailableUnfreezeCount_FullMethodName:          api.WalletSolidity_GetAvailableUnfreezeCount_FullMethodName,
+	api.Wallet_GetCanWithdrawUnfreezeAmount_FullMethodName:       api.WalletSolidity_GetCanWithdrawUnfreezeAmount_FullMethodName,
+	api.Wallet_ListWitnesses_FullMethodName:                      api.WalletSolidity_ListWitnesses_FullMethodName,
+	api.Wallet_GetRewardInfo_FullMethodName:                      api.WalletSolidity_GetRewardInfo_FullMethodName,
+	api.Wallet_GetBrokerageInfo_FullMethodName:                   api.WalletSolidity_GetBrokerageInfo_FullMethodName,
+	api.Wallet_GetAssetIssueById_FullMethodName:                  api.WalletSolidity_GetAssetIssueById_FullMethodName,
+	api.Wallet_GetAssetIssueListByName_FullMethodName:            api.WalletSolidity_GetAssetIssueListByName_FullMethodName,
+	api.Wallet_GetAssetIssueList_FullMethodName:                  api.WalletSolidity_GetAssetIssueList_FullMethodName,
+	api.Wallet_GetPaginatedAssetIssueList_FullMethodName:         api.WalletSolidity_GetPaginatedAssetIssueList_FullMethodName,
+	api.Wallet_ListExchanges_FullMethodName:                      api.WalletSolidity_ListExchanges_FullMethodName,
+	api.Wallet_GetExchangeById_FullMethodName:                    api.WalletSolidity_GetExchangeById_FullMethodName,
+}
+
+// solidityConn routes the Wallet calls of a WalletClient to the WalletSolidity
+// service of the same connection. The request and response messages of the
+// two services are identical, so only the method name changes.
+type solidityConn struct {
+	cc      grpc.ClientConnInterface
+	address string
+}
+
+func (c solidityConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	solidity, ok := solidityGRPCMethods[method]
+	if !ok {
+		return c.unsupported(method)
+	}
+	return c.cc.Invoke(ctx, solidity, args, reply, opts...)
+}
+
+func (c solidityConn) NewStream(_ context.Context, _ *grpc.StreamDesc, method string, _ ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, c.unsupported(method)
+}
+
+func (c solidityConn) unsupported(method string) error {
+	return &TransportError{
+		Host:     c.address,
+		Protocol: "grpc",
+		Method:   method,
+		Err:      ErrUnsupportedBySolidity,
+	}
+}
+
+// solidityHTTPEndpoints lists the /wallet endpoints HTTPTransport calls that
+// a solidity node serves under /walletsolidity.
+var solidityHTTPEndpoints = map[string]bool{
+	"/wallet/getaccount":                         true,
+	"/wallet/getnowblock":                        true,
+	"/wallet/getblockbynum":                      true,
+	"/wallet/getblockbyid":                       true,
+	"/wallet/getblockbylimitnext":                true,
+	"/wallet/getblockbylatestnum":                true,
+	"/wallet/gettransactionbyid":                 true,
+	"/wallet/gettransactioninfobyid":             true,
+	"/wallet/gettransactioninfobyblocknum":       true,
+	"/wallet/triggerconstantcontract":            true,
+	"/wallet/estimateenergy":                     true,
+	"/wallet/getdelegatedresource":               true,
+	"/wallet/getdelegatedresourcev2":             true,
+	"/wallet/getdelegatedresourceaccountindex":   true,
+	"/wallet/getdelegatedresourceaccountindexv2": true,
+	"/wallet/getcandelegatedmaxsize":             true,
+	"/wallet/getavailableunfreezecount":          true,
+	"/wallet/getcanwithdrawunfreezeamount":       true,
+	"/wallet/listwitnesses":                      true,
+	"/wallet/getReward":                          true,
+	"/wallet/getBrokerage":                       true,
+	"/wallet/getassetissuebyid":                  true,
+	"/wallet/getassetissuelistbyname":            true,
+	"/wallet/getassetissuelist":                  true,
+	"/wallet/getpaginatedassetissuelist":         true,
+	"/wallet/listexchanges":                      true,
+	"/wallet/getexchangebyid":                    true,
+}
+
+// solidityEndpoint returns the /walletsolidity path of a /wallet endpoint,
+// and false when solidity nodes do not serve it.
+func solidityEndpoint(endpoint string) (string, bool) {
+	if !solidityHTTPEndpoints[endpoint] {
+		return "", false
+	}
+	return "/walletsolidity/" + strings.TrimPrefix(endpoint, "/wallet/"), true
+}
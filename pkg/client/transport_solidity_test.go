@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"google.golang.org/grpc"
+)
+
+// recordingConn is a grpc.ClientConnInterface that records the methods it is
+// asked to invoke and answers every call with an empty reply.
+type recordingConn struct {
+	methods []string
+}
+
+func (c *recordingConn) Invoke(_ context.Context, method string, _, _ any, _ ...grpc.CallOption) error {
+	c.methods = append(c.methods, method)
+	return nil
+}
+
+func (c *recordingConn) NewStream(context.Context, *grpc.StreamDesc, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+	panic("not used")
+}
+
+func TestSolidityConnRoutesToWalletSolidity(t *testing.T) {
+	rec := &recordingConn{}
+	tr := &GRPCTransport{walletClient: api.NewWalletClient(solidityConn{cc: rec, address: "node:50061"})}
+
+	_, err := tr.GetAccount(t.Context(), &core.Account{})
+	require.NoError(t, err)
+	_, err = tr.GetNowBlock(t.Context())
+	require.NoError(t, err)
+	_, err = tr.TriggerConstantContract(t.Context(), &core.TriggerSmartContract{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"/protocol.WalletSolidity/GetAccount",
+		"/protocol.WalletSolidity/GetNowBlock2",
+		"/protocol.WalletSolidity/TriggerConstantContract",
+	}, rec.methods)
+}
+
+func TestSolidityConnRejectsUnsupportedMethods(t *testing.T) {
+	rec := &recordingConn{}
+	tr := &GRPCTransport{walletClient: api.NewWalletClient(solidityConn{cc: rec, address: "node:50061"})}
+
+	_, err := tr.CreateTransaction(t.Context(), &core.TransferContract{})
+	require.ErrorIs(t, err, ErrUnsupportedBySolidity)
+	_, err = tr.BroadcastTransaction(t.Context(), &core.Transaction{})
+	require.ErrorIs(t, err, ErrUnsupportedBySolidity)
+
+	te, ok := errors.AsType[*TransportError](err)
+	require.True(t, ok)
+	assert.Equal(t, "node:50061", te.Host)
+	assert.Equal(t, api.Wallet_BroadcastTransaction_FullMethodName, te.Method)
+	assert.Empty(t, rec.methods, "nothing must reach the node")
+	assert.False(t, isNetworkError(err), "an unsupported call says nothing about the node")
+}
+
+// Every solidity mapping must point at a real WalletSolidity method of the
+// same name, or the node answers Unimplemented.
+func TestSolidityGRPCMethodsMatchByName(t *testing.T) {
+	for wallet, solidity := range solidityGRPCMethods {
+		assert.Equal(t, "/protocol.WalletSolidity/"+wallet[len("/protocol.Wallet/"):], solidity)
+	}
+}
+
+func TestHTTPSolidityUsesWalletSolidityPaths(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	tr, err := NewHTTPTransport(NodeConfig{Protocol: ProtocolHTTP, Address: srv.URL, Solidity: true})
+	require.NoError(t, err)
+
+	_, err = tr.GetNowBlock(t.Context())
+	require.NoError(t, err)
+	_, err = tr.GetTransactionInfoById(t.Context(), []byte{1})
+	require.NoError(t, err)
+
+	_, err = tr.CreateTransaction(t.Context(), &core.TransferContract{})
+	require.ErrorIs(t, err, ErrUnsupportedBySolidity)
+
+	assert.Equal(t, []string{"/walletsolidity/getnowblock", "/walletsolidity/gettransactioninfobyid"}, paths)
+}
+
+func TestHTTPSolidityErrorsNameTheSolidityPath(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+
+	tr, err := NewHTTPTransport(NodeConfig{Protocol: ProtocolHTTP, Address: srv.URL, Solidity: true})
+	require.NoError(t, err)
+
+	_, err = tr.GetNowBlock(t.Context())
+	te, ok := errors.AsType[*TransportError](err)
+	require.True(t, ok)
+	assert.Equal(t, "/walletsolidity/getnowblock", te.Method)
+}
+
+func TestSolidityHTTPEndpointsAreCalledByTransport(t *testing.T) {
+	// A typo in the table would silently reject a supported read.
+	raw, err := os.ReadFile("transport_http.go")
+	require.NoError(t, err)
+	src := string(raw)
+	for endpoint := range solidityHTTPEndpoints {
+		assert.Contains(t, src, `"`+endpoint+`"`)
+	}
+}
+
+func TestClientConfirmed(t *testing.T) {
+	c, err := New(Config{
+		Nodes: []NodeConfig{
+			{Protocol: ProtocolHTTP, Address: "http://full.invalid"},
+			{Protocol: ProtocolHTTP, Address: "http://solidity.invalid", Solidity: true},
+		},
+		Health: HealthConfig{Disabled: true},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	confirmed, err := c.Confirmed()
+	require.NoError(t, err)
+	rr, ok := confirmed.transport.(*RoundRobinTransport)
+	require.True(t, ok)
+	require.Len(t, rr.transports, 1)
+	assert.True(t, rr.transports[0].(*HTTPTransport).solidity)
+
+	router, ok := c.transport.(*solidifiedRouter)
+	require.True(t, ok)
+	assert.Same(t, confirmed.transport, router.solidity)
+	rr, ok = router.full.(*RoundRobinTransport)
+	require.True(t, ok)
+	require.Len(t, rr.transports, 1, "solidity nodes stay out of the full-node pool")
+	assert.False(t, rr.transports[0].(*HTTPTransport).solidity)
+}
+
+func TestClientConfirmedWithoutSolidityNodes(t *testing.T) {
+	c, err := New(Config{
+		Nodes:  []NodeConfig{{Protocol: ProtocolHTTP, Address: "http://full.invalid"}},
+		Health: HealthConfig{Disabled: true},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	_, err = c.Confirmed()
+	require.ErrorIs(t, err, ErrNoSolidityNodes)
+}
@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// tracerName is the instrumentation scope of the spans gotron opens.
+const tracerName = "github.com/sxwebdev/gotron/pkg/client"
+
+// OTelTransport wraps a Transport and opens an OpenTelemetry span for every
+// call. Client builds it at two levels: once over each node pool, where a
+// span named "gotron.<Method>" covers the whole call, retries and hedges
+// included; and once over each node, where a client span named after the
+// method covers a single request and carries the node's address, protocol,
+// tier and the gRPC or HTTP status it answered with.
+//
+// A failed call records its error on the span. So does an answer the node
+// refused - a broadcast it rejected, a transaction it did not build - as the
+// BroadcastError or ContractValidateError the Client methods return for it.
+// The trace context of the span is sent to the node in the request's gRPC
+// metadata or HTTP headers, through TracingConfig.Propagator.
+type OTelTransport struct {
+	methodSet
+	transport  Transport
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	attrs      []attribute.KeyValue
+
+	// node is the node the transport talks to, nil over a pool.
+	node *NodeConfig
+}
+
+var _ Transport = (*OTelTransport)(nil)
+
+// NewOTelTransport creates a transport that traces every call made through
+// transport. cfg.Provider must not be nil.
+func NewOTelTransport(transport Transport, cfg TracingConfig, blockchain string) *OTelTransport {
+	if blockchain == "" {
+		blockchain = "tron"
+	}
+	return newOTelTransport(transport, cfg, nil, attribute.String("gotron.blockchain", blockchain))
+}
+
+// newNodeOTelTransport creates the per-node layer of tracing over the
+// transport of node.
+func newNodeOTelTransport(transport Transport, cfg TracingConfig, node NodeConfig) *OTelTransport {
+	return newOTelTransport(transport, cfg, &node,
+		attribute.String("rpc.system", string(node.GetProtocol())),
+		attribute.String("server.address", node.Address),
+		attribute.Int("gotron.node.tier", node.Tier),
+		attribute.Bool("gotron.node.solidity", node.Solidity),
+	)
+}
+
+func newOTelTransport(transport Transport, cfg TracingConfig, node *NodeConfig, attrs ...attribute.KeyValue) *OTelTransport {
+	propagator := cfg.Propagator
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+	t := &OTelTransport{
+		transport:  transport,
+		tracer:     cfg.Provider.Tracer(tracerName),
+		propagator: propagator,
+		attrs:      attrs,
+		node:       node,
+	}
+	t.handle = t.call
+	return t
+}
+
+// Close closes the underlying transport.
+func (t *OTelTransport) Close() error {
+	return t.transport.Close()
+}
+
+// call passes a call on inside a span for method.
+func (t *OTelTransport) call(ctx context.Context, method string, request proto.Message) (proto.Message, error) {
+	ctx, span := t.start(ctx, method)
+	defer span.End()
+	res, err := dispatch(ctx, t.transport, method, request)
+	t.finish(span, res, err)
+	return res, err
+}
+
+// start opens the span of a call and puts its trace context in the headers
+// the node transports send.
+func (t *OTelTransport) start(ctx context.Context, method string) (context.Context, trace.Span) {
+	name, kind := "gotron."+method, trace.SpanKindInternal
+	if t.node != nil {
+		name, kind = method, trace.SpanKindClient
+	}
+	ctx, span := t.tracer.Start(ctx, name,
+		trace.WithSpanKind(kind),
+		trace.WithAttributes(t.attrs...),
+		trace.WithAttributes(attribute.String("rpc.method", method)),
+	)
+
+	carrier := propagation.MapCarrier{}
+	t.propagator.Inject(ctx, carrier)
+	return withCallHeaders(ctx, carrier), span
+}
+
+// finish records the outcome of a call on its span.
+func (t *OTelTransport) finish(span trace.Span, res proto.Message, err error) {
+	if t.node != nil {
+		span.SetAttributes(statusAttributes(t.node.GetProtocol(), err)...)
+	}
+	if err == nil && res.ProtoReflect().IsValid() {
+		err = refusal(res)
+	}
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	if e, ok := errors.AsType[*BroadcastError](err); ok {
+		span.SetAttributes(attribute.String("error.type", "BroadcastError"), attribute.String("tron.response_code", e.Code.String()))
+	} else if e, ok := errors.AsType[*ContractValidateError](err); ok {
+		span.SetAttributes(attribute.String("error.type", "ContractValidateError"), attribute.String("tron.response_code", e.Code.String()))
+	}
+}
+
+// statusAttributes returns the status a node answered a call with: the gRPC
+// status code, or the HTTP status when the node sent one.
+func statusAttributes(protocol Protocol, err error) []attribute.KeyValue {
+	if protocol == ProtocolGRPC {
+		return []attribute.KeyValue{attribute.Int("rpc.grpc.status_code", int(status.Code(err)))}
+	}
+	if err == nil {
+		return []attribute.KeyValue{attribute.Int("http.response.status_code", http.StatusOK)}
+	}
+	if e, ok := errors.AsType[*HTTPStatusError](err); ok {
+		return []attribute.KeyValue{attribute.Int("http.response.status_code", e.Code)}
+	}
+	return nil
+}
+
+// refusal returns the error the Client methods make of an answer by which
+// the node refused a request, or nil: a BroadcastError for a rejected
+// broadcast, a ContractValidateError for a transaction the node did not
+// build.
+func refusal(res proto.Message) error {
+	switch res := res.(type) {
+	case *api.Return:
+		if !res.GetResult() || res.GetCode() != api.Return_SUCCESS {
+			return &BroadcastError{Code: res.GetCode(), Message: string(res.GetMessage())}
+		}
+	case *api.TransactionExtention:
+		if code := res.GetResult().GetCode(); code != api.Return_SUCCESS {
+			return &ContractValidateError{Code: code, Message: string(res.GetResult().GetMessage())}
+		}
+	}
+	return nil
+}
+
+// callHeadersKey is the context key of the headers set by withCallHeaders.
+type callHeadersKey struct{}
+
+// withCallHeaders returns a copy of ctx whose calls also send headers, as
+// HTTP headers or gRPC metadata, on top of those already set in ctx.
+func withCallHeaders(ctx context.Context, headers map[string]string) context.Context {
+	if len(headers) == 0 {
+		return ctx
+	}
+	merged := maps.Clone(callHeadersFrom(ctx))
+	if merged == nil {
+		merged = make(map[string]string, len(headers))
+	}
+	maps.Copy(merged, headers)
+	return context.WithValue(ctx, callHeadersKey{}, merged)
+}
+
+// callHeadersFrom returns the headers set in ctx by withCallHeaders.
+func callHeadersFrom(ctx context.Context) map[string]string {
+	headers, _ := ctx.Value(callHeadersKey{}).(map[string]string)
+	return headers
+}
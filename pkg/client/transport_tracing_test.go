@@ -0,0 +1,189 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sxwebdev/gotron/schema/pb/api"
+	"github.com/sxwebdev/gotron/schema/pb/core"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// newTracing returns a TracingConfig whose spans end up in the returned
+// exporter as soon as they end.
+func newTracing(t *testing.T) (TracingConfig, *tracetest.InMemoryExporter) {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	return TracingConfig{Provider: provider, Propagator: propagation.TraceContext{}}, exporter
+}
+
+// spanAttrs returns the attributes of span by key.
+func spanAttrs(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value, len(span.Attributes))
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+// newTracedPool builds a pool-level OTelTransport over a round-robin of one
+// node-level OTelTransport per node, as Client does.
+func newTracedPool(cfg TracingConfig, nodes ...NodeConfig) (*OTelTransport, []*controllableTransport) {
+	cts := make([]*controllableTransport, len(nodes))
+	transports := make([]Transport, len(nodes))
+	for i, node := range nodes {
+		cts[i] = newCT(node.Address)
+		transports[i] = newNodeOTelTransport(cts[i], cfg, node)
+	}
+	return NewOTelTransport(NewRoundRobinTransport(transports), cfg, "tron"), cts
+}
+
+func TestOTel_PoolAndNodeSpans(t *testing.T) {
+	cfg, exporter := newTracing(t)
+	tr, _ := newTracedPool(cfg, NodeConfig{Address: "node-a:50051", Tier: 1})
+
+	_, err := tr.GetAccount(t.Context(), &core.Account{})
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	node, pool := spans[0], spans[1]
+
+	assert.Equal(t, "gotron.GetAccount", pool.Name)
+	assert.Equal(t, trace.SpanKindInternal, pool.SpanKind)
+	assert.Equal(t, "tron", spanAttrs(pool)["gotron.blockchain"].AsString())
+	assert.Equal(t, otelcodes.Unset, pool.Status.Code)
+
+	assert.Equal(t, "GetAccount", node.Name)
+	assert.Equal(t, trace.SpanKindClient, node.SpanKind)
+	assert.Equal(t, pool.SpanContext.SpanID(), node.Parent.SpanID())
+	attrs := spanAttrs(node)
+	assert.Equal(t, "grpc", attrs["rpc.system"].AsString())
+	assert.Equal(t, "GetAccount", attrs["rpc.method"].AsString())
+	assert.Equal(t, "node-a:50051", attrs["server.address"].AsString())
+	assert.Equal(t, int64(1), attrs["gotron.node.tier"].AsInt64())
+	assert.Equal(t, int64(codes.OK), attrs["rpc.grpc.status_code"].AsInt64())
+}
+
+func TestOTel_ErrorsAreRecorded(t *testing.T) {
+	cfg, exporter := newTracing(t)
+	tr, cts := newTracedPool(cfg, NodeConfig{Address: "node-a:50051"})
+	cts[0].setNextErr(&TransportError{Host: "node-a:50051", Protocol: "grpc", Err: grpcErr(codes.Unavailable)})
+
+	_, err := tr.GetAccount(t.Context(), &core.Account{})
+	require.Error(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	for _, span := range spans {
+		assert.Equal(t, otelcodes.Error, span.Status.Code)
+		require.Len(t, span.Events, 1, "the error is recorded as an exception event")
+	}
+	assert.Equal(t, int64(codes.Unavailable), spanAttrs(spans[0])["rpc.grpc.status_code"].AsInt64())
+}
+
+func TestOTel_RefusedAnswersAreErrors(t *testing.T) {
+	cfg, exporter := newTracing(t)
+	tr, _ := newTracedPool(cfg, NodeConfig{Address: "node-a:50051"})
+
+	// controllableTransport answers a broadcast with an empty Return, which
+	// is Result=false: a rejected broadcast.
+	_, err := tr.BroadcastTransaction(t.Context(), &core.Transaction{})
+	require.NoError(t, err, "the transport itself does not fail")
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	pool := spans[1]
+	assert.Equal(t, otelcodes.Error, pool.Status.Code)
+	assert.Equal(t, "BroadcastError", spanAttrs(pool)["error.type"].AsString())
+	assert.Equal(t, api.Return_SUCCESS.String(), spanAttrs(pool)["tron.response_code"].AsString())
+
+	assert.Equal(t, &ContractValidateError{Code: api.Return_CONTRACT_VALIDATE_ERROR, Message: "no funds"},
+		refusal(&api.TransactionExtention{Result: &api.Return{Code: api.Return_CONTRACT_VALIDATE_ERROR, Message: []byte("no funds")}}))
+	assert.NoError(t, refusal(&api.TransactionExtention{Result: &api.Return{Result: true}}))
+	assert.NoError(t, refusal(&api.Return{Result: true}))
+}
+
+func TestOTel_HTTPNodePropagatesTraceContext(t *testing.T) {
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg, exporter := newTracing(t)
+	node := NodeConfig{Protocol: ProtocolHTTP, Address: srv.URL}
+	ht, err := NewHTTPTransport(node)
+	require.NoError(t, err)
+	tr := NewOTelTransport(newNodeOTelTransport(ht, cfg, node), cfg, "tron")
+
+	_, err = tr.GetNowBlock(t.Context())
+	require.Error(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	nodeSpan := spans[0]
+	assert.Contains(t, traceparent, nodeSpan.SpanContext.TraceID().String())
+	assert.Contains(t, traceparent, nodeSpan.SpanContext.SpanID().String(), "the node sees the node span as parent")
+	attrs := spanAttrs(nodeSpan)
+	assert.Equal(t, "http", attrs["rpc.system"].AsString())
+	assert.Equal(t, int64(http.StatusServiceUnavailable), attrs["http.response.status_code"].AsInt64())
+}
+
+func TestHeadersInterceptorSendsCallHeaders(t *testing.T) {
+	interceptor := headersInterceptor(map[string]string{"x-static": "1"})
+
+	var sent metadata.MD
+	invoker := func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		sent, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+
+	ctx := metadata.NewOutgoingContext(t.Context(), metadata.Pairs("x-caller", "2"))
+	ctx = withCallHeaders(ctx, map[string]string{"traceparent": "00-a-b-01"})
+	ctx = withCallHeaders(ctx, map[string]string{"traceparent": "00-c-d-01", "tracestate": "k=v"})
+	require.NoError(t, interceptor(ctx, "/protocol.Wallet/GetNowBlock2", nil, nil, nil, invoker))
+
+	assert.Equal(t, []string{"1"}, sent.Get("x-static"))
+	assert.Equal(t, []string{"2"}, sent.Get("x-caller"), "metadata of the caller is kept")
+	assert.Equal(t, []string{"00-c-d-01"}, sent.Get("traceparent"), "the innermost span wins")
+	assert.Equal(t, []string{"k=v"}, sent.Get("tracestate"))
+}
+
+func TestClientTracing(t *testing.T) {
+	cfg, _ := newTracing(t)
+	c, err := New(Config{
+		Nodes:   []NodeConfig{{Protocol: ProtocolHTTP, Address: "http://full.invalid"}},
+		Health:  HealthConfig{Disabled: true},
+		Tracing: cfg,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	router, ok := c.transport.(*solidifiedRouter)
+	require.True(t, ok)
+	pool, ok := router.full.(*OTelTransport)
+	require.True(t, ok)
+	assert.Nil(t, pool.node)
+	rr, ok := pool.transport.(*RoundRobinTransport)
+	require.True(t, ok)
+	node, ok := rr.transports[0].(*OTelTransport)
+	require.True(t, ok)
+	assert.Equal(t, "http://full.invalid", node.node.Address)
+	assert.IsType(t, &HTTPTransport{}, node.transport)
+}
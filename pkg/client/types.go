@@ -84,9 +84,10 @@ func (r ResourceType) ToProto() core.ResourceCode {
 // from a literal, e.g. client.SUN(1_500_000).
 type SUN = units.SUN
 
-// TokenAmount is an amount of a TRC20 token in the token's own minimal units.
-// Its scale comes from the token's decimals, so it is deliberately a different
-// type from SUN. Build one with FromTokenDecimal or FromTokenUnits.
+// TokenAmount is an amount of a TRC20 or TRC10 token in the token's own
+// minimal units. Its scale comes from the token's decimals, so it is
+// deliberately a different type from SUN. Build one with FromTokenDecimal or
+// FromTokenUnits.
 type TokenAmount = units.TokenAmount
 
 // Amount constructors, re-exported from pkg/units so that building an amount
@@ -166,3 +167,20 @@ type StakeInfo struct {
 	WithdrawableNow SUN              `json:"withdrawable_now"`
 	PendingUnstakes []PendingUnstake `json:"pending_unstakes"`
 }
+
+// TRC10Balance is an account's holding of one TRC10 token.
+//
+// Name, Abbr and Precision come from the token's issue. They are empty when
+// the node knows no issue under ID, which leaves Balance in minimal units.
+type TRC10Balance struct {
+	// ID is the token's numeric id, e.g. "1002000".
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Abbr string `json:"abbr"`
+	// Precision is the token's decimals, 0 to 6.
+	Precision int32 `json:"precision"`
+	// Amount is the balance in the token's minimal units.
+	Amount TokenAmount `json:"amount"`
+	// Balance is Amount with Precision applied.
+	Balance decimal.Decimal `json:"balance"`
+}
@@ -126,3 +126,152 @@ func (c *Client) GetWitnessBrokerage(ctx context.Context, witness string) (int64
 
 	return res.GetNum(), nil
 }
+
+const (
+	// MaxBrokerage is the most, in percent, of its voters' rewards a witness
+	// may keep.
+	MaxBrokerage = 100
+	// MaxWitnessURLLength is the longest URL a witness may register, in bytes.
+	MaxWitnessURLLength = 256
+	// MaxWitnessPage is the most witnesses GetPaginatedNowWitnessList returns
+	// in one page.
+	MaxWitnessPage = 1000
+)
+
+// WitnessApplyFee returns what applying to be a witness costs, the
+// getAccountUpgradeCost chain parameter. The fee is burned, not staked.
+func (c *Client) WitnessApplyFee(ctx context.Context) (SUN, error) {
+	param, err := c.ChainParam(ctx, "getAccountUpgradeCost")
+	if err != nil {
+		return 0, err
+	}
+
+	return SUN(param.GetValue()), nil
+}
+
+// CreateWitness builds a transaction applying for owner to become a witness -
+// a super representative candidate - with url as its website (CreateWitness2).
+//
+// Before asking the node it checks that owner is not a witness already and
+// holds the apply fee, which is returned as ErrInsufficientBalance when it
+// does not.
+func (c *Client) CreateWitness(ctx context.Context, owner, url string) (*api.TransactionExtention, error) {
+	addr, err := decodeOwner(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateWitnessURL(url); err != nil {
+		return nil, err
+	}
+
+	fee, err := c.WitnessApplyFee(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get witness apply fee: %w", err)
+	}
+
+	account, err := c.GetAccount(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if account.GetIsWitness() {
+		return nil, fmt.Errorf("%w: %s is already a witness", ErrInvalidParams, owner)
+	}
+	if account.GetBalance() < fee.Int64() {
+		return nil, fmt.Errorf("%w: applying costs %s, %s holds %s", ErrInsufficientBalance, fee, owner, SUN(account.GetBalance()))
+	}
+
+	tx, err := c.transport.CreateWitness(ctx, &core.WitnessCreateContract{
+		OwnerAddress: addr,
+		Url:          []byte(url),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// UpdateWitness builds a transaction replacing a witness's URL (UpdateWitness2).
+func (c *Client) UpdateWitness(ctx context.Context, owner, url string) (*api.TransactionExtention, error) {
+	addr, err := decodeOwner(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateWitnessURL(url); err != nil {
+		return nil, err
+	}
+
+	tx, err := c.transport.UpdateWitness(ctx, &core.WitnessUpdateContract{
+		OwnerAddress: addr,
+		UpdateUrl:    []byte(url),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// UpdateBrokerage builds a transaction setting the share of its voters'
+// rewards a witness keeps, in percent from 0 to MaxBrokerage. The new ratio
+// applies from the next maintenance cycle.
+func (c *Client) UpdateBrokerage(ctx context.Context, owner string, brokerage int64) (*api.TransactionExtention, error) {
+	addr, err := decodeOwner(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	if brokerage < 0 || brokerage > MaxBrokerage {
+		return nil, fmt.Errorf("%w: brokerage must be between 0 and %d percent, got %d", ErrInvalidParams, MaxBrokerage, brokerage)
+	}
+
+	tx, err := c.transport.UpdateBrokerage(ctx, &core.UpdateBrokerageContract{
+		OwnerAddress: addr,
+		Brokerage:    int32(brokerage),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// GetPaginatedNowWitnessList returns up to limit witnesses starting at offset,
+// ranked by the votes cast so far in the current maintenance cycle rather than
+// by the standings ListWitnesses reports, which are fixed at the last
+// maintenance. limit is 1 to MaxWitnessPage. Nodes refuse it while a
+// maintenance is under way.
+func (c *Client) GetPaginatedNowWitnessList(ctx context.Context, offset, limit int64) (*api.WitnessList, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: offset cannot be negative", ErrInvalidParams)
+	}
+	if limit < 1 || limit > MaxWitnessPage {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidParams, MaxWitnessPage)
+	}
+
+	return c.transport.GetPaginatedNowWitnessList(ctx, GetPaginatedMessage(offset, limit))
+}
+
+// validateWitnessURL checks a witness URL as java-tron's
+// TransactionUtil.validUrl: present and at most MaxWitnessURLLength bytes.
+func validateWitnessURL(url string) error {
+	if url == "" || len(url) > MaxWitnessURLLength {
+		return fmt.Errorf("%w: witness url must be 1 to %d bytes", ErrInvalidParams, MaxWitnessURLLength)
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -206,3 +207,97 @@ func TestListWitnesses(t *testing.T) {
 		require.ErrorIs(t, err, sentinel)
 	})
 }
+
+func TestCreateWitnessChecksOwner(t *testing.T) {
+	params := &core.ChainParameters{ChainParameter: []*core.ChainParameters_ChainParameter{
+		{Key: "getAccountUpgradeCost", Value: 9_999_000_000},
+	}}
+
+	cases := []struct {
+		name    string
+		url     string
+		account *core.Account
+		wantErr error
+	}{
+		{"empty url", "", &core.Account{Balance: 10_000_000_000}, ErrInvalidParams},
+		{"url too long", strings.Repeat("a", MaxWitnessURLLength+1), &core.Account{Balance: 10_000_000_000}, ErrInvalidParams},
+		{"balance below the fee", "https://sr.example", &core.Account{Balance: 9_998_999_999}, ErrInsufficientBalance},
+		{"already a witness", "https://sr.example", &core.Account{Balance: 10_000_000_000, IsWitness: true}, ErrInvalidParams},
+		{"enough", "https://sr.example", &core.Account{Balance: 9_999_000_000}, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var sent *core.WitnessCreateContract
+			c := newTestClient(&fakeTransport{
+				getChainParameters: func(context.Context) (*core.ChainParameters, error) { return params, nil },
+				getAccount: func(_ context.Context, a *core.Account) (*core.Account, error) {
+					tc.account.Address = a.GetAddress()
+					return tc.account, nil
+				},
+				createWitness: func(_ context.Context, ct *core.WitnessCreateContract) (*api.TransactionExtention, error) {
+					sent = ct
+					return okTx(), nil
+				},
+			})
+
+			_, err := c.CreateWitness(t.Context(), testAddr, tc.url)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				require.Nil(t, sent, "a refused application is not sent")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, mustDecode(t, testAddr), sent.GetOwnerAddress())
+			require.Equal(t, []byte(tc.url), sent.GetUrl())
+		})
+	}
+}
+
+func TestUpdateBrokerageRange(t *testing.T) {
+	var sent *core.UpdateBrokerageContract
+	c := newTestClient(&fakeTransport{
+		updateBrokerage: func(_ context.Context, ct *core.UpdateBrokerageContract) (*api.TransactionExtention, error) {
+			sent = ct
+			return okTx(), nil
+		},
+	})
+
+	for _, brokerage := range []int64{0, 30, MaxBrokerage} {
+		_, err := c.UpdateBrokerage(t.Context(), testAddr, brokerage)
+		require.NoError(t, err)
+		require.EqualValues(t, brokerage, sent.GetBrokerage())
+	}
+
+	for _, brokerage := range []int64{-1, MaxBrokerage + 1} {
+		sent = nil
+		_, err := c.UpdateBrokerage(t.Context(), testAddr, brokerage)
+		require.ErrorIs(t, err, ErrInvalidParams)
+		require.Nil(t, sent)
+	}
+
+	_, err := c.UpdateBrokerage(t.Context(), "bad!", 10)
+	require.Error(t, err)
+}
+
+func TestGetPaginatedNowWitnessListLimits(t *testing.T) {
+	var got *api.PaginatedMessage
+	c := newTestClient(&fakeTransport{
+		getPaginatedNowWitnessList: func(_ context.Context, m *api.PaginatedMessage) (*api.WitnessList, error) {
+			got = m
+			return &api.WitnessList{}, nil
+		},
+	})
+
+	_, err := c.GetPaginatedNowWitnessList(t.Context(), 27, 100)
+	require.NoError(t, err)
+	require.Equal(t, int64(27), got.GetOffset())
+	require.Equal(t, int64(100), got.GetLimit())
+
+	_, err = c.GetPaginatedNowWitnessList(t.Context(), 0, 0)
+	require.ErrorIs(t, err, ErrInvalidParams)
+	_, err = c.GetPaginatedNowWitnessList(t.Context(), 0, MaxWitnessPage+1)
+	require.ErrorIs(t, err, ErrInvalidParams)
+	_, err = c.GetPaginatedNowWitnessList(t.Context(), -1, 10)
+	require.ErrorIs(t, err, ErrInvalidParams)
+}
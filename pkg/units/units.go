@@ -105,10 +105,12 @@ func (s SUN) Int64() int64 { return int64(s) }
 // String renders the amount in TRX, the unit humans read.
 func (s SUN) String() string { return s.TRX().String() + " TRX" }
 
-// TokenAmount is an amount of a TRC20 token in the token's own minimal units.
+// TokenAmount is an amount of a TRC20 or TRC10 token in the token's own
+// minimal units.
 //
-// Its scale is set by the token contract's decimals, so it is deliberately not
-// interchangeable with SUN: 1_000_000 is one USDT but a millionth of a TRX.
+// Its scale is set by the token's decimals - a TRC10 token calls them its
+// precision - so it is deliberately not interchangeable with SUN: 1_000_000 is
+// one USDT but a millionth of a TRX.
 // The zero value is a valid zero amount.
 type TokenAmount struct{ v *big.Int }
 
@@ -178,6 +180,27 @@ func (a TokenAmount) IsPositive() bool { return a.v != nil && a.v.Sign() > 0 }
 // without the token's decimals.
 func (a TokenAmount) String() string { return a.TokenUnits().String() }
 
+// MarshalText renders the amount as String does, so that it serialises to JSON
+// as a string of minimal units rather than as an empty object.
+func (a TokenAmount) MarshalText() ([]byte, error) { return []byte(a.String()), nil }
+
+// UnmarshalText reads what MarshalText writes, with the checks of
+// FromTokenUnits.
+func (a *TokenAmount) UnmarshalText(text []byte) error {
+	v, ok := new(big.Int).SetString(string(text), 10)
+	if !ok {
+		return fmt.Errorf("%w: token amount is not a decimal integer", ErrInvalidAmount)
+	}
+
+	parsed, err := FromTokenUnits(v)
+	if err != nil {
+		return err
+	}
+
+	*a = parsed
+	return nil
+}
+
 // Energy is an amount of the energy resource.
 type Energy struct{ value decimal.Decimal }
 
@@ -1,6 +1,7 @@
 package units_test
 
 import (
+	"encoding/json"
 	"math"
 	"math/big"
 	"testing"
@@ -179,6 +180,32 @@ func TestTokenAmountZeroValue(t *testing.T) {
 	require.Equal(t, "0", a.Decimal(6).String())
 }
 
+// An amount serialises as a string of minimal units, which JSON can carry
+// beyond the 2^53 a number loses precision at.
+func TestTokenAmountJSON(t *testing.T) {
+	t.Parallel()
+
+	wide, ok := new(big.Int).SetString("123456789012345678901234567890", 10)
+	require.True(t, ok)
+	amount, err := units.FromTokenUnits(wide)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(struct {
+		Amount units.TokenAmount `json:"amount"`
+	}{amount})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"amount":"123456789012345678901234567890"}`, string(data))
+
+	var decoded struct {
+		Amount units.TokenAmount `json:"amount"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, 0, amount.TokenUnits().Cmp(decoded.Amount.TokenUnits()))
+
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"amount":"-1"}`), &decoded), units.ErrInvalidAmount)
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"amount":"1.5"}`), &decoded), units.ErrInvalidAmount)
+}
+
 // The resource pricing parameters are quoted in SUN per unit, so the conversion
 // multiplies. The previous implementation divided by transactionFee, which
 // agrees only while the parameter equals 1000 - the value it happens to have
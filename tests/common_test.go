@@ -1,6 +1,11 @@
 package tests
 
 import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -24,8 +29,79 @@ const (
 
 	// Known block with transactions for testing
 	testBlockNum = uint64(79831098)
+
+	// cassetteDir holds the recorded calls of each test, by test name and
+	// node protocols.
+	cassetteDir = "testdata/cassettes"
 )
 
+// cassetteMode is how the tests reach the chain, set with GOTRON_CASSETTE:
+// unset for live nodes, "record" to also record each test's calls into
+// cassetteDir, and "replay" to answer them from there without a network.
+var cassetteMode = os.Getenv("GOTRON_CASSETTE")
+
+// newClient creates a client of the nodes in cfg, or of their recording in
+// replay mode. A test with no cassette fails in replay mode; a call the
+// cassette has no answer for fails with client.ErrNotRecorded.
+func newClient(t *testing.T, cfg client.Config) *client.Client {
+	t.Helper()
+	if cassetteMode == "" {
+		c, err := client.New(cfg)
+		require.NoError(t, err)
+		return c
+	}
+
+	// Health probes would add calls of their own to the cassette.
+	custom := client.Config{Health: client.HealthConfig{Disabled: true}}
+	path := cassettePath(t, cfg.Nodes)
+	switch cassetteMode {
+	case "record":
+		transports := make([]client.Transport, 0, len(cfg.Nodes))
+		for _, node := range cfg.Nodes {
+			var transport client.Transport
+			var err error
+			if node.Protocol == client.ProtocolHTTP {
+				transport, err = client.NewHTTPTransport(node)
+			} else {
+				transport, err = client.NewGRPCTransport(node)
+			}
+			require.NoError(t, err)
+			transports = append(transports, transport)
+		}
+		cassette := &client.Cassette{}
+		t.Cleanup(func() {
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+			require.NoError(t, cassette.Save(path))
+		})
+		recording := client.NewRecordingTransport(client.NewRoundRobinTransport(transports), cassette)
+		c, err := client.NewWithTransport(recording, custom)
+		require.NoError(t, err)
+		return c
+	case "replay":
+		cassette, err := client.LoadCassette(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("no cassette %s: record it with GOTRON_CASSETTE=record", path)
+		}
+		require.NoError(t, err)
+		c, err := client.NewWithTransport(client.NewReplayTransport(cassette), custom)
+		require.NoError(t, err)
+		return c
+	}
+	t.Fatalf("GOTRON_CASSETTE=%q: want record or replay", cassetteMode)
+	return nil
+}
+
+// cassettePath names the cassette of a test's client of nodes. The protocols
+// tell apart the clients of a test that compares them.
+func cassettePath(t *testing.T, nodes []client.NodeConfig) string {
+	protocols := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		protocols = append(protocols, string(node.Protocol))
+	}
+	name := strings.ReplaceAll(t.Name(), "/", "_") + "." + strings.Join(protocols, "-") + ".json"
+	return filepath.Join(cassetteDir, name)
+}
+
 func newGRPCClient(t *testing.T) *client.Client {
 	cfg := client.Config{
 		Nodes: []client.NodeConfig{
@@ -36,9 +112,7 @@ func newGRPCClient(t *testing.T) *client.Client {
 			},
 		},
 	}
-	c, err := client.New(cfg)
-	require.NoError(t, err)
-	return c
+	return newClient(t, cfg)
 }
 
 func newHTTPClient(t *testing.T) *client.Client {
@@ -50,9 +124,7 @@ func newHTTPClient(t *testing.T) *client.Client {
 			},
 		},
 	}
-	c, err := client.New(cfg)
-	require.NoError(t, err)
-	return c
+	return newClient(t, cfg)
 }
 
 func newMultiNodeClient(t *testing.T) *client.Client {
@@ -69,7 +141,5 @@ func newMultiNodeClient(t *testing.T) *client.Client {
 			},
 		},
 	}
-	c, err := client.New(cfg)
-	require.NoError(t, err)
-	return c
+	return newClient(t, cfg)
 }
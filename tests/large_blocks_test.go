@@ -21,8 +21,7 @@ func TestGetBlockRange_LargeResponse_GRPC(t *testing.T) {
 			},
 		},
 	}
-	c, err := client.New(cfg)
-	require.NoError(t, err)
+	c := newClient(t, cfg)
 	defer func() { _ = c.Close() }()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
@@ -49,8 +48,7 @@ func TestGetBlockRange_LargeResponse_HTTP(t *testing.T) {
 			},
 		},
 	}
-	c, err := client.New(cfg)
-	require.NoError(t, err)
+	c := newClient(t, cfg)
 	defer func() { _ = c.Close() }()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
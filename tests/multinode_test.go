@@ -112,8 +112,7 @@ func TestMultiNode_SameProtocol(t *testing.T) {
 			},
 		},
 	}
-	c, err := client.New(cfg)
-	require.NoError(t, err)
+	c := newClient(t, cfg)
 	defer func() { _ = c.Close() }()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -24,9 +24,7 @@ func newTronGridGRPCClient(t *testing.T) *client.Client {
 			{Protocol: client.ProtocolGRPC, Address: tronGridGRPCAddress, UseTLS: false},
 		},
 	}
-	c, err := client.New(cfg)
-	require.NoError(t, err)
-	return c
+	return newClient(t, cfg)
 }
 
 func newTronGridHTTPClient(t *testing.T) *client.Client {
@@ -36,9 +34,7 @@ func newTronGridHTTPClient(t *testing.T) *client.Client {
 			{Protocol: client.ProtocolHTTP, Address: tronGridHTTPAddress},
 		},
 	}
-	c, err := client.New(cfg)
-	require.NoError(t, err)
-	return c
+	return newClient(t, cfg)
 }
 
 func TestGetNodeInfo_GRPC(t *testing.T) {
@@ -91,3 +91,14 @@ func New(cfg Config) (*Tron, error) {
 
 	return &Tron{Client: c}, nil
 }
+
+// NewWithTransport creates a Tron client whose calls all go to transport
+// rather than to configured nodes. See client.NewWithTransport.
+func NewWithTransport(transport client.Transport, cfg Config) (*Tron, error) {
+	c, err := client.NewWithTransport(transport, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tron{Client: c}, nil
+}